@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/baiirun/aetherflow/internal/client"
+	"github.com/baiirun/aetherflow/internal/daemon"
+	"github.com/spf13/cobra"
+)
+
+var attachCmd = &cobra.Command{
+	Use:   "attach <agent-name>",
+	Short: "Attach interactively to a running agent's session",
+	Long: `Attach to an agent's opencode session by agent name, without looking up
+its session ID first.
+
+The agent is resolved through the daemon (pool agents and spawns). If the
+agent's session has not been captured yet, the command says so and exits;
+retry once the agent has started. The server is probed first, as with
+'af session attach'.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := client.New(resolveDaemonURL(cmd))
+		detail, err := c.StatusAgent(args[0], 0)
+		if err != nil {
+			Fatal("%v", err)
+		}
+		serverRef, sessionID, err := attachTarget(detail)
+		if err != nil {
+			Fatal("%v", err)
+		}
+		timeout, _ := cmd.Flags().GetDuration("timeout")
+		execOpencodeAttach(serverRef, sessionID, timeout)
+	},
+}
+
+// attachTarget returns the server and session to attach to for an agent.
+// It fails when the agent has no session yet or its server ref is unusable.
+func attachTarget(d *client.AgentDetail) (serverRef, sessionID string, err error) {
+	sessionID = d.Session.SessionID
+	if sessionID == "" {
+		sessionID = d.SessionID
+	}
+	if sessionID == "" {
+		if d.SessionError != "" {
+			return "", "", fmt.Errorf("agent %s has no session: capture failed: %s", d.ID, d.SessionError)
+		}
+		return "", "", fmt.Errorf("agent %s: session not ready yet, try again once the agent has started", d.ID)
+	}
+
+	serverRef = d.Session.ServerRef
+	if serverRef == "" {
+		return "", "", fmt.Errorf("agent %s: no server ref for session %s", d.ID, sessionID)
+	}
+	if _, err := daemon.ValidateServerURLLocal(serverRef); err != nil {
+		return "", "", fmt.Errorf("agent %s: invalid server ref %q: %w", d.ID, serverRef, err)
+	}
+	if strings.HasPrefix(serverRef, "-") {
+		return "", "", fmt.Errorf("agent %s: invalid server ref %q", d.ID, serverRef)
+	}
+	return serverRef, sessionID, nil
+}
+
+func init() {
+	rootCmd.AddCommand(attachCmd)
+	attachCmd.Flags().Duration("timeout", 5*time.Second, "How long to wait for the opencode server to respond before attaching")
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/baiirun/aetherflow/internal/client"
+)
+
+func TestAttachTargetResolvesServerAndSession(t *testing.T) {
+	d := &client.AgentDetail{
+		AgentStatus: client.AgentStatus{ID: "ghost_wolf", SessionID: "ses_abc123"},
+		Session:     client.SessionMetadata{ServerRef: "http://127.0.0.1:4096", SessionID: "ses_abc123"},
+	}
+	server, session, err := attachTarget(d)
+	if err != nil {
+		t.Fatalf("attachTarget: %v", err)
+	}
+	if server != "http://127.0.0.1:4096" || session != "ses_abc123" {
+		t.Errorf("attachTarget = (%q, %q), want (http://127.0.0.1:4096, ses_abc123)", server, session)
+	}
+}
+
+func TestAttachTargetSessionNotReady(t *testing.T) {
+	d := &client.AgentDetail{
+		AgentStatus: client.AgentStatus{ID: "ghost_wolf"},
+		Session:     client.SessionMetadata{ServerRef: "http://127.0.0.1:4096"},
+	}
+	_, _, err := attachTarget(d)
+	if err == nil || !strings.Contains(err.Error(), "session not ready") {
+		t.Fatalf("err = %v, want session not ready", err)
+	}
+}
+
+func TestAttachTargetRejectsRemoteServer(t *testing.T) {
+	d := &client.AgentDetail{
+		AgentStatus: client.AgentStatus{ID: "ghost_wolf", SessionID: "ses_abc123"},
+		Session:     client.SessionMetadata{ServerRef: "http://example.com:4096", SessionID: "ses_abc123"},
+	}
+	if _, _, err := attachTarget(d); err == nil {
+		t.Fatal("expected error for non-local server ref")
+	}
+}
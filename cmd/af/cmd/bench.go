@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/baiirun/aetherflow/internal/daemon/bench"
+	"github.com/baiirun/aetherflow/internal/term"
+	"github.com/spf13/cobra"
+)
+
+// benchCmd groups development benchmarks. It is hidden from help: the
+// numbers are for working on the daemon, not for operating it.
+var benchCmd = &cobra.Command{
+	Use:    "bench",
+	Short:  "Benchmark daemon internals (development)",
+	Hidden: true,
+}
+
+var benchSpawnCmd = &cobra.Command{
+	Use:   "spawn",
+	Short: "Measure spawn latency with trivial agents",
+	Long: `Spawn --count trivial agents through a private pool and report
+percentiles of the time from queue to running and from running to reap.
+
+Each agent runs --spawn-cmd (default "true"), which must exit on its own
+and ignore the arguments the pool appends. Tasks are synthetic: prog and
+the daemon are not involved, and nothing is claimed.`,
+	Args: cobra.NoArgs,
+	Run:  runBenchSpawn,
+}
+
+func runBenchSpawn(cmd *cobra.Command, _ []string) {
+	count, _ := cmd.Flags().GetInt("count")
+	concurrency, _ := cmd.Flags().GetInt("concurrency")
+	spawnCmd, _ := cmd.Flags().GetString("spawn-cmd")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+	result, err := bench.Spawn(ctx, bench.SpawnOptions{
+		Count:       count,
+		Concurrency: concurrency,
+		SpawnCmd:    spawnCmd,
+	})
+	if err != nil {
+		Fatal("bench spawn: %v", err)
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(result)
+		return
+	}
+	fmt.Printf("%s %d agents in %v (%d failed)\n",
+		term.Bold("Spawned:"), result.Count, result.Elapsed.Round(time.Millisecond), result.Failed)
+	fmt.Printf("  %-14s  %s\n", "", term.Dim(fmt.Sprintf("%-10s  %-10s  %-10s  %s", "p50", "p90", "p99", "max")))
+	printLatencySpread("queue to run", result.Queue)
+	printLatencySpread("run to reap", result.Reap)
+}
+
+func printLatencySpread(label string, s bench.LatencySpread) {
+	round := func(d time.Duration) string { return d.Round(time.Microsecond).String() }
+	fmt.Printf("  %-14s  %-10s  %-10s  %-10s  %s\n", label, round(s.P50), round(s.P90), round(s.P99), round(s.Max))
+}
+
+func init() {
+	rootCmd.AddCommand(benchCmd)
+	benchCmd.AddCommand(benchSpawnCmd)
+	benchSpawnCmd.Flags().Int("count", 20, "Number of agents to spawn")
+	benchSpawnCmd.Flags().Int("concurrency", 4, "Maximum agents running at once")
+	benchSpawnCmd.Flags().String("spawn-cmd", bench.DefaultSpawnCmd, "Command each agent runs")
+	benchSpawnCmd.Flags().Bool("json", false, "Output as JSON")
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/baiirun/aetherflow/internal/daemon"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect daemon configuration",
+}
+
+var configShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the effective daemon configuration",
+	Long: `Print the configuration the daemon would run with, after merging
+CLI flags, the config file, and defaults (in that priority order).
+
+Accepts the same flags as 'af daemon start', so you can preview the effect
+of an override before starting the daemon. Credentials embedded in URLs
+or in KEY=value assignments in spawn_cmd are redacted.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		cfg := buildConfig(cmd)
+		if err := writeEffectiveConfig(os.Stdout, cfg, jsonOutput); err != nil {
+			Fatal("%v", err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configShowCmd)
+
+	f := configShowCmd.Flags()
+	addDaemonConfigFlags(f)
+	f.Bool("json", false, "Output JSON instead of YAML")
+}
+
+// writeEffectiveConfig renders cfg as YAML, or as indented JSON.
+func writeEffectiveConfig(w io.Writer, cfg daemon.Config, asJSON bool) error {
+	view := daemon.NewEffectiveConfig(cfg)
+	if asJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(view)
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(view); err != nil {
+		return err
+	}
+	return enc.Close()
+}
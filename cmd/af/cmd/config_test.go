@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/baiirun/aetherflow/internal/daemon"
+	"github.com/spf13/cobra"
+)
+
+func TestConfigShowReflectsOverrides(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, ".aetherflow.yaml")
+	if err := os.WriteFile(configPath, []byte("pool_size: 5\npoll_interval: 20s\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := &cobra.Command{}
+	addDaemonConfigFlags(cmd.Flags())
+	if err := cmd.Flags().Set("config", configPath); err != nil {
+		t.Fatal(err)
+	}
+	// Flag beats the config file's pool_size.
+	if err := cmd.Flags().Set("pool-size", "7"); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := buildConfig(cmd)
+
+	var yamlOut bytes.Buffer
+	if err := writeEffectiveConfig(&yamlOut, cfg, false); err != nil {
+		t.Fatalf("writeEffectiveConfig: %v", err)
+	}
+	for _, want := range []string{"pool_size: 7", "poll_interval: 20s", "reconcile_interval: 30s", "spawn_policy: manual"} {
+		if !strings.Contains(yamlOut.String(), want) {
+			t.Errorf("yaml output missing %q:\n%s", want, yamlOut.String())
+		}
+	}
+
+	var jsonOut bytes.Buffer
+	if err := writeEffectiveConfig(&jsonOut, cfg, true); err != nil {
+		t.Fatalf("writeEffectiveConfig json: %v", err)
+	}
+	var view daemon.EffectiveConfig
+	if err := json.Unmarshal(jsonOut.Bytes(), &view); err != nil {
+		t.Fatalf("unmarshal json output: %v", err)
+	}
+	if view.PoolSize != 7 || view.PollInterval != "20s" {
+		t.Errorf("json view = %+v, want pool_size 7 and poll_interval 20s", view)
+	}
+}
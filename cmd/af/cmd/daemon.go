@@ -11,6 +11,7 @@ import (
 	"github.com/baiirun/aetherflow/internal/client"
 	"github.com/baiirun/aetherflow/internal/daemon"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var daemonCmd = &cobra.Command{
@@ -44,11 +45,15 @@ Configuration is loaded from (in priority order):
 		background, _ := cmd.Flags().GetBool("detach")
 
 		if background {
+			if queueFile, _ := cmd.Flags().GetString("queue-file"); queueFile == daemon.QueueFileStdin {
+				Fatal("--queue-file=- reads stdin and cannot be combined with --detach")
+			}
 			startDetached(cmd)
 			return
 		}
 
 		cfg := buildConfig(cmd)
+		cfg.Reload = func() (daemon.Config, error) { return loadConfig(cmd) }
 		d := daemon.New(cfg)
 		if err := d.Run(); err != nil {
 			Fatal("%v", err)
@@ -57,7 +62,18 @@ Configuration is loaded from (in priority order):
 }
 
 // buildConfig assembles a Config from CLI flags and config file.
+// Exits on an invalid config.
 func buildConfig(cmd *cobra.Command) daemon.Config {
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		Fatal("%v", err)
+	}
+	return cfg
+}
+
+// loadConfig resolves CLI flags, the config file, and defaults into a
+// validated Config. The daemon calls it again on SIGHUP to reload.
+func loadConfig(cmd *cobra.Command) (daemon.Config, error) {
 	var cfg daemon.Config
 
 	// Read CLI flags into config. Only non-default values override.
@@ -89,6 +105,9 @@ func buildConfig(cmd *cobra.Command) daemon.Config {
 	if cmd.Flags().Changed("solo") {
 		cfg.Solo, _ = cmd.Flags().GetBool("solo")
 	}
+	if cmd.Flags().Changed("queue-file") {
+		cfg.QueueFile, _ = cmd.Flags().GetString("queue-file")
+	}
 
 	// Load config file (only fills zero-valued fields).
 	configPath, _ := cmd.Flags().GetString("config")
@@ -96,16 +115,16 @@ func buildConfig(cmd *cobra.Command) daemon.Config {
 		configPath = ".aetherflow.yaml"
 	}
 	if err := daemon.LoadConfigFile(configPath, &cfg); err != nil {
-		Fatal("%v", err)
+		return daemon.Config{}, err
 	}
 
 	// Apply defaults for anything still unset, then validate.
 	cfg.ApplyDefaults()
 	if err := cfg.Validate(); err != nil {
-		Fatal("%v", err)
+		return daemon.Config{}, err
 	}
 
-	return cfg
+	return cfg, nil
 }
 
 // startDetached re-execs the daemon in the background.
@@ -117,7 +136,7 @@ func startDetached(cmd *cobra.Command) {
 
 	// Forward all flags except --detach.
 	reArgs := []string{"daemon", "start"}
-	for _, name := range []string{"project", "listen-addr", "poll-interval", "pool-size", "spawn-cmd", "server-url", "spawn-policy", "max-retries", "solo", "config"} {
+	for _, name := range []string{"project", "listen-addr", "poll-interval", "pool-size", "spawn-cmd", "server-url", "spawn-policy", "max-retries", "solo", "queue-file", "config"} {
 		if cmd.Flags().Changed(name) {
 			val, _ := cmd.Flags().GetString(name)
 			// Duration and int flags also work with GetString via pflag.
@@ -146,8 +165,10 @@ var daemonStopCmd = &cobra.Command{
 	Short: "Stop the daemon",
 	Run: func(cmd *cobra.Command, args []string) {
 		force, _ := cmd.Flags().GetBool("force")
+		reason, _ := cmd.Flags().GetString("reason")
+		token, _ := cmd.Flags().GetString("token")
 		c := client.New(resolveDaemonURL(cmd))
-		result, err := c.StopDaemon(force)
+		result, err := c.StopDaemon(force, client.StopDaemonParams{Reason: reason, Token: token})
 		if err != nil {
 			var refused *client.ShutdownRefusedError
 			if errors.As(err, &refused) {
@@ -171,6 +192,18 @@ func init() {
 
 	f := daemonStartCmd.Flags()
 	f.BoolP("detach", "d", false, "Run in background")
+	addDaemonConfigFlags(f)
+
+	daemonStopCmd.Flags().Bool("force", false, "Stop even when the daemon reports active sessions")
+	daemonStopCmd.Flags().String("reason", "", "Why the daemon is being stopped; logged and reported at next start")
+	daemonStopCmd.Flags().String("token", "", "Confirmation token, required when the daemon sets shutdown_token")
+	daemonCmd.Flags().String("spawn-policy", "", "Daemon spawn policy hint for endpoint resolution (auto or manual)")
+	daemonStopCmd.Flags().String("spawn-policy", "", "Daemon spawn policy hint for endpoint resolution (auto or manual)")
+}
+
+// addDaemonConfigFlags registers the flags read by buildConfig. Shared by
+// commands that resolve daemon configuration the same way daemon start does.
+func addDaemonConfigFlags(f *pflag.FlagSet) {
 	f.StringP("project", "p", "", "Project to watch for tasks (required for --spawn-policy=auto)")
 	f.String("listen-addr", "", "Daemon listen address override (for example 127.0.0.1:7070)")
 	f.Duration("poll-interval", daemon.DefaultPollInterval, "How often to poll prog for tasks")
@@ -178,13 +211,10 @@ func init() {
 	f.String("spawn-cmd", daemon.DefaultSpawnCmd, "Command to launch agent sessions")
 	f.String("server-url", daemon.DefaultServerURL, "Opencode server URL for attach-based session launches")
 	f.String("spawn-policy", string(daemon.DefaultSpawnPolicy), "Daemon spawn policy: auto (schedule from prog) or manual (spawn-only)")
-	f.Int("max-retries", daemon.DefaultMaxRetries, "Max crash respawns per task")
+	f.Int("max-retries", daemon.DefaultMaxRetries, "Max crash respawns per task (-1 disables crash respawn)")
 	f.Bool("solo", false, "Solo mode: agents merge to main directly instead of creating PRs")
+	f.String("queue-file", "", "Read ready tasks from a JSON file (or - for stdin) instead of prog")
 	f.String("config", "", "Config file path (default: .aetherflow.yaml)")
-
-	daemonStopCmd.Flags().Bool("force", false, "Stop even when the daemon reports active sessions")
-	daemonCmd.Flags().String("spawn-policy", "", "Daemon spawn policy hint for endpoint resolution (auto or manual)")
-	daemonStopCmd.Flags().String("spawn-policy", "", "Daemon spawn policy hint for endpoint resolution (auto or manual)")
 }
 
 func printDaemonNotRunning(w io.Writer) {
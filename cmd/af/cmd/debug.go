@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/baiirun/aetherflow/internal/client"
+	"github.com/spf13/cobra"
+)
+
+var debugCmd = &cobra.Command{
+	Use:   "debug",
+	Short: "Daemon diagnostics for bug reports",
+}
+
+var debugSnapshotCmd = &cobra.Command{
+	Use:   "snapshot",
+	Short: "Capture the daemon's full state as JSON",
+	Long: `Capture everything useful for a bug report in one JSON document: the
+effective config (credentials redacted), pool and agent status, the spawn
+registry, recent errors, event buffer stats, and the managed server.
+
+Writes to stdout unless --output is given. Review the file before sharing;
+task titles and prompts are included as-is.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		output, _ := cmd.Flags().GetString("output")
+
+		c := client.New(resolveDaemonURL(cmd))
+		snap, err := c.DebugSnapshot()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, snap, "", "  "); err != nil {
+			Fatal("formatting snapshot: %v", err)
+		}
+		buf.WriteByte('\n')
+
+		if output == "" {
+			_, _ = os.Stdout.Write(buf.Bytes())
+			return
+		}
+		if err := os.WriteFile(output, buf.Bytes(), 0o600); err != nil {
+			Fatal("writing snapshot: %v", err)
+		}
+		fmt.Printf("Snapshot written to %s\n", output)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(debugCmd)
+	debugCmd.AddCommand(debugSnapshotCmd)
+	debugSnapshotCmd.Flags().StringP("output", "o", "", "Write the snapshot to this file instead of stdout")
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/baiirun/aetherflow/internal/client"
+	"github.com/baiirun/aetherflow/internal/term"
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "List tasks the daemon has worked since it started",
+	Long: `Print every task the daemon scheduled since startup with its outcome,
+attempt count, and timings. Useful as a summary after an unattended run.
+
+Outcomes: running, completed, failed (fatal exit code), retired, killed,
+crashed-max-retries, and stranded (a respawn was skipped or failed, leaving
+the task in_progress until the orphan scan recovers it).`,
+	Run: func(cmd *cobra.Command, args []string) {
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		c := newDaemonClient(cmd)
+		result, err := c.HistoryTasks()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			_ = enc.Encode(result)
+			return
+		}
+		printHistory(result.Tasks, time.Now())
+	},
+}
+
+func printHistory(tasks []client.TaskHistoryEntry, now time.Time) {
+	if len(tasks) == 0 {
+		fmt.Println("no tasks scheduled yet")
+		return
+	}
+
+	fmt.Printf("%-20s  %-10s  %-19s  %-8s  %-8s  %s\n", "TASK", "ROLE", "OUTCOME", "ATTEMPTS", "STARTED", "DURATION")
+	for _, t := range tasks {
+		end := t.EndedAt
+		if end.IsZero() {
+			end = now
+		}
+		fmt.Printf("%-20s  %-10s  %s  %-8d  %-8s  %s\n",
+			truncateString(t.TaskID, 20),
+			t.Role,
+			term.PadRight(t.Outcome, 19, outcomeColor(t.Outcome)),
+			t.Attempts,
+			t.StartedAt.Local().Format("15:04:05"),
+			end.Sub(t.StartedAt).Round(time.Second),
+		)
+	}
+}
+
+func outcomeColor(outcome string) func(string) string {
+	switch outcome {
+	case "completed":
+		return term.Green
+	case "running":
+		return term.Yellow
+	default:
+		return term.Red
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	historyCmd.Flags().Bool("json", false, "Output as JSON")
+}
@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/baiirun/aetherflow/internal/daemon"
+	"github.com/baiirun/aetherflow/internal/sessions"
+	"github.com/baiirun/aetherflow/internal/term"
+	"github.com/spf13/cobra"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Write a starter .aetherflow.yaml in the current directory",
+	Long: `Write a commented .aetherflow.yaml with the default pool size, spawn
+command, server URL, and session directory, ready to edit.
+
+The project comes from --project. Without it, af init asks for one,
+offering the current directory's name. An existing file is left alone
+unless --force is given.`,
+	Args: cobra.NoArgs,
+	Run:  runInit,
+}
+
+// initConfigTemplate is the file af init writes. The %s verbs are the
+// project and the default session directory.
+const initConfigTemplate = `# aetherflow configuration. CLI flags override these values; run
+# 'af config show' to see the effective configuration. The README's
+# Configuration section lists every setting.
+
+# prog project the daemon schedules tasks from.
+project: %s
+
+# Agents running at once.
+pool_size: %d
+
+# Command that launches an agent; the rendered role prompt is appended.
+# The daemon adds --attach <server_url> when it launches the agent.
+spawn_cmd: %s
+
+# opencode server agents attach to.
+server_url: %s
+
+# manual: agents are started with af spawn. auto: the daemon polls prog
+# and schedules ready tasks into the pool.
+spawn_policy: %s
+
+# Global session registry, shared by every project.
+# session_dir: %s
+`
+
+func runInit(cmd *cobra.Command, _ []string) {
+	force, _ := cmd.Flags().GetBool("force")
+	path, _ := cmd.Flags().GetString("config")
+	if path == "" {
+		path = ".aetherflow.yaml"
+	}
+
+	project, _ := cmd.Flags().GetString("project")
+	if project == "" {
+		var err error
+		project, err = promptProject(os.Stdin, os.Stdout)
+		if err != nil {
+			Fatal("%v", err)
+		}
+	}
+
+	if err := writeInitConfig(path, project, force); err != nil {
+		Fatal("%v", err)
+	}
+	fmt.Printf("wrote %s %s\n", path, term.Dimf("(project %s)", project))
+}
+
+// promptProject asks for a project name on in, defaulting to the current
+// directory's name when the answer is empty.
+func promptProject(in io.Reader, out io.Writer) (string, error) {
+	def := ""
+	if wd, err := os.Getwd(); err == nil {
+		def = filepath.Base(wd)
+	}
+	fmt.Fprintf(out, "Project [%s]: ", def)
+	input, err := bufio.NewReader(in).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("reading project: %w", err)
+	}
+	if project := strings.TrimSpace(input); project != "" {
+		return project, nil
+	}
+	if def == "" {
+		return "", errors.New("no project: pass --project")
+	}
+	return def, nil
+}
+
+// renderInitConfig returns the starter config for project, filled in with
+// the daemon's defaults.
+func renderInitConfig(project string) string {
+	var defaults daemon.Config
+	defaults.ApplyDefaults()
+	sessionDir, err := sessions.DefaultDir()
+	if err != nil {
+		sessionDir = "~/.config/aetherflow/sessions"
+	}
+	return fmt.Sprintf(initConfigTemplate,
+		project,
+		defaults.PoolSize,
+		defaults.SpawnCmd,
+		defaults.ServerURL,
+		defaults.SpawnPolicy,
+		sessionDir,
+	)
+}
+
+// writeInitConfig writes the starter config for project to path. It
+// refuses to replace an existing file unless force is set.
+func writeInitConfig(path, project string, force bool) error {
+	cfg := daemon.Config{Project: project, Logger: slog.New(slog.DiscardHandler)}
+	cfg.ApplyDefaults()
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+	f, err := os.OpenFile(path, flags, 0o644)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("%s already exists (use --force to overwrite)", path)
+		}
+		return fmt.Errorf("creating %s: %w", path, err)
+	}
+	if _, err := io.WriteString(f, renderInitConfig(project)); err != nil {
+		f.Close()
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+	initCmd.Flags().Bool("force", false, "Overwrite an existing config file")
+}
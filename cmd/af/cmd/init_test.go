@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/baiirun/aetherflow/internal/daemon"
+)
+
+func TestWriteInitConfigParsesAndValidates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".aetherflow.yaml")
+	if err := writeInitConfig(path, "myapp", false); err != nil {
+		t.Fatalf("writeInitConfig: %v", err)
+	}
+
+	var cfg daemon.Config
+	if err := daemon.LoadConfigFile(path, &cfg); err != nil {
+		t.Fatalf("LoadConfigFile: %v", err)
+	}
+	if cfg.Project != "myapp" || cfg.PoolSize != daemon.DefaultPoolSize || cfg.ServerURL != daemon.DefaultServerURL {
+		t.Errorf("config = project %q, pool_size %d, server_url %q; want myapp and the defaults", cfg.Project, cfg.PoolSize, cfg.ServerURL)
+	}
+	if cfg.SessionDir != "" {
+		t.Errorf("session_dir = %q, want it left commented out", cfg.SessionDir)
+	}
+	// The attach flag is added at launch from server_url, so baking it in
+	// would pin agents to the default server after server_url changes.
+	if cfg.SpawnCmd != daemon.DefaultSpawnCmd || strings.Contains(cfg.SpawnCmd, "--attach") {
+		t.Errorf("spawn_cmd = %q, want %q without --attach", cfg.SpawnCmd, daemon.DefaultSpawnCmd)
+	}
+	cfg.ApplyDefaults()
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate: %v", err)
+	}
+}
+
+func TestWriteInitConfigOverwrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".aetherflow.yaml")
+	if err := os.WriteFile(path, []byte("project: existing\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := writeInitConfig(path, "myapp", false)
+	if err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("writeInitConfig over existing file = %v, want error mentioning --force", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "project: existing\n" {
+		t.Errorf("existing file changed to %q", data)
+	}
+
+	if err := writeInitConfig(path, "myapp", true); err != nil {
+		t.Fatalf("writeInitConfig --force: %v", err)
+	}
+	var cfg daemon.Config
+	if err := daemon.LoadConfigFile(path, &cfg); err != nil || cfg.Project != "myapp" {
+		t.Errorf("after --force: project %q, err %v; want myapp", cfg.Project, err)
+	}
+}
+
+func TestWriteInitConfigRejectsInvalidProject(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".aetherflow.yaml")
+	if err := writeInitConfig(path, "my app", false); err == nil {
+		t.Error("writeInitConfig with invalid project succeeded, want error")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("config file written for invalid project (stat err %v)", err)
+	}
+}
+
+func TestPromptProject(t *testing.T) {
+	var out strings.Builder
+	got, err := promptProject(strings.NewReader("billing\n"), &out)
+	if err != nil || got != "billing" {
+		t.Errorf("promptProject = %q, %v; want billing", got, err)
+	}
+
+	wd, _ := os.Getwd()
+	got, err = promptProject(strings.NewReader("\n"), &out)
+	if err != nil || got != filepath.Base(wd) {
+		t.Errorf("promptProject with empty answer = %q, %v; want %q", got, err, filepath.Base(wd))
+	}
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"syscall"
@@ -21,6 +22,12 @@ opencode plugin event pipeline). By default shows all events formatted as
 human-readable output. Use --raw to see raw JSON, -n to limit the initial
 count, and -f/--follow or -w/--watch to stream new events as they arrive.
 
+--live instead tees the pool agent's raw stdout (its JSONL stream) to the
+terminal as the agent writes it, until the agent exits or you press
+ctrl-c. Earlier output is not replayed. If the terminal falls too far
+behind, whole lines are skipped and replaced by one
+{"type":"af_dropped","lines":N} line.
+
 Requires a running daemon and an active agent.`,
 	Args: cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
@@ -28,12 +35,25 @@ Requires a running daemon and an active agent.`,
 		watch, _ := cmd.Flags().GetBool("watch")
 		lines, _ := cmd.Flags().GetInt("lines")
 		raw, _ := cmd.Flags().GetBool("raw")
+		live, _ := cmd.Flags().GetBool("live")
+
+		if live {
+			streamAgentStdout(client.New(resolveDaemonURL(cmd)), args[0])
+			return
+		}
 
 		// Both --follow and --watch enable streaming; treat them as aliases.
 		streaming := follow || watch
 		_ = raw // reserved for future --raw flag (events.list raw=true)
 
-		c := client.New(resolveDaemonURL(cmd))
+		// Follow mode polls repeatedly, so keep the connection open.
+		var c *client.Client
+		if streaming {
+			c = client.NewPersistent(resolveDaemonURL(cmd))
+			defer c.Close()
+		} else {
+			c = client.New(resolveDaemonURL(cmd))
+		}
 		result, err := c.EventsList(args[0], 0)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "error: %v\n", err)
@@ -96,6 +116,40 @@ func followEvents(c *client.Client, agentName string, lastTS int64) {
 	}
 }
 
+// streamAgentStdout copies the agent's live stdout to ours until the agent
+// exits or the user interrupts.
+func streamAgentStdout(c *client.Client, agentName string) {
+	stream, err := c.AgentStdout(agentName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "streaming stdout of %s (ctrl-c to stop)\n", agentName)
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	interrupted := make(chan struct{})
+	go func() {
+		<-sigCh
+		close(interrupted)
+		_ = stream.Close()
+	}()
+
+	_, err = io.Copy(os.Stdout, stream)
+	_ = stream.Close()
+	select {
+	case <-interrupted:
+		fmt.Println() // clean line after ^C
+	default:
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "%s exited\n", agentName)
+	}
+}
+
 func init() {
 	rootCmd.AddCommand(logsCmd)
 
@@ -103,4 +157,5 @@ func init() {
 	logsCmd.Flags().BoolP("watch", "w", false, "Stream new events as they arrive (alias for --follow)")
 	logsCmd.Flags().IntP("lines", "n", defaultTailLines, "Number of initial lines to show")
 	logsCmd.Flags().Bool("raw", false, "Output raw event JSON instead of formatted text")
+	logsCmd.Flags().Bool("live", false, "Stream the pool agent's raw stdout as it is written")
 }
@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+
+	"github.com/baiirun/aetherflow/internal/client"
+	"github.com/baiirun/aetherflow/internal/daemon"
+	"github.com/baiirun/aetherflow/internal/term"
+	"github.com/spf13/cobra"
+)
+
+var logsGrepCmd = &cobra.Command{
+	Use:   "grep <agent-name> <pattern>",
+	Short: "Search an agent's tool calls",
+	Long: `Print the agent's tool calls whose tool name or key input matches a
+regular expression, oldest first.
+
+The key input is the field af status shows for each call: the command for
+bash, the file path for read/edit/write, the pattern for grep/glob. Every
+event the daemon still buffers for the agent's session is searched.
+
+Use --tool to search only calls to some tools, and --json for the matching
+calls as JSON.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		tools, _ := cmd.Flags().GetStringSlice("tool")
+		asJSON, _ := cmd.Flags().GetBool("json")
+
+		re, err := regexp.Compile(args[1])
+		if err != nil {
+			Fatal("invalid pattern: %v", err)
+		}
+
+		c := client.New(resolveDaemonURL(cmd))
+		detail, err := c.StatusAgentScan(args[0], daemon.DefaultEventBufSize, daemon.DefaultEventBufSize, tools...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+
+		matches := grepToolCalls(detail.ToolCalls, re)
+		if asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			_ = enc.Encode(matches)
+			return
+		}
+		for _, tc := range matches {
+			fmt.Printf("%s  %s %s\n",
+				term.Dim(tc.Timestamp.Local().Format("2006-01-02 15:04:05")),
+				term.PadRight(tc.Tool, 10, term.Cyan),
+				stripANSI(tc.Input),
+			)
+		}
+	},
+}
+
+// grepToolCalls keeps the calls whose tool name or key input matches re,
+// in order. The result is never nil so --json prints [] for no matches.
+func grepToolCalls(calls []client.ToolCall, re *regexp.Regexp) []client.ToolCall {
+	out := make([]client.ToolCall, 0)
+	for _, tc := range calls {
+		if re.MatchString(tc.Tool) || re.MatchString(tc.Input) {
+			out = append(out, tc)
+		}
+	}
+	return out
+}
+
+func init() {
+	logsCmd.AddCommand(logsGrepCmd)
+	logsGrepCmd.Flags().StringSlice("tool", nil, "Search only calls to these tools (e.g. --tool bash,edit)")
+	logsGrepCmd.Flags().Bool("json", false, "Output matching calls as JSON")
+}
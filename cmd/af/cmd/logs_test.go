@@ -1,7 +1,11 @@
 package cmd
 
 import (
+	"regexp"
+	"slices"
 	"testing"
+
+	"github.com/baiirun/aetherflow/internal/client"
 )
 
 func TestLogsFlagsRegistered(t *testing.T) {
@@ -81,3 +85,40 @@ func TestDefaultTailLines(t *testing.T) {
 		t.Errorf("defaultTailLines = %d, want 20", defaultTailLines)
 	}
 }
+
+func TestGrepToolCalls(t *testing.T) {
+	calls := []client.ToolCall{
+		{Tool: "bash", Input: "go test ./..."},
+		{Tool: "read", Input: "/repo/internal/daemon/pool.go"},
+		{Tool: "bash", Input: "git status"},
+		{Tool: "edit", Input: "/repo/go.mod"},
+		{Tool: "grep", Input: "TODO"},
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    []string // inputs of the expected matches, in order
+	}{
+		{name: "input substring", pattern: "go test", want: []string{"go test ./..."}},
+		{name: "input regexp", pattern: `\.go$`, want: []string{"/repo/internal/daemon/pool.go"}},
+		{name: "tool name", pattern: "^bash$", want: []string{"go test ./...", "git status"}},
+		{name: "tool and input", pattern: "grep|mod", want: []string{"/repo/go.mod", "TODO"}},
+		{name: "no match", pattern: "rm -rf", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := grepToolCalls(calls, regexp.MustCompile(tt.pattern))
+			if got == nil {
+				t.Fatal("grepToolCalls returned nil, want empty slice")
+			}
+			var inputs []string
+			for _, tc := range got {
+				inputs = append(inputs, tc.Input)
+			}
+			if !slices.Equal(inputs, tt.want) {
+				t.Errorf("matches = %q, want %q", inputs, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/baiirun/aetherflow/internal/sessions"
+)
+
+// objectiveCacheFile holds session objectives fetched by af sessions, next to
+// the session registry, so repeated listings skip the REST round trips.
+const objectiveCacheFile = "objectives.json"
+
+// objectiveCacheTTL bounds how long a cached objective is trusted. A session's
+// objective is its first prompt, so it rarely changes; the TTL only guards
+// against a cache that outlives a reused session ID.
+const objectiveCacheTTL = 24 * time.Hour
+
+// cachedObjective is one objective in the cache. RecordUpdatedAt is the
+// session record's UpdatedAt when the objective was fetched; a record that
+// has changed since invalidates the entry.
+type cachedObjective struct {
+	ServerRef       string    `json:"server_ref"`
+	SessionID       string    `json:"session_id"`
+	Objective       string    `json:"objective"`
+	RecordUpdatedAt time.Time `json:"record_updated_at"`
+	FetchedAt       time.Time `json:"fetched_at"`
+}
+
+// objectiveCache maps recordKey(server_ref, session_id) to a cached objective.
+type objectiveCache map[string]cachedObjective
+
+// loadObjectiveCache reads the cache at path. A missing or unreadable file
+// yields an empty cache: the cache is an optimization, never required.
+func loadObjectiveCache(path string) objectiveCache {
+	cache := make(objectiveCache)
+	if path == "" {
+		return cache
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cache
+	}
+	var entries []cachedObjective
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return cache
+	}
+	for _, e := range entries {
+		cache[recordKey(e.ServerRef, e.SessionID)] = e
+	}
+	return cache
+}
+
+// lookup returns the cached objective for r if it is fresh and r hasn't
+// changed since it was fetched.
+func (c objectiveCache) lookup(r sessions.Record, now time.Time) (string, bool) {
+	e, ok := c[recordKey(r.ServerRef, r.SessionID)]
+	if !ok || !e.RecordUpdatedAt.Equal(r.UpdatedAt) || now.Sub(e.FetchedAt) > objectiveCacheTTL {
+		return "", false
+	}
+	return e.Objective, true
+}
+
+// save writes the entries for recs to path, dropping sessions no longer
+// listed so the file doesn't grow without bound.
+func (c objectiveCache) save(path string, recs []sessions.Record) error {
+	entries := make([]cachedObjective, 0, len(recs))
+	for _, r := range recs {
+		if e, ok := c[recordKey(r.ServerRef, r.SessionID)]; ok {
+			entries = append(entries, e)
+		}
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling objective cache: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".objectives-*.json")
+	if err != nil {
+		return fmt.Errorf("creating temp objective cache: %w", err)
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("writing temp objective cache: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("closing temp objective cache: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("renaming objective cache: %w", err)
+	}
+	return nil
+}
@@ -3,6 +3,9 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/baiirun/aetherflow/internal/client"
 	"github.com/baiirun/aetherflow/internal/term"
@@ -20,7 +23,7 @@ allowed since those tasks are already claimed.
 
 Use 'af resume' to return to normal scheduling.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		c := client.New(resolveDaemonURL(cmd))
+		c := newDaemonClient(cmd)
 		result, err := c.PoolDrain()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "error: %v\n", err)
@@ -39,10 +42,14 @@ No new tasks will be scheduled and crashed agents will not be
 respawned. Agents currently running continue until they finish
 or crash.
 
+With --role, only tasks of that role (planner or worker) are held back:
+the pool stays active and other roles keep scheduling.
+
 Use 'af resume' to return to normal scheduling.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		c := client.New(resolveDaemonURL(cmd))
-		result, err := c.PoolPause()
+		role, _ := cmd.Flags().GetString("role")
+		c := newDaemonClient(cmd)
+		result, err := c.PoolPause(role)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "error: %v\n", err)
 			os.Exit(1)
@@ -57,10 +64,15 @@ var resumeCmd = &cobra.Command{
 	Long: `Transition the pool back to active mode from draining or paused.
 
 Normal scheduling resumes: tasks from the queue will be assigned to
-free slots and crashed agents will be respawned.`,
+free slots and crashed agents will be respawned. Role pauses set with
+'af pause --role' are lifted too.
+
+With --role, only that role's pause is lifted and the pool mode is
+unchanged.`,
 	Run: func(cmd *cobra.Command, args []string) {
-		c := client.New(resolveDaemonURL(cmd))
-		result, err := c.PoolResume()
+		role, _ := cmd.Flags().GetString("role")
+		c := newDaemonClient(cmd)
+		result, err := c.PoolResume(role)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "error: %v\n", err)
 			os.Exit(1)
@@ -69,6 +81,261 @@ free slots and crashed agents will be respawned.`,
 	},
 }
 
+var poolCmd = &cobra.Command{
+	Use:   "pool",
+	Short: "Pool maintenance operations",
+}
+
+var poolRollingRestartCmd = &cobra.Command{
+	Use:   "rolling-restart",
+	Short: "Restart running agents one at a time with the current spawn command",
+	Long: `Restart every running pool agent, one at a time.
+
+Each agent is stopped and respawned on the same task using the daemon's
+current spawn command, resuming its existing opencode session. The next
+agent is only restarted once the replacement is running, so the pool never
+loses more than one agent at a time.
+
+Use this after upgrading the agent binary so running agents pick it up.
+The restart runs in the background; follow progress with 'af status -w'.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		c := newDaemonClient(cmd)
+		result, err := c.PoolRollingRestart()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		if len(result.Tasks) == 0 {
+			fmt.Println(term.Dim("no running agents to restart"))
+			return
+		}
+		fmt.Printf("rolling restart started %s\n", term.Dimf("(%d agents)", len(result.Tasks)))
+		for _, taskID := range result.Tasks {
+			fmt.Printf("  %s\n", term.Blue(taskID))
+		}
+	},
+}
+
+var agentCmd = &cobra.Command{
+	Use:   "agent",
+	Short: "Per-agent pool operations",
+}
+
+var agentRetireCmd = &cobra.Command{
+	Use:   "retire <task-id>",
+	Short: "Let a task's agent finish, then retire the task",
+	Long: `Retire a single task without touching the rest of the pool.
+
+The agent working the task keeps running until it exits, but it is not
+respawned if it crashes, and the daemon will not schedule the task again
+even if prog still reports it as ready. Retirement lasts until the daemon
+restarts.
+
+Use 'af drain' or 'af pause' to stop scheduling pool-wide instead.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := newDaemonClient(cmd)
+		result, err := c.PoolRetire(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		if result.AgentID == "" {
+			fmt.Printf("retired %s %s\n", term.Blue(result.TaskID), term.Dim("(no agent running)"))
+			return
+		}
+		fmt.Printf("retired %s %s\n", term.Blue(result.TaskID), term.Dimf("(%s will finish, no respawn)", result.AgentID))
+	},
+}
+
+var agentKillCmd = &cobra.Command{
+	Use:   "kill <agent-name>",
+	Short: "Kill a pool agent and release its task",
+	Long: `Kill a pool agent by name, along with every process it started.
+
+The agent's process group is sent SIGKILL and the agent is not respawned.
+Its task is blocked in prog so the claim is released without rescheduling
+it; reopen the task to hand it back to the pool.
+
+Use 'af session kill' to stop an agent gracefully (SIGTERM) by session ID,
+or 'af agent retire' to let it finish first.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := newDaemonClient(cmd)
+		result, err := c.AgentKill(args[0])
+		if err != nil {
+			Fatal("killing agent: %v", err)
+		}
+		fmt.Printf("killed %s on %s %s\n", term.Cyan(result.AgentID), term.Blue(result.TaskID), term.Dimf("(pid %d)", result.PID))
+		if result.ReleaseError != "" {
+			Fatal("task %s is still in_progress: %s", result.TaskID, result.ReleaseError)
+		}
+		fmt.Printf("task %s blocked %s\n", term.Blue(result.TaskID), term.Dim("(reopen it in prog to reschedule)"))
+	},
+}
+
+var poolSetCmd = &cobra.Command{
+	Use:   "set <param> <value>",
+	Short: "Change a pool parameter on the running daemon",
+	Long: `Change a pool parameter without restarting the daemon.
+
+Parameters:
+  max-retries N            crash respawns per task, or -1 to never respawn
+  max-startup-retries N    respawns allowed for startup failures, or -1
+                           to never respawn one
+  min-healthy-uptime D     how long an agent must run before a crash is
+                           not a startup failure, e.g. 30s
+  pool-size N              concurrent agent slots
+
+The new value applies from the next crash or scheduling pass. It lasts
+until the daemon restarts or reloads its config file (SIGHUP), which puts
+the file's value back.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		params, err := parsePoolSetting(args[0], args[1])
+		if err != nil {
+			Fatal("%v", err)
+		}
+		c := newDaemonClient(cmd)
+		result, err := c.PoolConfig(params)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("pool %s\n", term.Dimf("(pool size %d, max retries %d, max startup retries %d, min healthy uptime %v)",
+			result.PoolSize, result.MaxRetries, result.MaxStartupRetries, result.MinHealthyUptime))
+	},
+}
+
+// parsePoolSetting turns an af pool set parameter and value into a pool
+// config request.
+func parsePoolSetting(name, value string) (client.PoolConfigParams, error) {
+	var params client.PoolConfigParams
+	var target **int
+	switch name {
+	case "max-retries":
+		target = &params.MaxRetries
+	case "max-startup-retries":
+		target = &params.MaxStartupRetries
+	case "pool-size":
+		target = &params.PoolSize
+	case "min-healthy-uptime":
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return params, fmt.Errorf("invalid %s %q: %v", name, value, err)
+		}
+		params.MinHealthyUptime = &d
+		return params, nil
+	default:
+		return params, fmt.Errorf("unknown pool parameter %q (want max-retries, max-startup-retries, min-healthy-uptime, or pool-size)", name)
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return params, fmt.Errorf("invalid %s %q: want an integer", name, value)
+	}
+	*target = &n
+	return params, nil
+}
+
+var agentLogLevelCmd = &cobra.Command{
+	Use:   "loglevel <task-id> <level>",
+	Short: "Set the daemon log level for one task",
+	Long: `Log one task's spawn, reap, and respawn lines at a different level.
+
+Use this to trace a misbehaving task at debug without flooding the log
+for everything else. Level is one of debug, info, warn, or error. The
+override can be set before the task is scheduled and is cleared once its
+agent exits without being respawned.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		c := newDaemonClient(cmd)
+		result, err := c.AgentLogLevel(args[0], args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		if result.AgentID == "" {
+			fmt.Printf("log level %s for %s %s\n", result.Level, term.Blue(result.TaskID), term.Dim("(no agent running)"))
+			return
+		}
+		fmt.Printf("log level %s for %s %s\n", result.Level, term.Blue(result.TaskID), term.Dimf("(%s)", result.AgentID))
+	},
+}
+
+var agentPromptCmd = &cobra.Command{
+	Use:   "prompt <agent-name>",
+	Short: "Show the prompt a pool agent was launched with",
+	Long: `Print the rendered prompt a running pool agent was launched with,
+exactly as passed to the spawn command. Nothing is redacted.
+
+Long prompts are cut after 40 lines; use --full for the whole prompt.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		full, _ := cmd.Flags().GetBool("full")
+
+		c := newDaemonClient(cmd)
+		result, err := c.AgentPrompt(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		if full {
+			fmt.Println(strings.TrimRight(result.Prompt, "\n"))
+			return
+		}
+		fmt.Printf("%s %s %s\n\n", term.Cyan(result.AgentID), term.Blue(result.TaskID), term.Dim(result.Role))
+		preview, more := previewLines(result.Prompt, agentPromptPreviewLines)
+		fmt.Println(preview)
+		if more > 0 {
+			fmt.Println(term.Dimf("… %d more lines (use --full)", more))
+		}
+	},
+}
+
+// agentPromptPreviewLines is how many prompt lines af agent prompt shows
+// without --full.
+const agentPromptPreviewLines = 40
+
+// previewLines returns the first n lines of s and how many were cut.
+func previewLines(s string, n int) (string, int) {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	if len(lines) <= n {
+		return strings.Join(lines, "\n"), 0
+	}
+	return strings.Join(lines[:n], "\n"), len(lines) - n
+}
+
+var taskCmd = &cobra.Command{
+	Use:   "task",
+	Short: "Task scheduling operations",
+}
+
+var taskEnqueueCmd = &cobra.Command{
+	Use:   "enqueue <task-id>...",
+	Short: "Schedule tasks ahead of the queue (planner agents only)",
+	Long: `Push task IDs to the front of the pool's schedule.
+
+Meant for planner agents: after decomposing a task into subtasks, the
+planner enqueues them so they are scheduled right away instead of on the
+next prog poll. The daemon rejects callers that are not running planner
+agents. The caller is identified by --agent, which defaults to the
+AETHERFLOW_AGENT_ID environment variable set on every pool agent.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		agentID, _ := cmd.Flags().GetString("agent")
+		if agentID == "" {
+			Fatal("no agent ID: pass --agent or set AETHERFLOW_AGENT_ID")
+		}
+		c := newDaemonClient(cmd)
+		result, err := c.TaskEnqueue(agentID, args)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("enqueued %d %s\n", result.Enqueued, term.Dimf("(%d requested)", len(args)))
+	},
+}
+
 func printPoolModeResult(result *client.PoolModeResult) {
 	var modeStr string
 	switch result.Mode {
@@ -82,11 +349,27 @@ func printPoolModeResult(result *client.PoolModeResult) {
 		modeStr = result.Mode
 	}
 	fmt.Printf("pool %s %s\n", modeStr, term.Dimf("(%d agents running)", result.Running))
+	if len(result.PausedRoles) > 0 {
+		fmt.Printf("paused roles: %s\n", term.Red(strings.Join(result.PausedRoles, ", ")))
+	}
 }
 
 func init() {
 	rootCmd.AddCommand(drainCmd)
 	rootCmd.AddCommand(pauseCmd)
 	rootCmd.AddCommand(resumeCmd)
-
+	pauseCmd.Flags().String("role", "", "Pause only this role (planner or worker)")
+	resumeCmd.Flags().String("role", "", "Resume only this role (planner or worker)")
+	rootCmd.AddCommand(poolCmd)
+	poolCmd.AddCommand(poolRollingRestartCmd)
+	poolCmd.AddCommand(poolSetCmd)
+	rootCmd.AddCommand(agentCmd)
+	agentCmd.AddCommand(agentRetireCmd)
+	agentCmd.AddCommand(agentKillCmd)
+	agentCmd.AddCommand(agentLogLevelCmd)
+	agentCmd.AddCommand(agentPromptCmd)
+	agentPromptCmd.Flags().Bool("full", false, "Print the whole prompt without truncation")
+	rootCmd.AddCommand(taskCmd)
+	taskCmd.AddCommand(taskEnqueueCmd)
+	taskEnqueueCmd.Flags().String("agent", os.Getenv("AETHERFLOW_AGENT_ID"), "Calling agent's name")
 }
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/baiirun/aetherflow/internal/client"
+	"github.com/baiirun/aetherflow/internal/daemon"
+	"github.com/spf13/cobra"
+)
+
+var queueCmd = &cobra.Command{
+	Use:   "queue",
+	Short: "Inspect the daemon's ready queue",
+}
+
+var queueLatencyCmd = &cobra.Command{
+	Use:   "latency",
+	Short: "Show how long tasks wait before being picked up",
+	Long: `Print p50/p90/p99 queue wait times: how long each task sat in the
+ready queue, from the first poll that saw it to its first agent starting.
+
+Only tasks first started within --window count. Tasks no poll saw ready
+(reclaimed after a restart, or enqueued before prog listed them) are left
+out.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		window, _ := cmd.Flags().GetDuration("window")
+		asJSON, _ := cmd.Flags().GetBool("json")
+		if window <= 0 {
+			Fatal("--window must be positive")
+		}
+
+		c := newDaemonClient(cmd)
+		result, err := c.QueueLatency(window)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+
+		if asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			_ = enc.Encode(result)
+			return
+		}
+		printQueueLatency(result)
+	},
+}
+
+func printQueueLatency(r *client.QueueLatencyResult) {
+	window := secondsDuration(r.WindowSeconds)
+	if r.Samples == 0 {
+		fmt.Printf("no queued tasks started in the last %s\n", window)
+		return
+	}
+	fmt.Printf("queue wait over the last %s (%d tasks)\n", window, r.Samples)
+	fmt.Printf("  p50  %s\n", secondsDuration(r.P50Seconds).Round(time.Second))
+	fmt.Printf("  p90  %s\n", secondsDuration(r.P90Seconds).Round(time.Second))
+	fmt.Printf("  p99  %s\n", secondsDuration(r.P99Seconds).Round(time.Second))
+}
+
+func secondsDuration(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+func init() {
+	rootCmd.AddCommand(queueCmd)
+	queueCmd.AddCommand(queueLatencyCmd)
+	queueLatencyCmd.Flags().Duration("window", daemon.DefaultQueueLatencyWindow, "Only count tasks first started this recently")
+	queueLatencyCmd.Flags().Bool("json", false, "Output as JSON")
+}
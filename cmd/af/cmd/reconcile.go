@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/baiirun/aetherflow/internal/daemon"
+	"github.com/baiirun/aetherflow/internal/term"
+	"github.com/spf13/cobra"
+)
+
+var reconcileCmd = &cobra.Command{
+	Use:   "reconcile",
+	Short: "Preview the reviewing-task reconciler without changing tasks",
+	Long: `Run the daemon's merge check on every reviewing task and report the
+result, without marking anything done.
+
+By default, lists the tasks whose branch (af/<task-id>) is merged into main
+or already deleted — the ones the daemon would mark done on its next pass.
+With --open, lists the tasks whose branch exists but is not merged yet
+(still in review), so you can nudge them.
+
+Runs in the current repository against prog directly; the daemon does not
+need to be running. The project comes from --project or the config file.`,
+	Run: runReconcile,
+}
+
+func runReconcile(cmd *cobra.Command, _ []string) {
+	open, _ := cmd.Flags().GetBool("open")
+	asJSON, _ := cmd.Flags().GetBool("json")
+
+	configPath, _ := cmd.Flags().GetString("config")
+	if configPath == "" {
+		configPath = ".aetherflow.yaml"
+	}
+	var cfg daemon.Config
+	_ = daemon.LoadConfigFile(configPath, &cfg) // ignore missing file
+	if cmd.Flags().Changed("project") {
+		cfg.Project, _ = cmd.Flags().GetString("project")
+	}
+	if cfg.Project == "" {
+		Fatal("no project: pass --project or set project in %s", configPath)
+	}
+
+	log := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	tasks, err := daemon.CheckReviewingTasks(context.Background(), cfg.Project, daemon.ExecCommandRunner, log)
+	if err != nil {
+		Fatal("checking reviewing tasks: %v", err)
+	}
+	tasks = daemon.FilterReviewing(tasks, !open)
+
+	if asJSON {
+		if tasks == nil {
+			tasks = []daemon.ReviewingTask{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(tasks)
+		return
+	}
+	printReconcile(tasks, open)
+}
+
+func printReconcile(tasks []daemon.ReviewingTask, open bool) {
+	if len(tasks) == 0 {
+		if open {
+			fmt.Println("no reviewing tasks with unmerged branches")
+		} else {
+			fmt.Println("no reviewing tasks ready to mark done")
+		}
+		return
+	}
+
+	if open {
+		fmt.Printf("%s %d\n", term.Bold("Open reviews:"), len(tasks))
+	} else {
+		fmt.Printf("%s %d\n", term.Bold("Would mark done:"), len(tasks))
+	}
+	for _, t := range tasks {
+		note := ""
+		if t.BranchMissing {
+			note = " " + term.Dim("(branch deleted)")
+		}
+		fmt.Printf("  %s  %s  %s%s\n",
+			term.PadRight(t.ID, 14, term.Blue),
+			term.PadRight(t.Branch, 17, term.Cyan),
+			term.Dim(quote(truncate(stripANSI(t.Title), 60))),
+			note,
+		)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(reconcileCmd)
+	reconcileCmd.Flags().Bool("open", false, "List reviewing tasks whose branches are not merged yet")
+	reconcileCmd.Flags().Bool("json", false, "Output as JSON")
+}
@@ -3,7 +3,9 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"slices"
 
+	"github.com/baiirun/aetherflow/internal/client"
 	"github.com/baiirun/aetherflow/internal/daemon"
 	"github.com/baiirun/aetherflow/internal/protocol"
 	"github.com/baiirun/aetherflow/internal/term"
@@ -35,7 +37,7 @@ func Execute() error {
 
 func init() {
 	rootCmd.PersistentFlags().StringP("config", "c", "", "config file (default is $HOME/.aetherflow.yaml)")
-	rootCmd.PersistentFlags().StringP("project", "p", "", "Project name (targets a project-scoped daemon URL when set, overrides config file)")
+	rootCmd.PersistentFlags().StringP("project", "p", "", "Project name (targets a project-scoped daemon URL when set, overrides config file; a project listed under projects selects it on the primary project's daemon)")
 	rootCmd.PersistentFlags().Bool("no-color", false, "Disable colored output")
 
 	// Wire --no-color to the term package. OnInitialize runs before any
@@ -50,11 +52,20 @@ func init() {
 
 // resolveDaemonURL determines the daemon URL from the CLI flags,
 // config file, and daemon mode. Priority:
-//  1. Explicit --project -> project-scoped daemon URL
+//  1. Explicit --project -> project-scoped daemon URL, unless the config
+//     file lists it under projects: then it is served by the primary
+//     project's daemon, resolved as below (see daemonProject)
 //  2. Explicit/configured listen_addr -> canonical daemon URL
 //  3. Auto mode + configured project -> project-scoped daemon URL
 //  4. Manual mode default -> DefaultDaemonURL
 func resolveDaemonURL(cmd *cobra.Command) string {
+	url, _ := resolveDaemonURLSource(cmd)
+	return url
+}
+
+// resolveDaemonURLSource is resolveDaemonURL, also reporting where the URL
+// came from: sourceFlag, sourceFile, or sourceDefault.
+func resolveDaemonURLSource(cmd *cobra.Command) (string, string) {
 	configPath, _ := cmd.Flags().GetString("config")
 	if configPath == "" {
 		configPath = ".aetherflow.yaml"
@@ -67,7 +78,11 @@ func resolveDaemonURL(cmd *cobra.Command) string {
 	explicitProject := ""
 	if cmd.Flags().Changed("project") {
 		explicitProject, _ = cmd.Flags().GetString("project")
-		cfg.Project = explicitProject
+		if isSecondaryProject(cfg, explicitProject) {
+			explicitProject = ""
+		} else {
+			cfg.Project = explicitProject
+		}
 	}
 	if cmd.Flags().Lookup("spawn-policy") != nil && cmd.Flags().Changed("spawn-policy") {
 		policy, _ := cmd.Flags().GetString("spawn-policy")
@@ -76,21 +91,60 @@ func resolveDaemonURL(cmd *cobra.Command) string {
 
 	normalizedPolicy := cfg.SpawnPolicy.Normalized()
 	if explicitProject != "" {
-		return protocol.DaemonURLFor(explicitProject)
+		return protocol.DaemonURLFor(explicitProject), sourceFlag
 	}
 
 	if listenAddr := cfg.ListenAddr; listenAddr != "" {
 		daemonURL, err := protocol.DaemonURLFromListenAddr(cfg.ListenAddr)
 		if err == nil {
-			return daemonURL
+			return daemonURL, sourceFile
 		}
 		fmt.Fprintf(os.Stderr, "warning: invalid listen_addr %q in %s: %v (using default daemon URL)\n", listenAddr, configPath, err)
 	}
 	if normalizedPolicy == daemon.SpawnPolicyAuto && cfg.Project != "" {
-		return protocol.DaemonURLFor(cfg.Project)
+		source := sourceFile
+		if cmd.Flags().Lookup("spawn-policy") != nil && cmd.Flags().Changed("spawn-policy") {
+			source = sourceFlag
+		}
+		return protocol.DaemonURLFor(cfg.Project), source
 	}
 
-	return protocol.DefaultDaemonURL
+	return protocol.DefaultDaemonURL, sourceDefault
+}
+
+// daemonProject returns the project a command selects on a multi-project
+// daemon: the --project value when the config file lists it under
+// projects, or "" for the daemon's primary project. Pass it to
+// client.SetProject.
+func daemonProject(cmd *cobra.Command) string {
+	if !cmd.Flags().Changed("project") {
+		return ""
+	}
+	project, _ := cmd.Flags().GetString("project")
+	configPath, _ := cmd.Flags().GetString("config")
+	if configPath == "" {
+		configPath = ".aetherflow.yaml"
+	}
+	var cfg daemon.Config
+	_ = daemon.LoadConfigFile(configPath, &cfg) // resolveDaemonURL warns about a bad file
+	if !isSecondaryProject(cfg, project) {
+		return ""
+	}
+	return project
+}
+
+// newDaemonClient returns a client for the daemon resolveDaemonURL picks,
+// scoped to the project daemonProject selects.
+func newDaemonClient(cmd *cobra.Command) *client.Client {
+	c := client.New(resolveDaemonURL(cmd))
+	c.SetProject(daemonProject(cmd))
+	return c
+}
+
+// isSecondaryProject reports whether project is one of the extra projects
+// cfg's daemon schedules alongside its primary one.
+func isSecondaryProject(cfg daemon.Config, project string) bool {
+	return cfg.Project != "" && project != cfg.Project && slices.Contains(cfg.Projects, project)
 }
 
 // Fatal prints an error and exits.
@@ -97,3 +97,29 @@ func writeResolveConfig(t *testing.T, contents string) string {
 	}
 	return configPath
 }
+
+func TestResolveDaemonURLSecondaryProjectUsesPrimaryDaemon(t *testing.T) {
+	configPath := writeResolveConfig(t, "project: alpha\nprojects: [beta]\nspawn_policy: auto\n")
+	cmd := newResolveTestCommand(t, configPath)
+	if err := cmd.Flags().Set("project", "beta"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := resolveDaemonURL(cmd), protocol.DaemonURLFor("alpha"); got != want {
+		t.Errorf("resolveDaemonURL = %q, want the primary project's daemon %q", got, want)
+	}
+	if got := daemonProject(cmd); got != "beta" {
+		t.Errorf("daemonProject = %q, want beta", got)
+	}
+
+	// A project the config doesn't list still names its own daemon.
+	if err := cmd.Flags().Set("project", "gamma"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := resolveDaemonURL(cmd), protocol.DaemonURLFor("gamma"); got != want {
+		t.Errorf("resolveDaemonURL = %q, want %q", got, want)
+	}
+	if got := daemonProject(cmd); got != "" {
+		t.Errorf("daemonProject = %q, want none", got)
+	}
+}
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,12 +11,15 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"time"
 
+	"github.com/baiirun/aetherflow/internal/client"
 	"github.com/baiirun/aetherflow/internal/daemon"
 	"github.com/baiirun/aetherflow/internal/sessions"
+	"github.com/baiirun/aetherflow/internal/term"
 	"github.com/spf13/cobra"
 )
 
@@ -25,7 +29,12 @@ var sessionsCmd = &cobra.Command{
 	Long: `List session records from aetherflow's global session registry.
 
 The registry tracks routing metadata ({server_ref, session_id}) and origin
-context so sessions can be resumed independently of task backends.`,
+context so sessions can be resumed independently of task backends.
+
+With --include-unmanaged, sessions opencode knows about but the registry
+does not are listed too, with origin "external" and the configured
+server_url as their server. Import one with 'af session adopt' to attach
+to it.`,
 	Run: runSessions,
 }
 
@@ -37,8 +46,57 @@ var sessionCmd = &cobra.Command{
 var sessionAttachCmd = &cobra.Command{
 	Use:   "attach <session-id>",
 	Short: "Attach interactively to a known session",
-	Args:  cobra.ExactArgs(1),
-	Run:   runSessionAttach,
+	Long: `Attach interactively to a session from the session registry with
+'opencode attach'.
+
+The session's server is probed first; if it doesn't respond within
+--timeout, the command fails with a "server unreachable" error instead of
+leaving the terminal hanging.`,
+	Args: cobra.ExactArgs(1),
+	Run:  runSessionAttach,
+}
+
+var sessionBackfillCmd = &cobra.Command{
+	Use:   "backfill <session-id>",
+	Short: "Backfill a session's events from the opencode server",
+	Long: `Fetch a session's message history from the opencode server and load it
+into the daemon's event buffer.
+
+Use this when the plugin missed a session's events (e.g. the daemon was
+not running when the session started). Sessions that already have events
+in the buffer are left untouched, so running this twice is harmless.`,
+	Args: cobra.ExactArgs(1),
+	Run:  runSessionBackfill,
+}
+
+var sessionKillCmd = &cobra.Command{
+	Use:   "kill <session-id>",
+	Short: "Stop the agent that owns a session",
+	Long: `Stop the running agent bound to an opencode session, when you know the
+session ID but not the agent name.
+
+For a pool agent, the process is sent SIGTERM and is not respawned, and its
+task is blocked in prog so the claim is released without rescheduling it;
+reopen the task to hand it back to the pool. For an af spawn agent, the
+process is sent SIGTERM and the spawn is marked exited.
+
+Fails if no running agent owns the session. Requires a running daemon.`,
+	Args: cobra.ExactArgs(1),
+	Run:  runSessionKill,
+}
+
+var sessionAdoptCmd = &cobra.Command{
+	Use:   "adopt <session-id>",
+	Short: "Import an unmanaged opencode session into the registry",
+	Long: `Add a session that opencode knows about but aetherflow's registry does
+not (origin "external" in 'af sessions --include-unmanaged') to the
+registry, so it can be listed and attached to like any other.
+
+The session is recorded with origin "manual", status "idle", and the
+server from --server, or the configured server_url by default. Fails if
+opencode does not list the session or the registry already has it.`,
+	Args: cobra.ExactArgs(1),
+	Run:  runSessionAdopt,
 }
 
 var runCommandOutput = func(name string, args ...string) ([]byte, error) {
@@ -49,6 +107,8 @@ type opencodeSessionSummary struct {
 	ID        string `json:"id"`
 	Title     string `json:"title"`
 	Directory string `json:"directory"`
+	Created   int64  `json:"created"` // Unix milliseconds
+	Updated   int64  `json:"updated"` // Unix milliseconds
 }
 
 type sessionMessage struct {
@@ -65,17 +125,27 @@ func init() {
 	rootCmd.AddCommand(sessionsCmd)
 	rootCmd.AddCommand(sessionCmd)
 	sessionCmd.AddCommand(sessionAttachCmd)
+	sessionCmd.AddCommand(sessionBackfillCmd)
+	sessionCmd.AddCommand(sessionKillCmd)
+	sessionCmd.AddCommand(sessionAdoptCmd)
 
 	sessionsCmd.Flags().Bool("json", false, "Output JSON")
+	sessionsCmd.Flags().Bool("compact", false, "Show only session ID, status, and what the session is about")
 	sessionsCmd.Flags().String("server", "", "Filter by server_ref")
 	sessionsCmd.Flags().String("session-dir", "", "Session registry directory (overrides config/default)")
+	sessionsCmd.Flags().Bool("include-unmanaged", false, "Also list opencode sessions missing from the registry (origin external)")
 	sessionAttachCmd.Flags().String("server", "", "Disambiguate by server_ref when session_id exists on multiple servers")
 	sessionAttachCmd.Flags().String("session-dir", "", "Session registry directory (overrides config/default)")
+	sessionAttachCmd.Flags().Duration("timeout", 5*time.Second, "How long to wait for the opencode server to respond before attaching")
+	sessionAdoptCmd.Flags().String("server", "", "Server the session lives on (default: configured server_url)")
+	sessionAdoptCmd.Flags().String("session-dir", "", "Session registry directory (overrides config/default)")
 }
 
 func runSessions(cmd *cobra.Command, _ []string) {
 	jsonOut, _ := cmd.Flags().GetBool("json")
+	compact, _ := cmd.Flags().GetBool("compact")
 	serverFilter, _ := cmd.Flags().GetString("server")
+	includeUnmanaged, _ := cmd.Flags().GetBool("include-unmanaged")
 
 	store, err := openSessionStore(cmd)
 	if err != nil {
@@ -86,6 +156,12 @@ func runSessions(cmd *cobra.Command, _ []string) {
 		Fatal("reading session registry: %v", err)
 	}
 
+	var sessionIndex map[string]opencodeSessionSummary
+	if includeUnmanaged {
+		sessionIndex = loadOpencodeSessionIndex()
+		recs = append(recs, unmanagedSessionRecords(recs, sessionIndex, sessionsServerURL(cmd))...)
+	}
+
 	if serverFilter != "" {
 		filtered := recs[:0]
 		for _, r := range recs {
@@ -108,10 +184,58 @@ func runSessions(cmd *cobra.Command, _ []string) {
 		return
 	}
 
-	sessionIndex := loadOpencodeSessionIndex()
-	semanticIndex := loadSessionSemanticIndex(recs, sessionIndex)
+	cfg := loadSessionsConfig(cmd)
+	order, err := sessionWhatOrder(cfg.SessionWhat)
+	if err != nil {
+		Fatal("%v", err)
+	}
+	if sessionIndex == nil {
+		sessionIndex = loadOpencodeSessionIndex()
+	}
+	cachePath := filepath.Join(filepath.Dir(store.Path()), objectiveCacheFile)
+	semanticIndex := loadSessionSemanticIndex(recs, sessionIndex, cachePath, order, spawnIDPrefix(cfg))
 
-	fmt.Printf("%-34s  %-24s  %-10s  %-8s  %-14s  %-14s  %s\n", "SESSION", "SERVER", "STATUS", "ORIGIN", "UPDATED", "WORK", "WHAT")
+	what := func(r sessions.Record) string {
+		return sessionWhatForRecord(r, sessionIndex, semanticIndex, order)
+	}
+	width := term.Width(sessionsDefaultWidth)
+	if compact {
+		writeSessionsCompact(os.Stdout, recs, what, width)
+		return
+	}
+	writeSessionsTable(os.Stdout, recs, what, width)
+}
+
+const (
+	// sessionsDefaultWidth is the table width when stdout isn't a terminal:
+	// wide enough for every column at full width.
+	sessionsDefaultWidth = 206
+	// Minimum and maximum widths of the WHAT column.
+	sessionsMinWhat = 16
+	sessionsMaxWhat = 96
+)
+
+// writeSessionsTable prints the full sessions table, fitted to width by
+// shrinking SERVER and WORK, then WHAT, down to their minimums.
+func writeSessionsTable(w io.Writer, recs []sessions.Record, what func(sessions.Record) string, width int) {
+	serverW, workW := 24, 14
+	// SESSION, STATUS, ORIGIN, UPDATED, and six two-space gaps.
+	fixed := 34 + 10 + 8 + 14 + 6*2
+	whatW := width - fixed - serverW - workW
+	for whatW < sessionsMinWhat && (serverW > 12 || workW > 8) {
+		if serverW > 12 {
+			serverW--
+			whatW++
+		}
+		if workW > 8 && whatW < sessionsMinWhat {
+			workW--
+			whatW++
+		}
+	}
+	whatW = min(max(whatW, sessionsMinWhat), sessionsMaxWhat)
+
+	rowFmt := fmt.Sprintf("%%-34s  %%-%ds  %%-10s  %%-8s  %%-14s  %%-%ds  %%s\n", serverW, workW)
+	fmt.Fprintf(w, rowFmt, "SESSION", "SERVER", "STATUS", "ORIGIN", "UPDATED", "WORK", "WHAT")
 	for _, r := range recs {
 		updated := r.UpdatedAt
 		if updated.IsZero() {
@@ -121,18 +245,79 @@ func runSessions(cmd *cobra.Command, _ []string) {
 		if work == "" {
 			work = "-"
 		}
-		fmt.Printf("%-34s  %-24s  %-10s  %-8s  %-14s  %-14s  %s\n",
+		fmt.Fprintf(w, rowFmt,
 			r.SessionID,
-			truncateString(r.ServerRef, 24),
-			r.Status,
+			truncateString(r.ServerRef, serverW),
+			sessionStatus(r),
 			r.Origin,
 			humanSince(updated),
-			truncateString(work, 14),
-			truncateString(sessionWhatForRecord(r, sessionIndex, semanticIndex), 96),
+			truncateString(work, workW),
+			truncateString(what(r), whatW),
 		)
 	}
 }
 
+// writeSessionsCompact prints only session ID, status, and what the
+// session is about, with WHAT fitted to width.
+func writeSessionsCompact(w io.Writer, recs []sessions.Record, what func(sessions.Record) string, width int) {
+	// SESSION, STATUS, and two two-space gaps.
+	whatW := min(max(width-(34+10+2*2), sessionsMinWhat), sessionsMaxWhat)
+	fmt.Fprintf(w, "%-34s  %-10s  %s\n", "SESSION", "STATUS", "WHAT")
+	for _, r := range recs {
+		fmt.Fprintf(w, "%-34s  %-10s  %s\n", r.SessionID, sessionStatus(r), truncateString(what(r), whatW))
+	}
+}
+
+// sessionStatus is r's status for display: "-" for unmanaged sessions,
+// which the registry has never tracked.
+func sessionStatus(r sessions.Record) string {
+	if r.Status == "" {
+		return "-"
+	}
+	return string(r.Status)
+}
+
+// unmanagedSessionRecords returns a record, with origin external, for each
+// session in index that no registry record in recs has, on any server.
+// Opencode's session list doesn't say which server a session lives on, so
+// they are given serverRef. The records are sorted newest first.
+func unmanagedSessionRecords(recs []sessions.Record, index map[string]opencodeSessionSummary, serverRef string) []sessions.Record {
+	known := make(map[string]bool, len(recs))
+	for _, r := range recs {
+		known[r.SessionID] = true
+	}
+	var out []sessions.Record
+	for id, summary := range index {
+		if known[id] {
+			continue
+		}
+		out = append(out, sessions.Record{
+			ServerRef: serverRef,
+			SessionID: id,
+			Directory: summary.Directory,
+			Origin:    sessions.OriginExternal,
+			CreatedAt: unixMilli(summary.Created),
+			UpdatedAt: unixMilli(summary.Updated),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].UpdatedAt.Equal(out[j].UpdatedAt) {
+			return out[i].UpdatedAt.After(out[j].UpdatedAt)
+		}
+		return out[i].SessionID < out[j].SessionID
+	})
+	return out
+}
+
+// unixMilli converts opencode's millisecond timestamps, leaving 0 as the
+// zero time.
+func unixMilli(ms int64) time.Time {
+	if ms == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
+
 func recordKey(serverRef, sessionID string) string {
 	return serverRef + "\x00" + sessionID
 }
@@ -156,25 +341,65 @@ func loadOpencodeSessionIndex() map[string]opencodeSessionSummary {
 	return index
 }
 
-func loadSessionSemanticIndex(recs []sessions.Record, index map[string]opencodeSessionSummary) map[string]string {
+// loadSessionSemanticIndex returns the objective of each session whose
+// opencode title says nothing useful, keyed by recordKey. Objectives come
+// from the cache at cachePath when fresh, and from the opencode REST API
+// otherwise; newly fetched ones are written back to the cache. Sessions
+// that a source ahead of the objective in order already describes are
+// skipped, as are all sessions when order leaves the objective out.
+// spawnPrefix is the configured spawn ID prefix, since spawned sessions are
+// titled after their spawn ID.
+func loadSessionSemanticIndex(recs []sessions.Record, index map[string]opencodeSessionSummary, cachePath string, order []string, spawnPrefix string) map[string]string {
 	result := make(map[string]string)
+	// Sources ahead of the objective in order win whenever they have a
+	// value, so don't fetch objectives the listing would never show.
+	i := slices.Index(order, whatObjective)
+	if i < 0 {
+		return result
+	}
+	ahead := order[:i]
+	cache := loadObjectiveCache(cachePath)
+	now := time.Now()
+	fetched := false
 	client := &http.Client{Timeout: 2 * time.Second}
 	for _, r := range recs {
 		if r.ServerRef == "" || r.SessionID == "" {
 			continue
 		}
 		title := strings.TrimSpace(index[r.SessionID].Title)
-		if !shouldEnrichSessionTitle(title) {
+		if !shouldEnrichSessionTitle(title, spawnPrefix) {
+			continue
+		}
+		if slices.ContainsFunc(ahead, func(source string) bool { return sessionWhatFrom(source, r, index, nil) != "" }) {
+			continue
+		}
+		key := recordKey(r.ServerRef, r.SessionID)
+		if what, ok := cache.lookup(r, now); ok {
+			result[key] = what
 			continue
 		}
 		if what := fetchSessionObjective(client, r.ServerRef, r.SessionID); what != "" {
-			result[recordKey(r.ServerRef, r.SessionID)] = what
+			result[key] = what
+			cache[key] = cachedObjective{
+				ServerRef:       r.ServerRef,
+				SessionID:       r.SessionID,
+				Objective:       what,
+				RecordUpdatedAt: r.UpdatedAt,
+				FetchedAt:       now,
+			}
+			fetched = true
 		}
 	}
+	if fetched && cachePath != "" {
+		_ = cache.save(cachePath, recs) // best-effort; the next listing refetches
+	}
 	return result
 }
 
-func shouldEnrichSessionTitle(title string) bool {
+// shouldEnrichSessionTitle reports whether an opencode title is a
+// placeholder (empty, generic, or just the spawn ID) that the session's
+// objective describes better.
+func shouldEnrichSessionTitle(title, spawnPrefix string) bool {
 	t := strings.ToLower(strings.TrimSpace(title))
 	if t == "" {
 		return true
@@ -182,7 +407,7 @@ func shouldEnrichSessionTitle(title string) bool {
 	for _, prefix := range []string{
 		"autonomous spawn",
 		"autonomous agent spawn",
-		"spawn-",
+		strings.ToLower(spawnPrefix),
 		"new session -",
 	} {
 		if strings.HasPrefix(t, prefix) {
@@ -192,7 +417,9 @@ func shouldEnrichSessionTitle(title string) bool {
 	return false
 }
 
-func fetchSessionObjective(client *http.Client, serverRef, sessionID string) string {
+// fetchSessionObjective fetches a session's first user prompt from the
+// opencode REST API and condenses it. Swappable for tests.
+var fetchSessionObjective = func(client *http.Client, serverRef, sessionID string) string {
 	u := strings.TrimRight(serverRef, "/") + "/session/" + url.PathEscape(sessionID) + "/message"
 	req, err := http.NewRequest(http.MethodGet, u, nil)
 	if err != nil {
@@ -261,32 +488,75 @@ func squashWhitespace(s string) string {
 	return strings.TrimSpace(string(bytes.Join(bytes.Fields([]byte(s)), []byte(" "))))
 }
 
-func sessionWhatForRecord(r sessions.Record, index map[string]opencodeSessionSummary, semanticIndex map[string]string) string {
-	if what := semanticIndex[recordKey(r.ServerRef, r.SessionID)]; what != "" {
-		return what
+// Sources for the WHAT column of af sessions (see Config.SessionWhat).
+const (
+	whatObjective = "objective" // first prompt's objective, fetched from the opencode server
+	whatTitle     = "title"     // opencode session title, else its directory
+	whatWorkRef   = "work_ref"
+	whatAgent     = "agent"
+	whatProject   = "project"
+	whatDir       = "dir" // the registry record's directory
+)
+
+// defaultSessionWhat is the WHAT source order when none is configured.
+var defaultSessionWhat = []string{whatObjective, whatTitle, whatWorkRef, whatAgent, whatProject, whatDir}
+
+// sessionWhatOrder validates a configured session_what list, returning
+// the default order when it is empty.
+func sessionWhatOrder(configured []string) ([]string, error) {
+	if len(configured) == 0 {
+		return defaultSessionWhat, nil
 	}
-	if summary, ok := index[r.SessionID]; ok {
-		title := strings.TrimSpace(summary.Title)
-		if title != "" {
+	for i, source := range configured {
+		if !slices.Contains(defaultSessionWhat, source) {
+			return nil, fmt.Errorf("session_what: unknown source %q (want one of %s)", source, strings.Join(defaultSessionWhat, ", "))
+		}
+		if slices.Contains(configured[:i], source) {
+			return nil, fmt.Errorf("session_what: %q is listed more than once", source)
+		}
+	}
+	return configured, nil
+}
+
+// sessionWhatForRecord describes what a session is about from the first
+// source in order that has a value, or "-" when none does.
+func sessionWhatForRecord(r sessions.Record, index map[string]opencodeSessionSummary, semanticIndex map[string]string, order []string) string {
+	for _, source := range order {
+		if what := sessionWhatFrom(source, r, index, semanticIndex); what != "" {
+			return what
+		}
+	}
+	return "-"
+}
+
+// sessionWhatFrom returns what one source says about a session, or "".
+func sessionWhatFrom(source string, r sessions.Record, index map[string]opencodeSessionSummary, semanticIndex map[string]string) string {
+	switch source {
+	case whatObjective:
+		return semanticIndex[recordKey(r.ServerRef, r.SessionID)]
+	case whatTitle:
+		summary, ok := index[r.SessionID]
+		if !ok {
+			return ""
+		}
+		if title := strings.TrimSpace(summary.Title); title != "" {
 			return title
 		}
 		if summary.Directory != "" {
 			return "dir: " + filepath.Base(summary.Directory)
 		}
-	}
-	if r.WorkRef != "" {
+	case whatWorkRef:
 		return r.WorkRef
-	}
-	if r.AgentID != "" {
+	case whatAgent:
 		return r.AgentID
-	}
-	if r.Project != "" {
+	case whatProject:
 		return r.Project
+	case whatDir:
+		if r.Directory != "" {
+			return filepath.Base(r.Directory)
+		}
 	}
-	if r.Directory != "" {
-		return filepath.Base(r.Directory)
-	}
-	return "-"
+	return ""
 }
 
 func runSessionAttach(cmd *cobra.Command, args []string) {
@@ -336,7 +606,19 @@ func runSessionAttach(cmd *cobra.Command, args []string) {
 	if strings.HasPrefix(target.ServerRef, "-") {
 		Fatal("invalid server_ref %q in session registry", target.ServerRef)
 	}
-	attach := exec.Command("opencode", "attach", target.ServerRef, "--session", target.SessionID)
+	timeout, _ := cmd.Flags().GetDuration("timeout")
+	execOpencodeAttach(target.ServerRef, target.SessionID, timeout)
+}
+
+// execOpencodeAttach probes serverRef and runs 'opencode attach' on the
+// session in the foreground, exiting with its exit code on failure.
+// Callers validate serverRef first.
+func execOpencodeAttach(serverRef, sessionID string, timeout time.Duration) {
+	if err := daemon.ProbeServer(context.Background(), serverRef, timeout); err != nil {
+		Fatal("%v\n\nIs the opencode server running? The daemon starts it with: af daemon start", err)
+	}
+
+	attach := exec.Command("opencode", "attach", serverRef, "--session", sessionID)
 	attach.Stdin = os.Stdin
 	attach.Stdout = os.Stdout
 	attach.Stderr = os.Stderr
@@ -349,26 +631,114 @@ func runSessionAttach(cmd *cobra.Command, args []string) {
 	}
 }
 
+func runSessionBackfill(cmd *cobra.Command, args []string) {
+	c := client.New(resolveDaemonURL(cmd))
+	result, err := c.SessionBackfill(args[0])
+	if err != nil {
+		Fatal("backfilling session: %v", err)
+	}
+	if result.Skipped {
+		fmt.Printf("session %s already has events %s\n", result.SessionID, term.Dim("(nothing to backfill)"))
+		return
+	}
+	fmt.Printf("backfilled %s %s\n", result.SessionID, term.Dimf("(%d events added)", result.Added))
+}
+
+func runSessionKill(cmd *cobra.Command, args []string) {
+	c := client.New(resolveDaemonURL(cmd))
+	result, err := c.SessionKill(args[0])
+	if err != nil {
+		Fatal("killing session: %v", err)
+	}
+	if result.Origin == "spawn" {
+		fmt.Printf("killed spawn %s %s\n", term.Cyan(result.AgentID), term.Dimf("(pid %d)", result.PID))
+		return
+	}
+	fmt.Printf("killed %s on %s %s\n", term.Cyan(result.AgentID), term.Blue(result.TaskID), term.Dimf("(pid %d)", result.PID))
+	if result.ReleaseError != "" {
+		Fatal("task %s is still in_progress: %s", result.TaskID, result.ReleaseError)
+	}
+	fmt.Printf("task %s blocked %s\n", term.Blue(result.TaskID), term.Dim("(reopen it in prog to reschedule)"))
+}
+
+func runSessionAdopt(cmd *cobra.Command, args []string) {
+	sessionID := args[0]
+	serverRef, _ := cmd.Flags().GetString("server")
+	if serverRef == "" {
+		serverRef = sessionsServerURL(cmd)
+	}
+	if _, err := daemon.ValidateServerURLLocal(serverRef); err != nil {
+		Fatal("invalid --server %q: %v", serverRef, err)
+	}
+
+	summary, ok := loadOpencodeSessionIndex()[sessionID]
+	if !ok {
+		Fatal("session %q not found in opencode's session list", sessionID)
+	}
+
+	store, err := openSessionStore(cmd)
+	if err != nil {
+		Fatal("opening session registry: %v", err)
+	}
+	recs, err := store.List()
+	if err != nil {
+		Fatal("reading session registry: %v", err)
+	}
+	for _, r := range recs {
+		if r.SessionID == sessionID && r.ServerRef == serverRef {
+			Fatal("session %q is already in the registry (origin %s)", sessionID, r.Origin)
+		}
+	}
+
+	rec := sessions.Record{
+		ServerRef: serverRef,
+		SessionID: sessionID,
+		Directory: summary.Directory,
+		Origin:    sessions.OriginManual,
+		Status:    sessions.StatusIdle,
+		CreatedAt: unixMilli(summary.Created),
+	}
+	if err := store.Upsert(rec); err != nil {
+		Fatal("adding session to registry: %v", err)
+	}
+	fmt.Printf("adopted %s %s\n", sessionID, term.Dimf("(server %s)", serverRef))
+}
+
 func openSessionStore(cmd *cobra.Command) (*sessions.Store, error) {
 	sessionDir, _ := cmd.Flags().GetString("session-dir")
 	if sessionDir != "" {
 		return sessions.Open(sessionDir)
 	}
+	return sessions.Open(loadSessionsConfig(cmd).SessionDir)
+}
+
+// sessionsServerURL returns the configured opencode server_url, or the
+// default server when none is set.
+func sessionsServerURL(cmd *cobra.Command) string {
+	if serverURL := loadSessionsConfig(cmd).ServerURL; serverURL != "" {
+		return serverURL
+	}
+	return daemon.DefaultServerURL
+}
 
+// loadSessionsConfig reads the config file named by --config, ignoring a
+// missing or unreadable file.
+func loadSessionsConfig(cmd *cobra.Command) daemon.Config {
 	configPath, _ := cmd.Flags().GetString("config")
 	if configPath == "" {
 		configPath = ".aetherflow.yaml"
 	}
 	var cfg daemon.Config
 	_ = daemon.LoadConfigFile(configPath, &cfg)
-	return sessions.Open(cfg.SessionDir)
+	return cfg
 }
 
 func humanSince(t time.Time) string {
 	if t.IsZero() {
 		return "-"
 	}
-	d := time.Since(t).Round(time.Second)
+	// Clock skew can put t in the future; don't print a negative age.
+	d := max(time.Since(t), 0).Round(time.Second)
 	if d < time.Minute {
 		return d.String()
 	}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/baiirun/aetherflow/internal/sessions"
+	"github.com/baiirun/aetherflow/internal/term"
+	"github.com/spf13/cobra"
+)
+
+var sessionsDoctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Validate and repair the session registry",
+	Long: `Check every record in the session registry for missing required fields
+(server_ref, session_id), unknown status or origin values, duplicate keys,
+and records that no longer parse.
+
+With --repair, records that cannot be salvaged are dropped and unknown
+status or origin values are reset, then the registry is rewritten
+atomically. Without it, nothing is changed. Exits non-zero when invalid
+records remain.`,
+	Args: cobra.NoArgs,
+	Run:  runSessionsDoctor,
+}
+
+func init() {
+	sessionsCmd.AddCommand(sessionsDoctorCmd)
+	sessionsDoctorCmd.Flags().Bool("repair", false, "Drop or fix invalid records")
+	sessionsDoctorCmd.Flags().Bool("json", false, "Output JSON")
+	sessionsDoctorCmd.Flags().String("session-dir", "", "Session registry directory (overrides config/default)")
+}
+
+func runSessionsDoctor(cmd *cobra.Command, _ []string) {
+	repair, _ := cmd.Flags().GetBool("repair")
+	jsonOut, _ := cmd.Flags().GetBool("json")
+
+	store, err := openSessionStore(cmd)
+	if err != nil {
+		Fatal("opening session registry: %v", err)
+	}
+	report, err := store.Doctor(repair)
+	if err != nil {
+		Fatal("checking session registry: %v\nIf the file is beyond repair, delete %s; the daemon rebuilds it.", err, store.Path())
+	}
+
+	if jsonOut {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(report)
+	} else {
+		printDoctorReport(report)
+	}
+	if len(report.Problems) > 0 && !report.Repaired {
+		os.Exit(1)
+	}
+}
+
+// printDoctorReport lists invalid records and what was (or would be) done.
+func printDoctorReport(report sessions.DoctorReport) {
+	fmt.Printf("%s %s %s\n", term.Bold("Registry:"), report.Path, term.Dim(fmt.Sprintf("(%d records)", report.Records)))
+	if len(report.Problems) == 0 {
+		fmt.Println(term.Green("No problems found."))
+		return
+	}
+	fmt.Printf("%s %s\n", term.Bold("Invalid records:"), term.Redf("%d", len(report.Problems)))
+	for _, p := range report.Problems {
+		action := p.Action
+		if !report.Repaired {
+			action = "would " + action
+		}
+		id := p.SessionID
+		if id == "" {
+			id = "-"
+		}
+		fmt.Printf("  #%-4d %s %s  %s\n",
+			p.Index,
+			term.PadRight(id, 24, term.Cyan),
+			term.PadRight(action, 10, term.Yellow),
+			strings.Join(p.Issues, "; "),
+		)
+	}
+	if report.Repaired {
+		fmt.Println(term.Green("Registry repaired."))
+	} else {
+		fmt.Println(term.Dim("Run with --repair to drop or fix these records."))
+	}
+}
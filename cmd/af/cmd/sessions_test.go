@@ -2,8 +2,15 @@ package cmd
 
 import (
 	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/baiirun/aetherflow/internal/daemon"
 	"github.com/baiirun/aetherflow/internal/sessions"
 )
 
@@ -88,7 +95,7 @@ func TestSessionWhatForRecord(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := sessionWhatForRecord(tt.rec, tt.index, tt.semantic)
+			got := sessionWhatForRecord(tt.rec, tt.index, tt.semantic, defaultSessionWhat)
 			if got != tt.want {
 				t.Fatalf("sessionWhatForRecord() = %q, want %q", got, tt.want)
 			}
@@ -125,8 +132,201 @@ func TestShouldEnrichSessionTitle(t *testing.T) {
 		{title: "Implement websocket retries", want: false},
 	}
 	for _, tt := range tests {
-		if got := shouldEnrichSessionTitle(tt.title); got != tt.want {
+		if got := shouldEnrichSessionTitle(tt.title, "spawn-"); got != tt.want {
 			t.Fatalf("shouldEnrichSessionTitle(%q) = %v, want %v", tt.title, got, tt.want)
 		}
 	}
+	if !shouldEnrichSessionTitle("Agent-ice_fox-a3f2", "agent-") {
+		t.Error("title starting with a custom spawn ID prefix should be enriched")
+	}
+	if shouldEnrichSessionTitle("Spawn-ice_fox objective", "agent-") {
+		t.Error("default prefix should not match when a custom prefix is configured")
+	}
+}
+
+func TestLoadSessionSemanticIndexUsesCache(t *testing.T) {
+	original := fetchSessionObjective
+	t.Cleanup(func() { fetchSessionObjective = original })
+
+	calls := 0
+	fetchSessionObjective = func(_ *http.Client, serverRef, sessionID string) string {
+		calls++
+		return "objective for " + sessionID
+	}
+
+	cachePath := filepath.Join(t.TempDir(), objectiveCacheFile)
+	updated := time.Now().Add(-time.Minute)
+	recs := []sessions.Record{{ServerRef: "http://127.0.0.1:4096", SessionID: "ses_1", UpdatedAt: updated}}
+	key := recordKey(recs[0].ServerRef, recs[0].SessionID)
+
+	first := loadSessionSemanticIndex(recs, nil, cachePath, defaultSessionWhat, daemon.DefaultSpawnIDPrefix)
+	if first[key] != "objective for ses_1" || calls != 1 {
+		t.Fatalf("first listing: index = %v, calls = %d; want fetched objective and 1 call", first, calls)
+	}
+
+	second := loadSessionSemanticIndex(recs, nil, cachePath, defaultSessionWhat, daemon.DefaultSpawnIDPrefix)
+	if second[key] != "objective for ses_1" {
+		t.Errorf("second listing: index = %v, want cached objective", second)
+	}
+	if calls != 1 {
+		t.Errorf("second listing within TTL called the fetcher; calls = %d, want 1", calls)
+	}
+
+	// A changed session record invalidates its cached objective.
+	recs[0].UpdatedAt = updated.Add(time.Second)
+	loadSessionSemanticIndex(recs, nil, cachePath, defaultSessionWhat, daemon.DefaultSpawnIDPrefix)
+	if calls != 2 {
+		t.Errorf("listing after record change: calls = %d, want 2", calls)
+	}
+}
+
+func TestSessionWhatOrderChangesWhat(t *testing.T) {
+	original := fetchSessionObjective
+	t.Cleanup(func() { fetchSessionObjective = original })
+	calls := 0
+	fetchSessionObjective = func(_ *http.Client, serverRef, sessionID string) string {
+		calls++
+		return "objective for " + sessionID
+	}
+
+	recs := []sessions.Record{
+		{ServerRef: "http://127.0.0.1:4096", SessionID: "ses_1", WorkRef: "ts-123", AgentID: "ghost_wolf"},
+		{ServerRef: "http://127.0.0.1:4096", SessionID: "ses_2", AgentID: "blur_knife"},
+	}
+	index := map[string]opencodeSessionSummary{
+		"ses_1": {ID: "ses_1", Title: "New session - 2026-02-17T04:16:43.591Z"},
+		"ses_2": {ID: "ses_2", Title: "New session - 2026-02-17T05:00:00.000Z"},
+	}
+
+	tests := []struct {
+		name      string
+		order     []string
+		want      []string
+		wantCalls int
+	}{
+		{
+			name:      "default prefers the objective",
+			order:     nil,
+			want:      []string{"objective for ses_1", "objective for ses_2"},
+			wantCalls: 2,
+		},
+		{
+			// ses_1 has a work ref, so its objective is never fetched.
+			name:      "work ref ahead of objective",
+			order:     []string{"work_ref", "objective", "agent"},
+			want:      []string{"ts-123", "objective for ses_2"},
+			wantCalls: 1,
+		},
+		{
+			name:      "objective left out",
+			order:     []string{"agent", "title"},
+			want:      []string{"ghost_wolf", "blur_knife"},
+			wantCalls: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = 0
+			order, err := sessionWhatOrder(tt.order)
+			if err != nil {
+				t.Fatalf("sessionWhatOrder(%v): %v", tt.order, err)
+			}
+			semantic := loadSessionSemanticIndex(recs, index, "", order, daemon.DefaultSpawnIDPrefix)
+			for i, r := range recs {
+				if got := sessionWhatForRecord(r, index, semantic, order); got != tt.want[i] {
+					t.Errorf("what(%s) = %q, want %q", r.SessionID, got, tt.want[i])
+				}
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("objective fetches = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestSessionWhatOrderRejectsBadSources(t *testing.T) {
+	for _, order := range [][]string{{"title", "summary"}, {"agent", "agent"}} {
+		if _, err := sessionWhatOrder(order); err == nil {
+			t.Errorf("sessionWhatOrder(%v) = nil error, want error", order)
+		}
+	}
+}
+
+func TestWriteSessionsCompact(t *testing.T) {
+	recs := []sessions.Record{
+		{ServerRef: "http://127.0.0.1:4096", SessionID: "ses_one", Status: sessions.StatusActive, Origin: sessions.OriginPool, WorkRef: "ts-1"},
+		{ServerRef: "http://127.0.0.1:4096", SessionID: "ses_two", Status: sessions.StatusIdle, Origin: sessions.OriginSpawn, WorkRef: "spawn-a"},
+	}
+	what := map[string]string{
+		"ses_one": "Fix race in daemon",
+		"ses_two": "Refactor the session registry so that every record is validated on write",
+	}
+
+	var buf strings.Builder
+	writeSessionsCompact(&buf, recs, func(r sessions.Record) string { return what[r.SessionID] }, 80)
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want header + 2 rows:\n%s", len(lines), buf.String())
+	}
+	if got := strings.Fields(lines[0]); !slices.Equal(got, []string{"SESSION", "STATUS", "WHAT"}) {
+		t.Errorf("header columns = %v, want SESSION STATUS WHAT", got)
+	}
+	want := []string{
+		fmt.Sprintf("%-34s  %-10s  %s", "ses_one", "active", "Fix race in daemon"),
+		// WHAT gets 80 - 48 = 32 columns.
+		fmt.Sprintf("%-34s  %-10s  %s", "ses_two", "idle", "Refactor the session registry..."),
+	}
+	for i, w := range want {
+		if lines[i+1] != w {
+			t.Errorf("row %d = %q, want %q", i, lines[i+1], w)
+		}
+	}
+	// Origin, server, and work ref are left out.
+	for _, dropped := range []string{"pool", "127.0.0.1", "ts-1", "spawn-a"} {
+		if strings.Contains(buf.String(), dropped) {
+			t.Errorf("compact output contains %q:\n%s", dropped, buf.String())
+		}
+	}
+}
+
+func TestUnmanagedSessionsMergedIntoListing(t *testing.T) {
+	original := runCommandOutput
+	t.Cleanup(func() { runCommandOutput = original })
+	runCommandOutput = func(name string, args ...string) ([]byte, error) {
+		return []byte(`[
+  {"id":"ses_managed","title":"Managed","directory":"/tmp/proj","updated":1767225600000},
+  {"id":"ses_outside","title":"Poke at the TUI","directory":"/tmp/scratch","created":1767222000000,"updated":1767225600000}
+]`), nil
+	}
+
+	recs := []sessions.Record{
+		{ServerRef: "http://127.0.0.1:4096", SessionID: "ses_managed", Status: sessions.StatusActive, Origin: sessions.OriginPool, WorkRef: "ts-1"},
+	}
+	index := loadOpencodeSessionIndex()
+	unmanaged := unmanagedSessionRecords(recs, index, "http://127.0.0.1:4096")
+	if len(unmanaged) != 1 {
+		t.Fatalf("unmanaged = %+v, want only ses_outside", unmanaged)
+	}
+	got := unmanaged[0]
+	if got.SessionID != "ses_outside" || got.Origin != sessions.OriginExternal || got.ServerRef != "http://127.0.0.1:4096" || got.Directory != "/tmp/scratch" {
+		t.Errorf("unmanaged record = %+v", got)
+	}
+	if want := time.UnixMilli(1767225600000); !got.UpdatedAt.Equal(want) {
+		t.Errorf("UpdatedAt = %v, want %v", got.UpdatedAt, want)
+	}
+
+	var buf strings.Builder
+	merged := append(recs, unmanaged...)
+	writeSessionsTable(&buf, merged, func(r sessions.Record) string { return sessionWhatForRecord(r, index, nil, defaultSessionWhat) }, sessionsDefaultWidth)
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want header + 2 rows:\n%s", len(lines), buf.String())
+	}
+	if fields := strings.Fields(lines[2]); len(fields) < 4 || fields[0] != "ses_outside" || fields[2] != "-" || fields[3] != "external" {
+		t.Errorf("external row = %q, want ses_outside with status - and origin external", lines[2])
+	}
+	if !strings.Contains(lines[2], "Poke at the TUI") {
+		t.Errorf("external row = %q, want its opencode title", lines[2])
+	}
 }
@@ -2,7 +2,6 @@ package cmd
 
 import (
 	"context"
-	"crypto/rand"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,12 +9,13 @@ import (
 	"os"
 	"os/exec"
 	"os/signal"
+	"path/filepath"
+	"strconv"
 	"strings"
 	"syscall"
 
 	"github.com/baiirun/aetherflow/internal/client"
 	"github.com/baiirun/aetherflow/internal/daemon"
-	"github.com/baiirun/aetherflow/internal/protocol"
 	"github.com/baiirun/aetherflow/internal/term"
 	"github.com/spf13/cobra"
 )
@@ -31,12 +31,27 @@ and creates a PR (or merges to main in --solo mode).
 No daemon or prog task required — the prompt is the spec, the PR is
 the deliverable.
 
+With --pick, no prompt is given: af spawn lists the project's ready
+tasks from prog, and the agent runs the chosen task with the same role
+prompt a pool agent would get. The task is claimed in prog first and
+labeled af-spawn, so the daemon won't schedule or reclaim it too, even
+when the agent isn't registered with it.
+
 Examples:
   af spawn "refactor the auth module to use JWT"
   af spawn "add rate limiting to the /api/users endpoint" --solo
-  af spawn "fix the flaky TestRetry test" -d`,
-	Args: cobra.ExactArgs(1),
-	Run:  runSpawn,
+  af spawn "fix the flaky TestRetry test" -d
+  af spawn --pick -d`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if pick, _ := cmd.Flags().GetBool("pick"); pick {
+			if len(args) > 0 {
+				return fmt.Errorf("--pick takes no prompt")
+			}
+			return nil
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
+	Run: runSpawn,
 }
 
 func init() {
@@ -48,16 +63,23 @@ func init() {
 	f.Bool("solo", false, "Solo mode: agent merges to main instead of creating a PR")
 	f.String("spawn-cmd", daemon.DefaultSpawnCmd, "Command to launch the agent session")
 	f.String("prompt-dir", "", "Override embedded prompts with files from this directory")
+	f.Bool("no-register", false, "Don't register the agent with the daemon (it won't appear in af status)")
+	f.Bool("require-daemon", false, "Fail instead of running unregistered when the daemon isn't reachable")
+	f.Bool("ephemeral", false, "Drop the agent from the daemon's registry soon after it exits (for throwaway experiments)")
+	f.Bool("pick", false, "Choose a ready prog task interactively instead of giving a prompt")
+	spawnCmd.MarkFlagsMutuallyExclusive("no-register", "require-daemon")
 }
 
 func runSpawn(cmd *cobra.Command, args []string) {
-	userPrompt := args[0]
-
 	detach, _ := cmd.Flags().GetBool("detach")
 	jsonOutput, _ := cmd.Flags().GetBool("json")
 	solo, _ := cmd.Flags().GetBool("solo")
 	spawnCmd, _ := cmd.Flags().GetString("spawn-cmd")
 	promptDir, _ := cmd.Flags().GetString("prompt-dir")
+	noRegister, _ := cmd.Flags().GetBool("no-register")
+	requireDaemon, _ := cmd.Flags().GetBool("require-daemon")
+	ephemeral, _ := cmd.Flags().GetBool("ephemeral")
+	pick, _ := cmd.Flags().GetBool("pick")
 
 	// Load config file values for fields not set by flags.
 	configPath, _ := cmd.Flags().GetString("config")
@@ -87,39 +109,108 @@ func runSpawn(cmd *cobra.Command, args []string) {
 	}
 	spawnCmd = daemon.EnsureAttachSpawnCmd(spawnCmd, serverURL)
 
-	// Generate a unique spawn ID for worktree/branch naming.
-	// The "spawn-" prefix ensures no collision with pool agent IDs.
-	// A random hex suffix expands the namespace from ~14K to ~943M
-	// combinations, making birthday-paradox collisions negligible.
-	spawnID := newSpawnID()
-
-	// Render the spawn prompt.
-	prompt, err := daemon.RenderSpawnPrompt(promptDir, userPrompt, spawnID, solo)
+	// Generate a unique spawn ID for worktree/branch naming, shaped by
+	// spawn_id_prefix and spawn_id_template.
+	spawnID, err := daemon.NewSpawnID(fileCfg.SpawnIDPrefix, fileCfg.SpawnIDTemplate)
 	if err != nil {
-		Fatal("rendering prompt: %v", err)
+		Fatal("%v", err)
+	}
+
+	// Pick a ready task and render its role prompt, or render the spawn
+	// prompt around the user's.
+	var userPrompt, prompt, taskID string
+	role := daemon.RoleSpawn
+	project := fileCfg.Project
+	if cmd.Flags().Changed("project") {
+		project, _ = cmd.Flags().GetString("project")
+	}
+	if pick {
+		if project == "" {
+			Fatal("--pick needs a project: pass --project or set project in %s", configPath)
+		}
+		picked, err := pickTask(context.Background(), project, daemon.ExecCommandRunner, listTaskSelector{}, promptDir, solo)
+		if errors.Is(err, errPickCancelled) {
+			fmt.Println("af spawn: no task picked")
+			return
+		}
+		if err != nil {
+			Fatal("%v", err)
+		}
+		taskID, role, prompt = picked.Task.ID, picked.Role, picked.Prompt
+		userPrompt = picked.Task.ID + ": " + picked.Task.Title
+	} else {
+		userPrompt = args[0]
+		prompt, err = daemon.RenderSpawnPrompt(promptDir, userPrompt, spawnID, solo)
+		if err != nil {
+			Fatal("rendering prompt: %v", err)
+		}
 	}
 
-	// Resolve the daemon URL for best-effort registration.
+	spawnCmd = daemon.ExpandSpawnCmd(spawnCmd, daemon.SpawnCmdVars{AgentID: spawnID, TaskID: taskID, Role: role})
+	if err := fileCfg.CheckSpawnCmd(spawnCmd); err != nil {
+		Fatal("%v", err)
+	}
+	if err := daemon.CheckPromptSize(prompt, fileCfg.MaxPromptBytes); err != nil {
+		Fatal("%v", err)
+	}
+
+	// Resolve the daemon URL for registration.
 	daemonURL := resolveDaemonURL(cmd)
 
+	// Check before starting the agent, so a missing daemon doesn't leave
+	// an agent running that the caller then has to clean up.
+	if requireDaemon {
+		if err := checkDaemonReachable(daemonURL); err != nil {
+			Fatal("%v", err)
+		}
+	}
+
+	// Claim last, once nothing else can stop the launch, so a failed
+	// check doesn't leave the task in_progress with no agent.
+	if taskID != "" {
+		if err := claimPickedTask(context.Background(), project, daemon.ExecCommandRunner, taskID); err != nil {
+			Fatal("%v", err)
+		}
+	}
+
+	opts := spawnOptions{
+		SpawnID:       spawnID,
+		TaskID:        taskID,
+		UserPrompt:    userPrompt,
+		SpawnCmd:      spawnCmd,
+		Prompt:        prompt,
+		DaemonURL:     daemonURL,
+		Register:      !noRegister,
+		RequireDaemon: requireDaemon,
+		Ephemeral:     ephemeral,
+		JSON:          jsonOutput,
+	}
 	if detach {
-		runDetached(spawnID, userPrompt, spawnCmd, prompt, daemonURL, jsonOutput)
+		runDetached(opts)
 		return
 	}
 
-	runForeground(spawnID, userPrompt, spawnCmd, prompt, daemonURL, jsonOutput)
+	runForeground(opts)
 }
 
-// newSpawnID generates a unique spawn identifier.
-// Format: spawn-<adjective>_<noun>-<4hex> (e.g., "spawn-ghost_wolf-a3f2").
-func newSpawnID() string {
-	name := protocol.GenerateAgentName()
-	suffix := make([]byte, 2)
-	if _, err := rand.Read(suffix); err != nil {
-		// Fallback: should never happen, but don't crash.
-		return "spawn-" + name
-	}
-	return fmt.Sprintf("spawn-%s-%x", name, suffix)
+// spawnOptions holds what runForeground and runDetached need to launch an
+// agent and report it to the daemon.
+type spawnOptions struct {
+	SpawnID    string
+	TaskID     string // prog task claimed with --pick, or ""
+	UserPrompt string // the prompt as the user gave it, shown in af status
+	SpawnCmd   string
+	Prompt     string // rendered prompt passed to SpawnCmd
+	DaemonURL  string
+
+	// Register contacts the daemon; false (--no-register) never does.
+	Register bool
+	// RequireDaemon makes a failed registration stop the agent and exit
+	// non-zero.
+	RequireDaemon bool
+	// Ephemeral asks the daemon to drop the entry soon after the agent exits.
+	Ephemeral bool
+	JSON      bool
 }
 
 // buildAgentProc creates a configured exec.Cmd for the agent process.
@@ -137,29 +228,121 @@ func buildAgentProc(ctx context.Context, spawnCmd, prompt, agentID string) *exec
 	return proc
 }
 
-// registerSpawn attempts to register the spawned agent with the daemon.
-// Best-effort — if the daemon isn't running, we log a warning for non-
-// connection errors and continue.
-func registerSpawn(daemonURL, spawnID string, pid int, prompt string) {
+// registerSpawn registers the spawned agent with the daemon.
+// By default it is best-effort: if the daemon isn't running we continue
+// silently, and other failures are logged as warnings. With required set
+// (--require-daemon), any failure is returned instead. taskID is the prog
+// task claimed with --pick, whose worktree is named after the task.
+func registerSpawn(daemonURL, spawnID, taskID string, pid int, prompt string, required, ephemeral bool) error {
 	c := client.New(daemonURL)
-	if err := c.SpawnRegister(client.SpawnRegisterParams{
-		SpawnID: spawnID,
-		PID:     pid,
-		Prompt:  prompt,
-	}); err != nil {
-		// Connection refused = daemon not running — expected, silent.
-		// Anything else is worth surfacing.
-		if !isConnectionRefused(err) {
-			fmt.Fprintf(os.Stderr, "af spawn: warning: daemon registration failed: %v\n", err)
-		}
+	err := c.SpawnRegister(client.SpawnRegisterParams{
+		SpawnID:      spawnID,
+		PID:          pid,
+		Prompt:       prompt,
+		WorktreePath: spawnWorktreePath(spawnWorktreeName(spawnID, taskID)),
+		Ephemeral:    ephemeral,
+		TaskID:       taskID,
+	})
+	if err == nil {
+		return nil
+	}
+	if required {
+		return daemonRequiredError(daemonURL, err)
+	}
+	// Connection refused = daemon not running — expected, silent.
+	// Anything else is worth surfacing.
+	if strings.Contains(err.Error(), daemon.ErrSpawnRegistryFull.Error()) {
+		fmt.Fprintf(os.Stderr, "af spawn: warning: daemon spawn registry is full; %s runs but won't appear in af status or af logs\n", spawnID)
+	} else if !isConnectionRefused(err) {
+		fmt.Fprintf(os.Stderr, "af spawn: warning: daemon registration failed: %v\n", err)
+	}
+	return nil
+}
+
+// checkDaemonReachable reports an error when the daemon at daemonURL can't
+// be reached, for --require-daemon.
+func checkDaemonReachable(daemonURL string) error {
+	if _, err := client.New(daemonURL).DaemonLifecycle(); err != nil {
+		return daemonRequiredError(daemonURL, err)
+	}
+	return nil
+}
+
+// daemonRequiredError explains a daemon failure under --require-daemon,
+// telling a daemon that is down apart from one that rejected the request.
+func daemonRequiredError(daemonURL string, err error) error {
+	if isConnectionRefused(err) {
+		return fmt.Errorf("daemon is not running at %s (--require-daemon); start it with af daemon start", daemonURL)
 	}
+	return fmt.Errorf("daemon registration failed (--require-daemon): %w", err)
+}
+
+// spawnWorktreeName returns the worktree directory name the agent's prompt
+// uses: the picked task's ID with --pick, otherwise the spawn ID.
+func spawnWorktreeName(spawnID, taskID string) string {
+	if taskID != "" {
+		return taskID
+	}
+	return spawnID
+}
+
+// spawnPIDFile returns the path of the marker af spawn writes next to a
+// spawn's worktree, holding the agent's PID. af spawn worktrees --prune
+// reads it to tell a running spawn from a dead one when the daemon has no
+// record of the spawn, e.g. after a daemon restart.
+func spawnPIDFile(worktreePath string) string {
+	return worktreePath + ".pid"
+}
+
+// writeSpawnPIDFile records pid in the spawn's PID file. It is best-effort:
+// without the file, --prune leaves the worktree alone.
+func writeSpawnPIDFile(spawnID, taskID string, pid int) {
+	path := spawnWorktreePath(spawnWorktreeName(spawnID, taskID))
+	if path == "" {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return
+	}
+	_ = os.WriteFile(spawnPIDFile(path), []byte(strconv.Itoa(pid)+"\n"), 0o644)
+}
+
+// removeSpawnPIDFile removes the spawn's PID file once the agent has exited.
+func removeSpawnPIDFile(spawnID, taskID string) {
+	if path := spawnWorktreePath(spawnWorktreeName(spawnID, taskID)); path != "" {
+		_ = os.Remove(spawnPIDFile(path))
+	}
+}
+
+// spawnWorktreePath returns the absolute path of the worktree the spawn
+// prompt tells the agent to create (.aetherflow/worktrees/<spawn-id>,
+// relative to the directory af spawn runs in). Returns "" if the working
+// directory can't be determined.
+func spawnWorktreePath(spawnID string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(wd, spawnWorktreeDir, spawnID)
 }
 
 // deregisterSpawn attempts to remove the spawned agent from the daemon registry.
 // Best-effort — if the daemon isn't running, we silently continue.
-func deregisterSpawn(daemonURL, spawnID string) {
+func deregisterSpawn(daemonURL, spawnID string, exitCode int) {
 	c := client.New(daemonURL)
-	_ = c.SpawnDeregister(spawnID)
+	_ = c.SpawnDeregister(spawnID, exitCode)
+}
+
+// agentExitCode maps the agent's Wait error to the exit status reported on
+// deregister. Failures without an exit status (e.g. a lost wait) count as 1.
+func agentExitCode(waitErr error) int {
+	if waitErr == nil {
+		return 0
+	}
+	if exitErr, ok := waitErr.(*exec.ExitError); ok && exitErr.ExitCode() != 0 {
+		return exitErr.ExitCode()
+	}
+	return 1
 }
 
 // isConnectionRefused returns true if the error is a "connection refused"
@@ -174,23 +357,41 @@ func isConnectionRefused(err error) bool {
 	return false
 }
 
-// spawnResult is the JSON output for --json mode.
+// spawnResult is the JSON output for --json mode. It is printed as soon
+// as the agent starts, before opencode has created its session, so the
+// session ID is normally pending; af status or af attach <spawn-id>
+// resolve it once the daemon has seen the session.
 type spawnResult struct {
-	SpawnID string `json:"spawn_id"`
-	PID     int    `json:"pid"`
+	SpawnID        string `json:"spawn_id"`
+	PID            int    `json:"pid"`
+	ServerURL      string `json:"server_url,omitempty"`
+	SpawnCmd       string `json:"spawn_cmd"` // launch command, without the prompt
+	SessionID      string `json:"session_id,omitempty"`
+	SessionPending bool   `json:"session_pending"`
+}
+
+// newSpawnResult describes a started agent for --json output.
+func newSpawnResult(spawnID string, pid int, spawnCmd string) spawnResult {
+	return spawnResult{
+		SpawnID:        spawnID,
+		PID:            pid,
+		ServerURL:      daemon.AttachURL(spawnCmd),
+		SpawnCmd:       daemon.RedactSecrets(spawnCmd),
+		SessionPending: true,
+	}
 }
 
 // runForeground launches the agent in the current terminal.
-func runForeground(spawnID, userPrompt, spawnCmd, prompt, daemonURL string, jsonOutput bool) {
-	if !jsonOutput {
-		fmt.Printf("%s Spawning agent %s\n", term.Bold("af spawn:"), term.Cyan(spawnID))
+func runForeground(opts spawnOptions) {
+	if !opts.JSON {
+		fmt.Printf("%s Spawning agent %s\n", term.Bold("af spawn:"), term.Cyan(opts.SpawnID))
 		fmt.Println()
 	}
 
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
-	proc := buildAgentProc(ctx, spawnCmd, prompt, spawnID)
+	proc := buildAgentProc(ctx, opts.SpawnCmd, opts.Prompt, opts.SpawnID)
 	proc.Stdout = os.Stdout
 	proc.Stderr = os.Stderr
 	proc.Stdin = os.Stdin
@@ -199,22 +400,28 @@ func runForeground(spawnID, userPrompt, spawnCmd, prompt, daemonURL string, json
 	if err := proc.Start(); err != nil {
 		Fatal("failed to start agent: %v", err)
 	}
+	writeSpawnPIDFile(opts.SpawnID, opts.TaskID, proc.Process.Pid)
 
-	if jsonOutput {
-		_ = json.NewEncoder(os.Stdout).Encode(spawnResult{
-			SpawnID: spawnID,
-			PID:     proc.Process.Pid,
-		})
+	if opts.JSON {
+		_ = json.NewEncoder(os.Stdout).Encode(newSpawnResult(opts.SpawnID, proc.Process.Pid, opts.SpawnCmd))
 	}
 
-	// Register with daemon for observability (best-effort).
-	registerSpawn(daemonURL, spawnID, proc.Process.Pid, userPrompt)
+	// Register with daemon for observability.
+	if opts.Register {
+		if err := registerSpawn(opts.DaemonURL, opts.SpawnID, opts.TaskID, proc.Process.Pid, opts.UserPrompt, opts.RequireDaemon, opts.Ephemeral); err != nil {
+			_ = syscall.Kill(-proc.Process.Pid, syscall.SIGTERM)
+			Fatal("%v", err)
+		}
+	}
 
 	// Wait for the process to exit.
 	waitErr := proc.Wait()
+	removeSpawnPIDFile(opts.SpawnID, opts.TaskID)
 
 	// Deregister from daemon (best-effort).
-	deregisterSpawn(daemonURL, spawnID)
+	if opts.Register {
+		deregisterSpawn(opts.DaemonURL, opts.SpawnID, agentExitCode(waitErr))
+	}
 
 	if waitErr != nil {
 		if exitErr, ok := waitErr.(*exec.ExitError); ok {
@@ -223,8 +430,8 @@ func runForeground(spawnID, userPrompt, spawnCmd, prompt, daemonURL string, json
 		Fatal("agent process failed: %v", waitErr)
 	}
 
-	if !jsonOutput {
-		fmt.Printf("\n%s Agent %s finished\n", term.Bold("af spawn:"), term.Cyan(spawnID))
+	if !opts.JSON {
+		fmt.Printf("\n%s Agent %s finished\n", term.Bold("af spawn:"), term.Cyan(opts.SpawnID))
 	}
 }
 
@@ -232,8 +439,8 @@ func runForeground(spawnID, userPrompt, spawnCmd, prompt, daemonURL string, json
 // The rendered prompt is passed directly to the spawn command, bypassing
 // af spawn entirely so there's no double-rendering or flag-forwarding.
 // Stdout/stderr are discarded — observability comes from the plugin event pipeline.
-func runDetached(spawnID, userPrompt, spawnCmd, prompt, daemonURL string, jsonOutput bool) {
-	proc := buildAgentProc(context.Background(), spawnCmd, prompt, spawnID)
+func runDetached(opts spawnOptions) {
+	proc := buildAgentProc(context.Background(), opts.SpawnCmd, opts.Prompt, opts.SpawnID)
 
 	// Redirect stdout/stderr to /dev/null. Observability is provided by the
 	// plugin event pipeline (session events flow through the daemon's event buffer).
@@ -249,20 +456,26 @@ func runDetached(spawnID, userPrompt, spawnCmd, prompt, daemonURL string, jsonOu
 		Fatal("failed to start agent: %v", err)
 	}
 
-	// Detach: don't wait for the child.
+	// Detach: don't wait for the child. The PID file outlives it; --prune
+	// finds the PID dead once the agent exits.
 	_ = devNull.Close()
+	writeSpawnPIDFile(opts.SpawnID, opts.TaskID, proc.Process.Pid)
 
-	// Register with daemon for observability (best-effort).
+	// Register with daemon for observability.
 	// The daemon's sweep will clean up the entry when the PID dies.
-	registerSpawn(daemonURL, spawnID, proc.Process.Pid, userPrompt)
+	if opts.Register {
+		if err := registerSpawn(opts.DaemonURL, opts.SpawnID, opts.TaskID, proc.Process.Pid, opts.UserPrompt, opts.RequireDaemon, opts.Ephemeral); err != nil {
+			_ = syscall.Kill(-proc.Process.Pid, syscall.SIGTERM)
+			Fatal("%v", err)
+		}
+	}
 
-	if jsonOutput {
-		_ = json.NewEncoder(os.Stdout).Encode(spawnResult{
-			SpawnID: spawnID,
-			PID:     proc.Process.Pid,
-		})
+	if opts.JSON {
+		_ = json.NewEncoder(os.Stdout).Encode(newSpawnResult(opts.SpawnID, proc.Process.Pid, opts.SpawnCmd))
 	} else {
-		fmt.Printf("%s Spawned agent %s (pid %d)\n", term.Bold("af spawn:"), term.Cyan(spawnID), proc.Process.Pid)
-		fmt.Printf("%s af logs %s -f\n", term.Dim("logs:"), spawnID)
+		fmt.Printf("%s Spawned agent %s (pid %d)\n", term.Bold("af spawn:"), term.Cyan(opts.SpawnID), proc.Process.Pid)
+		if opts.Register {
+			fmt.Printf("%s af logs %s -f\n", term.Dim("logs:"), opts.SpawnID)
+		}
 	}
 }
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/baiirun/aetherflow/internal/daemon"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// errPickCancelled is returned when the picker is closed without a choice.
+var errPickCancelled = errors.New("no task picked")
+
+// taskSelector asks the user to choose one of the ready tasks for
+// af spawn --pick. It returns errPickCancelled when they back out.
+type taskSelector interface {
+	SelectTask(tasks []daemon.Task) (daemon.Task, error)
+}
+
+// pickedTask is a ready task chosen with --pick and the role prompt its
+// agent runs, rendered the way the pool renders it.
+type pickedTask struct {
+	Task   daemon.Task
+	Role   daemon.Role
+	Prompt string
+}
+
+// pickTask fetches the project's ready queue from prog, lets sel choose a
+// task, and renders the role prompt for it. Nothing is claimed yet; see
+// claimPickedTask.
+func pickTask(ctx context.Context, project string, runner daemon.CommandRunner, sel taskSelector, promptDir string, solo bool) (pickedTask, error) {
+	tasks, err := daemon.NewPoller(project, 0, runner, nil).Poll(ctx)
+	if err != nil {
+		return pickedTask{}, err
+	}
+	if len(tasks) == 0 {
+		return pickedTask{}, fmt.Errorf("no ready tasks in project %s", project)
+	}
+
+	task, err := sel.SelectTask(tasks)
+	if err != nil {
+		return pickedTask{}, err
+	}
+
+	meta, err := daemon.FetchTaskMeta(ctx, task.ID, project, runner)
+	if err != nil {
+		return pickedTask{}, fmt.Errorf("fetching task metadata: %w", err)
+	}
+	role := daemon.InferRole(meta)
+	prompt, err := daemon.RenderPrompt(promptDir, role, task.ID, solo)
+	if err != nil {
+		return pickedTask{}, fmt.Errorf("rendering %s prompt: %w", role, err)
+	}
+	return pickedTask{Task: task, Role: role, Prompt: prompt}, nil
+}
+
+// claimPickedTask moves the task to in_progress in prog, as the pool does
+// before launching an agent, so the daemon doesn't schedule it as well, and
+// labels it with daemon.SpawnClaimLabel so the daemon doesn't reclaim it as
+// an orphan either, even if it never heard of this spawn. The daemon lets
+// the claim go when the spawn exits, or after spawn_claim_ttl when no
+// registered spawn is running the task.
+func claimPickedTask(ctx context.Context, project string, runner daemon.CommandRunner, taskID string) error {
+	if err := daemon.NewProgWorkSource(runner).Claim(ctx, taskID, project); err != nil {
+		return fmt.Errorf("claiming task %s: %w", taskID, err)
+	}
+	args := []string{"label", taskID, daemon.SpawnClaimLabel}
+	if project != "" {
+		args = append(args, "-p", project)
+	}
+	if output, err := runner(ctx, "prog", args...); err != nil {
+		// Unlabeled, the task looks like an orphan, so the daemon picks it
+		// up; that is the right outcome once this spawn gives up.
+		return fmt.Errorf("labeling claimed task %s: %w (output: %s); the daemon will reclaim it", taskID, err, string(output))
+	}
+	return nil
+}
+
+// listTaskSelector is the interactive taskSelector: a filterable list of
+// the ready tasks in the terminal.
+type listTaskSelector struct{}
+
+func (listTaskSelector) SelectTask(tasks []daemon.Task) (daemon.Task, error) {
+	items := make([]list.Item, len(tasks))
+	for i, t := range tasks {
+		items[i] = taskItem{t}
+	}
+	l := list.New(items, list.NewDefaultDelegate(), 0, 0)
+	l.Title = "Pick a task to spawn an agent for"
+
+	final, err := tea.NewProgram(pickModel{list: l}, tea.WithAltScreen()).Run()
+	if err != nil {
+		return daemon.Task{}, fmt.Errorf("task picker: %w", err)
+	}
+	m := final.(pickModel)
+	if m.chosen == nil {
+		return daemon.Task{}, errPickCancelled
+	}
+	return *m.chosen, nil
+}
+
+type taskItem struct{ task daemon.Task }
+
+func (i taskItem) Title() string       { return i.task.ID + "  " + i.task.Title }
+func (i taskItem) Description() string { return fmt.Sprintf("priority %d", i.task.Priority) }
+func (i taskItem) FilterValue() string { return i.task.ID + " " + i.task.Title }
+
+type pickModel struct {
+	list   list.Model
+	chosen *daemon.Task
+}
+
+func (m pickModel) Init() tea.Cmd { return nil }
+
+func (m pickModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.list.SetSize(msg.Width, msg.Height)
+	case tea.KeyMsg:
+		if m.list.FilterState() == list.Filtering {
+			break
+		}
+		switch msg.String() {
+		case "enter":
+			if item, ok := m.list.SelectedItem().(taskItem); ok {
+				m.chosen = &item.task
+			}
+			return m, tea.Quit
+		case "esc", "q", "ctrl+c":
+			return m, tea.Quit
+		}
+	}
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+func (m pickModel) View() string { return m.list.View() }
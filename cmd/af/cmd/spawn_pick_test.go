@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/baiirun/aetherflow/internal/daemon"
+)
+
+// fakeSelector picks the task with the given ID, or cancels when it is "".
+type fakeSelector struct {
+	pick    string
+	offered []daemon.Task
+}
+
+func (s *fakeSelector) SelectTask(tasks []daemon.Task) (daemon.Task, error) {
+	s.offered = tasks
+	for _, t := range tasks {
+		if t.ID == s.pick {
+			return t, nil
+		}
+	}
+	return daemon.Task{}, errPickCancelled
+}
+
+// fakeQueueRunner serves prog ready with the given output and prog show
+// for any task, recording every prog call.
+func fakeQueueRunner(ready string, calls *[]string) daemon.CommandRunner {
+	return func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		*calls = append(*calls, strings.Join(args, " "))
+		switch args[0] {
+		case "ready":
+			return []byte(ready), nil
+		case "show":
+			return []byte(fmt.Sprintf(`{"id": %q, "type": "task", "labels": []}`, args[1])), nil
+		case "start":
+			return []byte("Started"), nil
+		case "label":
+			return []byte("Labeled"), nil
+		}
+		return nil, fmt.Errorf("unexpected prog %v", args)
+	}
+}
+
+func TestPickTaskRendersChosenTaskPrompt(t *testing.T) {
+	var calls []string
+	runner := fakeQueueRunner("ID         PRI  TITLE\nts-aaa     1    First task\nts-bbb     2    Second task\n", &calls)
+	sel := &fakeSelector{pick: "ts-bbb"}
+
+	picked, err := pickTask(context.Background(), "proj", runner, sel, "", false)
+	if err != nil {
+		t.Fatalf("pickTask: %v", err)
+	}
+	if len(sel.offered) != 2 {
+		t.Errorf("selector offered %d tasks, want 2", len(sel.offered))
+	}
+	if picked.Task.ID != "ts-bbb" || picked.Task.Title != "Second task" {
+		t.Errorf("picked %+v, want ts-bbb", picked.Task)
+	}
+	if picked.Role != daemon.RoleWorker {
+		t.Errorf("Role = %q, want %q", picked.Role, daemon.RoleWorker)
+	}
+	if !strings.Contains(picked.Prompt, "ts-bbb") || strings.Contains(picked.Prompt, "{{task_id}}") {
+		t.Errorf("prompt does not have the task ID rendered in")
+	}
+	for _, c := range calls {
+		if strings.HasPrefix(c, "start") {
+			t.Errorf("pickTask claimed the task (%q); claiming is left to claimPickedTask", c)
+		}
+	}
+
+	if err := claimPickedTask(context.Background(), "proj", runner, picked.Task.ID); err != nil {
+		t.Fatalf("claimPickedTask: %v", err)
+	}
+	claim := calls[len(calls)-2:]
+	if claim[0] != "start ts-bbb -p proj" || claim[1] != "label ts-bbb af-spawn -p proj" {
+		t.Errorf("claim ran prog %q, want start then label af-spawn", claim)
+	}
+}
+
+func TestPickTaskCancelledOrEmpty(t *testing.T) {
+	var calls []string
+	_, err := pickTask(context.Background(), "proj", fakeQueueRunner("ID  PRI  TITLE\nts-aaa  1  Only task\n", &calls), &fakeSelector{}, "", false)
+	if !errors.Is(err, errPickCancelled) {
+		t.Errorf("cancelled pick error = %v, want errPickCancelled", err)
+	}
+
+	_, err = pickTask(context.Background(), "proj", fakeQueueRunner("ID  PRI  TITLE\n", &calls), &fakeSelector{pick: "ts-aaa"}, "", false)
+	if err == nil || !strings.Contains(err.Error(), "no ready tasks") {
+		t.Errorf("empty queue error = %v, want no ready tasks", err)
+	}
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/baiirun/aetherflow/internal/client"
+	"github.com/baiirun/aetherflow/internal/term"
+	"github.com/spf13/cobra"
+)
+
+var spawnStopAllCmd = &cobra.Command{
+	Use:   "stop-all",
+	Short: "Stop every running spawned agent",
+	Long: `Send SIGTERM to every spawned agent the daemon's spawn registry lists as
+running, and mark each one exited. Useful for cleaning up detached spawns
+(af spawn -d) in one go.
+
+Use --dry-run to list the spawns that would be stopped without signalling
+them. Pool agents are not affected; use af drain or af pause for those.
+
+Requires a running daemon, since the spawn registry lives there.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+
+		c := client.New(resolveDaemonURL(cmd))
+		result, err := c.SpawnStopAll(dryRun)
+		if err != nil {
+			Fatal("%v", err)
+		}
+
+		if jsonOutput {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			_ = enc.Encode(result)
+		} else {
+			printSpawnStopAll(result)
+		}
+		for _, s := range result.Spawns {
+			if s.Error != "" {
+				os.Exit(1)
+			}
+		}
+	},
+}
+
+func printSpawnStopAll(result *client.SpawnStopAllResult) {
+	if len(result.Spawns) == 0 {
+		fmt.Println("no running spawns")
+		return
+	}
+	for _, s := range result.Spawns {
+		switch {
+		case result.DryRun:
+			fmt.Printf("would stop %s (pid %d)\n", term.Cyan(s.SpawnID), s.PID)
+		case s.Stopped:
+			fmt.Printf("stopped %s (pid %d)\n", term.Cyan(s.SpawnID), s.PID)
+		default:
+			fmt.Printf("%s %s (pid %d): %s\n", term.Red("failed"), term.Cyan(s.SpawnID), s.PID, s.Error)
+		}
+	}
+}
+
+func init() {
+	spawnCmd.AddCommand(spawnStopAllCmd)
+
+	f := spawnStopAllCmd.Flags()
+	f.Bool("dry-run", false, "List running spawns without stopping them")
+	f.Bool("json", false, "Output JSON")
+}
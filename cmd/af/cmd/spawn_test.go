@@ -0,0 +1,177 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/baiirun/aetherflow/internal/daemon"
+)
+
+// fakeSpawnDaemon records spawn registry requests sent to the daemon API.
+func fakeSpawnDaemon(t *testing.T) (*httptest.Server, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var calls []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"success":true}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), calls...)
+	}
+}
+
+// testSpawnOptions returns options for a registered, JSON-output spawn that
+// runs true.
+func testSpawnOptions(spawnID, daemonURL string) spawnOptions {
+	return spawnOptions{
+		SpawnID:    spawnID,
+		UserPrompt: "do it",
+		SpawnCmd:   "true",
+		Prompt:     "rendered prompt",
+		DaemonURL:  daemonURL,
+		Register:   true,
+		JSON:       true,
+	}
+}
+
+func TestRunForegroundRegistersByDefault(t *testing.T) {
+	t.Chdir(t.TempDir()) // spawns write PID files under .aetherflow/worktrees
+	if _, err := exec.LookPath("true"); err != nil {
+		t.Skip("true not on PATH")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	srv, calls := fakeSpawnDaemon(t)
+
+	runForeground(testSpawnOptions("spawn-test-0001", srv.URL))
+
+	got := calls()
+	want := []string{"POST /api/v1/spawns", "DELETE /api/v1/spawns/spawn-test-0001"}
+	if len(got) != len(want) {
+		t.Fatalf("daemon calls = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("call[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestRunForegroundNoRegisterSkipsDaemon(t *testing.T) {
+	t.Chdir(t.TempDir()) // spawns write PID files under .aetherflow/worktrees
+	if _, err := exec.LookPath("true"); err != nil {
+		t.Skip("true not on PATH")
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	srv, calls := fakeSpawnDaemon(t)
+
+	fg := testSpawnOptions("spawn-test-0002", srv.URL)
+	fg.Register = false
+	runForeground(fg)
+	bg := testSpawnOptions("spawn-test-0003", srv.URL)
+	bg.Register = false
+	runDetached(bg)
+
+	if got := calls(); len(got) != 0 {
+		t.Errorf("daemon calls = %v, want none with --no-register", got)
+	}
+}
+
+// deadDaemonURL returns a loopback URL with nothing listening on it.
+func deadDaemonURL(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := "http://" + ln.Addr().String()
+	_ = ln.Close()
+	return url
+}
+
+func TestCheckDaemonReachable(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	srv, _ := fakeSpawnDaemon(t)
+
+	if err := checkDaemonReachable(srv.URL); err != nil {
+		t.Errorf("checkDaemonReachable(running daemon) = %v, want nil", err)
+	}
+	err := checkDaemonReachable(deadDaemonURL(t))
+	if err == nil || !strings.Contains(err.Error(), "daemon is not running") {
+		t.Errorf("checkDaemonReachable(no daemon) = %v, want daemon is not running", err)
+	}
+}
+
+func TestRunForegroundRequireDaemonWithoutDaemon(t *testing.T) {
+	t.Chdir(t.TempDir()) // spawns write PID files under .aetherflow/worktrees
+	if _, err := exec.LookPath("true"); err != nil {
+		t.Skip("true not on PATH")
+	}
+	// Fatal calls os.Exit, so the --require-daemon spawn runs in a child
+	// copy of the test binary.
+	if url := os.Getenv("AF_TEST_REQUIRE_DAEMON_URL"); url != "" {
+		opts := testSpawnOptions("spawn-test-0005", url)
+		opts.RequireDaemon = true
+		runForeground(opts)
+		return
+	}
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	url := deadDaemonURL(t)
+
+	// The default proceeds unregistered: runForeground returns normally.
+	runForeground(testSpawnOptions("spawn-test-0004", url))
+
+	// With --require-daemon the same spawn exits non-zero.
+	child := exec.Command(os.Args[0], "-test.run=^TestRunForegroundRequireDaemonWithoutDaemon$")
+	child.Env = append(os.Environ(), "AF_TEST_REQUIRE_DAEMON_URL="+url)
+	out, err := child.CombinedOutput()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() == 0 {
+		t.Fatalf("child err = %v, want non-zero exit; output:\n%s", err, out)
+	}
+	if !strings.Contains(string(out), "daemon is not running") {
+		t.Errorf("child output = %q, want daemon is not running", out)
+	}
+}
+
+func TestSpawnResultJSONIncludesServerAndSession(t *testing.T) {
+	spawnCmd := daemon.EnsureAttachSpawnCmd("OPENAI_API_KEY=sk-123 opencode run --format json", "http://127.0.0.1:4096")
+	data, err := json.Marshal(newSpawnResult("spawn-test-0006", 4242, spawnCmd))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got["spawn_id"] != "spawn-test-0006" || got["pid"] != 4242.0 {
+		t.Errorf("spawn_id/pid = %v/%v, want spawn-test-0006/4242", got["spawn_id"], got["pid"])
+	}
+	if got["server_url"] != "http://127.0.0.1:4096" {
+		t.Errorf("server_url = %v, want http://127.0.0.1:4096", got["server_url"])
+	}
+	if got["spawn_cmd"] != "OPENAI_API_KEY=REDACTED opencode run --format json --attach http://127.0.0.1:4096" {
+		t.Errorf("spawn_cmd = %v, want the redacted launch command", got["spawn_cmd"])
+	}
+	if got["session_pending"] != true {
+		t.Errorf("session_pending = %v, want true before the session is known", got["session_pending"])
+	}
+	if _, ok := got["session_id"]; ok {
+		t.Errorf("session_id = %v, want it omitted while pending", got["session_id"])
+	}
+}
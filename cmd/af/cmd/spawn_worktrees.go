@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/baiirun/aetherflow/internal/client"
+	"github.com/baiirun/aetherflow/internal/daemon"
+	"github.com/baiirun/aetherflow/internal/term"
+	"github.com/spf13/cobra"
+)
+
+// spawnWorktreeDir is where spawned agents create their worktrees, relative
+// to the project root. Mirrors the path used in the spawn prompt template.
+var spawnWorktreeDir = filepath.Join(".aetherflow", "worktrees")
+
+var spawnWorktreesCmd = &cobra.Command{
+	Use:   "worktrees",
+	Short: "List git worktrees created by spawned agents",
+	Long: `List git worktrees under .aetherflow/worktrees that belong to spawned
+agents, along with whether their spawn is still running.
+
+A worktree is orphaned when its spawn is no longer running according to
+the daemon's spawn registry — the agent exited (or crashed) without
+cleaning up. Use --orphaned to show only those, and --prune to remove
+them with 'git worktree remove'. Worktrees with uncommitted changes are
+left in place; git refuses to remove them without --force.
+
+The registry only knows spawns registered since the daemon started, so
+--prune removes a worktree the daemon has no record of only when the PID
+file af spawn left next to it names a process that has exited.
+
+Requires a running daemon, since the spawn registry lives there.`,
+	Args: cobra.NoArgs,
+	Run:  runSpawnWorktrees,
+}
+
+func init() {
+	spawnCmd.AddCommand(spawnWorktreesCmd)
+
+	f := spawnWorktreesCmd.Flags()
+	f.Bool("orphaned", false, "Only show worktrees whose spawn is no longer running")
+	f.Bool("prune", false, "Remove orphaned worktrees (implies --orphaned)")
+	f.Bool("json", false, "Output JSON")
+}
+
+// spawnWorktree is a spawn-owned git worktree and the state of its spawn.
+type spawnWorktree struct {
+	Path       string `json:"path"`
+	SpawnID    string `json:"spawn_id"`
+	SpawnState string `json:"spawn_state,omitempty"` // empty when the daemon has no record
+	Orphaned   bool   `json:"orphaned"`
+}
+
+func runSpawnWorktrees(cmd *cobra.Command, _ []string) {
+	orphanedOnly, _ := cmd.Flags().GetBool("orphaned")
+	prune, _ := cmd.Flags().GetBool("prune")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	paths, err := listGitWorktrees()
+	if err != nil {
+		Fatal("listing git worktrees: %v", err)
+	}
+
+	c := client.New(resolveDaemonURL(cmd))
+	status, err := c.StatusFull()
+	if err != nil {
+		Fatal("reading spawn registry: %v", err)
+	}
+
+	worktrees := classifySpawnWorktrees(paths, status.Spawns, spawnIDPrefix(loadSessionsConfig(cmd)))
+	if orphanedOnly || prune {
+		filtered := worktrees[:0]
+		for _, wt := range worktrees {
+			if wt.Orphaned {
+				filtered = append(filtered, wt)
+			}
+		}
+		worktrees = filtered
+	}
+
+	if prune {
+		failed := 0
+		for _, wt := range worktrees {
+			if reason := pruneBlocker(wt); reason != "" {
+				fmt.Fprintf(os.Stderr, "af spawn worktrees: skipping %s: %s\n", wt.Path, reason)
+				continue
+			}
+			if out, err := runCommandOutput("git", "worktree", "remove", wt.Path); err != nil {
+				fmt.Fprintf(os.Stderr, "af spawn worktrees: failed to remove %s: %v %s\n", wt.Path, err, strings.TrimSpace(string(out)))
+				failed++
+				continue
+			}
+			_ = os.Remove(spawnPIDFile(wt.Path))
+			fmt.Printf("removed %s %s\n", term.Cyan(wt.SpawnID), term.Dim(wt.Path))
+		}
+		if failed > 0 {
+			os.Exit(1)
+		}
+		return
+	}
+
+	if jsonOutput {
+		if worktrees == nil {
+			worktrees = []spawnWorktree{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(worktrees)
+		return
+	}
+
+	if len(worktrees) == 0 {
+		fmt.Println(term.Dim("no spawn worktrees"))
+		return
+	}
+	for _, wt := range worktrees {
+		state := wt.SpawnState
+		if state == "" {
+			state = "unknown"
+		}
+		marker := ""
+		if wt.Orphaned {
+			marker = " " + term.Yellow("orphaned")
+		}
+		fmt.Printf("%s %s%s\n  %s\n", term.Cyan(wt.SpawnID), term.Dimf("(%s)", state), marker, term.Dim(wt.Path))
+	}
+}
+
+// spawnIDPrefix returns the spawn ID prefix cfg configures, or the default.
+func spawnIDPrefix(cfg daemon.Config) string {
+	if cfg.SpawnIDPrefix != "" {
+		return cfg.SpawnIDPrefix
+	}
+	return daemon.DefaultSpawnIDPrefix
+}
+
+// pruneBlocker explains why an orphaned worktree must not be removed, or
+// returns "" when it is safe to. A spawn the registry saw exit is safe. One
+// it has no record of may still be running (registered with a daemon that
+// has since restarted, or never registered), so it is safe only when its
+// PID file names a process that is gone.
+func pruneBlocker(wt spawnWorktree) string {
+	if wt.SpawnState != "" {
+		return ""
+	}
+	data, err := os.ReadFile(spawnPIDFile(wt.Path))
+	if err != nil {
+		return "the daemon has no record of the spawn and it left no PID file; remove it with git worktree remove once it has stopped"
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || pid <= 0 {
+		return fmt.Sprintf("unreadable PID file %s", spawnPIDFile(wt.Path))
+	}
+	if err := syscall.Kill(pid, 0); err != syscall.ESRCH {
+		return fmt.Sprintf("the daemon has no record of the spawn and its agent (pid %d) is still running", pid)
+	}
+	return ""
+}
+
+// listGitWorktrees returns the paths of all worktrees attached to the
+// repository in the current directory.
+func listGitWorktrees() ([]string, error) {
+	out, err := runCommandOutput("git", "worktree", "list", "--porcelain")
+	if err != nil {
+		return nil, err
+	}
+	return parseWorktreeList(out), nil
+}
+
+// parseWorktreeList extracts worktree paths from `git worktree list --porcelain`.
+func parseWorktreeList(out []byte) []string {
+	var paths []string
+	for _, line := range strings.Split(string(out), "\n") {
+		if path, ok := strings.CutPrefix(line, "worktree "); ok {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
+// classifySpawnWorktrees picks out worktrees created by spawned agents and
+// marks those whose spawn is not running as orphaned. A spawn matches a
+// worktree by its recorded worktree path, which also covers --pick
+// worktrees named after their task. Worktrees with no recorded spawn are
+// matched by spawn ID when their name carries prefix, the configured spawn
+// ID prefix; the rest are taken to be pool agents'.
+func classifySpawnWorktrees(paths []string, spawns []client.SpawnStatus, prefix string) []spawnWorktree {
+	byID := make(map[string]client.SpawnStatus, len(spawns))
+	byPath := make(map[string]client.SpawnStatus, len(spawns))
+	for _, s := range spawns {
+		byID[s.SpawnID] = s
+		if s.WorktreePath != "" {
+			byPath[filepath.Clean(s.WorktreePath)] = s
+		}
+	}
+
+	var result []spawnWorktree
+	for _, p := range paths {
+		p = filepath.Clean(p)
+		if !strings.HasSuffix(filepath.Dir(p), spawnWorktreeDir) {
+			continue
+		}
+		wt := spawnWorktree{Path: p, SpawnID: filepath.Base(p)}
+		s, ok := byPath[p]
+		if !ok {
+			if !strings.HasPrefix(wt.SpawnID, prefix) {
+				continue
+			}
+			s, ok = byID[wt.SpawnID]
+		}
+		if ok {
+			wt.SpawnID = s.SpawnID
+			wt.SpawnState = s.State
+		}
+		wt.Orphaned = wt.SpawnState != client.SpawnStateRunning
+		result = append(result, wt)
+	}
+	return result
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/baiirun/aetherflow/internal/client"
+	"github.com/baiirun/aetherflow/internal/daemon"
+)
+
+func TestParseWorktreeList(t *testing.T) {
+	out := []byte(`worktree /repo
+HEAD 1111111111111111111111111111111111111111
+branch refs/heads/main
+
+worktree /repo/.aetherflow/worktrees/spawn-ghost_wolf-a3f2
+HEAD 2222222222222222222222222222222222222222
+branch refs/heads/af/spawn-ghost_wolf-a3f2
+
+`)
+	got := parseWorktreeList(out)
+	want := []string{"/repo", "/repo/.aetherflow/worktrees/spawn-ghost_wolf-a3f2"}
+	if len(got) != len(want) {
+		t.Fatalf("parseWorktreeList = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("path[%d] = %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestClassifySpawnWorktreesIdentifiesOrphans(t *testing.T) {
+	paths := []string{
+		"/repo",
+		"/repo/.aetherflow/worktrees/spawn-running-0001",
+		"/repo/.aetherflow/worktrees/spawn-exited-0002",
+		"/repo/.aetherflow/worktrees/spawn-unknown-0003",
+		"/repo/.aetherflow/worktrees/ts-abc123", // pool agent worktree
+		"/elsewhere/spawn-stray-0004",           // not under .aetherflow/worktrees
+	}
+	spawns := []client.SpawnStatus{
+		{SpawnID: "spawn-running-0001", State: client.SpawnStateRunning, WorktreePath: "/repo/.aetherflow/worktrees/spawn-running-0001"},
+		{SpawnID: "spawn-exited-0002", State: client.SpawnStateExited},
+	}
+
+	got := classifySpawnWorktrees(paths, spawns, daemon.DefaultSpawnIDPrefix)
+	if len(got) != 3 {
+		t.Fatalf("classifySpawnWorktrees returned %d worktrees, want 3: %+v", len(got), got)
+	}
+
+	want := map[string]struct {
+		state    string
+		orphaned bool
+	}{
+		"spawn-running-0001": {client.SpawnStateRunning, false},
+		"spawn-exited-0002":  {client.SpawnStateExited, true},
+		"spawn-unknown-0003": {"", true},
+	}
+	for _, wt := range got {
+		w, ok := want[wt.SpawnID]
+		if !ok {
+			t.Errorf("unexpected worktree %+v", wt)
+			continue
+		}
+		if wt.SpawnState != w.state || wt.Orphaned != w.orphaned {
+			t.Errorf("%s = {state:%q orphaned:%v}, want {state:%q orphaned:%v}", wt.SpawnID, wt.SpawnState, wt.Orphaned, w.state, w.orphaned)
+		}
+	}
+}
+
+func TestClassifySpawnWorktreesUsesConfiguredPrefix(t *testing.T) {
+	paths := []string{
+		"/repo/.aetherflow/worktrees/agent-ghost_wolf-a3f2",
+		"/repo/.aetherflow/worktrees/spawn-ghost_wolf-b4e1",
+	}
+	got := classifySpawnWorktrees(paths, nil, "agent-")
+	if len(got) != 1 || got[0].SpawnID != "agent-ghost_wolf-a3f2" {
+		t.Fatalf("classifySpawnWorktrees = %+v, want only agent-ghost_wolf-a3f2", got)
+	}
+}
+
+func TestClassifySpawnWorktreesMatchesPickWorktreesByPath(t *testing.T) {
+	paths := []string{
+		"/repo/.aetherflow/worktrees/ts-picked", // af spawn --pick, named after the task
+		"/repo/.aetherflow/worktrees/ts-pool",   // pool agent worktree
+	}
+	spawns := []client.SpawnStatus{
+		{SpawnID: "spawn-ghost_wolf-a3f2", State: client.SpawnStateExited, WorktreePath: "/repo/.aetherflow/worktrees/ts-picked"},
+	}
+
+	got := classifySpawnWorktrees(paths, spawns, daemon.DefaultSpawnIDPrefix)
+	if len(got) != 1 {
+		t.Fatalf("classifySpawnWorktrees = %+v, want only the picked worktree", got)
+	}
+	wt := got[0]
+	if wt.Path != "/repo/.aetherflow/worktrees/ts-picked" || wt.SpawnID != "spawn-ghost_wolf-a3f2" {
+		t.Errorf("worktree = %+v, want ts-picked owned by spawn-ghost_wolf-a3f2", wt)
+	}
+	if wt.SpawnState != client.SpawnStateExited || !wt.Orphaned {
+		t.Errorf("worktree = {state:%q orphaned:%v}, want {state:%q orphaned:true}", wt.SpawnState, wt.Orphaned, client.SpawnStateExited)
+	}
+}
+
+func TestPruneBlockerChecksPIDOfUnknownSpawns(t *testing.T) {
+	dir := t.TempDir()
+	worktree := func(name string) spawnWorktree {
+		return spawnWorktree{Path: filepath.Join(dir, name), SpawnID: name, Orphaned: true}
+	}
+	writePID := func(name string, pid int) {
+		t.Helper()
+		if err := os.WriteFile(spawnPIDFile(filepath.Join(dir, name)), []byte(strconv.Itoa(pid)+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	exited := exec.Command("true")
+	if err := exited.Run(); err != nil {
+		t.Fatal(err)
+	}
+	writePID("spawn-dead-0001", exited.Process.Pid)
+	writePID("spawn-live-0002", os.Getpid())
+
+	known := worktree("spawn-exited-0003")
+	known.SpawnState = client.SpawnStateExited
+	if reason := pruneBlocker(known); reason != "" {
+		t.Errorf("exited spawn blocked: %s", reason)
+	}
+	if reason := pruneBlocker(worktree("spawn-dead-0001")); reason != "" {
+		t.Errorf("unknown spawn with a dead PID blocked: %s", reason)
+	}
+	if reason := pruneBlocker(worktree("spawn-live-0002")); reason == "" {
+		t.Error("unknown spawn with a live PID should not be pruned")
+	}
+	if reason := pruneBlocker(worktree("spawn-nopid-0004")); reason == "" {
+		t.Error("unknown spawn without a PID file should not be pruned")
+	}
+}
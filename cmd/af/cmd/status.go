@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/signal"
 	"regexp"
+	"strings"
 	"syscall"
 	"time"
 
@@ -24,7 +25,25 @@ Without arguments, shows the swarm overview:
 
 With an agent name, shows detailed agent info:
   Task details, uptime, last prog log, and recent tool call history
-  from the agent's event stream.
+  from the agent's event stream. --tool limits the history to calls of
+  the named tools, before --limit is applied.
+
+With --task, shows only the agent working that task, its queue position
+if it hasn't started yet, and its past sessions from the session registry.
+
+With --errors, shows the daemon's recent operational errors instead: spawn
+and respawn failures, fatal exits, exhausted crash retries, and orphaned
+tasks, newest first (up to --limit).
+
+With --diff, compares the current status against a snapshot saved earlier
+with 'af status --json > prev.json' and prints what changed: agents added
+or removed, queued tasks added or removed, and pool mode changes. The exit
+status is 0 when nothing changed, 1 when something did, and 2 on error,
+so polling scripts can act on change. Add --json for the changes as JSON.
+
+On a daemon that schedules several projects (the projects config key),
+the overview covers the primary project with a line per project below
+the pool header. Select another project with -p/--project.
 
 Use -w/--watch or -f/--follow for continuous monitoring (refreshes every 2s by default).
 
@@ -32,17 +51,50 @@ Requires a running daemon.`,
 	Args: cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		daemonURL := resolveDaemonURL(cmd)
+		project := daemonProject(cmd)
 		asJSON, _ := cmd.Flags().GetBool("json")
 		watch, _ := cmd.Flags().GetBool("watch")
 		follow, _ := cmd.Flags().GetBool("follow")
 		interval, _ := cmd.Flags().GetDuration("interval")
+		taskID, _ := cmd.Flags().GetString("task")
+		showErrors, _ := cmd.Flags().GetBool("errors")
+		diffPath, _ := cmd.Flags().GetString("diff")
+
+		if taskID != "" && len(args) == 1 {
+			fmt.Fprintf(os.Stderr, "error: --task and an agent name cannot be combined\n")
+			os.Exit(1)
+		}
+
+		// --diff is checked first: with --diff, exit 1 means the status
+		// changed, so a bad flag combination exits 2 as diff(1) does.
+		if diffPath != "" && (taskID != "" || len(args) == 1 || watch || follow || showErrors) {
+			fmt.Fprintf(os.Stderr, "error: --diff cannot be combined with an agent name, --task, --errors, or --watch\n")
+			os.Exit(2)
+		}
+
+		if showErrors {
+			if taskID != "" || len(args) == 1 || watch || follow {
+				fmt.Fprintf(os.Stderr, "error: --errors cannot be combined with an agent name, --task, --diff, or --watch\n")
+				os.Exit(1)
+			}
+			limit, _ := cmd.Flags().GetInt("limit")
+			runStatusErrors(client.New(daemonURL), limit, asJSON)
+			return
+		}
+
+		if diffPath != "" {
+			c := client.New(daemonURL)
+			c.SetProject(project)
+			runStatusDiff(c, diffPath, asJSON)
+			return
+		}
 
 		// Both --watch and --follow enable streaming; treat them as aliases.
 		streaming := watch || follow
 
-		c := client.New(daemonURL)
-
 		if !streaming {
+			c := client.New(daemonURL)
+			c.SetProject(project)
 			runStatusOnce(c, args, asJSON, cmd)
 			return
 		}
@@ -53,12 +105,20 @@ Requires a running daemon.`,
 			os.Exit(1)
 		}
 
+		// Watch mode polls repeatedly, so keep the connection open.
+		c := client.NewPersistent(daemonURL)
+		c.SetProject(project)
+		defer c.Close()
 		runStatusWatch(c, args, interval, cmd)
 	},
 }
 
 // runStatusOnce fetches and prints status a single time.
 func runStatusOnce(c *client.Client, args []string, asJSON bool, cmd *cobra.Command) {
+	if taskID, _ := cmd.Flags().GetString("task"); taskID != "" {
+		runStatusTask(c, taskID, asJSON, cmd)
+		return
+	}
 	if len(args) == 1 {
 		runStatusAgent(c, args[0], asJSON, cmd)
 		return
@@ -94,6 +154,8 @@ func runStatusWatch(c *client.Client, args []string, interval time.Duration, cmd
 
 	// Read flags once — they don't change between ticks.
 	limit, _ := cmd.Flags().GetInt("limit")
+	tools, _ := cmd.Flags().GetStringSlice("tool")
+	taskID, _ := cmd.Flags().GetString("task")
 
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
@@ -106,8 +168,17 @@ func runStatusWatch(c *client.Client, args []string, interval time.Duration, cmd
 	for {
 		clearScreen()
 
-		if len(args) == 1 {
-			detail, err := c.StatusAgent(args[0], limit)
+		if taskID != "" {
+			status, err := c.StatusFull()
+			if err != nil {
+				fmt.Printf("error: %v\n", err)
+			} else {
+				f := focusStatus(status, taskID)
+				f.Sessions = taskSessions(cmd, taskID)
+				printTaskFocus(f)
+			}
+		} else if len(args) == 1 {
+			detail, err := c.StatusAgent(args[0], limit, tools...)
 			if err != nil {
 				fmt.Printf("error: %v\n", err)
 			} else {
@@ -143,7 +214,8 @@ func clearScreen() {
 
 func runStatusAgent(c *client.Client, agentName string, asJSON bool, cmd *cobra.Command) {
 	limit, _ := cmd.Flags().GetInt("limit")
-	detail, err := c.StatusAgent(agentName, limit)
+	tools, _ := cmd.Flags().GetStringSlice("tool")
+	detail, err := c.StatusAgent(agentName, limit, tools...)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
@@ -171,27 +243,36 @@ const (
 	agentRowPrefix = 2 + colID + 1 + colTask + 1 + colUptime + 2 + colRole + 1
 )
 
-func printStatus(s *client.FullStatus) {
+// formatPoolHeader renders the status header line, e.g.
+// "Pool: 2/3 active  [draining]  (my-instance)".
+func formatPoolHeader(s *client.FullStatus) string {
 	active := len(s.Agents)
-	idle := s.PoolSize - active
+	var b strings.Builder
 
 	// Pool header: "Pool: 2/3 active" with utilization color.
 	utilization := term.Greenf("%d/%d active", active, s.PoolSize)
 	if active == 0 {
 		utilization = term.Dimf("%d/%d active", active, s.PoolSize)
 	}
-	fmt.Printf("%s %s", term.Bold("Pool:"), utilization)
+	fmt.Fprintf(&b, "%s %s", term.Bold("Pool:"), utilization)
 
 	if s.PoolMode != "" && s.PoolMode != "active" {
-		fmt.Printf("  %s", term.Yellowf("[%s]", s.PoolMode))
+		fmt.Fprintf(&b, "  %s", term.Yellowf("[%s]", s.PoolMode))
 	}
 	if policy := s.NormalizedSpawnPolicy(); policy != client.SpawnPolicyAuto {
-		fmt.Printf("  %s", term.Yellowf("[spawn:%s]", policy))
+		fmt.Fprintf(&b, "  %s", term.Yellowf("[spawn:%s]", policy))
 	}
-	if s.Project != "" {
-		fmt.Printf("  %s", term.Dimf("(%s)", s.Project))
+	if label := s.Label(); label != "" {
+		fmt.Fprintf(&b, "  %s", term.Dimf("(%s)", label))
 	}
-	fmt.Println()
+	return b.String()
+}
+
+func printStatus(s *client.FullStatus) {
+	active := len(s.Agents)
+	idle := s.PoolSize - active
+
+	fmt.Println(formatPoolHeader(s))
 
 	if active > 0 {
 		width := term.Width(100)
@@ -207,13 +288,14 @@ func printStatus(s *client.FullStatus) {
 			if summary == "" {
 				summary = a.TaskTitle
 			}
-			summary = truncate(stripANSI(summary), summaryMax)
+			summary = truncate(stripANSI(summary), max(summaryMax-branchWidth(a.Branch), 20))
 
-			fmt.Printf("  %s %s %s  %s %s\n",
+			fmt.Printf("  %s %s %s  %s %s%s\n",
 				term.PadRight(a.ID, colID, term.Cyan),
 				term.PadRight(a.TaskID, colTask, term.Blue),
 				term.PadLeft(uptime, colUptime, term.Green),
 				term.PadRight(a.Role, colRole, term.Magenta),
+				formatBranch(a.Branch),
 				term.Dim(quote(summary)),
 			)
 		}
@@ -225,6 +307,14 @@ func printStatus(s *client.FullStatus) {
 
 	fmt.Println()
 
+	if len(s.Projects) > 1 {
+		fmt.Println(term.Bold("Projects:"))
+		for _, p := range s.Projects {
+			fmt.Println("  " + formatProjectLine(p))
+		}
+		fmt.Println()
+	}
+
 	// Show spawned agents (outside the pool).
 	if len(s.Spawns) > 0 {
 		var running, exited int
@@ -247,36 +337,54 @@ func printStatus(s *client.FullStatus) {
 		}
 		for _, sp := range s.Spawns {
 			uptime := formatUptime(sp.SpawnTime)
-			prompt := truncate(stripANSI(sp.Prompt), promptMax)
+			prompt := truncate(stripANSI(sp.Prompt), max(promptMax-branchWidth(sp.Branch), 20))
 			nameColor := term.Cyan
 			uptimeColor := term.Green
 			if sp.State == client.SpawnStateExited {
 				nameColor = term.Dim
 				uptimeColor = term.Dim
 			}
-			fmt.Printf("  %s %s  %s\n",
+			fmt.Printf("  %s %s  %s%s\n",
 				term.PadRight(sp.SpawnID, colID, nameColor),
 				term.PadLeft(uptime, colUptime, uptimeColor),
+				formatBranch(sp.Branch),
 				term.Dim(quote(prompt)),
 			)
 		}
 		fmt.Println()
 	}
+	if s.SpawnsFull || s.SpawnsRejected > 0 {
+		fmt.Printf("%s %s\n\n", term.Bold("Spawns:"), term.Red(formatSpawnSaturation(s)))
+	}
 
 	if len(s.Queue) > 0 {
 		fmt.Printf("%s %s\n", term.Bold("Queue:"), term.Yellowf("%d pending", len(s.Queue)))
 		for _, t := range s.Queue {
 			title := truncate(stripANSI(t.Title), 40)
-			fmt.Printf("  %s %s  %s\n",
+			age := ""
+			if !t.ReadySince.IsZero() {
+				age = formatUptime(t.ReadySince)
+			}
+			reason := ""
+			if r := s.SkipReasons[t.ID]; r != "" {
+				reason = " " + term.Dimf("(%s)", r)
+			}
+			fmt.Printf("  %s %s %s  %s%s\n",
 				term.PadRight(t.ID, colTask, term.Blue),
 				term.Yellowf("P%d", t.Priority),
+				term.PadLeft(age, colUptime, term.Dim),
 				term.Yellow(quote(title)),
+				reason,
 			)
 		}
 	} else {
 		fmt.Printf("%s %s\n", term.Bold("Queue:"), term.Dim("empty"))
 	}
 
+	if s.NormalizedSpawnPolicy() == client.SpawnPolicyAuto {
+		fmt.Printf("%s %s\n", term.Bold("Throughput:"), formatThroughput(s))
+	}
+
 	if len(s.Errors) > 0 {
 		fmt.Println()
 		fmt.Printf("%s %s\n", term.Bold("Warnings:"), term.Redf("%d", len(s.Errors)))
@@ -286,12 +394,70 @@ func printStatus(s *client.FullStatus) {
 	}
 }
 
+// formatProjectLine summarizes one project of a multi-project daemon, e.g.
+// "beta  1/2 active  [paused]  3 queued  2 done in last hour".
+func formatProjectLine(p client.ProjectStatus) string {
+	var b strings.Builder
+	b.WriteString(term.PadRight(p.Project, colID, term.Blue))
+	active := len(p.Agents)
+	if active > 0 {
+		b.WriteString(term.Greenf(" %d/%d active", active, p.PoolSize))
+	} else {
+		b.WriteString(term.Dimf(" %d/%d active", active, p.PoolSize))
+	}
+	if p.PoolMode != "" && p.PoolMode != "active" {
+		fmt.Fprintf(&b, "  %s", term.Yellowf("[%s]", p.PoolMode))
+	}
+	fmt.Fprintf(&b, "  %s  %s", term.Yellowf("%d queued", len(p.Queue)), term.Dimf("%d done in last hour", p.CompletedLastHour))
+	return b.String()
+}
+
+// formatSpawnSaturation warns that the daemon's spawn registry is full, so
+// new af spawn agents are missing from status and logs.
+func formatSpawnSaturation(s *client.FullStatus) string {
+	msg := "registry full, new spawns are not tracked"
+	if !s.SpawnsFull {
+		msg = "registry was full, some spawns are not tracked"
+	}
+	if s.SpawnsRejected > 0 {
+		msg += fmt.Sprintf(" (%d rejected)", s.SpawnsRejected)
+	}
+	return msg
+}
+
+// formatThroughput summarizes completions in the last hour, e.g.
+// "3 done in last hour (4.5/h)".
+func formatThroughput(s *client.FullStatus) string {
+	if s.CompletedLastHour == 0 {
+		return term.Dim("0 done in last hour")
+	}
+	return term.Greenf("%d done in last hour", s.CompletedLastHour) + " " + term.Dimf("(%.1f/h)", s.RatePerHour)
+}
+
+// formatBranch renders an agent's git branch followed by a space, or ""
+// when the daemon didn't report one.
+func formatBranch(branch string) string {
+	if branch == "" {
+		return ""
+	}
+	return term.Cyan(branch) + " "
+}
+
+// branchWidth is the visible width formatBranch adds to a row.
+func branchWidth(branch string) int {
+	if branch == "" {
+		return 0
+	}
+	return len([]rune(branch)) + 1
+}
+
 // formatUptime returns a human-readable duration since the given spawn time.
 func formatUptime(spawnTime time.Time) string {
 	if spawnTime.IsZero() {
 		return "?"
 	}
-	d := time.Since(spawnTime)
+	// Clock skew can put the start in the future; count that as just started.
+	d := max(time.Since(spawnTime), 0)
 
 	switch {
 	case d < time.Minute:
@@ -364,6 +530,9 @@ func printAgentDetail(d *client.AgentDetail) {
 	fmt.Printf("  %s %s\n", term.Bold("Role:"), term.Magenta(d.Role))
 	fmt.Printf("  %s %d\n", term.Bold("PID:"), d.PID)
 	fmt.Printf("  %s %s\n", term.Bold("Uptime:"), term.Green(uptime))
+	if !d.SessionCaptured && d.SessionError != "" {
+		fmt.Printf("  %s %s %s\n", term.Bold("Session:"), term.Red("capture failed"), term.Dim(stripANSI(d.SessionError)))
+	}
 
 	if d.LastLog != "" {
 		fmt.Printf("  %s %s\n", term.Bold("Activity:"), term.Dim(quote(truncate(stripANSI(d.LastLog), 70))))
@@ -414,8 +583,10 @@ func formatRelativeTime(t time.Time) string {
 	if t.IsZero() {
 		return "?"
 	}
+	// A timestamp in the future comes from clock skew between the agent's
+	// host and ours; treat it, like anything under a second old, as now.
 	d := time.Since(t)
-	if d < 0 {
+	if d < time.Second {
 		return "now"
 	}
 
@@ -438,8 +609,12 @@ func init() {
 	rootCmd.AddCommand(statusCmd)
 
 	statusCmd.Flags().Bool("json", false, "Output raw JSON")
-	statusCmd.Flags().Int("limit", 20, "Max tool calls to show in agent detail view")
+	statusCmd.Flags().Int("limit", 20, "Max tool calls to show in agent detail view, or entries with --errors")
 	statusCmd.Flags().BoolP("watch", "w", false, "Continuously refresh the display")
 	statusCmd.Flags().BoolP("follow", "f", false, "Continuously refresh the display (alias for --watch)")
 	statusCmd.Flags().Duration("interval", 2*time.Second, "Refresh interval for streaming mode")
+	statusCmd.Flags().StringSlice("tool", nil, "In agent detail, show only calls to these tools (e.g. --tool bash,edit)")
+	statusCmd.Flags().String("task", "", "Show only the agent, queue position, and sessions for this task")
+	statusCmd.Flags().Bool("errors", false, "Show recent operational errors (spawn failures, fatal exits, exhausted retries)")
+	statusCmd.Flags().String("diff", "", "Show what changed since a saved 'af status --json' snapshot; exits 1 if anything did")
 }
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/baiirun/aetherflow/internal/client"
+	"github.com/baiirun/aetherflow/internal/term"
+)
+
+// statusDiff is what changed between a saved status snapshot and the
+// current one. Agents are matched by name and queued tasks by ID, so a
+// respawned agent shows as one removed and one added.
+type statusDiff struct {
+	ModeFrom      string               `json:"mode_from,omitempty"`
+	ModeTo        string               `json:"mode_to,omitempty"`
+	AgentsAdded   []client.AgentStatus `json:"agents_added,omitempty"`
+	AgentsRemoved []client.AgentStatus `json:"agents_removed,omitempty"`
+	QueueAdded    []client.Task        `json:"queue_added,omitempty"`
+	QueueRemoved  []client.Task        `json:"queue_removed,omitempty"`
+}
+
+// changed reports whether anything differs between the snapshots.
+func (d statusDiff) changed() bool {
+	return d.ModeFrom != d.ModeTo ||
+		len(d.AgentsAdded) > 0 || len(d.AgentsRemoved) > 0 ||
+		len(d.QueueAdded) > 0 || len(d.QueueRemoved) > 0
+}
+
+// diffStatus compares prev against cur.
+func diffStatus(prev, cur *client.FullStatus) statusDiff {
+	var d statusDiff
+	if prev.PoolMode != cur.PoolMode {
+		d.ModeFrom, d.ModeTo = prev.PoolMode, cur.PoolMode
+	}
+	agentID := func(a client.AgentStatus) string { return a.ID }
+	d.AgentsAdded = missingFrom(cur.Agents, prev.Agents, agentID)
+	d.AgentsRemoved = missingFrom(prev.Agents, cur.Agents, agentID)
+	taskID := func(t client.Task) string { return t.ID }
+	d.QueueAdded = missingFrom(cur.Queue, prev.Queue, taskID)
+	d.QueueRemoved = missingFrom(prev.Queue, cur.Queue, taskID)
+	return d
+}
+
+// missingFrom returns the items of a whose key is not in b, in a's order.
+func missingFrom[T any](a, b []T, key func(T) string) []T {
+	var out []T
+	for _, item := range a {
+		if !slices.ContainsFunc(b, func(other T) bool { return key(other) == key(item) }) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
+
+// writeStatusDiff prints d one change per line.
+func writeStatusDiff(w io.Writer, d statusDiff) {
+	if !d.changed() {
+		fmt.Fprintln(w, term.Dim("no changes"))
+		return
+	}
+	if d.ModeFrom != d.ModeTo {
+		fmt.Fprintf(w, "~ mode %s -> %s\n", d.ModeFrom, d.ModeTo)
+	}
+	for _, a := range d.AgentsRemoved {
+		fmt.Fprintf(w, "%s agent %s %s\n", term.Red("-"), term.Cyan(a.ID), term.Dim(a.TaskID))
+	}
+	for _, a := range d.AgentsAdded {
+		fmt.Fprintf(w, "%s agent %s %s\n", term.Green("+"), term.Cyan(a.ID), term.Dim(a.TaskID))
+	}
+	for _, t := range d.QueueRemoved {
+		fmt.Fprintf(w, "%s queue %s %s\n", term.Red("-"), term.Blue(t.ID), term.Dim(quote(truncate(stripANSI(t.Title), 40))))
+	}
+	for _, t := range d.QueueAdded {
+		fmt.Fprintf(w, "%s queue %s %s\n", term.Green("+"), term.Blue(t.ID), term.Dim(quote(truncate(stripANSI(t.Title), 40))))
+	}
+}
+
+// runStatusDiff compares the daemon's status against the snapshot saved at
+// prevPath and exits like diff(1): 0 when nothing changed, 1 when
+// something did, and 2 on trouble.
+func runStatusDiff(c *client.Client, prevPath string, asJSON bool) {
+	data, err := os.ReadFile(prevPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: reading snapshot: %v\n", err)
+		os.Exit(2)
+	}
+	var prev client.FullStatus
+	if err := json.Unmarshal(data, &prev); err != nil {
+		fmt.Fprintf(os.Stderr, "error: parsing snapshot %s: %v\n", prevPath, err)
+		os.Exit(2)
+	}
+	cur, err := c.StatusFull()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(2)
+	}
+
+	d := diffStatus(&prev, cur)
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(d)
+	} else {
+		writeStatusDiff(os.Stdout, d)
+	}
+	if d.changed() {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/baiirun/aetherflow/internal/client"
+)
+
+func TestDiffStatus(t *testing.T) {
+	prev := &client.FullStatus{
+		PoolMode: "active",
+		Agents: []client.AgentStatus{
+			{ID: "ghost_wolf", TaskID: "ts-1"},
+			{ID: "blur_knife", TaskID: "ts-2"},
+		},
+		Queue: []client.Task{{ID: "ts-3"}, {ID: "ts-4"}},
+	}
+	cur := &client.FullStatus{
+		PoolMode: "draining",
+		Agents: []client.AgentStatus{
+			{ID: "ghost_wolf", TaskID: "ts-1"},
+			{ID: "iron_moth", TaskID: "ts-3"},
+		},
+		Queue: []client.Task{{ID: "ts-4"}, {ID: "ts-5", Title: "Add retries"}},
+	}
+
+	d := diffStatus(prev, cur)
+	if !d.changed() {
+		t.Fatal("changed() = false, want true")
+	}
+	if d.ModeFrom != "active" || d.ModeTo != "draining" {
+		t.Errorf("mode = %q -> %q, want active -> draining", d.ModeFrom, d.ModeTo)
+	}
+	ids := func(agents []client.AgentStatus) []string {
+		var out []string
+		for _, a := range agents {
+			out = append(out, a.ID)
+		}
+		return out
+	}
+	taskIDs := func(tasks []client.Task) []string {
+		var out []string
+		for _, t := range tasks {
+			out = append(out, t.ID)
+		}
+		return out
+	}
+	if got := ids(d.AgentsAdded); len(got) != 1 || got[0] != "iron_moth" {
+		t.Errorf("agents added = %v, want [iron_moth]", got)
+	}
+	if got := ids(d.AgentsRemoved); len(got) != 1 || got[0] != "blur_knife" {
+		t.Errorf("agents removed = %v, want [blur_knife]", got)
+	}
+	if got := taskIDs(d.QueueAdded); len(got) != 1 || got[0] != "ts-5" {
+		t.Errorf("queue added = %v, want [ts-5]", got)
+	}
+	if got := taskIDs(d.QueueRemoved); len(got) != 1 || got[0] != "ts-3" {
+		t.Errorf("queue removed = %v, want [ts-3]", got)
+	}
+
+	var buf bytes.Buffer
+	writeStatusDiff(&buf, d)
+	for _, want := range []string{"mode active -> draining", "iron_moth", "blur_knife", "ts-5", `"Add retries"`} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("diff output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestDiffStatusUnchanged(t *testing.T) {
+	snap := &client.FullStatus{
+		PoolMode: "active",
+		Agents:   []client.AgentStatus{{ID: "ghost_wolf", TaskID: "ts-1", LastLog: "working"}},
+		Queue:    []client.Task{{ID: "ts-2"}},
+	}
+	// Fields the diff doesn't track, like an agent's last log, don't count.
+	cur := *snap
+	cur.Agents = []client.AgentStatus{{ID: "ghost_wolf", TaskID: "ts-1", LastLog: "testing"}}
+
+	d := diffStatus(snap, &cur)
+	if d.changed() {
+		t.Errorf("changed() = true for equivalent snapshots: %+v", d)
+	}
+	var buf bytes.Buffer
+	writeStatusDiff(&buf, d)
+	if !strings.Contains(buf.String(), "no changes") {
+		t.Errorf("output = %q, want no changes", buf.String())
+	}
+}
+
+func TestStatusDiffFlagConflictsExitTwo(t *testing.T) {
+	// The flag checks call os.Exit, so af status runs in a child copy of
+	// the test binary.
+	if args := os.Getenv("AF_TEST_STATUS_ARGS"); args != "" {
+		rootCmd.SetArgs(strings.Fields(args))
+		_ = rootCmd.Execute()
+		return
+	}
+	for _, args := range []string{
+		"status --errors --diff prev.json",
+		"status --task ts-1 --diff prev.json",
+		"status --watch --diff prev.json",
+	} {
+		child := exec.Command(os.Args[0], "-test.run=^TestStatusDiffFlagConflictsExitTwo$")
+		child.Env = append(os.Environ(), "AF_TEST_STATUS_ARGS="+args)
+		out, err := child.CombinedOutput()
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 2 {
+			t.Errorf("af %s: err = %v, want exit 2; output:\n%s", args, err, out)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/baiirun/aetherflow/internal/client"
+	"github.com/baiirun/aetherflow/internal/term"
+)
+
+func runStatusErrors(c *client.Client, limit int, asJSON bool) {
+	result, err := c.ErrorsRecent(limit)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(result)
+		return
+	}
+	printRecentErrors(result.Errors)
+}
+
+// printRecentErrors lists the recent-errors feed, newest first.
+func printRecentErrors(errs []client.OpError) {
+	if len(errs) == 0 {
+		fmt.Printf("%s %s\n", term.Bold("Recent errors:"), term.Dim("none"))
+		return
+	}
+	fmt.Printf("%s %d\n", term.Bold("Recent errors:"), len(errs))
+	for _, e := range errs {
+		level := term.Red(e.Level)
+		if e.Level == "warn" {
+			level = term.Yellow(e.Level)
+		}
+		subject := e.TaskID
+		if e.AgentID != "" {
+			subject += " " + term.Cyan(e.AgentID)
+		}
+		fmt.Printf("  %s %s %s %s  %s\n",
+			term.PadLeft(formatUptime(e.Time), colUptime, term.Dim),
+			level,
+			term.PadRight(e.Kind, 17, term.Magenta),
+			term.Blue(subject),
+			e.Message,
+		)
+	}
+}
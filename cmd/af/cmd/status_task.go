@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/baiirun/aetherflow/internal/client"
+	"github.com/baiirun/aetherflow/internal/sessions"
+	"github.com/baiirun/aetherflow/internal/term"
+	"github.com/spf13/cobra"
+)
+
+// taskFocus is the status view narrowed to a single task: the agents
+// working it, its place in the queue, and its past sessions.
+type taskFocus struct {
+	TaskID        string               `json:"task_id"`
+	Agents        []client.AgentStatus `json:"agents"`
+	QueuePosition int                  `json:"queue_position,omitempty"` // 1-based; 0 when not queued
+	QueuedTask    *client.Task         `json:"queued_task,omitempty"`
+	SkipReason    string               `json:"skip_reason,omitempty"` // why the queued task isn't running
+	Sessions      []sessions.Record    `json:"sessions,omitempty"`
+}
+
+// found reports whether the task appears anywhere in the focused view.
+func (f taskFocus) found() bool {
+	return len(f.Agents) > 0 || f.QueuePosition > 0 || len(f.Sessions) > 0
+}
+
+// focusStatus filters a full status down to the agents and queue entry for
+// taskID. A task normally has one agent, but a respawn can briefly overlap
+// with the agent it replaces, so all matches are kept.
+func focusStatus(s *client.FullStatus, taskID string) taskFocus {
+	f := taskFocus{TaskID: taskID, Agents: []client.AgentStatus{}}
+	for _, a := range s.Agents {
+		if a.TaskID == taskID {
+			f.Agents = append(f.Agents, a)
+		}
+	}
+	for i, t := range s.Queue {
+		if t.ID == taskID {
+			f.QueuePosition = i + 1
+			task := t
+			f.QueuedTask = &task
+			f.SkipReason = s.SkipReasons[taskID]
+			break
+		}
+	}
+	return f
+}
+
+// taskSessions returns session registry records whose work ref is taskID.
+// Registry errors are non-fatal: the live view is still useful without
+// history.
+func taskSessions(cmd *cobra.Command, taskID string) []sessions.Record {
+	store, err := openSessionStore(cmd)
+	if err != nil {
+		return nil
+	}
+	recs, err := store.List()
+	if err != nil {
+		return nil
+	}
+	var matched []sessions.Record
+	for _, r := range recs {
+		if r.WorkRef == taskID {
+			matched = append(matched, r)
+		}
+	}
+	return matched
+}
+
+func runStatusTask(c *client.Client, taskID string, asJSON bool, cmd *cobra.Command) {
+	status, err := c.StatusFull()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+
+	f := focusStatus(status, taskID)
+	f.Sessions = taskSessions(cmd, taskID)
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		_ = enc.Encode(f)
+		return
+	}
+
+	if !f.found() {
+		fmt.Fprintf(os.Stderr, "error: task %s is not running, queued, or in the session registry\n", taskID)
+		os.Exit(1)
+	}
+	printTaskFocus(f)
+}
+
+func printTaskFocus(f taskFocus) {
+	fmt.Printf("%s %s\n", term.Bold("Task:"), term.Blue(f.TaskID))
+
+	switch {
+	case len(f.Agents) > 0:
+		fmt.Println()
+		for _, a := range f.Agents {
+			summary := a.LastLog
+			if summary == "" {
+				summary = a.TaskTitle
+			}
+			fmt.Printf("  %s %s  %s %s\n",
+				term.PadRight(a.ID, colID, term.Cyan),
+				term.PadLeft(formatUptime(a.SpawnTime), colUptime, term.Green),
+				term.PadRight(a.Role, colRole, term.Magenta),
+				term.Dim(quote(truncate(stripANSI(summary), 60))),
+			)
+		}
+	case f.QueuedTask != nil:
+		reason := ""
+		if f.SkipReason != "" {
+			reason = " " + term.Dimf("(%s)", f.SkipReason)
+		}
+		fmt.Printf("  %s %s  %s%s\n",
+			term.Yellowf("queued #%d", f.QueuePosition),
+			term.Yellowf("P%d", f.QueuedTask.Priority),
+			term.Yellow(quote(truncate(stripANSI(f.QueuedTask.Title), 40))),
+			reason,
+		)
+	default:
+		fmt.Printf("  %s\n", term.Dim("no agent running"))
+	}
+
+	if len(f.Sessions) > 0 {
+		fmt.Println()
+		fmt.Printf("%s %d\n", term.Bold("Sessions:"), len(f.Sessions))
+		for _, r := range f.Sessions {
+			updated := r.UpdatedAt
+			if updated.IsZero() {
+				updated = r.CreatedAt
+			}
+			fmt.Printf("  %s %s  %s\n",
+				term.Cyan(r.SessionID),
+				term.Dimf("(%s)", r.Status),
+				term.Dim(humanSince(updated)),
+			)
+		}
+	}
+}
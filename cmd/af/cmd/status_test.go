@@ -1,8 +1,11 @@
 package cmd
 
 import (
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/baiirun/aetherflow/internal/client"
 )
 
 func TestFormatUptime(t *testing.T) {
@@ -41,6 +44,11 @@ func TestFormatUptime(t *testing.T) {
 			spawnTime: time.Time{},
 			want:      "?",
 		},
+		{
+			name:      "future from clock skew",
+			spawnTime: time.Now().Add(5 * time.Second),
+			want:      "0s",
+		},
 	}
 
 	for _, tt := range tests {
@@ -96,6 +104,9 @@ func TestFormatRelativeTime(t *testing.T) {
 		{"hours ago", time.Now().Add(-2 * time.Hour), "2h ago"},
 		{"hours and minutes", time.Now().Add(-1*time.Hour - 30*time.Minute), "1h30m"},
 		{"zero time", time.Time{}, "?"},
+		{"just now", time.Now(), "now"},
+		{"seconds in the future", time.Now().Add(5 * time.Second), "now"},
+		{"hours in the future", time.Now().Add(3 * time.Hour), "now"},
 	}
 
 	for _, tt := range tests {
@@ -213,3 +224,65 @@ func TestStripANSI(t *testing.T) {
 		})
 	}
 }
+
+func TestFocusStatusSelectsAgentByTaskID(t *testing.T) {
+	s := &client.FullStatus{
+		Agents: []client.AgentStatus{
+			{ID: "ghost_wolf", TaskID: "ts-aaa"},
+			{ID: "iron_owl", TaskID: "ts-bbb"},
+		},
+		Queue: []client.Task{
+			{ID: "ts-ccc", Priority: 1, Title: "First"},
+			{ID: "ts-ddd", Priority: 2, Title: "Second"},
+		},
+	}
+
+	f := focusStatus(s, "ts-bbb")
+	if len(f.Agents) != 1 || f.Agents[0].ID != "iron_owl" {
+		t.Errorf("agents = %+v, want only iron_owl", f.Agents)
+	}
+	if f.QueuePosition != 0 {
+		t.Errorf("QueuePosition = %d, want 0 for a running task", f.QueuePosition)
+	}
+
+	f = focusStatus(s, "ts-ddd")
+	if len(f.Agents) != 0 {
+		t.Errorf("agents = %+v, want none for a queued task", f.Agents)
+	}
+	if f.QueuePosition != 2 || f.QueuedTask == nil || f.QueuedTask.Title != "Second" {
+		t.Errorf("queue = %d %+v, want position 2 (Second)", f.QueuePosition, f.QueuedTask)
+	}
+
+	if f := focusStatus(s, "ts-missing"); f.found() {
+		t.Errorf("focusStatus(ts-missing) = %+v, want not found", f)
+	}
+}
+
+func TestFormatPoolHeaderShowsInstance(t *testing.T) {
+	s := &client.FullStatus{PoolSize: 3, Project: "myproj", Instance: "east", SpawnPolicy: client.SpawnPolicyAuto}
+	if got := stripANSI(formatPoolHeader(s)); !strings.Contains(got, "(east · myproj)") {
+		t.Errorf("header = %q, want instance and project label", got)
+	}
+
+	s.Instance = "myproj"
+	if got := stripANSI(formatPoolHeader(s)); !strings.Contains(got, "(myproj)") || strings.Contains(got, "·") {
+		t.Errorf("header = %q, want project label only when instance matches", got)
+	}
+}
+
+func TestFormatProjectLine(t *testing.T) {
+	p := client.ProjectStatus{
+		Project:           "beta",
+		PoolSize:          2,
+		PoolMode:          "paused",
+		Agents:            []client.AgentStatus{{ID: "ghost_wolf"}},
+		Queue:             []client.Task{{ID: "ts-1"}, {ID: "ts-2"}, {ID: "ts-3"}},
+		CompletedLastHour: 4,
+	}
+	got := stripANSI(formatProjectLine(p))
+	for _, want := range []string{"beta", "1/2 active", "[paused]", "3 queued", "4 done in last hour"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("line = %q, missing %q", got, want)
+		}
+	}
+}
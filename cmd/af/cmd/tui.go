@@ -3,7 +3,9 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 
+	"github.com/baiirun/aetherflow/internal/daemon"
 	"github.com/baiirun/aetherflow/internal/tui"
 	"github.com/spf13/cobra"
 )
@@ -28,12 +30,32 @@ Navigation:
   ?      Help
   q      Back / quit
 
+Colors come from tui_theme and tui_colors in the config file, or --theme.
+Built-in themes: default (dark terminals), light, and high-contrast.
+
 Requires a running daemon.`,
 	Run: func(cmd *cobra.Command, args []string) {
 		daemonURL := resolveDaemonURL(cmd)
 
+		configPath, _ := cmd.Flags().GetString("config")
+		if configPath == "" {
+			configPath = ".aetherflow.yaml"
+		}
+		var fileCfg daemon.Config
+		_ = daemon.LoadConfigFile(configPath, &fileCfg) // ignore missing file
+		themeName := fileCfg.TUITheme
+		if cmd.Flags().Changed("theme") {
+			themeName, _ = cmd.Flags().GetString("theme")
+		}
+		theme, err := tui.ResolveTheme(themeName, fileCfg.TUIColors)
+		if err != nil {
+			Fatal("%v", err)
+		}
+
 		cfg := tui.Config{
 			DaemonURL: daemonURL,
+			Project:   daemonProject(cmd),
+			Theme:     theme,
 		}
 
 		if err := tui.Run(cfg); err != nil {
@@ -45,4 +67,5 @@ Requires a running daemon.`,
 
 func init() {
 	rootCmd.AddCommand(tuiCmd)
+	tuiCmd.Flags().String("theme", "", "Color theme: "+strings.Join(tui.ThemeNames(), ", "))
 }
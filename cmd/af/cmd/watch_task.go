@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/baiirun/aetherflow/internal/client"
+	"github.com/baiirun/aetherflow/internal/term"
+	"github.com/spf13/cobra"
+)
+
+var watchTaskCmd = &cobra.Command{
+	Use:   "watch-task <task-id>",
+	Short: "Follow a task's tool calls across agent respawns",
+	Long: `Stream the tool calls of whichever pool agent is working a task.
+
+A crashed task respawns under a new agent name, which af status <agent>
+cannot follow. watch-task resolves the task's current agent on every
+refresh and keeps streaming after a respawn. It exits when the task
+finishes (completed, failed, killed, or out of retries).`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		interval, _ := cmd.Flags().GetDuration("interval")
+		tools, _ := cmd.Flags().GetStringSlice("tool")
+		if interval < minWatchInterval {
+			Fatal("--interval must be at least %s", minWatchInterval)
+		}
+		runWatchTask(newDaemonClient(cmd), args[0], interval, tools)
+	},
+}
+
+// taskFollower tracks what watch-task has already printed for a task.
+type taskFollower struct {
+	taskID  string
+	agentID string    // agent the last refresh resolved, "" when none
+	outcome string    // last reported history outcome
+	since   time.Time // newest tool call printed for agentID
+}
+
+// finishedOutcomes are history outcomes after which the task gets no more
+// agents, so there is nothing left to follow.
+var finishedOutcomes = map[string]bool{
+	"completed":           true,
+	"failed":              true,
+	"crashed-max-retries": true,
+	"retired":             true,
+	"killed":              true,
+}
+
+func runWatchTask(c *client.Client, taskID string, interval time.Duration, tools []string) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	f := &taskFollower{taskID: taskID}
+	for {
+		if done := f.refresh(c, tools); done {
+			return
+		}
+		select {
+		case <-sigCh:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// refresh resolves the task's agent, announces agent changes, and prints
+// tool calls finished since the last refresh. It reports true once the
+// task is finished.
+func (f *taskFollower) refresh(c *client.Client, tools []string) bool {
+	ta, err := c.TaskAgent(f.taskID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		return false
+	}
+
+	if ta.AgentID != f.agentID {
+		if ta.Running {
+			fmt.Printf("%s %s now on %s %s\n",
+				term.Bold("af watch-task:"), term.Blue(f.taskID), term.Cyan(ta.AgentID),
+				term.Dimf("(pid %d, attempt %d)", ta.PID, ta.Attempts))
+		}
+		f.agentID = ta.AgentID
+		f.since = time.Time{}
+	}
+
+	if ta.Running {
+		detail, err := c.StatusAgent(ta.AgentID, 50, tools...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		} else {
+			f.since = printNewToolCalls(detail.ToolCalls, f.since)
+		}
+	}
+
+	if !ta.Running && ta.Outcome != f.outcome {
+		switch {
+		case ta.Outcome == "":
+			fmt.Printf("%s %s has no agent yet\n", term.Bold("af watch-task:"), term.Blue(f.taskID))
+		case ta.Outcome == "running":
+			fmt.Printf("%s %s waiting to respawn\n", term.Bold("af watch-task:"), term.Blue(f.taskID))
+		default:
+			fmt.Printf("%s %s finished: %s\n", term.Bold("af watch-task:"), term.Blue(f.taskID),
+				outcomeColor(ta.Outcome)(ta.Outcome))
+		}
+	}
+	f.outcome = ta.Outcome
+	return !ta.Running && finishedOutcomes[ta.Outcome]
+}
+
+// printNewToolCalls prints finished calls newer than since, oldest first,
+// and returns the timestamp of the newest one printed. A call's timestamp
+// is that of its latest event, so a call still running is skipped now and
+// printed on the refresh after it completes.
+func printNewToolCalls(calls []client.ToolCall, since time.Time) time.Time {
+	for _, tc := range calls {
+		if !tc.Timestamp.After(since) {
+			continue
+		}
+		if tc.Status != "completed" && tc.Status != "error" {
+			continue
+		}
+		dur := ""
+		if tc.DurationMs > 0 {
+			dur = term.Dimf(" (%dms)", tc.DurationMs)
+		}
+		fmt.Printf("  %s  %s %s%s\n",
+			term.Dim(tc.Timestamp.Local().Format("15:04:05")),
+			term.PadRight(tc.Tool, 10, term.Cyan),
+			truncate(stripANSI(tc.Input), 80),
+			dur,
+		)
+		since = tc.Timestamp
+	}
+	return since
+}
+
+func init() {
+	rootCmd.AddCommand(watchTaskCmd)
+	watchTaskCmd.Flags().Duration("interval", 2*time.Second, "Refresh interval")
+	watchTaskCmd.Flags().StringSlice("tool", nil, "Show only calls to these tools (e.g. --tool bash,edit)")
+}
@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/baiirun/aetherflow/internal/daemon"
+	"github.com/baiirun/aetherflow/internal/sessions"
+	"github.com/baiirun/aetherflow/internal/term"
+	"github.com/spf13/cobra"
+)
+
+// Where a resolved setting came from, in priority order.
+const (
+	sourceFlag    = "flag"
+	sourceEnv     = "env"
+	sourceFile    = "file"
+	sourceDefault = "default"
+)
+
+var whereamiCmd = &cobra.Command{
+	Use:   "whereami",
+	Short: "Show which config file, session registry, and daemon are in use",
+	Long: `Print the files, directories, and URLs af resolves from the current
+directory and flags, and where each came from: a flag, the environment,
+the config file, or the built-in default.
+
+  config       the config file read (--config, or .aetherflow.yaml here)
+  session_dir  the session registry (--session-dir, session_dir, or the
+               user config dir, which $XDG_CONFIG_HOME moves)
+  daemon_url   the daemon af commands talk to (the daemon listens on this
+               HTTP address; there is no socket file)
+  server_url   the opencode server agents attach to
+  event_sink   the JSONL event log, if event_sink is set (the daemon logs
+               to stderr; there is no log directory)`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, _ []string) {
+		asJSON, _ := cmd.Flags().GetBool("json")
+		entries, err := resolveWhereami(cmd)
+		if err != nil {
+			Fatal("%v", err)
+		}
+		if asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			_ = enc.Encode(entries)
+			return
+		}
+		writeWhereami(os.Stdout, entries)
+	},
+}
+
+// whereamiEntry is one resolved setting. Note flags something the user
+// likely wants to know, such as a config file that doesn't exist.
+type whereamiEntry struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+	Note   string `json:"note,omitempty"`
+}
+
+// resolveWhereami resolves each setting the way the commands that use it
+// do, recording its source.
+func resolveWhereami(cmd *cobra.Command) ([]whereamiEntry, error) {
+	configPath, _ := cmd.Flags().GetString("config")
+	configEntry := whereamiEntry{Name: "config", Value: configPath, Source: sourceFlag}
+	if configPath == "" {
+		configPath = ".aetherflow.yaml"
+		configEntry.Value, configEntry.Source = configPath, sourceDefault
+	}
+	if abs, err := filepath.Abs(configPath); err == nil {
+		configEntry.Value = abs
+	}
+	if _, err := os.Stat(configPath); os.IsNotExist(err) {
+		configEntry.Note = "not found; defaults apply"
+	}
+	var fileCfg daemon.Config
+	if err := daemon.LoadConfigFile(configPath, &fileCfg); err != nil {
+		return nil, err
+	}
+
+	sessionEntry := whereamiEntry{Name: "session_dir"}
+	if dir, _ := cmd.Flags().GetString("session-dir"); dir != "" {
+		sessionEntry.Value, sessionEntry.Source = dir, sourceFlag
+	} else if fileCfg.SessionDir != "" {
+		sessionEntry.Value, sessionEntry.Source = fileCfg.SessionDir, sourceFile
+	} else {
+		dir, err := sessions.DefaultDir()
+		if err != nil {
+			return nil, err
+		}
+		sessionEntry.Value, sessionEntry.Source = dir, sourceDefault
+		if os.Getenv("XDG_CONFIG_HOME") != "" {
+			sessionEntry.Source = sourceEnv
+		}
+	}
+
+	daemonURL, daemonSource := resolveDaemonURLSource(cmd)
+
+	serverEntry := whereamiEntry{Name: "server_url", Value: fileCfg.ServerURL, Source: sourceFile}
+	if serverEntry.Value == "" {
+		serverEntry.Value, serverEntry.Source = daemon.DefaultServerURL, sourceDefault
+	}
+
+	sinkEntry := whereamiEntry{Name: "event_sink", Value: fileCfg.EventSink, Source: sourceFile}
+	if sinkEntry.Value == "" {
+		sinkEntry.Source, sinkEntry.Note = sourceDefault, "disabled"
+	}
+
+	return []whereamiEntry{
+		configEntry,
+		sessionEntry,
+		{Name: "daemon_url", Value: daemonURL, Source: daemonSource},
+		serverEntry,
+		sinkEntry,
+	}, nil
+}
+
+func writeWhereami(w io.Writer, entries []whereamiEntry) {
+	for _, e := range entries {
+		note := ""
+		if e.Note != "" {
+			note = " " + term.Dimf("(%s)", e.Note)
+		}
+		value := e.Value
+		if value == "" {
+			value = "-"
+		}
+		fmt.Fprintf(w, "%-12s %s %s%s\n", e.Name, value, term.Dimf("[%s]", e.Source), note)
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(whereamiCmd)
+	whereamiCmd.Flags().String("session-dir", "", "Session registry directory (overrides config/default)")
+	whereamiCmd.Flags().Bool("json", false, "Output as JSON")
+}
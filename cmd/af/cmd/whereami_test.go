@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/baiirun/aetherflow/internal/daemon"
+	"github.com/baiirun/aetherflow/internal/protocol"
+)
+
+func TestResolveWhereami(t *testing.T) {
+	fileConfig := "project: billing\nspawn_policy: auto\nsession_dir: /srv/af/sessions\nserver_url: http://127.0.0.1:5000\nevent_sink: /srv/af/events.jsonl\n"
+
+	tests := []struct {
+		name       string
+		config     string // config file contents; empty means no --config
+		project    string
+		sessionDir string
+		xdg        string
+		want       map[string]whereamiEntry
+	}{
+		{
+			name: "defaults",
+			xdg:  "/home/u/.config",
+			want: map[string]whereamiEntry{
+				"session_dir": {Value: "/home/u/.config/aetherflow/sessions", Source: sourceEnv},
+				"daemon_url":  {Value: protocol.DefaultDaemonURL, Source: sourceDefault},
+				"server_url":  {Value: daemon.DefaultServerURL, Source: sourceDefault},
+				"event_sink":  {Value: "", Source: sourceDefault},
+			},
+		},
+		{
+			name:   "config file",
+			config: fileConfig,
+			want: map[string]whereamiEntry{
+				"session_dir": {Value: "/srv/af/sessions", Source: sourceFile},
+				"daemon_url":  {Value: protocol.DaemonURLFor("billing"), Source: sourceFile},
+				"server_url":  {Value: "http://127.0.0.1:5000", Source: sourceFile},
+				"event_sink":  {Value: "/srv/af/events.jsonl", Source: sourceFile},
+			},
+		},
+		{
+			name:       "flags override the config file",
+			config:     fileConfig,
+			project:    "payroll",
+			sessionDir: "/tmp/sessions",
+			want: map[string]whereamiEntry{
+				"session_dir": {Value: "/tmp/sessions", Source: sourceFlag},
+				"daemon_url":  {Value: protocol.DaemonURLFor("payroll"), Source: sourceFlag},
+				"server_url":  {Value: "http://127.0.0.1:5000", Source: sourceFile},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("XDG_CONFIG_HOME", tt.xdg)
+			configPath := ""
+			if tt.config != "" {
+				configPath = writeResolveConfig(t, tt.config)
+			} else {
+				t.Chdir(t.TempDir()) // no .aetherflow.yaml in the working directory
+			}
+			cmd := newResolveTestCommand(t, configPath)
+			cmd.Flags().String("session-dir", "", "")
+			if tt.project != "" {
+				if err := cmd.Flags().Set("project", tt.project); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.sessionDir != "" {
+				if err := cmd.Flags().Set("session-dir", tt.sessionDir); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			entries, err := resolveWhereami(cmd)
+			if err != nil {
+				t.Fatalf("resolveWhereami: %v", err)
+			}
+			got := make(map[string]whereamiEntry, len(entries))
+			for _, e := range entries {
+				got[e.Name] = e
+			}
+
+			config := got["config"]
+			if tt.config != "" {
+				if config.Value != configPath || config.Source != sourceFlag || config.Note != "" {
+					t.Errorf("config = %+v, want %s from flag", config, configPath)
+				}
+			} else if filepath.Base(config.Value) != ".aetherflow.yaml" || config.Source != sourceDefault || config.Note == "" {
+				t.Errorf("config = %+v, want missing default .aetherflow.yaml", config)
+			}
+			for name, want := range tt.want {
+				if g := got[name]; g.Value != want.Value || g.Source != want.Source {
+					t.Errorf("%s = %q [%s], want %q [%s]", name, g.Value, g.Source, want.Value, want.Source)
+				}
+			}
+		})
+	}
+}
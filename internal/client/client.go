@@ -4,6 +4,7 @@ package client
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -12,6 +13,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/baiirun/aetherflow/internal/protocol"
@@ -22,11 +24,103 @@ type Client struct {
 	baseURL    string
 	authToken  string
 	httpClient *http.Client
+
+	// retries is how many times a request is retried after a transient
+	// connection error, waiting retryBackoff and doubling it between tries.
+	// Zero fails fast. See SetRetries.
+	retries      int
+	retryBackoff time.Duration
+
+	// project selects one project of a multi-project daemon for status,
+	// history, and pool requests. Empty means the primary project. See
+	// SetProject.
+	project string
 }
 
+// persistentIdleTimeout is how long a persistent client keeps an idle
+// connection open. Shorter than the daemon's 60s IdleTimeout so the client
+// gives up the connection before the server closes it underneath us.
+const persistentIdleTimeout = 30 * time.Second
+
+// Default retry policy for persistent clients: up to 3 retries, 200ms then
+// 400ms then 800ms apart, so a daemon restart is ridden out within one 2s
+// watch interval.
+const (
+	persistentRetries      = 3
+	persistentRetryBackoff = 200 * time.Millisecond
+)
+
 // New creates a new client targeting the given daemon URL.
 // If daemonURL is empty, the default daemon URL is used.
+//
+// Each call opens a fresh connection that is closed when the call returns,
+// and a failed connection is reported immediately, which suits one-shot CLI
+// commands. Use NewPersistent for polling loops.
 func New(daemonURL string) *Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DisableKeepAlives = true
+	return newClient(daemonURL, transport)
+}
+
+// NewPersistent creates a client that keeps its connection to the daemon
+// open and reuses it across calls. Use it for watch loops and the TUI, which
+// poll every couple of seconds. If the connection is dropped (e.g. the daemon
+// restarts), the call retries with backoff before giving up; see SetRetries.
+// Call Close when done.
+func NewPersistent(daemonURL string) *Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = 2
+	transport.IdleConnTimeout = persistentIdleTimeout
+	c := newClient(daemonURL, transport)
+	c.SetRetries(persistentRetries, persistentRetryBackoff)
+	return c
+}
+
+// SetRetries sets how many times a request is retried after a transient
+// connection error (daemon refusing connections, or dropping one mid-request),
+// waiting backoff before the first retry and doubling it after each.
+// n = 0 disables retries.
+func (c *Client) SetRetries(n int, backoff time.Duration) {
+	c.retries = max(0, n)
+	c.retryBackoff = backoff
+}
+
+// SetProject scopes status, history, and pool requests to one project of a
+// daemon that schedules several (see the projects config key). An empty
+// project means the daemon's primary project.
+func (c *Client) SetProject(project string) {
+	c.project = project
+}
+
+// scoped adds the client's project selector to a GET path.
+func (c *Client) scoped(path string) string {
+	if c.project == "" {
+		return path
+	}
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + "project=" + url.QueryEscape(c.project)
+}
+
+// scopedBody adds the client's project selector to a pool request body.
+// It returns nil for an empty body and no project, which the daemon reads
+// as the whole pool of the primary project.
+func (c *Client) scopedBody(body map[string]string) any {
+	if c.project != "" {
+		if body == nil {
+			body = map[string]string{}
+		}
+		body["project"] = c.project
+	}
+	if body == nil {
+		return nil
+	}
+	return body
+}
+
+func newClient(daemonURL string, transport http.RoundTripper) *Client {
 	if daemonURL == "" {
 		daemonURL = protocol.DefaultDaemonURL
 	}
@@ -34,11 +128,24 @@ func New(daemonURL string) *Client {
 		baseURL:   daemonURL,
 		authToken: loadAuthToken(daemonURL),
 		httpClient: &http.Client{
-			Timeout: 10 * time.Second,
+			Timeout:   10 * time.Second,
+			Transport: transport,
 		},
 	}
 }
 
+// Close releases any idle connections held by the client.
+func (c *Client) Close() {
+	c.httpClient.CloseIdleConnections()
+}
+
+// closeBody drains and closes a response body. A connection can only be
+// reused once its body has been read to EOF.
+func closeBody(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	_ = resp.Body.Close()
+}
+
 // Response is the JSON response envelope from the daemon API.
 type Response struct {
 	Success bool            `json:"success"`
@@ -62,11 +169,11 @@ func (c *Client) doGet(path string, result any) error {
 	if err != nil {
 		return err
 	}
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
-		return fmt.Errorf("failed to connect to aetherd: %w (is aetherd running?)", err)
+		return err
 	}
-	defer func() { _ = resp.Body.Close() }()
+	defer closeBody(resp)
 
 	return c.decodeResponse(resp, result)
 }
@@ -88,11 +195,11 @@ func (c *Client) doPost(path string, body any, result any) error {
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
-		return fmt.Errorf("failed to connect to aetherd: %w (is aetherd running?)", err)
+		return err
 	}
-	defer func() { _ = resp.Body.Close() }()
+	defer closeBody(resp)
 
 	return c.decodeResponse(resp, result)
 }
@@ -103,15 +210,58 @@ func (c *Client) doDelete(path string, result any) error {
 	if err != nil {
 		return err
 	}
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
-		return fmt.Errorf("failed to connect to aetherd: %w (is aetherd running?)", err)
+		return err
 	}
-	defer func() { _ = resp.Body.Close() }()
+	defer closeBody(resp)
 
 	return c.decodeResponse(resp, result)
 }
 
+// do sends req, retrying transient connection errors per SetRetries.
+// A refused connection never reached the daemon, so it is retried for any
+// method. A connection dropped mid-request is retried only for GET, since
+// the daemon may already have acted on anything else.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	backoff := c.retryBackoff
+	for attempt := 0; ; attempt++ {
+		resp, err := c.httpClient.Do(req)
+		if err == nil {
+			return resp, nil
+		}
+		if attempt >= c.retries || !isTransient(req.Method, err) {
+			return nil, fmt.Errorf("failed to connect to aetherd: %w (is aetherd running?)", err)
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+		if req.GetBody != nil {
+			body, bodyErr := req.GetBody()
+			if bodyErr != nil {
+				return nil, fmt.Errorf("failed to retry request: %w", bodyErr)
+			}
+			req.Body = body
+		}
+	}
+}
+
+// isTransient reports whether err is a connection failure worth retrying
+// for a request with the given method.
+func isTransient(method string, err error) bool {
+	if errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	if method != http.MethodGet {
+		return false
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+	// net/http reports a connection closed before any response with an
+	// unexported error, so match its message.
+	return strings.Contains(err.Error(), "server closed idle connection")
+}
+
 // decodeResponse reads and decodes the JSON response envelope.
 func (c *Client) decodeResponse(resp *http.Response, result any) error {
 	if resp.StatusCode >= 400 {
@@ -192,12 +342,42 @@ func authTokenPath(daemonURL string) (string, error) {
 type FullStatus struct {
 	PoolSize    int           `json:"pool_size"`
 	PoolMode    string        `json:"pool_mode"`
+	MaxRetries  int           `json:"max_retries,omitempty"`
 	Project     string        `json:"project"`
+	Instance    string        `json:"instance,omitempty"`
 	SpawnPolicy string        `json:"spawn_policy"`
 	Agents      []AgentStatus `json:"agents"`
 	Spawns      []SpawnStatus `json:"spawns,omitempty"`
 	Queue       []Task        `json:"queue"`
 	Errors      []string      `json:"errors,omitempty"`
+
+	// SkipReasons says why queued tasks aren't running, keyed by task ID.
+	SkipReasons map[string]string `json:"skip_reasons,omitempty"`
+
+	// SessionStoreError is set while session persistence is degraded.
+	SessionStoreError string `json:"session_store_error,omitempty"`
+
+	// SpawnsFull is set while the spawn registry is at capacity and new
+	// spawns go untracked; SpawnsRejected counts those turned away.
+	SpawnsFull     bool `json:"spawns_full,omitempty"`
+	SpawnsRejected int  `json:"spawns_rejected,omitempty"`
+
+	CompletedLastHour int     `json:"completed_last_hour"`
+	RatePerHour       float64 `json:"rate_per_hour"`
+
+	// Projects breaks status down per project on a multi-project daemon,
+	// primary first. The top-level fields cover the primary project.
+	Projects []ProjectStatus `json:"projects,omitempty"`
+}
+
+// ProjectStatus is one project's share of a multi-project daemon.
+type ProjectStatus struct {
+	Project           string        `json:"project"`
+	PoolSize          int           `json:"pool_size"`
+	PoolMode          string        `json:"pool_mode"`
+	Agents            []AgentStatus `json:"agents"`
+	Queue             []Task        `json:"queue"`
+	CompletedLastHour int           `json:"completed_last_hour"`
 }
 
 const (
@@ -224,6 +404,19 @@ func (s *FullStatus) IsManualSpawnPolicy() bool {
 	return s.NormalizedSpawnPolicy() == SpawnPolicyManual
 }
 
+// Label names the daemon in status headers: the instance name, followed by
+// the project when the two differ.
+func (s *FullStatus) Label() string {
+	switch {
+	case s.Instance == "":
+		return s.Project
+	case s.Project == "" || s.Instance == s.Project:
+		return s.Instance
+	default:
+		return s.Instance + " · " + s.Project
+	}
+}
+
 // SpawnStatus is the status of a spawned agent registered with the daemon.
 type SpawnStatus struct {
 	SpawnID         string    `json:"spawn_id"`
@@ -234,6 +427,8 @@ type SpawnStatus struct {
 	LastActivityAt  time.Time `json:"last_activity_at,omitempty"`
 	AttentionNeeded bool      `json:"attention_needed,omitempty"`
 	Prompt          string    `json:"prompt"`
+	WorktreePath    string    `json:"worktree_path,omitempty"`
+	Branch          string    `json:"branch,omitempty"`
 	SpawnTime       time.Time `json:"spawn_time"`
 	ExitedAt        time.Time `json:"exited_at,omitempty"`
 }
@@ -246,8 +441,11 @@ type AgentStatus struct {
 	PID             int       `json:"pid"`
 	SpawnTime       time.Time `json:"spawn_time"`
 	TaskTitle       string    `json:"task_title"`
+	Branch          string    `json:"branch,omitempty"`
 	LastLog         string    `json:"last_log,omitempty"`
 	SessionID       string    `json:"session_id,omitempty"`
+	SessionCaptured bool      `json:"session_captured"`
+	SessionError    string    `json:"session_error,omitempty"`
 	State           string    `json:"state,omitempty"`
 	LifecycleState  string    `json:"lifecycle_state,omitempty"`
 	LastActivityAt  time.Time `json:"last_activity_at,omitempty"`
@@ -256,9 +454,10 @@ type AgentStatus struct {
 
 // Task is a pending task from the queue.
 type Task struct {
-	ID       string `json:"id"`
-	Priority int    `json:"priority"`
-	Title    string `json:"title"`
+	ID         string    `json:"id"`
+	Priority   int       `json:"priority"`
+	Title      string    `json:"title"`
+	ReadySince time.Time `json:"ready_since,omitempty"`
 }
 
 // ToolCall is a single tool invocation from the agent's event stream.
@@ -297,15 +496,35 @@ type SessionMetadata struct {
 
 // StatusAgentParams is the request shape for agent detail lookups.
 type StatusAgentParams struct {
-	AgentName string `json:"agent_name"`
-	Limit     int    `json:"limit,omitempty"`
+	AgentName string   `json:"agent_name"`
+	Limit     int      `json:"limit,omitempty"`
+	Tools     []string `json:"tools,omitempty"`
+}
+
+// StatusAgent returns detailed status for a single agent including tool call
+// history. With tools, only calls to those tools are returned; the daemon
+// filters before applying limit.
+func (c *Client) StatusAgent(agentName string, limit int, tools ...string) (*AgentDetail, error) {
+	return c.StatusAgentScan(agentName, limit, 0, tools...)
 }
 
-// StatusAgent returns detailed status for a single agent including tool call history.
-func (c *Client) StatusAgent(agentName string, limit int) (*AgentDetail, error) {
+// StatusAgentScan is StatusAgent with a cap on how many of the session's
+// newest events the daemon scans for tool calls. Zero uses the daemon
+// default (500).
+func (c *Client) StatusAgentScan(agentName string, limit, scanLimit int, tools ...string) (*AgentDetail, error) {
 	path := "/api/v1/status/agents/" + url.PathEscape(agentName)
+	q := url.Values{}
 	if limit > 0 {
-		path = fmt.Sprintf("%s?limit=%d", path, limit)
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	if scanLimit > 0 {
+		q.Set("scan_limit", strconv.Itoa(scanLimit))
+	}
+	if len(tools) > 0 {
+		q.Set("tools", strings.Join(tools, ","))
+	}
+	if len(q) > 0 {
+		path += "?" + q.Encode()
 	}
 	var result AgentDetail
 	if err := c.doGet(path, &result); err != nil {
@@ -314,15 +533,84 @@ func (c *Client) StatusAgent(agentName string, limit int) (*AgentDetail, error)
 	return &result, nil
 }
 
+// StatusAgentsResult maps agent names to their details. Names the daemon
+// could not resolve are listed in Errors.
+type StatusAgentsResult struct {
+	Agents map[string]*AgentDetail `json:"agents"`
+	Errors map[string]string       `json:"errors,omitempty"`
+}
+
+// StatusAgents returns detailed status for several agents in one request.
+func (c *Client) StatusAgents(agentNames []string, limit int) (*StatusAgentsResult, error) {
+	q := url.Values{}
+	for _, name := range agentNames {
+		q.Add("name", name)
+	}
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	var result StatusAgentsResult
+	if err := c.doGet("/api/v1/status/agents?"+q.Encode(), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 // StatusFull returns the enriched swarm status with task metadata from prog.
 func (c *Client) StatusFull() (*FullStatus, error) {
 	var result FullStatus
-	if err := c.doGet("/api/v1/status", &result); err != nil {
+	if err := c.doGet(c.scoped("/api/v1/status"), &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
+// StatusStream is an open status stream. Each Next call blocks until the
+// daemon pushes a new FullStatus. Call Close when done.
+type StatusStream struct {
+	body io.ReadCloser
+	dec  *json.Decoder
+}
+
+// StatusStream opens a streaming connection that receives the full status
+// whenever it changes, instead of polling StatusFull. The first frame
+// arrives immediately.
+func (c *Client) StatusStream() (*StatusStream, error) {
+	req, err := c.newRequest(http.MethodGet, c.scoped("/api/v1/status/stream"), nil)
+	if err != nil {
+		return nil, err
+	}
+	// No overall timeout: the response stays open for the life of the stream.
+	streamClient := &http.Client{Transport: c.httpClient.Transport}
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to aetherd: %w (is aetherd running?)", err)
+	}
+	if resp.StatusCode >= 400 {
+		defer closeBody(resp)
+		return nil, c.decodeResponse(resp, nil)
+	}
+	return &StatusStream{body: resp.Body, dec: json.NewDecoder(resp.Body)}, nil
+}
+
+// Next returns the next status frame. It returns io.EOF when the daemon
+// ends the stream.
+func (s *StatusStream) Next() (*FullStatus, error) {
+	var status FullStatus
+	if err := s.dec.Decode(&status); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("failed to read status stream: %w", err)
+	}
+	return &status, nil
+}
+
+// Close ends the stream and releases its connection.
+func (s *StatusStream) Close() error {
+	return s.body.Close()
+}
+
 // DaemonLifecycle returns daemon lifecycle status.
 func (c *Client) DaemonLifecycle() (*protocol.DaemonLifecycleStatus, error) {
 	var result protocol.DaemonLifecycleStatus
@@ -374,32 +662,362 @@ func (c *Client) EventsList(agentName string, afterTimestamp int64) (*EventsList
 
 // PoolModeResult is the response payload for pool control endpoints.
 type PoolModeResult struct {
-	Mode    string `json:"mode"`
-	Running int    `json:"running"`
+	Mode        string   `json:"mode"`
+	Running     int      `json:"running"`
+	PausedRoles []string `json:"paused_roles,omitempty"`
 }
 
 // PoolDrain transitions the pool to draining mode.
 func (c *Client) PoolDrain() (*PoolModeResult, error) {
 	var result PoolModeResult
-	if err := c.doPost("/api/v1/pool/drain", nil, &result); err != nil {
+	if err := c.doPost("/api/v1/pool/drain", c.scopedBody(nil), &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
-// PoolPause transitions the pool to paused mode.
-func (c *Client) PoolPause() (*PoolModeResult, error) {
+// PoolPause transitions the pool to paused mode. With a non-empty role,
+// only scheduling for that role is paused.
+func (c *Client) PoolPause(role string) (*PoolModeResult, error) {
+	var body map[string]string
+	if role != "" {
+		body = map[string]string{"role": role}
+	}
 	var result PoolModeResult
-	if err := c.doPost("/api/v1/pool/pause", nil, &result); err != nil {
+	if err := c.doPost("/api/v1/pool/pause", c.scopedBody(body), &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
 }
 
-// PoolResume transitions the pool back to active mode.
-func (c *Client) PoolResume() (*PoolModeResult, error) {
+// PoolResume transitions the pool back to active mode. With a non-empty
+// role, only that role's pause is lifted.
+func (c *Client) PoolResume(role string) (*PoolModeResult, error) {
+	var body map[string]string
+	if role != "" {
+		body = map[string]string{"role": role}
+	}
 	var result PoolModeResult
-	if err := c.doPost("/api/v1/pool/resume", nil, &result); err != nil {
+	if err := c.doPost("/api/v1/pool/resume", c.scopedBody(body), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RollingRestartResult is the response payload for the rolling restart endpoint.
+type RollingRestartResult struct {
+	Tasks []string `json:"tasks"`
+}
+
+// PoolRollingRestart restarts running pool agents one at a time.
+func (c *Client) PoolRollingRestart() (*RollingRestartResult, error) {
+	var result RollingRestartResult
+	if err := c.doPost("/api/v1/pool/rolling-restart", c.scopedBody(nil), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// PoolConfigParams names the pool parameters to change; nil fields are
+// left as they are.
+type PoolConfigParams struct {
+	PoolSize          *int           `json:"pool_size,omitempty"`
+	MaxRetries        *int           `json:"max_retries,omitempty"`
+	MaxStartupRetries *int           `json:"max_startup_retries,omitempty"`
+	MinHealthyUptime  *time.Duration `json:"min_healthy_uptime,omitempty"`
+
+	// Project is filled from SetProject when left empty.
+	Project string `json:"project,omitempty"`
+}
+
+// PoolConfigResult reports the pool parameters in effect after a change.
+type PoolConfigResult struct {
+	Project           string        `json:"project,omitempty"`
+	PoolSize          int           `json:"pool_size"`
+	MaxRetries        int           `json:"max_retries"`
+	MaxStartupRetries int           `json:"max_startup_retries"`
+	MinHealthyUptime  time.Duration `json:"min_healthy_uptime"`
+}
+
+// PoolConfig changes pool parameters on the running daemon until the next
+// config reload.
+func (c *Client) PoolConfig(params PoolConfigParams) (*PoolConfigResult, error) {
+	if params.Project == "" {
+		params.Project = c.project
+	}
+	var result PoolConfigResult
+	if err := c.doPost("/api/v1/pool/config", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// PoolRetireResult reports the task that was retired and its running agent.
+type PoolRetireResult struct {
+	TaskID  string `json:"task_id"`
+	AgentID string `json:"agent_id,omitempty"`
+}
+
+// PoolRetire lets a task's agent finish without respawning it, and stops
+// the task from being scheduled again.
+func (c *Client) PoolRetire(taskID string) (*PoolRetireResult, error) {
+	var result PoolRetireResult
+	if err := c.doPost("/api/v1/pool/retire", c.scopedBody(map[string]string{"task_id": taskID}), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// AgentKillResult reports which agent an agent kill stopped and whether its
+// task was released.
+type AgentKillResult struct {
+	AgentID      string `json:"agent_id"`
+	TaskID       string `json:"task_id"`
+	PID          int    `json:"pid"`
+	Released     bool   `json:"released,omitempty"`
+	ReleaseError string `json:"release_error,omitempty"`
+}
+
+// AgentKill asks the daemon to kill the named pool agent and its process
+// group. Its task is released (blocked in prog) so it is not respawned.
+func (c *Client) AgentKill(agentID string) (*AgentKillResult, error) {
+	var result AgentKillResult
+	if err := c.doPost("/api/v1/agents/kill", c.scopedBody(map[string]string{"agent_id": agentID}), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// AgentLogLevelResult reports the task whose log level was overridden.
+type AgentLogLevelResult struct {
+	TaskID  string `json:"task_id"`
+	Level   string `json:"level"`
+	AgentID string `json:"agent_id,omitempty"`
+}
+
+// AgentLogLevel sets the daemon log level for one task's spawn, reap, and
+// respawn lines until its agent exits for good.
+func (c *Client) AgentLogLevel(taskID, level string) (*AgentLogLevelResult, error) {
+	var result AgentLogLevelResult
+	if err := c.doPost("/api/v1/pool/loglevel", c.scopedBody(map[string]string{"task_id": taskID, "level": level}), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// TaskEnqueueResult reports how many task IDs an enqueue request added.
+type TaskEnqueueResult struct {
+	Enqueued int `json:"enqueued"`
+}
+
+// TaskEnqueue asks the daemon to schedule taskIDs ahead of the polled queue.
+// agentID must name a running planner agent.
+func (c *Client) TaskEnqueue(agentID string, taskIDs []string) (*TaskEnqueueResult, error) {
+	params := struct {
+		AgentID string   `json:"agent_id"`
+		TaskIDs []string `json:"task_ids"`
+	}{AgentID: agentID, TaskIDs: taskIDs}
+	var result TaskEnqueueResult
+	if err := c.doPost("/api/v1/tasks/enqueue", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// TaskHistoryEntry is one task the daemon scheduled since startup.
+type TaskHistoryEntry struct {
+	TaskID    string    `json:"task_id"`
+	Role      string    `json:"role"`
+	Outcome   string    `json:"outcome"`
+	Attempts  int       `json:"attempts"`
+	QueuedAt  time.Time `json:"queued_at,omitempty"`
+	StartedAt time.Time `json:"started_at"`
+	EndedAt   time.Time `json:"ended_at,omitempty"`
+}
+
+// HistoryTasksResult is the response for the task history endpoint.
+type HistoryTasksResult struct {
+	Tasks []TaskHistoryEntry `json:"tasks"`
+}
+
+// HistoryTasks returns every task the daemon has scheduled since startup,
+// with its outcome and timings.
+func (c *Client) HistoryTasks() (*HistoryTasksResult, error) {
+	var result HistoryTasksResult
+	if err := c.doGet(c.scoped("/api/v1/history/tasks"), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// OpError is one entry in the daemon's recent-errors feed.
+type OpError struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"`
+	Kind    string    `json:"kind"`
+	TaskID  string    `json:"task_id,omitempty"`
+	AgentID string    `json:"agent_id,omitempty"`
+	Message string    `json:"message"`
+}
+
+// ErrorsRecentResult is the response for the recent-errors endpoint.
+type ErrorsRecentResult struct {
+	Errors []OpError `json:"errors"`
+}
+
+// ErrorsRecent returns up to limit of the daemon's recent operational
+// errors (spawn failures, fatal exits, exhausted retries), newest first.
+// limit <= 0 returns the whole feed.
+func (c *Client) ErrorsRecent(limit int) (*ErrorsRecentResult, error) {
+	path := "/api/v1/errors/recent"
+	if limit > 0 {
+		path += "?limit=" + strconv.Itoa(limit)
+	}
+	var result ErrorsRecentResult
+	if err := c.doGet(path, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// QueueLatencyResult summarizes how long tasks waited in the ready queue
+// before their first agent started.
+type QueueLatencyResult struct {
+	Project       string  `json:"project"`
+	WindowSeconds float64 `json:"window_seconds"`
+	Samples       int     `json:"samples"`
+	P50Seconds    float64 `json:"p50_seconds"`
+	P90Seconds    float64 `json:"p90_seconds"`
+	P99Seconds    float64 `json:"p99_seconds"`
+}
+
+// QueueLatency returns p50/p90/p99 queue wait times for tasks first
+// started within the last window. window <= 0 uses the daemon's default.
+func (c *Client) QueueLatency(window time.Duration) (*QueueLatencyResult, error) {
+	path := "/api/v1/queue/latency"
+	if window > 0 {
+		path += "?window=" + url.QueryEscape(window.String())
+	}
+	var result QueueLatencyResult
+	if err := c.doGet(c.scoped(path), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// TaskAgentResult is the pool agent currently working a task.
+type TaskAgentResult struct {
+	TaskID    string    `json:"task_id"`
+	Running   bool      `json:"running"`
+	AgentID   string    `json:"agent_id,omitempty"`
+	PID       int       `json:"pid,omitempty"`
+	SessionID string    `json:"session_id,omitempty"`
+	SpawnTime time.Time `json:"spawn_time,omitempty"`
+	Outcome   string    `json:"outcome,omitempty"`
+	Attempts  int       `json:"attempts,omitempty"`
+}
+
+// TaskAgent resolves the pool agent currently assigned to taskID. The
+// agent name changes when a crashed task respawns, so callers following
+// a task should resolve it on every refresh.
+func (c *Client) TaskAgent(taskID string) (*TaskAgentResult, error) {
+	var result TaskAgentResult
+	if err := c.doGet(c.scoped("/api/v1/tasks/agent?task_id="+url.QueryEscape(taskID)), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// AgentPromptResult is the rendered prompt a pool agent was launched with.
+type AgentPromptResult struct {
+	AgentID string `json:"agent_id"`
+	TaskID  string `json:"task_id"`
+	Role    string `json:"role"`
+	Prompt  string `json:"prompt"`
+}
+
+// AgentPrompt returns the rendered prompt the running pool agent was
+// launched with.
+func (c *Client) AgentPrompt(agentName string) (*AgentPromptResult, error) {
+	var result AgentPromptResult
+	if err := c.doGet("/api/v1/agents/prompt?agent_name="+url.QueryEscape(agentName), &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// AgentStdout opens a live stream of a running pool agent's stdout, its
+// JSONL event stream, starting from now. Reads return io.EOF once the
+// agent exits. Close the stream when done.
+func (c *Client) AgentStdout(agentName string) (io.ReadCloser, error) {
+	req, err := c.newRequest(http.MethodGet, "/api/v1/agents/stdout?agent_name="+url.QueryEscape(agentName), nil)
+	if err != nil {
+		return nil, err
+	}
+	// No overall timeout: the response stays open for the life of the stream.
+	streamClient := &http.Client{Transport: c.httpClient.Transport}
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to aetherd: %w (is aetherd running?)", err)
+	}
+	if resp.StatusCode >= 400 {
+		defer closeBody(resp)
+		return nil, c.decodeResponse(resp, nil)
+	}
+	return resp.Body, nil
+}
+
+// DebugSnapshot returns the daemon's debug snapshot (redacted config, pool
+// and spawn state, recent errors, event buffer and server status) as raw
+// JSON, so it can be saved for a bug report without losing fields.
+func (c *Client) DebugSnapshot() (json.RawMessage, error) {
+	var result json.RawMessage
+	if err := c.doGet("/api/v1/debug/snapshot", &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SessionBackfillResult reports how many events an on-demand backfill added.
+type SessionBackfillResult struct {
+	SessionID string `json:"session_id"`
+	Added     int    `json:"added"`
+	Skipped   bool   `json:"skipped,omitempty"`
+}
+
+// SessionBackfill asks the daemon to backfill a single session's events
+// from the opencode server. Sessions that already have events are skipped.
+func (c *Client) SessionBackfill(sessionID string) (*SessionBackfillResult, error) {
+	params := struct {
+		SessionID string `json:"session_id"`
+	}{SessionID: sessionID}
+	var result SessionBackfillResult
+	if err := c.doPost("/api/v1/sessions/backfill", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// SessionKillResult reports which agent a session kill stopped.
+type SessionKillResult struct {
+	SessionID    string `json:"session_id"`
+	Origin       string `json:"origin"` // "pool" or "spawn"
+	AgentID      string `json:"agent_id"`
+	TaskID       string `json:"task_id,omitempty"`
+	PID          int    `json:"pid"`
+	Released     bool   `json:"released,omitempty"`
+	ReleaseError string `json:"release_error,omitempty"`
+}
+
+// SessionKill asks the daemon to stop the pool agent or spawn that owns
+// sessionID. A pool agent's task is released (blocked in prog) so it is not
+// respawned.
+func (c *Client) SessionKill(sessionID string) (*SessionKillResult, error) {
+	params := struct {
+		SessionID string `json:"session_id"`
+	}{SessionID: sessionID}
+	var result SessionKillResult
+	if err := c.doPost("/api/v1/sessions/kill", params, &result); err != nil {
 		return nil, err
 	}
 	return &result, nil
@@ -407,9 +1025,12 @@ func (c *Client) PoolResume() (*PoolModeResult, error) {
 
 // SpawnRegisterParams is the payload for registering a tracked spawn.
 type SpawnRegisterParams struct {
-	SpawnID string `json:"spawn_id"`
-	PID     int    `json:"pid"`
-	Prompt  string `json:"prompt"`
+	SpawnID      string `json:"spawn_id"`
+	PID          int    `json:"pid"`
+	Prompt       string `json:"prompt"`
+	WorktreePath string `json:"worktree_path,omitempty"`
+	Ephemeral    bool   `json:"ephemeral,omitempty"`
+	TaskID       string `json:"task_id,omitempty"`
 }
 
 // SpawnRegister registers a spawned agent with the daemon for observability.
@@ -420,27 +1041,66 @@ func (c *Client) SpawnRegister(params SpawnRegisterParams) error {
 }
 
 // SpawnDeregister marks a spawned agent as exited in the daemon's registry.
-func (c *Client) SpawnDeregister(spawnID string) error {
+// A non-zero exitCode marks the spawn's session terminated rather than idle.
+func (c *Client) SpawnDeregister(spawnID string, exitCode int) error {
 	path := "/api/v1/spawns/" + url.PathEscape(spawnID)
+	if exitCode != 0 {
+		path += "?exit_code=" + strconv.Itoa(exitCode)
+	}
 	return c.doDelete(path, nil)
 }
 
+// SpawnStopResult reports what stop-all did with one running spawn.
+type SpawnStopResult struct {
+	SpawnID string `json:"spawn_id"`
+	PID     int    `json:"pid"`
+	Stopped bool   `json:"stopped"`
+	Error   string `json:"error,omitempty"`
+}
+
+// SpawnStopAllResult is the response from stopping every running spawn.
+type SpawnStopAllResult struct {
+	DryRun bool              `json:"dry_run,omitempty"`
+	Spawns []SpawnStopResult `json:"spawns"`
+}
+
+// SpawnStopAll signals every running spawn to exit and marks it exited in
+// the daemon's registry. With dryRun, it only lists what would be stopped.
+func (c *Client) SpawnStopAll(dryRun bool) (*SpawnStopAllResult, error) {
+	var result SpawnStopAllResult
+	if err := c.doPost("/api/v1/spawns/stop-all", map[string]bool{"dry_run": dryRun}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
 // Shutdown stops the daemon. When force is false and the daemon has active
 // sessions, it returns a "refused" error with a human-readable message.
 // Pass force=true to stop unconditionally.
 func (c *Client) Shutdown(force bool) error {
-	_, err := c.StopDaemon(force)
+	_, err := c.StopDaemon(force, StopDaemonParams{})
 	return err
 }
 
+// StopDaemonParams carries the optional reason and confirmation token of
+// a stop request.
+type StopDaemonParams struct {
+	Reason string `json:"reason,omitempty"`
+	Token  string `json:"token,omitempty"`
+}
+
 // StopDaemon stops the daemon and returns the daemon-owned outcome.
-func (c *Client) StopDaemon(force bool) (*protocol.StopDaemonResult, error) {
+func (c *Client) StopDaemon(force bool, params StopDaemonParams) (*protocol.StopDaemonResult, error) {
 	path := "/api/v1/shutdown"
 	if force {
 		path += "?force=true"
 	}
+	var body any
+	if params != (StopDaemonParams{}) {
+		body = params
+	}
 	var result protocol.StopDaemonResult
-	if err := c.doPost(path, nil, &result); err != nil {
+	if err := c.doPost(path, body, &result); err != nil {
 		return nil, err
 	}
 	if result.Outcome == protocol.StopOutcomeRefused {
@@ -3,11 +3,15 @@ package client
 import (
 	"encoding/json"
 	"errors"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/baiirun/aetherflow/internal/protocol"
 )
@@ -28,7 +32,7 @@ func TestStopDaemonPreservesRefusedOutcome(t *testing.T) {
 	}))
 	defer server.Close()
 
-	result, err := New(server.URL).StopDaemon(false)
+	result, err := New(server.URL).StopDaemon(false, StopDaemonParams{})
 	if result == nil {
 		t.Fatal("StopDaemon result = nil, want refusal payload")
 	}
@@ -81,3 +85,192 @@ func mustMarshal(t *testing.T, value any) json.RawMessage {
 	}
 	return data
 }
+
+// countingServer returns a daemon stub that counts new TCP connections.
+func countingServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var conns atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(Response{Success: true, Result: json.RawMessage(`{"pool_size":3}`)})
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+	return server, &conns
+}
+
+func TestPersistentClientReusesConnection(t *testing.T) {
+	server, conns := countingServer(t)
+
+	c := NewPersistent(server.URL)
+	defer c.Close()
+	for i := 0; i < 5; i++ {
+		if _, err := c.StatusFull(); err != nil {
+			t.Fatalf("StatusFull call %d: %v", i, err)
+		}
+	}
+
+	if got := conns.Load(); got != 1 {
+		t.Errorf("connections opened = %d, want 1", got)
+	}
+}
+
+func TestOneShotClientDoesNotKeepConnections(t *testing.T) {
+	server, conns := countingServer(t)
+
+	c := New(server.URL)
+	for i := 0; i < 3; i++ {
+		if _, err := c.StatusFull(); err != nil {
+			t.Fatalf("StatusFull call %d: %v", i, err)
+		}
+	}
+
+	if got := conns.Load(); got != 3 {
+		t.Errorf("connections opened = %d, want 3 (one per call)", got)
+	}
+}
+
+func TestStatusStreamReadsFrames(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/status/stream" {
+			t.Errorf("path = %q, want /api/v1/status/stream", r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		enc := json.NewEncoder(w)
+		_ = enc.Encode(FullStatus{PoolMode: "active"})
+		_ = enc.Encode(FullStatus{PoolMode: "paused"})
+	}))
+	defer server.Close()
+
+	stream, err := New(server.URL).StatusStream()
+	if err != nil {
+		t.Fatalf("StatusStream: %v", err)
+	}
+	defer stream.Close()
+
+	for _, want := range []string{"active", "paused"} {
+		status, err := stream.Next()
+		if err != nil {
+			t.Fatalf("Next: %v", err)
+		}
+		if status.PoolMode != want {
+			t.Errorf("PoolMode = %q, want %q", status.PoolMode, want)
+		}
+	}
+	if _, err := stream.Next(); !errors.Is(err, io.EOF) {
+		t.Errorf("Next after end = %v, want io.EOF", err)
+	}
+}
+
+func TestSetProjectScopesRequests(t *testing.T) {
+	type seen struct{ query, body string }
+	var got []seen
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got = append(got, seen{query: r.URL.Query().Get("project"), body: string(body)})
+		_ = json.NewEncoder(w).Encode(Response{Success: true, Result: json.RawMessage(`{}`)})
+	}))
+	defer server.Close()
+
+	c := New(server.URL)
+	c.SetProject("beta")
+	if _, err := c.StatusFull(); err != nil {
+		t.Fatalf("StatusFull: %v", err)
+	}
+	if _, err := c.PoolPause("worker"); err != nil {
+		t.Fatalf("PoolPause: %v", err)
+	}
+	if _, err := c.PoolDrain(); err != nil {
+		t.Fatalf("PoolDrain: %v", err)
+	}
+
+	if got[0].query != "beta" {
+		t.Errorf("status project query = %q, want beta", got[0].query)
+	}
+	for _, req := range got[1:] {
+		var body map[string]string
+		if err := json.Unmarshal([]byte(req.body), &body); err != nil || body["project"] != "beta" {
+			t.Errorf("pool request body = %q, want project beta", req.body)
+		}
+	}
+}
+
+// flakyServer returns a daemon stub that drops its first connection
+// without answering, as a daemon mid-restart would, then serves normally.
+func flakyServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	var dropped atomic.Bool
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(Response{Success: true, Result: json.RawMessage(`{"pool_size":3}`)})
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if state == http.StateNew && dropped.CompareAndSwap(false, true) {
+			_ = conn.Close()
+		}
+	}
+	server.Start()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPersistentClientRetriesDroppedConnection(t *testing.T) {
+	server := flakyServer(t)
+
+	c := NewPersistent(server.URL)
+	defer c.Close()
+	c.SetRetries(2, time.Millisecond)
+	status, err := c.StatusFull()
+	if err != nil {
+		t.Fatalf("StatusFull: %v, want success after retry", err)
+	}
+	if status.PoolSize != 3 {
+		t.Errorf("PoolSize = %d, want 3", status.PoolSize)
+	}
+}
+
+func TestOneShotClientFailsFast(t *testing.T) {
+	server := flakyServer(t)
+
+	if _, err := New(server.URL).StatusFull(); err == nil {
+		t.Fatal("StatusFull succeeded, want the dropped connection reported without retry")
+	}
+}
+
+func TestPersistentClientRetriesRefusedConnection(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close() // refuse until the "restarted" daemon listens again
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode(Response{Success: true, Result: json.RawMessage(`{"pool_size":3}`)})
+	}))
+	t.Cleanup(server.Close)
+	started := make(chan struct{})
+	go func() {
+		defer close(started)
+		time.Sleep(20 * time.Millisecond)
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			t.Errorf("relisten on %s: %v", addr, err)
+			return
+		}
+		server.Listener = ln
+		server.Start()
+	}()
+
+	c := NewPersistent("http://" + addr)
+	defer c.Close()
+	c.SetRetries(5, 50*time.Millisecond)
+	_, err = c.StatusFull()
+	<-started
+	if err != nil {
+		t.Fatalf("StatusFull: %v, want success once the daemon accepts", err)
+	}
+}
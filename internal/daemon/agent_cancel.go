@@ -0,0 +1,89 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// CancelAgent kills the running agent named agentID by cancelling the
+// context its process was started with, without signalling its PID. For a
+// process from ExecProcessStarter that kills its whole process group. As
+// with KillBySession, the task is not respawned when the agent exits; reap
+// cleans up as usual. It reports false if no running agent has that name.
+func (p *Pool) CancelAgent(agentID string) (Agent, bool) {
+	p.mu.Lock()
+	var target *Agent
+	for _, a := range p.agents {
+		if string(a.ID) == agentID && a.State == AgentRunning && a.cancel != nil {
+			target = a
+			break
+		}
+	}
+	if target == nil {
+		p.mu.Unlock()
+		return Agent{}, false
+	}
+	snapshot := *target
+	p.killed[snapshot.TaskID] = true
+	p.mu.Unlock()
+
+	p.taskLog(snapshot.TaskID).Info("cancelling agent",
+		"agent_id", snapshot.ID,
+		"task_id", snapshot.TaskID,
+		"pid", snapshot.PID,
+	)
+	target.cancel()
+	return snapshot, true
+}
+
+// AgentKillParams is the request shape for killing a pool agent by name.
+type AgentKillParams struct {
+	ProjectSelector
+	AgentID string `json:"agent_id"`
+}
+
+// AgentKillResult is the response for the agent kill handler.
+type AgentKillResult struct {
+	AgentID  string `json:"agent_id"`
+	TaskID   string `json:"task_id"`
+	PID      int    `json:"pid"`
+	Released bool   `json:"released,omitempty"` // task moved out of in_progress
+	// ReleaseError is set when the agent was killed but its task could not
+	// be released; the task is left in_progress for the operator.
+	ReleaseError string `json:"release_error,omitempty"`
+}
+
+// handleAgentKill kills a pool agent by name with CancelAgent, then blocks
+// its task in prog like handleSessionKill, releasing the claim so the task
+// is not respawned or rescheduled.
+func (d *Daemon) handleAgentKill(ctx context.Context, params AgentKillParams) *Response {
+	pool, err := d.poolFor(params.Project)
+	if err != nil {
+		return &Response{Success: false, Error: err.Error()}
+	}
+	if params.AgentID == "" {
+		return &Response{Success: false, Error: "agent_id is required"}
+	}
+	agent, ok := pool.CancelAgent(params.AgentID)
+	if !ok {
+		return &Response{Success: false, Error: fmt.Sprintf("agent %s is not running", params.AgentID)}
+	}
+
+	result := AgentKillResult{AgentID: string(agent.ID), TaskID: agent.TaskID, PID: agent.PID}
+	bctx, cancel := context.WithTimeout(ctx, sessionKillBlockTimeout)
+	defer cancel()
+	reason := fmt.Sprintf("agent %s killed via af agent kill", agent.ID)
+	if err := pool.work.Block(bctx, agent.TaskID, pool.Project(), reason); err != nil {
+		d.log.Warn("failed to release killed agent's task", "task_id", agent.TaskID, "error", err)
+		result.ReleaseError = err.Error()
+	} else {
+		result.Released = true
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return &Response{Success: false, Error: fmt.Sprintf("marshal agent kill result: %v", err)}
+	}
+	return &Response{Success: true, Result: data}
+}
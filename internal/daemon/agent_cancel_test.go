@@ -0,0 +1,166 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestCancelAgentTearsDownProcess(t *testing.T) {
+	var spawns atomic.Int32
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		spawns.Add(1)
+		// Like exec.CommandContext: the process dies when ctx is done.
+		proc, release := newFakeProcessWithError(100, exitCodeError(-1))
+		go func() {
+			<-ctx.Done()
+			release()
+		}()
+		return proc, nil
+	}
+	pool := testPool(t, progRunner(testTaskMeta), starter)
+	pool.schedule(context.Background(), []Task{{ID: "ts-abc"}, {ID: "ts-def"}})
+
+	agents := pool.Status()
+	if len(agents) != 2 {
+		t.Fatalf("agents = %d, want 2", len(agents))
+	}
+	var target, other Agent
+	for _, a := range agents {
+		if a.TaskID == "ts-abc" {
+			target = a
+		} else {
+			other = a
+		}
+	}
+
+	got, ok := pool.CancelAgent(string(target.ID))
+	if !ok || got.TaskID != "ts-abc" {
+		t.Fatalf("CancelAgent = %+v, %v; want the ts-abc agent", got, ok)
+	}
+	waitFor(t, func() bool { return len(pool.Status()) == 1 })
+
+	// Only that agent is reaped, and its task is not respawned.
+	time.Sleep(50 * time.Millisecond)
+	if remaining := pool.Status(); len(remaining) != 1 || remaining[0].ID != other.ID {
+		t.Errorf("agents = %+v, want only %s", remaining, other.ID)
+	}
+	if n := spawns.Load(); n != 2 {
+		t.Errorf("spawns = %d, want 2 (no respawn after cancel)", n)
+	}
+
+	if _, ok := pool.CancelAgent(string(target.ID)); ok {
+		t.Error("CancelAgent on a reaped agent = true, want false")
+	}
+}
+
+func TestHandleAgentKillCancelsAgentAndBlocksTask(t *testing.T) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		proc, release := newFakeProcessWithError(100, exitCodeError(-1))
+		go func() {
+			<-ctx.Done()
+			release()
+		}()
+		return proc, nil
+	}
+	var mu sync.Mutex
+	var blocked [][]string
+	show := progRunner(testTaskMeta)
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if len(args) >= 1 && args[0] == "block" {
+			mu.Lock()
+			blocked = append(blocked, args)
+			mu.Unlock()
+			return nil, nil
+		}
+		return show(ctx, name, args...)
+	}
+	pool := testPool(t, runner, starter)
+	pool.schedule(context.Background(), []Task{{ID: "ts-abc"}})
+	agents := pool.Status()
+	if len(agents) != 1 {
+		t.Fatalf("agents = %d, want 1", len(agents))
+	}
+
+	d := &Daemon{config: pool.config, pool: pool, spawns: NewSpawnRegistry(), log: testLogger()}
+	resp := d.handleAgentKill(context.Background(), AgentKillParams{AgentID: string(agents[0].ID)})
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	var result AgentKillResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if result.TaskID != "ts-abc" || result.AgentID != string(agents[0].ID) || !result.Released {
+		t.Errorf("result = %+v, want released agent on ts-abc", result)
+	}
+	mu.Lock()
+	if len(blocked) != 1 || blocked[0][1] != "ts-abc" || !slices.Contains(blocked[0], "testproject") {
+		t.Errorf("prog block calls = %v, want one for ts-abc in testproject", blocked)
+	}
+	mu.Unlock()
+	waitFor(t, func() bool { return len(pool.Status()) == 0 })
+
+	resp = d.handleAgentKill(context.Background(), AgentKillParams{AgentID: string(agents[0].ID)})
+	if resp.Success || !strings.Contains(resp.Error, "not running") {
+		t.Errorf("second kill = %+v, want not running error", resp)
+	}
+	if resp := d.handleAgentKill(context.Background(), AgentKillParams{}); resp.Success {
+		t.Error("kill without agent_id succeeded, want error")
+	}
+}
+
+func TestExecProcessStarterCancelKillsProcessGroup(t *testing.T) {
+	pidFile := filepath.Join(t.TempDir(), "child.pid")
+	ctx, cancel := context.WithCancel(context.Background())
+	proc, err := ExecProcessStarter(ctx, "sh -c",
+		fmt.Sprintf("sleep 30 & echo $! > %s; wait", pidFile),
+		"steel_gloom", io.Discard, StartOptions{})
+	if err != nil {
+		t.Fatalf("ExecProcessStarter: %v", err)
+	}
+	var child int
+	waitFor(t, func() bool {
+		data, err := os.ReadFile(pidFile)
+		if err != nil {
+			return false
+		}
+		child, err = strconv.Atoi(strings.TrimSpace(string(data)))
+		return err == nil
+	})
+
+	cancel()
+	done := make(chan error, 1)
+	go func() { done <- proc.Wait() }()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Wait did not return after cancel")
+	}
+	waitFor(t, func() bool { return !processRunning(child) })
+}
+
+// processRunning reports whether pid exists and is not a zombie. An
+// orphaned child may linger as a zombie when nothing reaps it.
+func processRunning(pid int) bool {
+	if syscall.Kill(pid, 0) != nil {
+		return false
+	}
+	stat, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return false
+	}
+	fields := strings.Fields(string(stat[strings.LastIndexByte(string(stat), ')')+1:]))
+	return len(fields) == 0 || fields[0] != "Z"
+}
@@ -0,0 +1,66 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// AgentPromptParams is the request shape for fetching an agent's prompt.
+type AgentPromptParams struct {
+	AgentName string `json:"agent_name"`
+}
+
+// AgentPromptResult is the rendered prompt a pool agent was launched with.
+// A respawned agent reports the prompt re-rendered for its own launch.
+type AgentPromptResult struct {
+	AgentID string `json:"agent_id"`
+	TaskID  string `json:"task_id"`
+	Role    Role   `json:"role"`
+	Prompt  string `json:"prompt"`
+}
+
+// AgentPrompt returns the launch prompt of the running pool agent named
+// agentID, or false when no such agent is running.
+func (p *Pool) AgentPrompt(agentID string) (AgentPromptResult, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for _, a := range p.agents {
+		if string(a.ID) == agentID && a.State == AgentRunning {
+			return AgentPromptResult{
+				AgentID: agentID,
+				TaskID:  a.TaskID,
+				Role:    a.Role,
+				Prompt:  a.Prompt,
+			}, true
+		}
+	}
+	return AgentPromptResult{}, false
+}
+
+// handleAgentPrompt returns the rendered prompt of a running pool agent.
+func (d *Daemon) handleAgentPrompt(params AgentPromptParams) *Response {
+	if params.AgentName == "" {
+		return &Response{Success: false, Error: "agent_name is required"}
+	}
+	pools := d.pools()
+	if len(pools) == 0 {
+		return &Response{Success: false, Error: "no pool configured"}
+	}
+
+	var prompt AgentPromptResult
+	found := false
+	for _, pool := range pools {
+		if prompt, found = pool.AgentPrompt(params.AgentName); found {
+			break
+		}
+	}
+	if !found {
+		return &Response{Success: false, Error: fmt.Sprintf("no running pool agent %q", params.AgentName)}
+	}
+	result, err := json.Marshal(prompt)
+	if err != nil {
+		return &Response{Success: false, Error: fmt.Sprintf("marshal agent prompt: %v", err)}
+	}
+	return &Response{Success: true, Result: result}
+}
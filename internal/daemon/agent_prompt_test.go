@@ -0,0 +1,69 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestHandleAgentPromptReturnsRenderedPrompt(t *testing.T) {
+	var launched string
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		launched = prompt
+		proc, _ := newFakeProcess(1234)
+		return proc, nil
+	}
+	pool := testPool(t, progRunner(testTaskMeta), starter)
+	d := &Daemon{config: pool.config, pool: pool, log: testLogger()}
+
+	pool.schedule(context.Background(), []Task{{ID: "ts-abc"}})
+	agents := pool.Status()
+	if len(agents) != 1 {
+		t.Fatalf("agents = %d, want 1", len(agents))
+	}
+
+	resp := d.handleAgentPrompt(AgentPromptParams{AgentName: string(agents[0].ID)})
+	if !resp.Success {
+		t.Fatalf("handleAgentPrompt: %s", resp.Error)
+	}
+	var result AgentPromptResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if result.TaskID != "ts-abc" || result.Role != RoleWorker {
+		t.Errorf("result = %+v, want task ts-abc with role worker", result)
+	}
+	if !strings.Contains(result.Prompt, "ts-abc") {
+		t.Errorf("prompt does not contain the substituted task ID:\n%s", result.Prompt)
+	}
+	if strings.Contains(result.Prompt, "{{task_id}}") {
+		t.Error("prompt still contains the {{task_id}} placeholder")
+	}
+	if result.Prompt != launched {
+		t.Error("prompt differs from the one passed to the starter")
+	}
+
+	// The prompt stays out of status payloads.
+	status, err := json.Marshal(agents[0])
+	if err != nil {
+		t.Fatalf("marshal agent: %v", err)
+	}
+	if strings.Contains(string(status), "prompt") {
+		t.Errorf("agent status JSON includes the prompt: %s", status)
+	}
+}
+
+func TestHandleAgentPromptUnknownAgent(t *testing.T) {
+	pool := testPool(t, progRunner(testTaskMeta), nil)
+	d := &Daemon{config: pool.config, pool: pool, log: testLogger()}
+
+	resp := d.handleAgentPrompt(AgentPromptParams{AgentName: "ghost"})
+	if resp.Success || !strings.Contains(resp.Error, "ghost") {
+		t.Errorf("response = %+v, want an error naming the agent", resp)
+	}
+	if resp := d.handleAgentPrompt(AgentPromptParams{}); resp.Success {
+		t.Error("empty agent name accepted")
+	}
+}
@@ -0,0 +1,216 @@
+package daemon
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// stdoutSubBuffer is how many unread lines of agent stdout a live
+// subscriber may fall behind by before further lines are dropped for it.
+const stdoutSubBuffer = 256
+
+// stdoutMaxLine bounds how much of an unterminated line the tee holds.
+// A longer line is dropped whole rather than split.
+const stdoutMaxLine = 1 << 20
+
+// stdoutTee is a pool agent's stdout. It copies every complete line the
+// agent writes to whoever is subscribed at the time (af logs --live), and
+// discards it otherwise. Writes never block on a subscriber: one that
+// falls behind misses whole lines instead of stalling the agent, and is
+// sent a stdoutDroppedLine marker before the next line it does receive,
+// so the stream stays valid JSONL.
+type stdoutTee struct {
+	mu       sync.Mutex
+	subs     map[chan []byte]int // subscriber -> lines dropped since its last delivery
+	partial  []byte              // unterminated tail of the last write
+	overlong bool                // partial outgrew stdoutMaxLine and is being discarded
+	closed   bool
+}
+
+func newStdoutTee() *stdoutTee {
+	return &stdoutTee{subs: make(map[chan []byte]int)}
+}
+
+// stdoutDroppedLine is the JSONL marker a subscriber gets in place of the
+// n lines it fell too far behind to receive.
+func stdoutDroppedLine(n int) []byte {
+	return fmt.Appendf(nil, "{\"type\":\"af_dropped\",\"lines\":%d}\n", n)
+}
+
+func (t *stdoutTee) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	data := p
+	for len(data) > 0 {
+		i := bytes.IndexByte(data, '\n')
+		if i < 0 {
+			t.hold(data)
+			break
+		}
+		head := data[:i+1]
+		data = data[i+1:]
+		if t.overlong {
+			t.overlong = false
+			t.dropLine()
+			continue
+		}
+		// The caller may reuse p once Write returns, so each line is a copy.
+		line := make([]byte, 0, len(t.partial)+len(head))
+		line = append(append(line, t.partial...), head...)
+		t.partial = t.partial[:0]
+		t.sendLine(line)
+	}
+	return len(p), nil
+}
+
+// hold keeps an unterminated line until its newline arrives, giving up on
+// it once it grows past stdoutMaxLine. Caller must hold t.mu.
+func (t *stdoutTee) hold(data []byte) {
+	if t.overlong {
+		return
+	}
+	if len(t.partial)+len(data) > stdoutMaxLine {
+		t.partial = nil
+		t.overlong = true
+		return
+	}
+	t.partial = append(t.partial, data...)
+}
+
+// sendLine offers one complete line to every subscriber, after the marker
+// for any lines it missed. Caller must hold t.mu.
+func (t *stdoutTee) sendLine(line []byte) {
+	for ch, dropped := range t.subs {
+		if dropped > 0 {
+			select {
+			case ch <- stdoutDroppedLine(dropped):
+				dropped = 0
+			default:
+				t.subs[ch] = dropped + 1
+				continue
+			}
+		}
+		select {
+		case ch <- line:
+			t.subs[ch] = 0
+		default:
+			t.subs[ch] = dropped + 1
+		}
+	}
+}
+
+// dropLine counts a line no subscriber gets. Caller must hold t.mu.
+func (t *stdoutTee) dropLine() {
+	for ch, dropped := range t.subs {
+		t.subs[ch] = dropped + 1
+	}
+}
+
+// subscribe returns a channel of the lines completed from now on, and a
+// func to unsubscribe. The channel is closed when the agent exits or the
+// subscriber unsubscribes.
+func (t *stdoutTee) subscribe() (<-chan []byte, func()) {
+	ch := make(chan []byte, stdoutSubBuffer)
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	t.subs[ch] = 0
+	return ch, func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if _, ok := t.subs[ch]; ok {
+			delete(t.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// close ends every subscription. Called once the agent's process has
+// exited and its stdout is drained.
+func (t *stdoutTee) close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closed = true
+	for ch := range t.subs {
+		delete(t.subs, ch)
+		close(ch)
+	}
+}
+
+// SubscribeStdout subscribes to the stdout of the running pool agent named
+// agentID. It returns false when no such agent is running.
+func (p *Pool) SubscribeStdout(agentID string) (<-chan []byte, func(), bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	for _, a := range p.agents {
+		if string(a.ID) == agentID && a.State == AgentRunning && a.stdout != nil {
+			ch, cancel := a.stdout.subscribe()
+			return ch, cancel, true
+		}
+	}
+	return nil, nil, false
+}
+
+// httpAgentStdout streams a pool agent's stdout (its JSONL event stream)
+// as it is written, until the agent exits, the client disconnects, or the
+// daemon shuts down. Output from before the request is not replayed.
+func (d *Daemon) httpAgentStdout(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("agent_name")
+	if name == "" {
+		writeJSON(w, http.StatusBadRequest, &Response{Success: false, Error: "agent_name is required"})
+		return
+	}
+	var (
+		lines  <-chan []byte
+		cancel func()
+		found  bool
+	)
+	for _, pool := range d.pools() {
+		if lines, cancel, found = pool.SubscribeStdout(name); found {
+			break
+		}
+	}
+	if !found {
+		writeJSON(w, http.StatusNotFound, &Response{Success: false, Error: fmt.Sprintf("no running pool agent %q", name)})
+		return
+	}
+	defer cancel()
+
+	rc := http.NewResponseController(w)
+	// The server's read and write timeouts are sized for one-shot requests.
+	_ = rc.SetReadDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	_ = rc.SetWriteDeadline(time.Now().Add(statusStreamWriteTimeout))
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	for {
+		select {
+		case line, ok := <-lines:
+			if !ok {
+				return
+			}
+			_ = rc.SetWriteDeadline(time.Now().Add(statusStreamWriteTimeout))
+			if _, err := w.Write(line); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		case <-r.Context().Done():
+			return
+		case <-d.shutdown:
+			return
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestHTTPAgentStdoutStreamsLinesWrittenAfterSubscribe(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		stdout  io.Writer
+		release func()
+	)
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, w io.Writer, _ StartOptions) (Process, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		var proc *fakeProcess
+		proc, release = newFakeProcess(1234)
+		stdout = w
+		return proc, nil
+	}
+	pool := testPool(t, progRunner(testTaskMeta), starter)
+	d := &Daemon{config: pool.config, pool: pool, log: testLogger(), shutdown: make(chan struct{}), authToken: "test-token"}
+
+	pool.schedule(context.Background(), []Task{{ID: "ts-abc"}})
+	agents := pool.Status()
+	if len(agents) != 1 {
+		t.Fatalf("agents = %d, want 1", len(agents))
+	}
+	mu.Lock()
+	agentOut := stdout
+	mu.Unlock()
+
+	// Written before anyone subscribes: not replayed.
+	if _, err := io.WriteString(agentOut, `{"type":"early"}`+"\n"); err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(d.newHTTPHandler())
+	defer srv.Close()
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/api/v1/agents/stdout?agent_name="+string(agents[0].ID), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(daemonAuthHeader, d.authToken)
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+
+	// The handler subscribes before sending the response header, so
+	// everything written from here on reaches the stream.
+	want := []string{`{"type":"step_start"}`, `{"type":"text"}`}
+	for _, line := range want {
+		if _, err := io.WriteString(agentOut, line+"\n"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, w := range want {
+		select {
+		case got := <-lines:
+			if got != w {
+				t.Errorf("line = %q, want %q", got, w)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for %q", w)
+		}
+	}
+
+	// The stream ends once the agent exits.
+	mu.Lock()
+	release()
+	mu.Unlock()
+	select {
+	case extra, ok := <-lines:
+		if ok {
+			t.Errorf("unexpected line after exit: %q", extra)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream did not end after the agent exited")
+	}
+}
+
+func TestHTTPAgentStdoutUnknownAgent(t *testing.T) {
+	pool := testPool(t, progRunner(testTaskMeta), nil)
+	d := &Daemon{config: pool.config, pool: pool, log: testLogger(), shutdown: make(chan struct{}), authToken: "test-token"}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/agents/stdout?agent_name=ghost_wolf", nil)
+	req.Host = "127.0.0.1:7070"
+	req.Header.Set(daemonAuthHeader, d.authToken)
+	rec := httptest.NewRecorder()
+	d.newHTTPHandler().ServeHTTP(rec, req)
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d: %s", rec.Code, http.StatusNotFound, rec.Body.String())
+	}
+}
+
+func TestStdoutTeeSendsWholeLines(t *testing.T) {
+	tee := newStdoutTee()
+	ch, cancel := tee.subscribe()
+	defer cancel()
+
+	// A line split across writes arrives whole; a trailing partial waits.
+	for _, w := range []string{`{"type":`, `"a"}` + "\n" + `{"type":"b"}` + "\n" + `{"ty`, `pe":"c"}` + "\n"} {
+		if _, err := io.WriteString(tee, w); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, want := range []string{`{"type":"a"}`, `{"type":"b"}`, `{"type":"c"}`} {
+		if got := string(<-ch); got != want+"\n" {
+			t.Errorf("line = %q, want %q", got, want+"\n")
+		}
+	}
+	select {
+	case extra := <-ch:
+		t.Errorf("unexpected line %q", extra)
+	default:
+	}
+}
+
+func TestStdoutTeeMarksDroppedLines(t *testing.T) {
+	tee := newStdoutTee()
+	ch, cancel := tee.subscribe()
+	defer cancel()
+
+	// Fill the subscriber's buffer, then overflow it by three lines.
+	for i := 0; i < stdoutSubBuffer+3; i++ {
+		if _, err := io.WriteString(tee, `{"type":"text"}`+"\n"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 0; i < stdoutSubBuffer; i++ {
+		<-ch
+	}
+	if _, err := io.WriteString(tee, `{"type":"next"}`+"\n"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(<-ch), `{"type":"af_dropped","lines":3}`+"\n"; got != want {
+		t.Errorf("marker = %q, want %q", got, want)
+	}
+	if got, want := string(<-ch), `{"type":"next"}`+"\n"; got != want {
+		t.Errorf("line = %q, want %q", got, want)
+	}
+}
+
+func TestStdoutTeeDropsOverlongLineWhole(t *testing.T) {
+	tee := newStdoutTee()
+	ch, cancel := tee.subscribe()
+	defer cancel()
+
+	long := strings.Repeat("x", stdoutMaxLine/2)
+	for _, w := range []string{long, long, long, "\n", `{"type":"ok"}` + "\n"} {
+		if _, err := io.WriteString(tee, w); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := string(<-ch), `{"type":"af_dropped","lines":1}`+"\n"; got != want {
+		t.Errorf("marker = %q, want %q", got, want)
+	}
+	if got, want := string(<-ch), `{"type":"ok"}`+"\n"; got != want {
+		t.Errorf("line = %q, want %q", got, want)
+	}
+}
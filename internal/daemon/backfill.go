@@ -3,7 +3,9 @@ package daemon
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/baiirun/aetherflow/internal/sessions"
@@ -19,12 +21,18 @@ import (
 //  3. Convert each message part into a SessionEvent (same shape as plugin events).
 //  4. Push events into the buffer so af status / af logs work immediately.
 //
+// At most concurrency sessions are fetched at once, all under ctx's deadline,
+// so a large registry finishes quickly without flooding the server.
+//
 // This is best-effort: failures are logged but don't prevent the daemon
 // from starting. The plugin will deliver future events regardless.
-func backfillEvents(ctx context.Context, api *opencodeClient, store *sessions.Store, events *EventBuffer, log *slog.Logger) {
+func backfillEvents(ctx context.Context, api *opencodeClient, store *sessions.Store, events *EventBuffer, concurrency int, log *slog.Logger) {
 	if store == nil || api == nil || events == nil {
 		return
 	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
 
 	records, err := store.List()
 	if err != nil {
@@ -36,7 +44,12 @@ func backfillEvents(ctx context.Context, api *opencodeClient, store *sessions.St
 		return
 	}
 
-	var backfilled, skipped, errored int
+	var (
+		mu                           sync.Mutex
+		wg                           sync.WaitGroup
+		backfilled, skipped, errored int
+	)
+	sem := make(chan struct{}, concurrency)
 	for _, rec := range records {
 		if rec.SessionID == "" {
 			continue
@@ -53,24 +66,43 @@ func backfillEvents(ctx context.Context, api *opencodeClient, store *sessions.St
 			continue
 		}
 
-		n, err := backfillSession(ctx, api, events, rec.SessionID)
-		if err != nil {
-			log.Warn("backfill: failed to fetch session",
-				"session_id", rec.SessionID,
-				"error", err,
-			)
-			errored++
-			continue
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
 		}
-
-		if n > 0 {
-			backfilled++
-			log.Debug("backfill: populated session",
-				"session_id", rec.SessionID,
-				"events", n,
-			)
+		if ctx.Err() != nil {
+			break
 		}
+
+		wg.Add(1)
+		go func(sessionID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			n, err := backfillSession(ctx, api, events, sessionID)
+			if err != nil {
+				log.Warn("backfill: failed to fetch session",
+					"session_id", sessionID,
+					"error", err,
+				)
+				mu.Lock()
+				errored++
+				mu.Unlock()
+				return
+			}
+
+			if n > 0 {
+				mu.Lock()
+				backfilled++
+				mu.Unlock()
+				log.Debug("backfill: populated session",
+					"session_id", sessionID,
+					"events", n,
+				)
+			}
+		}(rec.SessionID)
 	}
+	wg.Wait()
 
 	if backfilled > 0 || errored > 0 {
 		log.Info("backfill complete",
@@ -163,3 +195,52 @@ func extractPartTimestamp(raw json.RawMessage) int64 {
 // backfillTimeout is the maximum time spent on the entire backfill operation.
 // Individual session fetches may be faster. This bounds total startup delay.
 const backfillTimeout = 30 * time.Second
+
+// SessionBackfillParams is the HTTP payload for an on-demand session backfill.
+type SessionBackfillParams struct {
+	SessionID string `json:"session_id"`
+}
+
+// SessionBackfillResult reports how many events an on-demand backfill added.
+// Skipped is true when the buffer already held events for the session.
+type SessionBackfillResult struct {
+	SessionID string `json:"session_id"`
+	Added     int    `json:"added"`
+	Skipped   bool   `json:"skipped,omitempty"`
+}
+
+// handleSessionBackfill backfills a single session from the opencode REST API
+// on demand. This recovers sessions whose plugin events were missed (e.g. the
+// daemon was down when the session started) without restarting the daemon.
+//
+// Like the startup backfill, sessions that already have buffered events are
+// left alone so plugin-delivered events are never duplicated.
+func (d *Daemon) handleSessionBackfill(ctx context.Context, params SessionBackfillParams) *Response {
+	if params.SessionID == "" {
+		return &Response{Success: false, Error: "session_id is required"}
+	}
+	if !isValidSessionID(params.SessionID) {
+		return &Response{Success: false, Error: fmt.Sprintf("invalid session_id %q", params.SessionID)}
+	}
+
+	result := SessionBackfillResult{SessionID: params.SessionID}
+	if d.events.Len(params.SessionID) > 0 {
+		result.Skipped = true
+	} else {
+		bctx, cancel := context.WithTimeout(ctx, backfillTimeout)
+		defer cancel()
+		api := newOpencodeClient(d.config.ServerURL)
+		n, err := backfillSession(bctx, api, d.events, params.SessionID)
+		if err != nil {
+			return &Response{Success: false, Error: fmt.Sprintf("backfill failed: %v", err)}
+		}
+		result.Added = n
+		d.log.Info("session backfill", "session_id", params.SessionID, "events", n)
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return &Response{Success: false, Error: fmt.Sprintf("marshal error: %v", err)}
+	}
+	return &Response{Success: true, Result: data}
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -130,7 +131,7 @@ func TestBackfillSessionConvertsParts(t *testing.T) {
 	}
 
 	// Verify ToolCallsFromEvents can extract the tool call from backfilled events.
-	calls := ToolCallsFromEvents(allEvents, 0)
+	calls := ToolCallsFromEvents(allEvents, 0, nil)
 	if len(calls) != 1 {
 		t.Fatalf("ToolCallsFromEvents returned %d calls, want 1", len(calls))
 	}
@@ -188,7 +189,7 @@ func TestBackfillEventsSkipsExistingSessions(t *testing.T) {
 	})
 
 	log := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
-	backfillEvents(context.Background(), api, store, events, log)
+	backfillEvents(context.Background(), api, store, events, DefaultBackfillConcurrency, log)
 
 	// ses_existing should still have exactly 1 event (the pre-populated one).
 	if events.Len("ses_existing") != 1 {
@@ -230,7 +231,7 @@ func TestBackfillEventsSkipsTerminatedSessions(t *testing.T) {
 	})
 
 	log := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
-	backfillEvents(context.Background(), api, store, events, log)
+	backfillEvents(context.Background(), api, store, events, DefaultBackfillConcurrency, log)
 
 	// Terminated session should not be backfilled.
 	if events.Len("ses_terminated") != 0 {
@@ -267,7 +268,7 @@ func TestBackfillSessionAPIError(t *testing.T) {
 func TestBackfillEventsNilStore(t *testing.T) {
 	// Should not panic with nil store.
 	log := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
-	backfillEvents(context.Background(), newOpencodeClient("http://localhost"), nil, NewEventBuffer(100), log)
+	backfillEvents(context.Background(), newOpencodeClient("http://localhost"), nil, NewEventBuffer(100), DefaultBackfillConcurrency, log)
 }
 
 func TestPartToEventEnvelope(t *testing.T) {
@@ -399,7 +400,7 @@ func TestBackfillMultipleMessages(t *testing.T) {
 
 	// Verify ToolCallsFromEvents finds both tool calls.
 	allEvents := events.Events("ses_multi")
-	calls := ToolCallsFromEvents(allEvents, 0)
+	calls := ToolCallsFromEvents(allEvents, 0, nil)
 	if len(calls) != 2 {
 		t.Fatalf("ToolCallsFromEvents returned %d calls, want 2", len(calls))
 	}
@@ -457,3 +458,112 @@ func TestFetchSessionMessagesTimeout(t *testing.T) {
 		t.Error("expected timeout error, got nil")
 	}
 }
+
+func TestHandleSessionBackfill(t *testing.T) {
+	textPart := json.RawMessage(`{"id": "prt_1", "type": "text", "text": "hello"}`)
+	toolPart := json.RawMessage(`{
+		"id": "prt_2",
+		"type": "tool",
+		"tool": "bash",
+		"state": {"status": "completed", "input": {"command": "ls"}, "time": {"start": 1700000000000, "end": 1700000000100}}
+	}`)
+
+	server := newTestOpencodeServer(t, map[string][]apiMessage{
+		"ses_missed": {{ID: "msg_1", Parts: []json.RawMessage{textPart, toolPart}}},
+	})
+	defer server.Close()
+
+	d := newTestDaemonForEvents()
+	d.config.ServerURL = server.URL
+
+	decode := func(resp *Response) SessionBackfillResult {
+		t.Helper()
+		if !resp.Success {
+			t.Fatalf("handleSessionBackfill failed: %s", resp.Error)
+		}
+		var result SessionBackfillResult
+		if err := json.Unmarshal(resp.Result, &result); err != nil {
+			t.Fatalf("unmarshal result: %v", err)
+		}
+		return result
+	}
+
+	first := decode(d.handleSessionBackfill(context.Background(), SessionBackfillParams{SessionID: "ses_missed"}))
+	if first.Added != 2 || first.Skipped {
+		t.Errorf("first backfill = %+v, want 2 events added", first)
+	}
+	if got := d.events.Len("ses_missed"); got != 2 {
+		t.Fatalf("buffer has %d events, want 2", got)
+	}
+
+	// A second call must not duplicate events already in the buffer.
+	second := decode(d.handleSessionBackfill(context.Background(), SessionBackfillParams{SessionID: "ses_missed"}))
+	if second.Added != 0 || !second.Skipped {
+		t.Errorf("second backfill = %+v, want skipped with 0 added", second)
+	}
+	if got := d.events.Len("ses_missed"); got != 2 {
+		t.Errorf("buffer has %d events after second call, want 2", got)
+	}
+}
+
+func TestHandleSessionBackfillRejectsInvalidSessionID(t *testing.T) {
+	d := newTestDaemonForEvents()
+
+	for _, id := range []string{"", "../etc/passwd", "ses?x=1"} {
+		resp := d.handleSessionBackfill(context.Background(), SessionBackfillParams{SessionID: id})
+		if resp.Success {
+			t.Errorf("handleSessionBackfill(%q) succeeded, want error", id)
+		}
+	}
+}
+
+func TestBackfillEventsBoundsConcurrentFetches(t *testing.T) {
+	const sessionsTotal = 20
+	const limit = 3
+
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			peak := maxInFlight.Load()
+			if n <= peak || maxInFlight.CompareAndSwap(peak, n) {
+				break
+			}
+		}
+		// Hold the request so concurrent fetches overlap.
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"id": "msg_1", "parts": [{"id": "prt_1", "type": "text", "text": "hi"}]}]`))
+	}))
+	defer server.Close()
+
+	store, err := sessions.Open(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range sessionsTotal {
+		_ = store.Upsert(sessions.Record{
+			ServerRef: "http://127.0.0.1:4096",
+			SessionID: fmt.Sprintf("ses_%02d", i),
+			Status:    sessions.StatusActive,
+		})
+	}
+
+	events := NewEventBuffer(DefaultEventBufSize)
+	log := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelError}))
+	backfillEvents(context.Background(), newOpencodeClient(server.URL), store, events, limit, log)
+
+	if got := maxInFlight.Load(); got > limit {
+		t.Errorf("max in-flight fetches = %d, want <= %d", got, limit)
+	}
+	if got := maxInFlight.Load(); got < 2 {
+		t.Errorf("max in-flight fetches = %d, want fetches to run concurrently", got)
+	}
+	for i := range sessionsTotal {
+		id := fmt.Sprintf("ses_%02d", i)
+		if events.Len(id) != 1 {
+			t.Errorf("%s has %d events, want 1", id, events.Len(id))
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// Package bench measures daemon internals for af bench. It drives a private
+// daemon.Pool with synthetic tasks; nothing in it runs inside the daemon.
+package bench
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/baiirun/aetherflow/internal/daemon"
+)
+
+// DefaultSpawnCmd is the agent af bench spawn launches: it exits at once
+// and ignores the arguments the pool appends.
+const DefaultSpawnCmd = "true"
+
+// SpawnOptions configures Spawn. Zero fields take defaults: Concurrency 4,
+// SpawnCmd DefaultSpawnCmd, Starter daemon.ExecProcessStarter, and a logger
+// that discards everything.
+type SpawnOptions struct {
+	Count       int
+	Concurrency int
+	SpawnCmd    string
+	Starter     daemon.ProcessStarter
+	Logger      *slog.Logger
+}
+
+// SpawnResult reports spawn latency over a benchmark run. Queue is the
+// time from a task first being seen ready to its agent running; Reap is the
+// time from the agent running to the pool recording its exit.
+type SpawnResult struct {
+	Count   int           `json:"count"`
+	Failed  int           `json:"failed"`
+	Elapsed time.Duration `json:"elapsed"`
+	Queue   LatencySpread `json:"queue"`
+	Reap    LatencySpread `json:"reap"`
+}
+
+// LatencySpread holds nearest-rank percentiles of a set of durations.
+type LatencySpread struct {
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P99 time.Duration `json:"p99"`
+	Max time.Duration `json:"max"`
+}
+
+func newLatencySpread(ds []time.Duration) LatencySpread {
+	if len(ds) == 0 {
+		return LatencySpread{}
+	}
+	slices.Sort(ds)
+	return LatencySpread{
+		P50: percentile(ds, 50),
+		P90: percentile(ds, 90),
+		P99: percentile(ds, 99),
+		Max: ds[len(ds)-1],
+	}
+}
+
+// percentile returns the nearest-rank pct-th percentile of sorted, which
+// must be non-empty and in ascending order.
+func percentile(sorted []time.Duration, pct float64) time.Duration {
+	rank := int(math.Ceil(pct / 100 * float64(len(sorted))))
+	rank = min(max(rank, 1), len(sorted))
+	return sorted[rank-1]
+}
+
+// workSource hands the pool synthetic tasks: claims are no-ops and every
+// task is a dependency-free worker task.
+type workSource struct{}
+
+func (workSource) Claim(context.Context, string, string) error { return nil }
+func (workSource) GetMeta(_ context.Context, id, _ string) (daemon.TaskMeta, error) {
+	return daemon.TaskMeta{ID: id, Type: "task", Status: "open"}, nil
+}
+func (workSource) Dependencies(context.Context, string, string) ([]daemon.Dependency, error) {
+	return nil, nil
+}
+func (workSource) Block(context.Context, string, string, string) error { return nil }
+
+// Spawn runs opts.Count trivial agents through a private pool, the
+// same claim, prompt, start, and reap path the daemon uses, and reports
+// the spawn latency. It feeds the pool the tasks still waiting whenever an
+// agent exits, like a poller that never sleeps. Crashed agents are not
+// respawned, and agents that fail to start are not retried; both count as
+// failed. It returns an error when no agent starts at all.
+func Spawn(ctx context.Context, opts SpawnOptions) (SpawnResult, error) {
+	if opts.Count <= 0 {
+		return SpawnResult{}, fmt.Errorf("count must be positive, got %d", opts.Count)
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+	if opts.SpawnCmd == "" {
+		opts.SpawnCmd = DefaultSpawnCmd
+	}
+	if opts.Starter == nil {
+		opts.Starter = daemon.ExecProcessStarter
+	}
+	if opts.Logger == nil {
+		opts.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	}
+
+	cfg := daemon.Config{
+		Project:    "bench",
+		PoolSize:   opts.Concurrency,
+		SpawnCmd:   opts.SpawnCmd,
+		MaxRetries: daemon.NoCrashRespawn,
+		Logger:     opts.Logger,
+	}
+	cfg.ApplyDefaults()
+	pool := daemon.NewPool(cfg, nil, opts.Starter, opts.Logger)
+	pool.SetWorkSource(workSource{})
+	pool.SetContext(ctx)
+
+	tasks := make([]daemon.Task, opts.Count)
+	for i := range tasks {
+		tasks[i] = daemon.Task{ID: fmt.Sprintf("bench-%d", i+1), Title: "spawn benchmark"}
+	}
+
+	start := time.Now()
+	// A task whose agent fails to start records no history, only an entry
+	// in the error feed, so track those tasks here: each counts as a failed
+	// sample and is not tried again.
+	startFailed := make(map[string]bool)
+	var lastFailure string
+	for {
+		changed := pool.Changes()
+		history := pool.History()
+		ended := 0
+		launched := make(map[string]bool, len(history))
+		for _, entry := range history {
+			launched[entry.TaskID] = true
+			if !entry.EndedAt.IsZero() {
+				ended++
+			}
+		}
+		if ended+len(startFailed) >= opts.Count {
+			break
+		}
+		var waiting []daemon.Task
+		for _, t := range tasks {
+			if !launched[t.ID] && !startFailed[t.ID] {
+				waiting = append(waiting, t)
+			}
+		}
+		pool.Schedule(ctx, waiting)
+		for _, e := range pool.RecentErrors(0) {
+			if e.Kind == daemon.OpErrorSpawnFailed && e.TaskID != "" && !launched[e.TaskID] {
+				startFailed[e.TaskID] = true
+				lastFailure = e.Message
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return SpawnResult{}, ctx.Err()
+		case <-changed:
+		case <-time.After(time.Second):
+		}
+	}
+	if len(startFailed) == opts.Count {
+		return SpawnResult{}, fmt.Errorf("no agent started: %s", lastFailure)
+	}
+
+	result := SpawnResult{Count: opts.Count, Failed: len(startFailed), Elapsed: time.Since(start)}
+	var queue, reap []time.Duration
+	for _, entry := range pool.History() {
+		if entry.Outcome != daemon.TaskOutcomeCompleted {
+			result.Failed++
+		}
+		queue = append(queue, entry.StartedAt.Sub(entry.QueuedAt))
+		reap = append(reap, entry.EndedAt.Sub(entry.StartedAt))
+	}
+	result.Queue = newLatencySpread(queue)
+	result.Reap = newLatencySpread(reap)
+	return result, nil
+}
@@ -0,0 +1,132 @@
+package bench
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/baiirun/aetherflow/internal/daemon"
+)
+
+// fakeProcess is an agent that exits with err once released.
+type fakeProcess struct {
+	pid    int
+	waitCh chan struct{}
+	err    error
+}
+
+func (p *fakeProcess) Wait() error {
+	<-p.waitCh
+	return p.err
+}
+
+func (p *fakeProcess) PID() int {
+	return p.pid
+}
+
+// newFakeProcess returns a process whose Wait blocks until release is
+// called, then returns err.
+func newFakeProcess(pid int, err error) (*fakeProcess, func()) {
+	p := &fakeProcess{pid: pid, waitCh: make(chan struct{}), err: err}
+	return p, func() { close(p.waitCh) }
+}
+
+// exitCodeError is a non-zero agent exit.
+type exitCodeError int
+
+func (e exitCodeError) Error() string { return fmt.Sprintf("exit status %d", int(e)) }
+func (e exitCodeError) ExitCode() int { return int(e) }
+
+func TestSpawnCollectsLatencies(t *testing.T) {
+	var mu sync.Mutex
+	spawned := 0
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ daemon.StartOptions) (daemon.Process, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		spawned++
+		// Every third agent crashes; the rest exit cleanly at once.
+		var err error
+		if spawned%3 == 0 {
+			err = exitCodeError(1)
+		}
+		proc, release := newFakeProcess(1000+spawned, err)
+		go func() {
+			time.Sleep(time.Millisecond)
+			release()
+		}()
+		return proc, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	result, err := Spawn(ctx, SpawnOptions{Count: 9, Concurrency: 3, Starter: starter})
+	if err != nil {
+		t.Fatalf("Spawn: %v", err)
+	}
+	if result.Count != 9 || result.Failed != 3 {
+		t.Errorf("count=%d failed=%d, want 9 and 3", result.Count, result.Failed)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if spawned != 9 {
+		t.Errorf("spawned = %d, want 9 (crashed agents are not respawned)", spawned)
+	}
+	for name, spread := range map[string]LatencySpread{"queue": result.Queue, "reap": result.Reap} {
+		if spread.P50 < 0 || spread.P50 > spread.P90 || spread.P90 > spread.P99 || spread.P99 > spread.Max {
+			t.Errorf("%s spread = %+v, want 0 <= p50 <= p90 <= p99 <= max", name, spread)
+		}
+	}
+	if result.Reap.Max < time.Millisecond {
+		t.Errorf("reap max = %v, want at least the agents' 1ms run time", result.Reap.Max)
+	}
+}
+
+func TestSpawnRejectsNonPositiveCount(t *testing.T) {
+	if _, err := Spawn(context.Background(), SpawnOptions{}); err == nil {
+		t.Error("Spawn with count 0 succeeded, want error")
+	}
+}
+
+func TestSpawnCountsStartFailuresAsFailed(t *testing.T) {
+	var mu sync.Mutex
+	spawned := 0
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ daemon.StartOptions) (daemon.Process, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		spawned++
+		// Every other agent fails to start.
+		if spawned%2 == 0 {
+			return nil, fmt.Errorf("exec: fake-agent: text file busy")
+		}
+		proc, release := newFakeProcess(1000+spawned, nil)
+		release()
+		return proc, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	result, err := Spawn(ctx, SpawnOptions{Count: 4, Concurrency: 2, Starter: starter})
+	if err != nil {
+		t.Fatalf("Spawn: %v", err)
+	}
+	if result.Count != 4 || result.Failed != 2 {
+		t.Errorf("count=%d failed=%d, want 4 and 2", result.Count, result.Failed)
+	}
+}
+
+func TestSpawnFailsWhenNoAgentStarts(t *testing.T) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ daemon.StartOptions) (daemon.Process, error) {
+		return nil, fmt.Errorf("exec: fake-agent: not found")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	_, err := Spawn(ctx, SpawnOptions{Count: 3, Starter: starter})
+	if err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Fatalf("Spawn error = %v, want the start failure", err)
+	}
+}
@@ -0,0 +1,150 @@
+package daemon
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock is a Clock that only moves when Advance is called. Tickers and
+// After channels fire when Advance crosses their deadline.
+type fakeClock struct {
+	mu      sync.Mutex
+	now     time.Time
+	tickers []*fakeTicker
+	waiters []fakeWaiter
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	c.waiters = append(c.waiters, fakeWaiter{at: c.now.Add(d), ch: ch})
+	return ch
+}
+
+func (c *fakeClock) NewTicker(d time.Duration) Ticker {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTicker{clock: c, period: d, next: c.now.Add(d), ch: make(chan time.Time, 1)}
+	c.tickers = append(c.tickers, t)
+	return t
+}
+
+// Advance moves the clock forward, firing due tickers and After channels.
+// Like time.Ticker, a ticker whose channel is full drops the tick.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+
+	for _, t := range c.tickers {
+		if t.stopped || t.next.After(c.now) {
+			continue
+		}
+		select {
+		case t.ch <- c.now:
+		default:
+		}
+		for !t.next.After(c.now) {
+			t.next = t.next.Add(t.period)
+		}
+	}
+
+	pending := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			pending = append(pending, w)
+			continue
+		}
+		w.ch <- c.now
+	}
+	c.waiters = pending
+}
+
+// tickerCount reports how many live tickers exist, so tests can wait for
+// a goroutine to create its ticker before advancing.
+func (c *fakeClock) tickerCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	n := 0
+	for _, t := range c.tickers {
+		if !t.stopped {
+			n++
+		}
+	}
+	return n
+}
+
+// waiterCount reports how many After channels have yet to fire.
+func (c *fakeClock) waiterCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+type fakeTicker struct {
+	clock   *fakeClock
+	period  time.Duration
+	next    time.Time
+	ch      chan time.Time
+	stopped bool
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.ch }
+
+func (t *fakeTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.stopped = true
+}
+
+func TestPoolSweepRunsOnFakeClock(t *testing.T) {
+	// Wait() hangs forever but the PID is gone; only the periodic sweep
+	// can free the slot.
+	proc := &fakeProcess{pid: 99999, waitCh: make(chan struct{})}
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		return proc, nil
+	}
+
+	pool := testPool(t, progRunner(testTaskMeta), starter)
+	clock := newFakeClock(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+	pool.clock = clock
+	pool.pidAlive = func(pid int) bool { return false }
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	taskCh := make(chan []Task, 1)
+	taskCh <- []Task{{ID: "ts-abc", Priority: 1, Title: "Do it"}}
+	go pool.Run(ctx, taskCh)
+
+	waitFor(t, func() bool { return len(pool.Status()) == 1 && clock.tickerCount() == 1 })
+
+	// Not yet due: the agent stays.
+	clock.Advance(sweepInterval - time.Second)
+	time.Sleep(20 * time.Millisecond)
+	if got := len(pool.Status()); got != 1 {
+		t.Fatalf("before sweep interval: running agents = %d, want 1", got)
+	}
+
+	clock.Advance(time.Second)
+	waitFor(t, func() bool { return len(pool.Status()) == 0 })
+}
@@ -34,10 +34,20 @@ func listenAddrFromURL(rawURL string) string {
 const (
 	DefaultPoolSize          = 3
 	DefaultServerURL         = "http://127.0.0.1:4096"
-	DefaultSpawnCmd          = "opencode run --attach " + DefaultServerURL + " --format json"
+	DefaultMaxPromptBytes    = 100 << 10
+	DefaultSpawnCmd          = "opencode run --format json" // --attach <server_url> is added at launch
 	DefaultMaxRetries        = 3
+	NoCrashRespawn           = -1 // MaxRetries value that disables crash respawn
+	DefaultMaxStartupRetries = 1
+	NoStartupRespawn         = -1 // MaxStartupRetries value that never respawns a startup failure
 	DefaultSpawnPolicy       = SpawnPolicyManual
 	DefaultReconcileInterval = 30 * time.Second
+	DefaultMetricsInterval   = time.Minute
+	DefaultSpawnClaimTTL     = 2 * time.Hour
+
+	DefaultBackfillConcurrency  = 4
+	DefaultProgWriteConcurrency = 1
+	DefaultConnReadTimeout      = 5 * time.Second
 )
 
 // SpawnPolicy controls whether the daemon auto-spawns pool agents from prog.
@@ -95,6 +105,12 @@ type Config struct {
 	// Required in auto mode; optional in manual mode.
 	Project string `yaml:"project"`
 
+	// Projects lists more prog projects for this daemon to schedule. Each
+	// gets its own poller and pool of PoolSize agents; all share the API,
+	// session registry, and event buffer. Project remains the primary one
+	// that commands without a project selector act on.
+	Projects []string `yaml:"projects"`
+
 	// PollInterval is how often to check prog for ready tasks.
 	PollInterval time.Duration `yaml:"poll_interval"`
 
@@ -112,9 +128,30 @@ type Config struct {
 	// "auto" polls prog and fills the pool; "manual" disables auto-spawn.
 	SpawnPolicy SpawnPolicy `yaml:"spawn_policy"`
 
-	// MaxRetries is the maximum number of crash respawns per task.
+	// MaxRetries is the maximum number of crash respawns per task. Zero
+	// means the default; NoCrashRespawn (-1) never respawns a crashed agent,
+	// so the task surfaces as crashed-max-retries on its first crash.
 	MaxRetries int `yaml:"max_retries"`
 
+	// MinHealthyUptime is how long an agent must run before a crash counts
+	// as a mid-task crash. An agent that dies sooner (e.g. bad config) is a
+	// startup failure: it is respawned at most MaxStartupRetries times and
+	// does not count toward MaxRetries. A crash after the agent was healthy
+	// clears its startup failures. Zero treats every crash as mid-task.
+	// MaxStartupRetries of zero means the default; NoStartupRespawn (-1)
+	// gives up on a task at its first startup failure.
+	MinHealthyUptime  time.Duration `yaml:"min_healthy_uptime"`
+	MaxStartupRetries int           `yaml:"max_startup_retries"`
+
+	// StartupProbe is a window after launch during which an agent must stay
+	// up. Unlike MinHealthyUptime it also catches agents that exit cleanly
+	// at once, and with StartupProbeSession an agent that has not opened
+	// its opencode session by the end of the window is stopped. Either
+	// failure is a startup failure, respawned at most MaxStartupRetries
+	// times. Zero disables the probe.
+	StartupProbe        time.Duration `yaml:"startup_probe"`
+	StartupProbeSession bool          `yaml:"startup_probe_session"`
+
 	// PromptDir overrides the embedded prompt templates with files from this
 	// directory. When empty, the daemon uses prompts compiled into the binary.
 	// Set this for development or to customize agent behavior without rebuilding.
@@ -132,9 +169,138 @@ type Config struct {
 	// ReconcileInterval is how often the daemon checks if reviewing tasks
 	// have been merged to main. When a task's af/<task-id> branch is an
 	// ancestor of main (or the branch no longer exists), the daemon
-	// automatically marks the task done via `prog done`.
+	// automatically marks the task done via `prog done`. The orphan scan
+	// (claimed in_progress tasks with no agent) runs on the same interval.
 	ReconcileInterval time.Duration `yaml:"reconcile_interval"`
 
+	// FatalExitCodes lists agent exit codes that indicate a deterministic
+	// failure (e.g. a bad prompt). A crash with one of these codes is not
+	// respawned and does not count toward MaxRetries. Any other non-zero
+	// exit is treated as transient and retried.
+	FatalExitCodes []int `yaml:"fatal_exit_codes"`
+
+	// SpawnIdleTimeout marks a running spawn exited when its session has
+	// produced no events for this long, even if the PID is still alive.
+	// Zero disables the idle check.
+	SpawnIdleTimeout time.Duration `yaml:"spawn_idle_timeout"`
+
+	// SpawnIdleSignal sends SIGTERM to a spawn's process group when it is
+	// marked exited by the idle timeout. Off by default: the agent may
+	// still be waiting on a long-running command.
+	SpawnIdleSignal bool `yaml:"spawn_idle_signal"`
+
+	// SpawnClaimTTL is how long a task labeled SpawnClaimLabel is left
+	// alone when no registered spawn is running it (af spawn --no-register,
+	// or a spawn from before a daemon restart). After that the claim is
+	// treated as abandoned and the task can be reclaimed.
+	SpawnClaimTTL time.Duration `yaml:"spawn_claim_ttl"`
+
+	// RoleLimits caps the memory and CPU of each pool agent by role
+	// (planner, worker), set before the agent's command runs. Roles not
+	// listed run unlimited. Linux only; elsewhere a warning is logged.
+	RoleLimits RoleLimits `yaml:"role_limits"`
+
+	// PrefetchMetaTTL enables fetching metadata for queued tasks ahead of
+	// spawn, so a task whose slot frees up is claimed without waiting on
+	// `prog show`. Prefetched metadata older than this is fetched again.
+	// Zero disables prefetching.
+	PrefetchMetaTTL time.Duration `yaml:"prefetch_meta_ttl"`
+
+	// QueueFile replaces `prog ready` with a JSON task list for offline
+	// scheduling and replay. "-" reads the list from stdin once at startup.
+	// Claims become no-ops so prog is never touched. Empty uses prog.
+	QueueFile string `yaml:"queue_file"`
+
+	// BackfillConcurrency caps how many sessions the startup backfill fetches
+	// from the opencode server at once.
+	BackfillConcurrency int `yaml:"backfill_concurrency"`
+
+	// ProgWriteConcurrency caps how many prog commands that modify tasks
+	// (start, done, block) the daemon runs at once. prog's SQLite database
+	// rejects concurrent writers with "database is locked"; reads are not
+	// limited.
+	ProgWriteConcurrency int `yaml:"prog_write_concurrency"`
+
+	// EventSink is a file the daemon appends every session event to as
+	// JSONL, for external tools to tail. Empty disables the sink.
+	EventSink string `yaml:"event_sink"`
+
+	// MetricsFile is a file the daemon appends a pool metrics sample to
+	// every MetricsInterval, as JSONL, for offline analysis. Empty
+	// disables sampling.
+	MetricsFile     string        `yaml:"metrics_file"`
+	MetricsInterval time.Duration `yaml:"metrics_interval"`
+
+	// InstanceName labels this daemon in status output and the TUI, to tell
+	// several daemons apart. Defaults to the project name.
+	InstanceName string `yaml:"instance_name"`
+
+	// FairRespawn makes a crashed task yield its slot when other tasks are
+	// waiting in the queue: it respawns once queued work has had a turn,
+	// instead of immediately. Off by default.
+	FairRespawn bool `yaml:"fair_respawn"`
+
+	// Preempt lets an urgent task take a slot in a full pool: the running
+	// agent with the lowest priority is stopped and resumes its session
+	// once a slot frees up. A task is urgent when its priority number is
+	// at most PreemptPriority (default 0, P0 only). Off by default.
+	Preempt         bool `yaml:"preempt"`
+	PreemptPriority int  `yaml:"preempt_priority"`
+
+	// ConnReadTimeout is how long the daemon's HTTP server waits for a
+	// complete request header on a connection, including one that was just
+	// opened, before closing it. Bounds clients that connect and go quiet.
+	ConnReadTimeout time.Duration `yaml:"conn_read_timeout"`
+
+	// SpawnIDPrefix and SpawnIDTemplate shape the IDs af spawn gives its
+	// agents, which also name their worktree and branch. See NewSpawnID.
+	SpawnIDPrefix   string `yaml:"spawn_id_prefix"`
+	SpawnIDTemplate string `yaml:"spawn_id_template"`
+
+	// MaxPromptBytes caps the rendered prompt passed to the spawn command.
+	// The prompt travels as a single argv string, which the OS limits, so
+	// a larger prompt is rejected with a clear error before starting.
+	MaxPromptBytes int `yaml:"max_prompt_bytes"`
+
+	// SpawnPromptRedaction limits how much of each af spawn prompt the
+	// daemon stores and shows in status: "none" (the default), "first_line",
+	// or "hash". The agent still gets the full prompt.
+	SpawnPromptRedaction PromptRedaction `yaml:"spawn_prompt_redaction"`
+
+	// TaskEnvFields lists prog task metadata fields exported to pool agents
+	// as AETHERFLOW_TASK_<FIELD> env vars (e.g. "labels" becomes
+	// AETHERFLOW_TASK_LABELS). Empty exports nothing.
+	TaskEnvFields []string `yaml:"task_env_fields"`
+
+	// ToolInputFields maps custom tool names to the input fields that
+	// summarize their calls in status and logs, e.g. {deploy: [service]}.
+	// Built-in tools keep their mappings unless overridden here.
+	ToolInputFields ToolInputFields `yaml:"tool_input_fields"`
+
+	// AllowedSpawnCmds restricts which programs agents may be launched
+	// with. When set, the first word of every resolved spawn command (pool
+	// agents and af spawn) must equal one of these entries exactly, e.g.
+	// "opencode" or "/usr/local/bin/opencode"; otherwise the daemon refuses
+	// to start and the spawn is rejected. Empty allows any command.
+	AllowedSpawnCmds []string `yaml:"allowed_spawn_cmds"`
+
+	// TUITheme names a built-in af tui color theme; empty uses the default.
+	// TUIColors overrides single style roles (title, dim, red, selected, ...)
+	// with ANSI or hex colors. Only af tui reads these; it validates them.
+	TUITheme  string            `yaml:"tui_theme"`
+	TUIColors map[string]string `yaml:"tui_colors"`
+
+	// SessionWhat orders the sources af sessions tries for its WHAT
+	// column (objective, title, work_ref, agent, project, dir); the first
+	// non-empty one wins. Empty uses that order. Only af sessions reads
+	// it; it validates it.
+	SessionWhat []string `yaml:"session_what"`
+
+	// ShutdownToken, when set, must accompany every shutdown request (af
+	// daemon stop --token), so a stray script can't stop the daemon by
+	// accident. It is a confirmation, not access control.
+	ShutdownToken string `yaml:"shutdown_token"`
+
 	// Runner is the command execution function. Not configurable via file/flags.
 	Runner CommandRunner `yaml:"-"`
 
@@ -146,6 +312,10 @@ type Config struct {
 	// to avoid requiring opencode on PATH.
 	ServerStarter func(ctx context.Context, serverURL string, env []string, logf func(string, ...any)) (*exec.Cmd, error) `yaml:"-"`
 
+	// Reload re-resolves the configuration (flags, file, defaults) when the
+	// daemon receives SIGHUP. When nil, SIGHUP is logged and ignored.
+	Reload func() (Config, error) `yaml:"-"`
+
 	// Logger is the structured logger. Not configurable via file/flags.
 	Logger *slog.Logger `yaml:"-"`
 }
@@ -180,10 +350,40 @@ func (c *Config) ApplyDefaults() {
 	if c.MaxRetries == 0 {
 		c.MaxRetries = DefaultMaxRetries
 	}
+	if c.MaxStartupRetries == 0 {
+		c.MaxStartupRetries = DefaultMaxStartupRetries
+	}
 	// PromptDir intentionally has no default — empty means use embedded prompts.
 	if c.ReconcileInterval == 0 {
 		c.ReconcileInterval = DefaultReconcileInterval
 	}
+	if c.MetricsInterval == 0 {
+		c.MetricsInterval = DefaultMetricsInterval
+	}
+	if c.SpawnClaimTTL == 0 {
+		c.SpawnClaimTTL = DefaultSpawnClaimTTL
+	}
+	if c.BackfillConcurrency == 0 {
+		c.BackfillConcurrency = DefaultBackfillConcurrency
+	}
+	if c.ProgWriteConcurrency == 0 {
+		c.ProgWriteConcurrency = DefaultProgWriteConcurrency
+	}
+	if c.ConnReadTimeout == 0 {
+		c.ConnReadTimeout = DefaultConnReadTimeout
+	}
+	if c.InstanceName == "" {
+		c.InstanceName = c.Project
+	}
+	if c.SpawnIDPrefix == "" {
+		c.SpawnIDPrefix = DefaultSpawnIDPrefix
+	}
+	if c.SpawnIDTemplate == "" {
+		c.SpawnIDTemplate = DefaultSpawnIDTemplate
+	}
+	if c.MaxPromptBytes == 0 {
+		c.MaxPromptBytes = DefaultMaxPromptBytes
+	}
 	if c.Logger == nil {
 		c.Logger = slog.Default()
 	}
@@ -231,6 +431,29 @@ func (c *Config) Validate() error {
 	if !spawnCmdHasAttach(c.SpawnCmd) {
 		c.SpawnCmd = EnsureAttachSpawnCmd(c.SpawnCmd, c.ServerURL)
 	}
+	if strings.Contains(ExpandSpawnCmd(c.SpawnCmd, SpawnCmdVars{}), "{{") {
+		return fmt.Errorf("spawn-cmd has an unknown placeholder (want %s)", spawnCmdPlaceholders)
+	}
+	for _, bin := range c.AllowedSpawnCmds {
+		if bin == "" || strings.ContainsAny(bin, " \t\n") {
+			return fmt.Errorf("allowed_spawn_cmds: invalid entry %q (want a single program name or path)", bin)
+		}
+	}
+	if err := c.CheckSpawnCmd(ExpandSpawnCmd(c.SpawnCmd, SpawnCmdVars{})); err != nil {
+		return err
+	}
+	if err := validateSpawnIDTemplate(c.SpawnIDPrefix, c.SpawnIDTemplate); err != nil {
+		return err
+	}
+	if err := c.ToolInputFields.validate(); err != nil {
+		return err
+	}
+	if err := c.SpawnPromptRedaction.Validate(); err != nil {
+		return err
+	}
+	if c.MaxPromptBytes < 0 || c.MaxPromptBytes > maxArgBytes {
+		return fmt.Errorf("max_prompt_bytes must be between 1 and %d (the OS limit for one argument)", maxArgBytes)
+	}
 	if c.SpawnPolicy == "" {
 		c.SpawnPolicy = DefaultSpawnPolicy
 	}
@@ -247,12 +470,84 @@ func (c *Config) Validate() error {
 	if c.Project != "" && !validProjectName.MatchString(c.Project) {
 		return fmt.Errorf("project name %q contains invalid characters (allowed: letters, digits, hyphens, underscores, dots)", c.Project)
 	}
-	if c.MaxRetries < 0 {
-		return fmt.Errorf("max-retries must be non-negative, got %d", c.MaxRetries)
+	if len(c.Projects) > 0 {
+		if c.Project == "" {
+			return fmt.Errorf("projects requires project to be set")
+		}
+		if c.QueueFile != "" {
+			return fmt.Errorf("projects cannot be combined with queue_file")
+		}
+		seen := map[string]bool{c.Project: true}
+		for _, project := range c.Projects {
+			if !validProjectName.MatchString(project) {
+				return fmt.Errorf("projects: project name %q contains invalid characters (allowed: letters, digits, hyphens, underscores, dots)", project)
+			}
+			if seen[project] {
+				return fmt.Errorf("projects: %q is listed more than once", project)
+			}
+			seen[project] = true
+		}
+	}
+	if c.MaxRetries < NoCrashRespawn {
+		return fmt.Errorf("max-retries must be non-negative, or -1 to disable crash respawn, got %d", c.MaxRetries)
+	}
+	for _, field := range c.TaskEnvFields {
+		if !validTaskEnvField.MatchString(field) {
+			return fmt.Errorf("task-env-fields: invalid field name %q (allowed: letters, digits, hyphens, underscores)", field)
+		}
+	}
+	if c.MinHealthyUptime < 0 {
+		return fmt.Errorf("min-healthy-uptime must be non-negative, got %v", c.MinHealthyUptime)
+	}
+	if c.PreemptPriority < 0 {
+		return fmt.Errorf("preempt_priority must be non-negative, got %d", c.PreemptPriority)
+	}
+	if c.MaxStartupRetries < NoStartupRespawn {
+		return fmt.Errorf("max-startup-retries must be non-negative, or -1 to never respawn a startup failure, got %d", c.MaxStartupRetries)
+	}
+	if c.StartupProbe < 0 {
+		return fmt.Errorf("startup_probe must be non-negative, got %v", c.StartupProbe)
+	}
+	if c.StartupProbeSession && c.StartupProbe == 0 {
+		return fmt.Errorf("startup_probe_session requires startup_probe")
 	}
 	if c.ReconcileInterval < 5*time.Second {
 		return fmt.Errorf("reconcile-interval must be at least 5s, got %v", c.ReconcileInterval)
 	}
+	if c.MetricsFile != "" && c.MetricsInterval < time.Second {
+		return fmt.Errorf("metrics-interval must be at least 1s, got %v", c.MetricsInterval)
+	}
+	for _, code := range c.FatalExitCodes {
+		if code < 1 || code > 255 {
+			return fmt.Errorf("fatal-exit-codes must be between 1 and 255, got %d", code)
+		}
+	}
+	if err := validateRoleLimits(c.RoleLimits); err != nil {
+		return err
+	}
+	if c.PrefetchMetaTTL < 0 {
+		return fmt.Errorf("prefetch-meta-ttl must be non-negative, got %v", c.PrefetchMetaTTL)
+	}
+	if c.SpawnIdleTimeout < 0 {
+		return fmt.Errorf("spawn-idle-timeout must be non-negative, got %v", c.SpawnIdleTimeout)
+	}
+	if c.SpawnClaimTTL < 0 {
+		return fmt.Errorf("spawn-claim-ttl must be non-negative, got %v", c.SpawnClaimTTL)
+	}
+	if c.BackfillConcurrency < 0 {
+		return fmt.Errorf("backfill-concurrency must be non-negative, got %d", c.BackfillConcurrency)
+	}
+	if c.ProgWriteConcurrency < 0 {
+		return fmt.Errorf("prog-write-concurrency must be non-negative, got %d", c.ProgWriteConcurrency)
+	}
+	if c.ConnReadTimeout < 0 {
+		return fmt.Errorf("conn-read-timeout must be positive, got %v", c.ConnReadTimeout)
+	}
+	if c.QueueFile != "" && c.QueueFile != QueueFileStdin {
+		if _, err := os.Stat(c.QueueFile); err != nil {
+			return fmt.Errorf("queue-file: %w", err)
+		}
+	}
 
 	// When PromptDir is set (filesystem override), resolve to absolute path
 	// and verify the directory contains the required prompt files.
@@ -311,6 +606,9 @@ func mergeConfig(src, dst *Config) {
 	if dst.Project == "" {
 		dst.Project = src.Project
 	}
+	if len(dst.Projects) == 0 {
+		dst.Projects = src.Projects
+	}
 	if dst.PollInterval == 0 {
 		dst.PollInterval = src.PollInterval
 	}
@@ -329,6 +627,18 @@ func mergeConfig(src, dst *Config) {
 	if dst.MaxRetries == 0 {
 		dst.MaxRetries = src.MaxRetries
 	}
+	if dst.MinHealthyUptime == 0 {
+		dst.MinHealthyUptime = src.MinHealthyUptime
+	}
+	if dst.MaxStartupRetries == 0 {
+		dst.MaxStartupRetries = src.MaxStartupRetries
+	}
+	if dst.StartupProbe == 0 {
+		dst.StartupProbe = src.StartupProbe
+	}
+	if src.StartupProbeSession && !dst.StartupProbeSession {
+		dst.StartupProbeSession = true
+	}
 	if dst.PromptDir == "" {
 		dst.PromptDir = src.PromptDir
 	}
@@ -343,4 +653,88 @@ func mergeConfig(src, dst *Config) {
 	if dst.SessionDir == "" {
 		dst.SessionDir = src.SessionDir
 	}
+	if len(dst.FatalExitCodes) == 0 {
+		dst.FatalExitCodes = src.FatalExitCodes
+	}
+	if dst.SpawnIdleTimeout == 0 {
+		dst.SpawnIdleTimeout = src.SpawnIdleTimeout
+	}
+	if dst.SpawnClaimTTL == 0 {
+		dst.SpawnClaimTTL = src.SpawnClaimTTL
+	}
+	if dst.RoleLimits == nil {
+		dst.RoleLimits = src.RoleLimits
+	}
+	if dst.PrefetchMetaTTL == 0 {
+		dst.PrefetchMetaTTL = src.PrefetchMetaTTL
+	}
+	if src.SpawnIdleSignal && !dst.SpawnIdleSignal {
+		dst.SpawnIdleSignal = true
+	}
+	if dst.QueueFile == "" {
+		dst.QueueFile = src.QueueFile
+	}
+	if dst.BackfillConcurrency == 0 {
+		dst.BackfillConcurrency = src.BackfillConcurrency
+	}
+	if dst.ProgWriteConcurrency == 0 {
+		dst.ProgWriteConcurrency = src.ProgWriteConcurrency
+	}
+	if dst.EventSink == "" {
+		dst.EventSink = src.EventSink
+	}
+	if dst.MetricsFile == "" {
+		dst.MetricsFile = src.MetricsFile
+	}
+	if dst.MetricsInterval == 0 {
+		dst.MetricsInterval = src.MetricsInterval
+	}
+	if dst.InstanceName == "" {
+		dst.InstanceName = src.InstanceName
+	}
+	if src.FairRespawn && !dst.FairRespawn {
+		dst.FairRespawn = true
+	}
+	if src.Preempt && !dst.Preempt {
+		dst.Preempt = true
+	}
+	if dst.PreemptPriority == 0 {
+		dst.PreemptPriority = src.PreemptPriority
+	}
+	if dst.ConnReadTimeout == 0 {
+		dst.ConnReadTimeout = src.ConnReadTimeout
+	}
+	if dst.SpawnIDPrefix == "" {
+		dst.SpawnIDPrefix = src.SpawnIDPrefix
+	}
+	if dst.SpawnIDTemplate == "" {
+		dst.SpawnIDTemplate = src.SpawnIDTemplate
+	}
+	if dst.MaxPromptBytes == 0 {
+		dst.MaxPromptBytes = src.MaxPromptBytes
+	}
+	if dst.ShutdownToken == "" {
+		dst.ShutdownToken = src.ShutdownToken
+	}
+	if dst.SpawnPromptRedaction == "" {
+		dst.SpawnPromptRedaction = src.SpawnPromptRedaction
+	}
+	if len(dst.TaskEnvFields) == 0 {
+		dst.TaskEnvFields = src.TaskEnvFields
+	}
+	if len(dst.ToolInputFields) == 0 {
+		dst.ToolInputFields = src.ToolInputFields
+	}
+	if len(dst.AllowedSpawnCmds) == 0 {
+		dst.AllowedSpawnCmds = src.AllowedSpawnCmds
+	}
+	if dst.TUITheme == "" {
+		dst.TUITheme = src.TUITheme
+	}
+	if dst.TUIColors == nil {
+		dst.TUIColors = src.TUIColors
+	}
+	if len(dst.SessionWhat) == 0 {
+		dst.SessionWhat = src.SessionWhat
+	}
 }
@@ -215,6 +215,16 @@ func TestConfigValidate(t *testing.T) {
 			cfg:     Config{Project: "test", PollInterval: time.Second, PoolSize: 1, SpawnCmd: ""},
 			wantErr: "spawn-cmd must not be empty",
 		},
+		{
+			name:    "unknown spawn cmd placeholder",
+			cfg:     Config{Project: "test", PollInterval: time.Second, PoolSize: 1, SpawnCmd: "opencode run --agent {{ role }}"},
+			wantErr: "spawn-cmd has an unknown placeholder",
+		},
+		{
+			name:    "spawn id template without hex",
+			cfg:     Config{Project: "test", PollInterval: time.Second, PoolSize: 1, SpawnCmd: "opencode run", SpawnIDTemplate: "{{prefix}}{{name}}"},
+			wantErr: "spawn_id_template must include {{hex}}",
+		},
 		{
 			name:    "invalid server url",
 			cfg:     Config{Project: "test", PollInterval: time.Second, PoolSize: 1, SpawnCmd: "opencode run", ServerURL: "://bad"},
@@ -230,16 +240,51 @@ func TestConfigValidate(t *testing.T) {
 			cfg:     Config{Project: "test", PollInterval: time.Second, PoolSize: 1, SpawnCmd: "cmd", SpawnPolicy: "sometimes"},
 			wantErr: "spawn-policy must be one of",
 		},
+		{
+			name:    "unknown spawn prompt redaction",
+			cfg:     Config{Project: "test", PollInterval: time.Second, PoolSize: 1, SpawnCmd: "cmd", SpawnPromptRedaction: "blur"},
+			wantErr: "spawn_prompt_redaction must be one of",
+		},
+		{
+			name:    "startup probe session without window",
+			cfg:     Config{Project: "test", PollInterval: time.Second, PoolSize: 1, SpawnCmd: "cmd", ReconcileInterval: DefaultReconcileInterval, StartupProbeSession: true},
+			wantErr: "startup_probe_session requires startup_probe",
+		},
+		{
+			name:    "negative preempt priority",
+			cfg:     Config{Project: "test", PollInterval: time.Second, PoolSize: 1, SpawnCmd: "cmd", ReconcileInterval: DefaultReconcileInterval, PreemptPriority: -1},
+			wantErr: "preempt_priority must be non-negative",
+		},
 		{
 			name:    "negative max retries",
-			cfg:     Config{Project: "test", PollInterval: time.Second, PoolSize: 1, SpawnCmd: "cmd", MaxRetries: -1, ReconcileInterval: DefaultReconcileInterval},
-			wantErr: "max-retries must be non-negative",
+			cfg:     Config{Project: "test", PollInterval: time.Second, PoolSize: 1, SpawnCmd: "cmd", MaxRetries: -2, ReconcileInterval: DefaultReconcileInterval},
+			wantErr: "max-retries must be non-negative, or -1",
 		},
 		{
 			name:    "reconcile interval too small",
 			cfg:     Config{Project: "test", PollInterval: time.Second, PoolSize: 1, SpawnCmd: "cmd", ReconcileInterval: time.Second},
 			wantErr: "reconcile-interval must be at least 5s",
 		},
+		{
+			name:    "spawn cmd outside allowlist",
+			cfg:     Config{Project: "test", PollInterval: time.Second, PoolSize: 1, SpawnCmd: "curl evil.sh", ReconcileInterval: DefaultReconcileInterval, AllowedSpawnCmds: []string{"opencode"}},
+			wantErr: `spawn command "curl" is not in allowed_spawn_cmds`,
+		},
+		{
+			name:    "projects without primary project",
+			cfg:     Config{SpawnPolicy: SpawnPolicyManual, PollInterval: time.Second, PoolSize: 1, SpawnCmd: "cmd", ReconcileInterval: DefaultReconcileInterval, ListenAddr: "127.0.0.1:7070", Projects: []string{"beta"}},
+			wantErr: "projects requires project",
+		},
+		{
+			name:    "duplicate project",
+			cfg:     Config{Project: "test", PollInterval: time.Second, PoolSize: 1, SpawnCmd: "cmd", ReconcileInterval: DefaultReconcileInterval, Projects: []string{"beta", "test"}},
+			wantErr: `projects: "test" is listed more than once`,
+		},
+		{
+			name:    "projects with queue file",
+			cfg:     Config{Project: "test", PollInterval: time.Second, PoolSize: 1, SpawnCmd: "cmd", ReconcileInterval: DefaultReconcileInterval, Projects: []string{"beta"}, QueueFile: "-"},
+			wantErr: "cannot be combined with queue_file",
+		},
 		{
 			name:    "invalid prompt dir",
 			cfg:     Config{Project: "test", PollInterval: time.Second, PoolSize: 1, SpawnCmd: "cmd", ReconcileInterval: DefaultReconcileInterval, PromptDir: "/nonexistent/prompts"},
@@ -286,6 +331,18 @@ func TestConfigValidate(t *testing.T) {
 			},
 			wantErr: "",
 		},
+		{
+			name: "fatal exit code out of range",
+			cfg: Config{
+				PollInterval:      time.Second,
+				PoolSize:          1,
+				SpawnCmd:          "cmd",
+				SpawnPolicy:       SpawnPolicyManual,
+				ReconcileInterval: DefaultReconcileInterval,
+				FatalExitCodes:    []int{2, 0},
+			},
+			wantErr: "fatal-exit-codes must be between 1 and 255",
+		},
 	}
 
 	for _, tt := range tests {
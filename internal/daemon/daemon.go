@@ -9,15 +9,18 @@ package daemon
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"net"
 	"net/http"
 	"os"
 	"os/exec"
 	"os/signal"
+	"slices"
 	"sync"
 	"syscall"
 	"time"
@@ -33,17 +36,24 @@ const (
 // Daemon holds the daemon state.
 type Daemon struct {
 	config       Config
+	queueErr     error // set when QueueFile could not be loaded; Run fails fast
 	httpServer   *http.Server
 	poller       *Poller
 	pool         *Pool
+	projects     []*projectRuntime // every scheduled project; projects[0] owns poller and pool
 	spawns       *SpawnRegistry
+	sstoreMu     sync.RWMutex // guards sstore and sstoreErr; the registry may open late
 	sstore       *sessions.Store
+	sstoreErr    error // why the session registry is unavailable
 	events       *EventBuffer
+	changes      *changeNotifier // wakes status streams; see httpStatusStream
 	server       *exec.Cmd
 	serverMu     sync.Mutex
 	authToken    string
 	shutdown     chan struct{}
 	shutdownOnce sync.Once
+	cleanStart   bool   // previous daemon left a clean-shutdown marker
+	stopReason   string // reason given with the shutdown request, if any
 	lifeMu       sync.RWMutex
 	life         protocol.DaemonLifecycleStatus
 	log          *slog.Logger
@@ -67,6 +77,7 @@ func New(cfg Config) *Daemon {
 	if cfg.Runner == nil {
 		cfg.Runner = ExecCommandRunner
 	}
+	cfg.Runner = guardProgWrites(cfg.Runner, cfg.ProgWriteConcurrency)
 	if cfg.Starter == nil {
 		cfg.Starter = ExecProcessStarter
 	}
@@ -75,26 +86,65 @@ func New(cfg Config) *Daemon {
 
 	var poller *Poller
 	var pool *Pool
-	store, storeErr := sessions.Open(cfg.SessionDir)
+	var queueErr error
+	store, storeErr := openSessionStoreWithRetry(cfg.SessionDir)
 	if storeErr != nil && log != nil {
-		log.Warn("session registry unavailable", "error", storeErr)
+		log.Warn("session registry unavailable, session persistence degraded", "error", storeErr)
 	}
+	spawns := NewSpawnRegistry()
+	changes := newChangeNotifier()
+	spawns.onChange = changes.Notify
+	var projects []*projectRuntime
 	if cfg.Project != "" {
-		poller = NewPoller(cfg.Project, cfg.PollInterval, cfg.Runner, log)
-		pool = NewPool(cfg, cfg.Runner, cfg.Starter, log)
-		if pool != nil {
-			pool.sstore = store
+		warnAggressivePolling(log, cfg.PollInterval, cfg.PoolSize)
+		// One name generator for every pool: agents are looked up by name
+		// across projects, so names must be unique daemon-wide.
+		names := protocol.NewNameGenerator()
+		for _, project := range append([]string{cfg.Project}, cfg.Projects...) {
+			rt := newProjectRuntime(cfg, project, store, names, log)
+			rt.pool.heldElsewhere = spawns.HoldsTask
+			rt.pool.releasedElsewhere = spawns.ReleasedTask
+			rt.pool.onChange = changes.Notify
+			projects = append(projects, rt)
+		}
+		poller, pool = projects[0].poller, projects[0].pool
+		if cfg.QueueFile != "" {
+			src, err := NewFileWorkSource(cfg.QueueFile, os.Stdin)
+			if err != nil {
+				queueErr = err
+			} else {
+				poller.queue = src
+				pool.work = src
+				pool.queue = src
+			}
+		}
+	}
+
+	// Restore recent events from the sink a previous daemon wrote, so
+	// status shows tool-call history without waiting for new events.
+	events := NewEventBuffer(DefaultEventBufSize)
+	if cfg.EventSink != "" {
+		n, err := events.LoadEventSink(cfg.EventSink, eventSinkReplayBytes, time.Now().Add(-sessionIdleTTL))
+		if err != nil && log != nil {
+			log.Warn("failed to replay event sink", "path", cfg.EventSink, "error", err)
+		}
+		if n > 0 && log != nil {
+			log.Info("replayed events from event sink", "path", cfg.EventSink, "events", n, "sessions", events.SessionCount())
 		}
 	}
 
 	return &Daemon{
-		config:   cfg,
-		poller:   poller,
-		pool:     pool,
-		spawns:   NewSpawnRegistry(),
-		sstore:   store,
-		events:   NewEventBuffer(DefaultEventBufSize),
-		shutdown: make(chan struct{}),
+		config:    cfg,
+		queueErr:  queueErr,
+		poller:    poller,
+		pool:      pool,
+		projects:  projects,
+		spawns:    spawns,
+		changes:   changes,
+		sstore:    store,
+		sstoreErr: storeErr,
+		events:    events,
+		shutdown:  make(chan struct{}),
 		life: protocol.DaemonLifecycleStatus{
 			State:       protocol.LifecycleStateStopped,
 			Project:     cfg.Project,
@@ -127,8 +177,25 @@ func (d *Daemon) Run() error {
 	if (d.poller == nil) != (d.pool == nil) {
 		return fmt.Errorf("invariant violated: poller and pool must be both nil or both non-nil")
 	}
+	if d.queueErr != nil {
+		return d.queueErr
+	}
 	d.setLifecycleState(protocol.LifecycleStateStarting, "")
 
+	// Hold a lock per project for the daemon's lifetime: a second daemon
+	// for the same project on another listen address would otherwise
+	// double-schedule its tasks.
+	if d.config.Project != "" {
+		for _, project := range append([]string{d.config.Project}, d.config.Projects...) {
+			release, err := acquireProjectLock(d.config.SessionDir, project)
+			if err != nil {
+				d.setLifecycleState(protocol.LifecycleStateFailed, err.Error())
+				return err
+			}
+			defer release()
+		}
+	}
+
 	daemonURL := daemonURLOrDefault(d.config.ListenAddr)
 	authToken, err := ensureDaemonAuthToken(daemonURL)
 	if err != nil {
@@ -137,15 +204,7 @@ func (d *Daemon) Run() error {
 	}
 	d.authToken = authToken
 
-	// Create HTTP server with the API handler.
-	d.httpServer = &http.Server{
-		Addr:              d.config.ListenAddr,
-		Handler:           d.newHTTPHandler(),
-		ReadHeaderTimeout: 5 * time.Second,
-		ReadTimeout:       30 * time.Second,
-		WriteTimeout:      30 * time.Second,
-		IdleTimeout:       60 * time.Second,
-	}
+	d.httpServer = d.newHTTPServer()
 
 	// Start listener early so we can detect port conflicts before launching
 	// background goroutines.
@@ -162,6 +221,19 @@ func (d *Daemon) Run() error {
 
 	d.log.Info("daemon started", "listen_addr", d.config.ListenAddr, "url", daemonURL)
 
+	// Find out whether the previous run stopped cleanly. The marker is
+	// removed either way, so if this run crashes the next start knows.
+	marker, clean, err := consumeShutdownMarker(d.config.SessionDir, d.config.Project)
+	if err != nil {
+		d.log.Warn("could not read clean-shutdown marker, assuming a crash", "error", err)
+	}
+	d.cleanStart = clean
+	if clean {
+		d.log.Info("previous daemon stopped cleanly", "stopped_at", marker.StoppedAt, "reason", marker.Reason)
+	} else {
+		d.log.Info("no clean-shutdown marker, previous daemon crashed or never ran")
+	}
+
 	// Handle shutdown gracefully
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
@@ -192,6 +264,21 @@ func (d *Daemon) Run() error {
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
 
+	// SIGHUP reloads the config without restarting.
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	defer signal.Stop(hupCh)
+	go func() {
+		for {
+			select {
+			case <-hupCh:
+				d.reloadConfig()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
 	go func() {
 		select {
 		case <-sigCh:
@@ -211,41 +298,66 @@ func (d *Daemon) Run() error {
 		if !policy.AutoSchedulingEnabled() {
 			d.log.Info("spawn policy manual: auto-scheduling disabled")
 		} else {
-			// Set pool context before launching goroutines so both Run and
-			// Reclaim (which calls respawn, which uses p.ctx) are safe.
-			d.pool.SetContext(ctx)
-
-			taskCh := d.poller.Start(ctx)
-			go d.pool.Run(ctx, taskCh)
-
-			// Reclaim orphaned in_progress tasks from a previous daemon session.
-			// These are tasks that were claimed in prog but whose agents died
-			// when the daemon crashed or was stopped.
-			//
-			// Delay briefly so the poller's initial `prog ready` completes first.
-			// Both hit prog's SQLite database and concurrent access during WAL
-			// mode initialization causes "database is locked" errors.
-			go func() {
-				select {
-				case <-time.After(2 * time.Second):
-					d.pool.Reclaim(ctx)
-				case <-ctx.Done():
-				}
+			for _, rt := range d.projects {
+				// Set pool context before launching goroutines so both Run and
+				// Reclaim (which calls respawn, which uses p.ctx) are safe.
+				rt.pool.SetContext(ctx)
+
+				taskCh := rt.poller.Start(ctx)
+				go rt.pool.Run(ctx, taskCh)
+			}
+
+			// A queue file stands in for prog entirely, so there is nothing
+			// to reclaim or reconcile against.
+			if d.config.QueueFile == "" {
+				d.startProgMaintenance(ctx)
+			}
+		}
+	}
+
+	// Mirror buffered events to the sink file before backfill starts pushing.
+	var sink *os.File
+	if d.config.EventSink != "" {
+		sink, err = openEventSink(d.config.EventSink)
+		if err != nil {
+			d.log.Warn("event sink disabled", "path", d.config.EventSink, "error", err)
+		} else {
+			d.events.SetSink(sink)
+			defer func() {
+				d.events.SetSink(nil)
+				_ = sink.Close()
 			}()
+		}
+	}
 
-			// Reconcile reviewing tasks — periodically check if branches have
-			// been merged to main and mark the corresponding tasks as done.
-			// Skip in solo mode: solo agents merge directly and call prog done
-			// themselves, so there's nothing to reconcile.
-			if !d.config.Solo {
-				go d.reconcileReviewing(ctx)
+	// Append pool metrics samples to the metrics file, one stream per
+	// project. Each sample is a single write, so lines don't interleave.
+	if d.config.MetricsFile != "" && d.pool != nil {
+		f, err := openMetricsFile(d.config.MetricsFile)
+		if err != nil {
+			d.log.Warn("metrics sampling disabled", "path", d.config.MetricsFile, "error", err)
+		} else {
+			var wg sync.WaitGroup
+			for _, pool := range d.pools() {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					pool.sampleMetrics(ctx, f, d.config.MetricsInterval)
+				}()
 			}
+			go func() {
+				wg.Wait()
+				_ = f.Close()
+			}()
 		}
 	}
 
 	// Sweep stale data periodically (spawn entries, event buffers, session records).
 	go d.sweepStale(ctx)
 
+	// Keep trying to open the session registry if it failed at startup.
+	go d.watchSessionStore(ctx)
+
 	// Backfill event buffer from the opencode REST API for sessions that
 	// existed before this daemon started. Runs in background so it doesn't
 	// block accepting connections — the daemon is usable immediately, and
@@ -254,16 +366,83 @@ func (d *Daemon) Run() error {
 		bctx, bcancel := context.WithTimeout(ctx, backfillTimeout)
 		defer bcancel()
 		api := newOpencodeClient(d.config.ServerURL)
-		backfillEvents(bctx, api, d.sstore, d.events, d.log)
+		backfillEvents(bctx, api, d.sessionStore(), d.events, d.config.BackfillConcurrency, d.log)
 	}()
 
 	// Serve HTTP. This blocks until the server is shut down.
 	if err := d.httpServer.Serve(listener); err != http.ErrServerClosed {
 		return fmt.Errorf("http server error: %w", err)
 	}
+	cancel()
+	d.flushOnShutdown(sink)
 	return nil
 }
 
+// flushOnShutdown runs once the HTTP server has drained on a graceful
+// stop. It syncs the event sink and crash retry counts to disk, then
+// writes the clean-shutdown marker, so the next start can tell this stop
+// from a crash. The session registry needs no flush: each change is
+// written through to disk.
+func (d *Daemon) flushOnShutdown(sink *os.File) {
+	if sink != nil {
+		d.events.SetSink(nil)
+		if err := sink.Sync(); err != nil {
+			d.log.Warn("failed to sync event sink", "path", d.config.EventSink, "error", err)
+		}
+	}
+	for _, pool := range d.pools() {
+		pool.persistRetries()
+	}
+	if err := writeShutdownMarker(d.config.SessionDir, d.config.Project, time.Now(), d.stopReason); err != nil {
+		d.log.Warn("failed to write clean-shutdown marker", "error", err)
+		return
+	}
+	d.log.Info("state flushed, clean shutdown recorded")
+}
+
+// startProgMaintenance starts the background jobs that keep prog's task
+// state in step with the pool: reclaiming orphans and reconciling reviews.
+func (d *Daemon) startProgMaintenance(ctx context.Context) {
+	// Reclaim orphaned in_progress tasks from a previous daemon session.
+	// These are tasks that were claimed in prog but whose agents died
+	// when the daemon crashed or was stopped.
+	//
+	// Delay briefly so the poller's initial `prog ready` completes first.
+	// Both hit prog's SQLite database and concurrent access during WAL
+	// mode initialization causes "database is locked" errors.
+	//
+	// After a clean stop the startup reclaim is skipped: any in_progress
+	// task was left by an operator's forced stop, so it is recovered by the
+	// slower orphan scan, which needs two passes to agree, giving time to
+	// pause or retire it first. After a crash the interrupted tasks are
+	// reclaimed straight away.
+	if d.cleanStart {
+		d.log.Info("reclaim: skipped after clean shutdown, orphan scan will recover stranded tasks")
+	} else {
+		go func() {
+			select {
+			case <-time.After(2 * time.Second):
+				for _, pool := range d.pools() {
+					pool.Reclaim(ctx)
+				}
+			case <-ctx.Done():
+			}
+		}()
+	}
+
+	// Periodically recover tasks claimed mid-run whose agent never started
+	// or went missing, so they don't wait for the next daemon restart.
+	go d.scanOrphans(ctx)
+
+	// Reconcile reviewing tasks — periodically check if branches have
+	// been merged to main and mark the corresponding tasks as done.
+	// Skip in solo mode: solo agents merge directly and call prog done
+	// themselves, so there's nothing to reconcile.
+	if !d.config.Solo {
+		go d.reconcileReviewing(ctx)
+	}
+}
+
 func (d *Daemon) superviseServer(ctx context.Context) {
 	daemonURL := daemonURLOrDefault(d.config.ListenAddr)
 
@@ -320,14 +499,13 @@ func (d *Daemon) sweepStale(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			if result := d.spawns.SweepDead(); result.Total() > 0 {
-				d.log.Info("spawn sweep", "marked_exited", result.Marked, "removed", result.Removed)
-			}
+			d.sweepDeadSpawns()
+			d.sweepIdleSpawns()
 			if n := d.events.SweepIdle(); n > 0 {
 				d.log.Info("event buffer sweep", "sessions_removed", n)
 			}
-			if d.sstore != nil {
-				if n, err := d.sstore.SweepStale(retentionTTL); err != nil {
+			if sstore := d.sessionStore(); sstore != nil {
+				if n, err := sstore.SweepStale(retentionTTL); err != nil {
 					d.log.Warn("session registry sweep failed", "error", err)
 				} else if n > 0 {
 					d.log.Info("session registry sweep", "records_removed", n)
@@ -337,11 +515,157 @@ func (d *Daemon) sweepStale(ctx context.Context) {
 	}
 }
 
-func (d *Daemon) handleShutdown(force bool) *Response {
-	d.log.Info("shutdown requested via API", "force", force)
+// scanOrphans runs the pool's orphan scan every ReconcileInterval.
+func (d *Daemon) scanOrphans(ctx context.Context) {
+	ticker := time.NewTicker(d.config.ReconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, pool := range d.pools() {
+				pool.RecoverOrphans(ctx)
+			}
+		}
+	}
+}
+
+// sweepDeadSpawns marks spawns exited whose process is gone and drops exited
+// entries past their retention. Such a process died without deregistering,
+// so its exit status is unknown; its session is marked idle, since the
+// session itself is still resumable as after a clean exit.
+func (d *Daemon) sweepDeadSpawns() {
+	result := d.spawns.SweepDead()
+	if result.Total() == 0 {
+		return
+	}
+	d.log.Info("spawn sweep", "marked_exited", result.Marked, "removed", result.Removed)
+	for _, spawnID := range result.Exited {
+		d.markSpawnSession(spawnID, sessions.StatusIdle)
+	}
+}
+
+// sweepIdleSpawns marks spawns exited whose session has been idle longer
+// than SpawnIdleTimeout, optionally signalling the lingering process.
+func (d *Daemon) sweepIdleSpawns() {
+	if d.config.SpawnIdleTimeout <= 0 {
+		return
+	}
+	for _, entry := range d.spawns.SweepIdle(d.events, d.config.SpawnIdleTimeout) {
+		d.log.Info("spawn idle timeout, marked exited",
+			"spawn_id", entry.SpawnID,
+			"pid", entry.PID,
+			"session_id", entry.SessionID,
+			"idle_timeout", d.config.SpawnIdleTimeout,
+		)
+		if d.config.SpawnIdleSignal {
+			if err := defaultStopProcess(entry.PID); err != nil {
+				d.log.Warn("failed to signal idle spawn", "spawn_id", entry.SpawnID, "pid", entry.PID, "error", err)
+			}
+		}
+		if sstore := d.sessionStore(); sstore != nil {
+			if _, err := sstore.SetStatusBySession(d.config.ServerURL, entry.SessionID, sessions.StatusIdle); err != nil {
+				d.log.Warn("failed to update idle spawn session status", "spawn_id", entry.SpawnID, "session_id", entry.SessionID, "error", err)
+			}
+			d.notifyChange()
+		}
+	}
+}
+
+// reloadConfig re-resolves the configuration via Config.Reload and applies
+// it. A failed reload keeps the running configuration.
+func (d *Daemon) reloadConfig() {
+	if d.config.Reload == nil {
+		d.log.Warn("received SIGHUP but config reload is not available")
+		return
+	}
+	next, err := d.config.Reload()
+	if err != nil {
+		d.log.Error("config reload failed, keeping current config", "error", err)
+		return
+	}
+	d.applyConfig(next)
+	d.log.Info("config reloaded")
+}
+
+// applyConfig applies the hot-reloadable fields of next (see
+// Pool.Reconfigure, plus the poll interval) to the running pool and poller.
+// Changes to any other field need a restart and are ignored with a warning.
+// d.config keeps the startup values.
+func (d *Daemon) applyConfig(next Config) {
+	cur := d.config
+	fixed := []struct {
+		name    string
+		changed bool
+	}{
+		{"listen_addr", next.ListenAddr != cur.ListenAddr},
+		{"project", next.Project != cur.Project},
+		{"projects", !slices.Equal(next.Projects, cur.Projects)},
+		{"server_url", next.ServerURL != cur.ServerURL},
+		{"spawn_policy", next.SpawnPolicy.Normalized() != cur.SpawnPolicy.Normalized()},
+		{"prompt_dir", next.PromptDir != cur.PromptDir},
+		{"solo", next.Solo != cur.Solo},
+		{"session_dir", next.SessionDir != cur.SessionDir},
+		{"reconcile_interval", next.ReconcileInterval != cur.ReconcileInterval},
+		{"queue_file", next.QueueFile != cur.QueueFile},
+		{"prefetch_meta_ttl", next.PrefetchMetaTTL != cur.PrefetchMetaTTL},
+		{"role_limits", !maps.Equal(next.RoleLimits, cur.RoleLimits)},
+		{"event_sink", next.EventSink != cur.EventSink},
+		{"metrics_file", next.MetricsFile != cur.MetricsFile},
+		{"metrics_interval", next.MetricsInterval != cur.MetricsInterval},
+		{"conn_read_timeout", next.ConnReadTimeout != cur.ConnReadTimeout},
+		{"instance_name", next.InstanceName != cur.InstanceName},
+		{"prog_write_concurrency", next.ProgWriteConcurrency != cur.ProgWriteConcurrency},
+		{"tool_input_fields", !maps.EqualFunc(next.ToolInputFields, cur.ToolInputFields, slices.Equal)},
+		{"shutdown_token", next.ShutdownToken != cur.ShutdownToken},
+		{"allowed_spawn_cmds", !slices.Equal(next.AllowedSpawnCmds, cur.AllowedSpawnCmds)},
+		{"spawn_prompt_redaction", next.SpawnPromptRedaction != cur.SpawnPromptRedaction},
+		{"spawn_idle_timeout", next.SpawnIdleTimeout != cur.SpawnIdleTimeout},
+		{"spawn_idle_signal", next.SpawnIdleSignal != cur.SpawnIdleSignal},
+		{"backfill_concurrency", next.BackfillConcurrency != cur.BackfillConcurrency},
+	}
+	for _, f := range fixed {
+		if f.changed {
+			d.log.Warn("config field cannot be reloaded, restart the daemon to apply it", "field", f.name)
+		}
+	}
+
+	for _, rt := range d.projects {
+		rt.pool.Reconfigure(next)
+		rt.poller.SetInterval(next.PollInterval)
+	}
+	if len(d.projects) > 0 {
+		warnAggressivePolling(d.log, next.PollInterval, next.PoolSize)
+	}
+}
+
+// ShutdownParams is the optional body of a shutdown request.
+type ShutdownParams struct {
+	// Reason says who or what stopped the daemon. It is logged and kept in
+	// the clean-shutdown marker for the next start to report.
+	Reason string `json:"reason,omitempty"`
+	// Token must match the configured shutdown_token, when one is set.
+	Token string `json:"token,omitempty"`
+}
+
+// maxShutdownReasonLen caps the stored shutdown reason.
+const maxShutdownReasonLen = 512
+
+func (d *Daemon) handleShutdown(force bool, params ShutdownParams) *Response {
+	if len(params.Reason) > maxShutdownReasonLen {
+		params.Reason = params.Reason[:maxShutdownReasonLen]
+	}
+	d.log.Info("shutdown requested via API", "force", force, "reason", params.Reason)
+
+	if want := d.config.ShutdownToken; want != "" && subtle.ConstantTimeCompare([]byte(params.Token), []byte(want)) != 1 {
+		d.log.Warn("shutdown rejected: missing or wrong confirmation token")
+		return &Response{Success: false, Error: "shutdown requires the daemon's confirmation token (af daemon stop --token)"}
+	}
 
 	life := d.lifecycleStatus()
-	activeWorkCount, _ := activeWorkSnapshot(d.pool, d.spawns)
+	activeWorkCount, _ := activeWorkSnapshot(d.pools(), d.spawns)
 	if !force && activeWorkCount > 0 {
 		result, _ := json.Marshal(protocol.StopDaemonResult{
 			Outcome: protocol.StopOutcomeRefused,
@@ -353,6 +677,7 @@ func (d *Daemon) handleShutdown(force bool) *Response {
 
 	// Signal shutdown in background so we can send the response first.
 	d.shutdownOnce.Do(func() {
+		d.stopReason = params.Reason
 		go func() {
 			d.setLifecycleState(protocol.LifecycleStateStopping, "")
 			close(d.shutdown)
@@ -393,7 +718,7 @@ func (d *Daemon) handleStatusAgent(ctx context.Context, params StatusAgentParams
 	}
 
 	start := time.Now()
-	detail, err := BuildAgentDetail(ctx, d.pool, d.spawns, d.sstore, d.events, d.config, d.config.Runner, params)
+	detail, err := d.buildAgentDetail(ctx, params)
 	if err != nil {
 		return &Response{Success: false, Error: err.Error()}
 	}
@@ -416,9 +741,66 @@ func (d *Daemon) handleStatusAgent(ctx context.Context, params StatusAgentParams
 	return &Response{Success: true, Result: result}
 }
 
-func (d *Daemon) handleStatusFull(ctx context.Context) *Response {
+// handleStatusAgents builds details for several agents concurrently,
+// reusing BuildAgentDetail for each one.
+func (d *Daemon) handleStatusAgents(ctx context.Context, params StatusAgentsParams) *Response {
+	if len(params.AgentNames) == 0 {
+		return &Response{Success: false, Error: "at least one agent name is required"}
+	}
+	if len(params.AgentNames) > maxBatchAgents {
+		return &Response{Success: false, Error: fmt.Sprintf("too many agents: %d (max %d)", len(params.AgentNames), maxBatchAgents)}
+	}
+
 	start := time.Now()
-	status := BuildFullStatus(ctx, d.pool, d.spawns, d.sstore, d.events, d.config, d.config.Runner)
+	result := StatusAgentsResult{Agents: make(map[string]*AgentDetail, len(params.AgentNames))}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, name := range params.AgentNames {
+		if name == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			detail, err := d.buildAgentDetail(ctx, StatusAgentParams{AgentName: name, Limit: params.Limit, ScanLimit: params.ScanLimit})
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if result.Errors == nil {
+					result.Errors = make(map[string]string)
+				}
+				result.Errors[name] = err.Error()
+				return
+			}
+			result.Agents[name] = detail
+		}(name)
+	}
+	wg.Wait()
+
+	d.log.Info("status.agents",
+		"requested", len(params.AgentNames),
+		"found", len(result.Agents),
+		"errors", len(result.Errors),
+		"duration", time.Since(start),
+	)
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return &Response{Success: false, Error: fmt.Sprintf("marshal error: %v", err)}
+	}
+	return &Response{Success: true, Result: data}
+}
+
+func (d *Daemon) handleStatusFull(ctx context.Context, params ProjectSelector) *Response {
+	start := time.Now()
+	status, err := d.buildProjectStatus(ctx, params.Project)
+	if err != nil {
+		return &Response{Success: false, Error: err.Error()}
+	}
+	if ctx.Err() != nil {
+		d.log.Info("status.full client disconnected", "stage", "build", "duration", time.Since(start))
+		return &Response{Success: false, Error: "client disconnected"}
+	}
 
 	d.log.Info("status.full",
 		"agents", len(status.Agents),
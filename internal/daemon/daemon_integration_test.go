@@ -123,7 +123,7 @@ func TestHTTPSpawnRegistrationLifecycle(t *testing.T) {
 	}
 
 	// Deregister marks it as exited.
-	if err := c.SpawnDeregister(spawnID); err != nil {
+	if err := c.SpawnDeregister(spawnID, 0); err != nil {
 		t.Fatalf("SpawnDeregister: %v", err)
 	}
 
@@ -1,11 +1,15 @@
 package daemon
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net"
+	"os"
 	"os/exec"
+	"slices"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -226,6 +230,65 @@ func TestDaemonSecondInstanceSameAddrFailsFast(t *testing.T) {
 	waitForDaemonExit(t, done1, 2*time.Second)
 }
 
+func TestDaemonSecondInstanceSameProjectFailsFast(t *testing.T) {
+	sessionDir := t.TempDir()
+	newDaemon := func() (*Daemon, string) {
+		listenAddr := testListenAddr(t)
+		return New(Config{
+			ListenAddr:        listenAddr,
+			Project:           "lock-test",
+			PollInterval:      10 * time.Millisecond,
+			PoolSize:          1,
+			SpawnCmd:          "echo test",
+			SpawnPolicy:       SpawnPolicyManual,
+			ReconcileInterval: DefaultReconcileInterval,
+			ServerStarter:     noopServerStarter,
+			SessionDir:        sessionDir,
+		}), listenAddr
+	}
+
+	d1, addr1 := newDaemon()
+	done1 := make(chan error, 1)
+	go func() { done1 <- d1.Run() }()
+	c := client.New(fmt.Sprintf("http://%s", addr1))
+	waitForDaemonStatus(t, c, 2*time.Second)
+
+	// A different listen address doesn't help: the project is taken.
+	d2, _ := newDaemon()
+	done2 := make(chan error, 1)
+	go func() { done2 <- d2.Run() }()
+
+	select {
+	case err := <-done2:
+		if err == nil {
+			t.Fatal("expected second daemon startup to fail, got nil error")
+		}
+		if !strings.Contains(err.Error(), `already running for project "lock-test"`) {
+			t.Fatalf("expected project lock error, got: %v", err)
+		}
+		if !strings.Contains(err.Error(), fmt.Sprintf("pid %d", os.Getpid())) {
+			t.Errorf("lock error should name the holder's pid, got: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("second daemon startup did not fail within timeout")
+	}
+	if got := d2.lifecycleStatus().State; got != protocol.LifecycleStateFailed {
+		t.Errorf("second daemon lifecycle = %q, want %q", got, protocol.LifecycleStateFailed)
+	}
+
+	if err := c.Shutdown(false); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+	waitForDaemonExit(t, done1, 2*time.Second)
+
+	// Once the first daemon is gone the lock is free again.
+	release, err := acquireProjectLock(sessionDir, "lock-test")
+	if err != nil {
+		t.Fatalf("lock still held after the first daemon stopped: %v", err)
+	}
+	release()
+}
+
 func TestHandleShutdownRefusesActiveWorkWithoutSession(t *testing.T) {
 	cfg := Config{
 		ListenAddr:        "127.0.0.1:7070",
@@ -252,7 +315,7 @@ func TestHandleShutdownRefusesActiveWorkWithoutSession(t *testing.T) {
 	}
 	d.pool.mu.Unlock()
 
-	resp := d.handleShutdown(false)
+	resp := d.handleShutdown(false, ShutdownParams{})
 	if !resp.Success {
 		t.Fatalf("handleShutdown error: %s", resp.Error)
 	}
@@ -307,3 +370,147 @@ func TestDaemonRunRejectsUnknownSpawnPolicy(t *testing.T) {
 		t.Fatalf("expected unknown-spawn-policy error, got: %v", err)
 	}
 }
+
+func TestDaemonReloadConfigAppliesHotFields(t *testing.T) {
+	cfg := Config{
+		ListenAddr:        "127.0.0.1:7070",
+		Project:           "reload-test",
+		PollInterval:      time.Second,
+		PoolSize:          1,
+		SpawnCmd:          "echo test",
+		MaxRetries:        1,
+		SpawnPolicy:       SpawnPolicyManual,
+		ReconcileInterval: DefaultReconcileInterval,
+		ServerStarter:     noopServerStarter,
+		SessionDir:        t.TempDir(),
+	}
+	next := cfg
+	next.PoolSize = 4
+	next.SpawnCmd = "echo reloaded"
+	next.MaxRetries = 5
+	next.Project = "other-project" // not reloadable
+	cfg.Reload = func() (Config, error) { return next, nil }
+
+	d := New(cfg)
+	d.reloadConfig()
+
+	if got := d.pool.Size(); got != 4 {
+		t.Errorf("pool size = %d, want 4", got)
+	}
+	if got := d.pool.spawnCmd(); got != "echo reloaded" {
+		t.Errorf("spawn cmd = %q, want %q", got, "echo reloaded")
+	}
+	d.pool.mu.RLock()
+	maxRetries := d.pool.config.MaxRetries
+	project := d.pool.config.Project
+	d.pool.mu.RUnlock()
+	if maxRetries != 5 {
+		t.Errorf("max retries = %d, want 5", maxRetries)
+	}
+	if project != "reload-test" {
+		t.Errorf("project = %q, want unchanged %q", project, "reload-test")
+	}
+	select {
+	case got := <-d.poller.reset:
+		if got != time.Second {
+			t.Errorf("poll interval = %v, want %v", got, time.Second)
+		}
+	default:
+		t.Error("poller did not receive an interval update")
+	}
+}
+
+func reloadTestConfig(t *testing.T) Config {
+	return Config{
+		ListenAddr:        "127.0.0.1:7070",
+		Project:           "reload-test",
+		PollInterval:      time.Second,
+		PoolSize:          1,
+		SpawnCmd:          "echo test",
+		SpawnPolicy:       SpawnPolicyManual,
+		ReconcileInterval: DefaultReconcileInterval,
+		ServerStarter:     noopServerStarter,
+		SessionDir:        t.TempDir(),
+	}
+}
+
+func TestDaemonReloadConfigAppliesPoolPolicyFields(t *testing.T) {
+	tests := []struct {
+		field  string
+		change func(*Config)
+		check  func(Config) bool
+	}{
+		{"fatal_exit_codes", func(c *Config) { c.FatalExitCodes = []int{2} }, func(c Config) bool { return slices.Equal(c.FatalExitCodes, []int{2}) }},
+		{"max_prompt_bytes", func(c *Config) { c.MaxPromptBytes = 4096 }, func(c Config) bool { return c.MaxPromptBytes == 4096 }},
+		{"task_env_fields", func(c *Config) { c.TaskEnvFields = []string{"labels"} }, func(c Config) bool { return slices.Equal(c.TaskEnvFields, []string{"labels"}) }},
+		{"spawn_claim_ttl", func(c *Config) { c.SpawnClaimTTL = time.Minute }, func(c Config) bool { return c.SpawnClaimTTL == time.Minute }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			cfg := reloadTestConfig(t)
+			next := cfg
+			tt.change(&next)
+			cfg.Reload = func() (Config, error) { return next, nil }
+
+			d := New(cfg)
+			d.reloadConfig()
+
+			d.pool.mu.RLock()
+			got := d.pool.config
+			d.pool.mu.RUnlock()
+			if !tt.check(got) {
+				t.Errorf("%s not applied on reload", tt.field)
+			}
+		})
+	}
+}
+
+func TestDaemonReloadConfigWarnsRestartOnlyFields(t *testing.T) {
+	tests := []struct {
+		field  string
+		change func(*Config)
+	}{
+		{"spawn_idle_timeout", func(c *Config) { c.SpawnIdleTimeout = time.Hour }},
+		{"spawn_idle_signal", func(c *Config) { c.SpawnIdleSignal = true }},
+		{"backfill_concurrency", func(c *Config) { c.BackfillConcurrency = 8 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.field, func(t *testing.T) {
+			var logs bytes.Buffer
+			cfg := reloadTestConfig(t)
+			cfg.Logger = slog.New(slog.NewTextHandler(&logs, nil))
+			next := cfg
+			tt.change(&next)
+			cfg.Reload = func() (Config, error) { return next, nil }
+
+			d := New(cfg)
+			d.reloadConfig()
+
+			if !strings.Contains(logs.String(), "cannot be reloaded") || !strings.Contains(logs.String(), "field="+tt.field) {
+				t.Errorf("no restart warning for %s; logs:\n%s", tt.field, logs.String())
+			}
+		})
+	}
+}
+
+func TestDaemonReloadConfigErrorKeepsCurrent(t *testing.T) {
+	cfg := Config{
+		ListenAddr:        "127.0.0.1:7070",
+		Project:           "reload-test",
+		PollInterval:      time.Second,
+		PoolSize:          2,
+		SpawnCmd:          "echo test",
+		SpawnPolicy:       SpawnPolicyManual,
+		ReconcileInterval: DefaultReconcileInterval,
+		ServerStarter:     noopServerStarter,
+		SessionDir:        t.TempDir(),
+		Reload:            func() (Config, error) { return Config{}, fmt.Errorf("pool_size must be positive") },
+	}
+
+	d := New(cfg)
+	d.reloadConfig()
+
+	if got := d.pool.Size(); got != 2 {
+		t.Errorf("pool size = %d, want 2 after failed reload", got)
+	}
+}
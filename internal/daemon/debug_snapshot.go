@@ -0,0 +1,80 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/baiirun/aetherflow/internal/protocol"
+)
+
+// DebugSnapshot is a one-shot capture of daemon internals for bug reports.
+// It composes the existing status views; credentials in the config are
+// redacted.
+type DebugSnapshot struct {
+	TakenAt      time.Time                      `json:"taken_at"`
+	Lifecycle    protocol.DaemonLifecycleStatus `json:"lifecycle"`
+	Config       EffectiveConfig                `json:"config"`
+	Status       FullStatus                     `json:"status"`
+	Spawns       []SpawnEntry                   `json:"spawns"`
+	RecentErrors []OpError                      `json:"recent_errors"`
+	Events       EventBufferStats               `json:"events"`
+	Server       ManagedServerStatus            `json:"server"`
+}
+
+// EventBufferStats summarizes the event buffer.
+type EventBufferStats struct {
+	Sessions           int `json:"sessions"`
+	Events             int `json:"events"`
+	CapacityPerSession int `json:"capacity_per_session"`
+}
+
+// ManagedServerStatus describes the opencode server the daemon supervises.
+// Managed is false when the daemon attached to a server it did not start.
+type ManagedServerStatus struct {
+	URL     string `json:"url"`
+	Managed bool   `json:"managed"`
+	PID     int    `json:"pid,omitempty"`
+}
+
+// handleDebugSnapshot captures the daemon's state for a bug report.
+func (d *Daemon) handleDebugSnapshot(ctx context.Context) *Response {
+	snap := DebugSnapshot{
+		TakenAt:   time.Now(),
+		Lifecycle: d.lifecycleStatus(),
+		Config:    NewEffectiveConfig(d.config),
+		Status:    d.buildFullStatus(ctx),
+		Spawns:    d.spawns.List(),
+		Server:    d.managedServerStatus(),
+	}
+	if snap.Spawns == nil {
+		snap.Spawns = []SpawnEntry{}
+	}
+	snap.RecentErrors = d.recentErrors(0)
+	if d.events != nil {
+		snap.Events = EventBufferStats{
+			Sessions:           d.events.SessionCount(),
+			Events:             d.events.EventCount(),
+			CapacityPerSession: d.events.maxSize,
+		}
+	}
+
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return &Response{Success: false, Error: fmt.Sprintf("marshal debug snapshot: %v", err)}
+	}
+	return &Response{Success: true, Result: data}
+}
+
+// managedServerStatus reports the supervised opencode server, if any.
+func (d *Daemon) managedServerStatus() ManagedServerStatus {
+	status := ManagedServerStatus{URL: RedactSecrets(d.config.ServerURL)}
+	d.serverMu.Lock()
+	defer d.serverMu.Unlock()
+	if d.server != nil && d.server.Process != nil {
+		status.Managed = true
+		status.PID = d.server.Process.Pid
+	}
+	return status
+}
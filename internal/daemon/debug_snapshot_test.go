@@ -0,0 +1,47 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestHandleDebugSnapshotIncludesAgentsAndConfig(t *testing.T) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		proc, _ := newFakeProcess(1234)
+		return proc, nil
+	}
+	runner := progRunner(testTaskMeta)
+	pool := testPool(t, runner, starter)
+	pool.ctx = context.Background()
+	pool.schedule(context.Background(), []Task{{ID: "ts-abc"}})
+
+	cfg := pool.config
+	cfg.SpawnCmd = "OPENAI_API_KEY=sk-123 opencode run"
+	cfg.Runner = runner
+	d := &Daemon{config: cfg, pool: pool, spawns: NewSpawnRegistry(), events: NewEventBuffer(0), log: testLogger()}
+
+	resp := d.handleDebugSnapshot(context.Background())
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	var snap DebugSnapshot
+	if err := json.Unmarshal(resp.Result, &snap); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if len(snap.Status.Agents) != 1 || snap.Status.Agents[0].TaskID != "ts-abc" {
+		t.Fatalf("Status.Agents = %+v, want one agent on ts-abc", snap.Status.Agents)
+	}
+	if snap.Config.Project != "testproject" || snap.Config.PoolSize != 2 {
+		t.Errorf("Config = project %q pool_size %d, want testproject/2", snap.Config.Project, snap.Config.PoolSize)
+	}
+	if strings.Contains(snap.Config.SpawnCmd, "sk-123") {
+		t.Errorf("Config.SpawnCmd = %q, want the API key redacted", snap.Config.SpawnCmd)
+	}
+	if snap.Spawns == nil || snap.RecentErrors == nil {
+		t.Error("Spawns and RecentErrors should be empty lists, not null")
+	}
+}
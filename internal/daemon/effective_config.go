@@ -0,0 +1,154 @@
+package daemon
+
+import (
+	"net/url"
+	"strings"
+)
+
+// EffectiveConfig is the printable view of a resolved Config, shared by
+// af config show and the debug snapshot. Field names match the config file keys so the output can be pasted back
+// into .aetherflow.yaml. Durations are rendered as strings ("10s") rather
+// than nanosecond integers.
+type EffectiveConfig struct {
+	ListenAddr           string            `yaml:"listen_addr" json:"listen_addr"`
+	Project              string            `yaml:"project" json:"project"`
+	Projects             []string          `yaml:"projects" json:"projects"`
+	PollInterval         string            `yaml:"poll_interval" json:"poll_interval"`
+	PoolSize             int               `yaml:"pool_size" json:"pool_size"`
+	SpawnCmd             string            `yaml:"spawn_cmd" json:"spawn_cmd"`
+	ServerURL            string            `yaml:"server_url" json:"server_url"`
+	SpawnPolicy          string            `yaml:"spawn_policy" json:"spawn_policy"`
+	MaxRetries           int               `yaml:"max_retries" json:"max_retries"`
+	MinHealthyUptime     string            `yaml:"min_healthy_uptime" json:"min_healthy_uptime"`
+	MaxStartupRetries    int               `yaml:"max_startup_retries" json:"max_startup_retries"`
+	StartupProbe         string            `yaml:"startup_probe" json:"startup_probe"`
+	StartupProbeSession  bool              `yaml:"startup_probe_session" json:"startup_probe_session"`
+	PromptDir            string            `yaml:"prompt_dir" json:"prompt_dir"`
+	Solo                 bool              `yaml:"solo" json:"solo"`
+	SessionDir           string            `yaml:"session_dir" json:"session_dir"`
+	ReconcileInterval    string            `yaml:"reconcile_interval" json:"reconcile_interval"`
+	FatalExitCodes       []int             `yaml:"fatal_exit_codes" json:"fatal_exit_codes"`
+	SpawnIdleTimeout     string            `yaml:"spawn_idle_timeout" json:"spawn_idle_timeout"`
+	SpawnIdleSignal      bool              `yaml:"spawn_idle_signal" json:"spawn_idle_signal"`
+	SpawnClaimTTL        string            `yaml:"spawn_claim_ttl" json:"spawn_claim_ttl"`
+	RoleLimits           RoleLimits        `yaml:"role_limits,omitempty" json:"role_limits,omitempty"`
+	PrefetchMetaTTL      string            `yaml:"prefetch_meta_ttl" json:"prefetch_meta_ttl"`
+	QueueFile            string            `yaml:"queue_file" json:"queue_file"`
+	BackfillConcurrency  int               `yaml:"backfill_concurrency" json:"backfill_concurrency"`
+	ProgWriteConcurrency int               `yaml:"prog_write_concurrency" json:"prog_write_concurrency"`
+	EventSink            string            `yaml:"event_sink" json:"event_sink"`
+	MetricsFile          string            `yaml:"metrics_file" json:"metrics_file"`
+	MetricsInterval      string            `yaml:"metrics_interval" json:"metrics_interval"`
+	InstanceName         string            `yaml:"instance_name" json:"instance_name"`
+	FairRespawn          bool              `yaml:"fair_respawn" json:"fair_respawn"`
+	Preempt              bool              `yaml:"preempt" json:"preempt"`
+	PreemptPriority      int               `yaml:"preempt_priority" json:"preempt_priority"`
+	ConnReadTimeout      string            `yaml:"conn_read_timeout" json:"conn_read_timeout"`
+	SpawnIDPrefix        string            `yaml:"spawn_id_prefix" json:"spawn_id_prefix"`
+	SpawnIDTemplate      string            `yaml:"spawn_id_template" json:"spawn_id_template"`
+	MaxPromptBytes       int               `yaml:"max_prompt_bytes" json:"max_prompt_bytes"`
+	SpawnPromptRedaction string            `yaml:"spawn_prompt_redaction" json:"spawn_prompt_redaction"`
+	ShutdownToken        string            `yaml:"shutdown_token" json:"shutdown_token"`
+	AllowedSpawnCmds     []string          `yaml:"allowed_spawn_cmds" json:"allowed_spawn_cmds"`
+	TaskEnvFields        []string          `yaml:"task_env_fields" json:"task_env_fields"`
+	ToolInputFields      ToolInputFields   `yaml:"tool_input_fields,omitempty" json:"tool_input_fields,omitempty"`
+	TUITheme             string            `yaml:"tui_theme" json:"tui_theme"`
+	TUIColors            map[string]string `yaml:"tui_colors,omitempty" json:"tui_colors,omitempty"`
+	SessionWhat          []string          `yaml:"session_what,omitempty" json:"session_what,omitempty"`
+}
+
+// NewEffectiveConfig builds the printable view of cfg, with credentials in
+// spawn_cmd and server_url redacted.
+func NewEffectiveConfig(cfg Config) EffectiveConfig {
+	return EffectiveConfig{
+		ListenAddr:           cfg.ListenAddr,
+		Project:              cfg.Project,
+		Projects:             cfg.Projects,
+		PollInterval:         cfg.PollInterval.String(),
+		PoolSize:             cfg.PoolSize,
+		SpawnCmd:             RedactSecrets(cfg.SpawnCmd),
+		ServerURL:            RedactSecrets(cfg.ServerURL),
+		SpawnPolicy:          string(cfg.SpawnPolicy.Normalized()),
+		MaxRetries:           cfg.MaxRetries,
+		MinHealthyUptime:     cfg.MinHealthyUptime.String(),
+		MaxStartupRetries:    cfg.MaxStartupRetries,
+		StartupProbe:         cfg.StartupProbe.String(),
+		StartupProbeSession:  cfg.StartupProbeSession,
+		PromptDir:            cfg.PromptDir,
+		Solo:                 cfg.Solo,
+		SessionDir:           cfg.SessionDir,
+		ReconcileInterval:    cfg.ReconcileInterval.String(),
+		FatalExitCodes:       cfg.FatalExitCodes,
+		SpawnIdleTimeout:     cfg.SpawnIdleTimeout.String(),
+		SpawnIdleSignal:      cfg.SpawnIdleSignal,
+		SpawnClaimTTL:        cfg.SpawnClaimTTL.String(),
+		RoleLimits:           cfg.RoleLimits,
+		PrefetchMetaTTL:      cfg.PrefetchMetaTTL.String(),
+		QueueFile:            cfg.QueueFile,
+		BackfillConcurrency:  cfg.BackfillConcurrency,
+		ProgWriteConcurrency: cfg.ProgWriteConcurrency,
+		EventSink:            cfg.EventSink,
+		MetricsFile:          cfg.MetricsFile,
+		MetricsInterval:      cfg.MetricsInterval.String(),
+		InstanceName:         cfg.InstanceName,
+		FairRespawn:          cfg.FairRespawn,
+		Preempt:              cfg.Preempt,
+		PreemptPriority:      cfg.PreemptPriority,
+		ConnReadTimeout:      cfg.ConnReadTimeout.String(),
+		SpawnIDPrefix:        cfg.SpawnIDPrefix,
+		SpawnIDTemplate:      cfg.SpawnIDTemplate,
+		MaxPromptBytes:       cfg.MaxPromptBytes,
+		SpawnPromptRedaction: string(cfg.SpawnPromptRedaction),
+		ShutdownToken:        redactToken(cfg.ShutdownToken),
+		AllowedSpawnCmds:     cfg.AllowedSpawnCmds,
+		TaskEnvFields:        cfg.TaskEnvFields,
+		ToolInputFields:      cfg.ToolInputFields,
+		TUITheme:             cfg.TUITheme,
+		TUIColors:            cfg.TUIColors,
+		SessionWhat:          cfg.SessionWhat,
+	}
+}
+
+// redactToken hides a configured token while still showing whether one is set.
+func redactToken(s string) string {
+	if s == "" {
+		return ""
+	}
+	return "REDACTED"
+}
+
+// RedactSecrets masks credentials in a command line or URL: passwords in
+// URL userinfo, and values of KEY=value words whose key looks secret
+// (e.g. OPENAI_API_KEY=sk-...).
+func RedactSecrets(s string) string {
+	fields := strings.Fields(s)
+	changed := false
+	for i, f := range fields {
+		if key, _, ok := strings.Cut(f, "="); ok && looksSecret(key) {
+			fields[i] = key + "=REDACTED"
+			changed = true
+			continue
+		}
+		if u, err := url.Parse(f); err == nil && u.User != nil {
+			if _, hasPassword := u.User.Password(); hasPassword {
+				fields[i] = u.Redacted()
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return s
+	}
+	return strings.Join(fields, " ")
+}
+
+// looksSecret reports whether a KEY in KEY=value names a credential.
+func looksSecret(key string) bool {
+	key = strings.ToUpper(strings.TrimLeft(key, "-"))
+	for _, marker := range []string{"TOKEN", "SECRET", "PASSWORD", "API_KEY", "APIKEY"} {
+		if strings.Contains(key, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// errorFeedSize bounds the recent-errors feed. Older entries are dropped.
+const errorFeedSize = 200
+
+// Kinds of operational errors recorded in the recent-errors feed.
+const (
+	OpErrorSpawnFailed     = "spawn_failed"      // a queued task could not be started
+	OpErrorRespawnFailed   = "respawn_failed"    // a crashed or reclaimed task could not be restarted
+	OpErrorFatalExit       = "fatal_exit"        // agent exited with a configured fatal exit code
+	OpErrorCrashMaxRetries = "crash_max_retries" // agent crashed with its retry budget spent
+	OpErrorStartupFailed   = "startup_failed"    // agent kept dying before MinHealthyUptime
+	OpErrorReclaimFailed   = "reclaim_failed"    // orphan recovery could not query prog
+	OpErrorOrphanFound     = "orphan_found"      // a claimed task was found with no agent
+	OpErrorTaskGone        = "task_gone"         // a running agent's task was deleted or cancelled
+)
+
+// OpError is one entry in the recent-errors feed: an operational failure
+// that was logged, kept so it can be reviewed without the daemon log.
+type OpError struct {
+	Time    time.Time `json:"time"`
+	Level   string    `json:"level"` // "error" or "warn"
+	Kind    string    `json:"kind"`
+	TaskID  string    `json:"task_id,omitempty"`
+	AgentID string    `json:"agent_id,omitempty"`
+	Message string    `json:"message"`
+}
+
+// errorFeed keeps the most recent operational errors in a fixed-size ring.
+// Safe for concurrent use; it has its own lock so recording never needs
+// the pool mutex.
+type errorFeed struct {
+	mu      sync.Mutex
+	entries []OpError
+	next    int
+}
+
+func (f *errorFeed) record(e OpError) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.entries) < errorFeedSize {
+		f.entries = append(f.entries, e)
+		return
+	}
+	f.entries[f.next] = e
+	f.next = (f.next + 1) % errorFeedSize
+}
+
+// recent returns up to limit entries, newest first. limit <= 0 returns all.
+func (f *errorFeed) recent(limit int) []OpError {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := len(f.entries)
+	if limit <= 0 || limit > n {
+		limit = n
+	}
+	out := make([]OpError, 0, limit)
+	for i := range limit {
+		// The newest entry sits just before next once the ring has wrapped.
+		out = append(out, f.entries[(f.next-1-i+2*n)%n])
+	}
+	return out
+}
+
+// recordError adds an entry to the pool's recent-errors feed. Call it next
+// to the log line reporting the same failure.
+func (p *Pool) recordError(level, kind, taskID, agentID string, err error) {
+	p.errs.record(OpError{
+		Time:    p.clock.Now(),
+		Level:   level,
+		Kind:    kind,
+		TaskID:  taskID,
+		AgentID: agentID,
+		Message: err.Error(),
+	})
+}
+
+// RecentErrors returns up to limit recent operational errors, newest first.
+// limit <= 0 returns the whole feed.
+func (p *Pool) RecentErrors(limit int) []OpError {
+	return p.errs.recent(limit)
+}
+
+// ErrorsRecentParams is the request shape for the recent-errors handler.
+type ErrorsRecentParams struct {
+	Limit int `json:"limit"`
+}
+
+// ErrorsRecentResult is the response for the recent-errors handler.
+type ErrorsRecentResult struct {
+	Errors []OpError `json:"errors"`
+}
+
+// recentErrors merges the recent-errors feeds of every project's pool,
+// newest first, keeping up to limit entries (limit <= 0 keeps all). Without
+// a pool (manual spawn policy) the feed is simply empty.
+func (d *Daemon) recentErrors(limit int) []OpError {
+	errs := []OpError{}
+	for _, pool := range d.pools() {
+		errs = append(errs, pool.RecentErrors(limit)...)
+	}
+	slices.SortStableFunc(errs, func(a, b OpError) int { return b.Time.Compare(a.Time) })
+	if limit > 0 && len(errs) > limit {
+		errs = errs[:limit]
+	}
+	return errs
+}
+
+// handleErrorsRecent returns the pools' recent operational errors.
+func (d *Daemon) handleErrorsRecent(params ErrorsRecentParams) *Response {
+	result, err := json.Marshal(ErrorsRecentResult{Errors: d.recentErrors(params.Limit)})
+	if err != nil {
+		return &Response{Success: false, Error: fmt.Sprintf("marshal recent errors: %v", err)}
+	}
+	return &Response{Success: true, Result: result}
+}
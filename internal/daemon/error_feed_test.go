@@ -0,0 +1,72 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestSpawnFailureAppearsInRecentErrors(t *testing.T) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		return nil, errors.New("exec: \"opencode\": executable file not found in $PATH")
+	}
+	pool := testPool(t, progRunner(testTaskMeta), starter)
+	pool.ctx = context.Background()
+
+	pool.schedule(context.Background(), []Task{{ID: "ts-abc"}})
+
+	d := &Daemon{config: pool.config, pool: pool, log: testLogger()}
+	resp := d.handleErrorsRecent(ErrorsRecentParams{})
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	var result ErrorsRecentResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if len(result.Errors) != 1 {
+		t.Fatalf("got %d errors, want 1: %+v", len(result.Errors), result.Errors)
+	}
+	e := result.Errors[0]
+	if e.Kind != OpErrorSpawnFailed || e.TaskID != "ts-abc" || e.Level != "error" {
+		t.Errorf("entry = %+v, want spawn_failed error for ts-abc", e)
+	}
+	if !strings.Contains(e.Message, "executable file not found") {
+		t.Errorf("message = %q, want the starter error", e.Message)
+	}
+	if e.Time.IsZero() {
+		t.Error("entry has no timestamp")
+	}
+}
+
+func TestErrorFeedKeepsNewestFirstAndBounded(t *testing.T) {
+	var f errorFeed
+	for i := range errorFeedSize + 5 {
+		f.record(OpError{Message: fmt.Sprintf("e%d", i)})
+	}
+
+	all := f.recent(0)
+	if len(all) != errorFeedSize {
+		t.Fatalf("feed holds %d entries, want %d", len(all), errorFeedSize)
+	}
+	if want := fmt.Sprintf("e%d", errorFeedSize+4); all[0].Message != want {
+		t.Errorf("newest = %q, want %q", all[0].Message, want)
+	}
+	if all[len(all)-1].Message != "e5" {
+		t.Errorf("oldest = %q, want e5", all[len(all)-1].Message)
+	}
+
+	top := f.recent(2)
+	if len(top) != 2 || top[1].Message != fmt.Sprintf("e%d", errorFeedSize+3) {
+		t.Errorf("recent(2) = %+v", top)
+	}
+
+	var empty errorFeed
+	if got := empty.recent(10); len(got) != 0 {
+		t.Errorf("empty feed returned %d entries", len(got))
+	}
+}
@@ -1,7 +1,11 @@
 package daemon
 
 import (
+	"bufio"
 	"encoding/json"
+	"fmt"
+	"io"
+	"os"
 	"sync"
 	"time"
 )
@@ -35,6 +39,13 @@ type EventBuffer struct {
 	mu       sync.RWMutex
 	sessions map[string]*sessionBuf
 	maxSize  int
+
+	// sink receives every pushed event as a JSON line when set.
+	// Writes are best-effort: a failed write never blocks buffering.
+	// sinkMu guards it and serializes writes apart from mu, so a slow
+	// disk never holds up readers of the buffer.
+	sinkMu sync.Mutex
+	sink   io.Writer
 }
 
 type sessionBuf struct {
@@ -56,17 +67,34 @@ func NewEventBuffer(maxSize int) *EventBuffer {
 // Push appends an event to the session's buffer, evicting the oldest
 // event if the buffer is at capacity.
 func (b *EventBuffer) Push(ev SessionEvent) {
+	b.writeSink(ev)
+
 	b.mu.Lock()
 	defer b.mu.Unlock()
+	b.appendLocked(ev, time.Now())
+}
 
-	now := time.Now()
+// writeSink mirrors ev to the sink, if one is set.
+func (b *EventBuffer) writeSink(ev SessionEvent) {
+	b.sinkMu.Lock()
+	defer b.sinkMu.Unlock()
+	if b.sink == nil {
+		return
+	}
+	if line, err := json.Marshal(ev); err == nil {
+		_, _ = b.sink.Write(append(line, '\n'))
+	}
+}
 
+// appendLocked adds ev to its session's buffer as pushed at the given
+// time. Caller must hold b.mu for writing.
+func (b *EventBuffer) appendLocked(ev SessionEvent, at time.Time) {
 	buf, ok := b.sessions[ev.SessionID]
 	if !ok {
 		buf = &sessionBuf{events: make([]SessionEvent, 0, 64)}
 		b.sessions[ev.SessionID] = buf
 	}
-	buf.lastPush = now
+	buf.lastPush = at
 
 	if len(buf.events) >= b.maxSize {
 		// Drop oldest event. This is O(n) but maxSize is bounded (2000)
@@ -78,6 +106,90 @@ func (b *EventBuffer) Push(ev SessionEvent) {
 	}
 }
 
+// SetSink mirrors every subsequently pushed event to w as JSONL, in push
+// order. Pass nil to stop mirroring; once SetSink returns, no write to the
+// old sink is in flight.
+func (b *EventBuffer) SetSink(w io.Writer) {
+	b.sinkMu.Lock()
+	defer b.sinkMu.Unlock()
+	b.sink = w
+}
+
+// openEventSink opens path for appending events, creating it if needed.
+// The file is owner-only since events carry session content.
+func openEventSink(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening event sink: %w", err)
+	}
+	// Tighten permissions on a file that already existed.
+	if err := f.Chmod(0o600); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("securing event sink: %w", err)
+	}
+	return f, nil
+}
+
+// eventSinkReplayBytes bounds how much of the end of the event sink the
+// daemon reads back on startup.
+const eventSinkReplayBytes = 32 << 20
+
+// LoadEventSink restores events from an event sink file written by a
+// previous daemon, so status has tool-call history before any new events
+// arrive. Only the last maxBytes of the file are read, and events older
+// than since are skipped. Restored events are not mirrored back to the
+// sink. Sessions restored here are then skipped by REST backfill, which
+// only fills sessions with no buffered events. A missing file restores
+// nothing; malformed lines are skipped. Returns the number of events
+// restored.
+func (b *EventBuffer) LoadEventSink(path string, maxBytes int64, since time.Time) (int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("opening event sink: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("reading event sink: %w", err)
+	}
+	offset := max(info.Size()-maxBytes, 0)
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, fmt.Errorf("reading event sink: %w", err)
+	}
+	r := bufio.NewReader(f)
+	if offset > 0 {
+		// Drop the partial line the window starts in.
+		if _, err := r.ReadBytes('\n'); err != nil {
+			return 0, nil
+		}
+	}
+
+	cutoff := since.UnixMilli()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	restored := 0
+	for {
+		line, err := r.ReadBytes('\n')
+		if len(line) > 0 {
+			var ev SessionEvent
+			if json.Unmarshal(line, &ev) == nil && ev.SessionID != "" && ev.Timestamp >= cutoff {
+				b.appendLocked(ev, time.UnixMilli(ev.Timestamp))
+				restored++
+			}
+		}
+		if err == io.EOF {
+			return restored, nil
+		}
+		if err != nil {
+			return restored, fmt.Errorf("reading event sink: %w", err)
+		}
+	}
+}
+
 // Events returns all events for the given session, oldest first.
 // Returns nil if no events exist for the session.
 func (b *EventBuffer) Events(sessionID string) []SessionEvent {
@@ -95,6 +207,23 @@ func (b *EventBuffer) Events(sessionID string) []SessionEvent {
 	return out
 }
 
+// Tail returns the newest n events for the given session, oldest first.
+// Returns nil if no events exist for the session.
+func (b *EventBuffer) Tail(sessionID string, n int) []SessionEvent {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	buf, ok := b.sessions[sessionID]
+	if !ok || len(buf.events) == 0 || n <= 0 {
+		return nil
+	}
+
+	start := max(0, len(buf.events)-n)
+	out := make([]SessionEvent, len(buf.events)-start)
+	copy(out, buf.events[start:])
+	return out
+}
+
 // EventsSince returns events for the given session with timestamps strictly
 // after the given timestamp, oldest first. Useful for incremental reads.
 func (b *EventBuffer) EventsSince(sessionID string, afterTimestamp int64) []SessionEvent {
@@ -158,6 +287,17 @@ func (b *EventBuffer) SessionCount() int {
 	return len(b.sessions)
 }
 
+// EventCount returns the total number of events stored across sessions.
+func (b *EventBuffer) EventCount() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	n := 0
+	for _, buf := range b.sessions {
+		n += len(buf.events)
+	}
+	return n
+}
+
 // Len returns the number of events stored for the given session.
 func (b *EventBuffer) Len(sessionID string) int {
 	b.mu.RLock()
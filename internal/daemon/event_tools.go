@@ -29,8 +29,9 @@ type eventPartEnvelope struct {
 // It scans message.part.updated events where part.type is "tool",
 // keeping only the latest state per part ID (events arrive as a lifecycle:
 // pending → running → completed). Returns up to limit most recent calls
-// (0 means all), ordered by event timestamp.
-func ToolCallsFromEvents(events []SessionEvent, limit int) []ToolCall {
+// (0 means all), ordered by event timestamp. fields adds tool input
+// mappings on top of the built-in ones.
+func ToolCallsFromEvents(events []SessionEvent, limit int, fields ToolInputFields) []ToolCall {
 	// Build a map of part ID → latest tool call, preserving insertion order.
 	type entry struct {
 		order int
@@ -67,7 +68,7 @@ func ToolCallsFromEvents(events []SessionEvent, limit int) []ToolCall {
 			Tool:      envelope.Part.Tool,
 			Title:     envelope.Part.State.Title,
 			Status:    envelope.Part.State.Status,
-			Input:     extractKeyInput(envelope.Part.Tool, envelope.Part.State.Input),
+			Input:     extractKeyInput(envelope.Part.Tool, envelope.Part.State.Input, fields),
 		}
 
 		if envelope.Part.State.Time.Start > 0 && envelope.Part.State.Time.End > 0 {
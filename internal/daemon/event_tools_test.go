@@ -15,7 +15,7 @@ func TestToolCallsFromEventsHappyPath(t *testing.T) {
 		{EventType: "session.idle", SessionID: "ses-1", Timestamp: 4000},
 	}
 
-	calls := ToolCallsFromEvents(events, 0)
+	calls := ToolCallsFromEvents(events, 0, nil)
 
 	if len(calls) != 2 {
 		t.Fatalf("got %d calls, want 2", len(calls))
@@ -60,7 +60,7 @@ func TestToolCallsFromEventsDeduplicatesByPartID(t *testing.T) {
 			Data: json.RawMessage(`{"part":{"id":"prt_1","type":"tool","tool":"bash","state":{"status":"completed","input":{"command":"echo hello"},"title":"Echo hello","time":{"start":2000,"end":3000}}}}`)},
 	}
 
-	calls := ToolCallsFromEvents(events, 0)
+	calls := ToolCallsFromEvents(events, 0, nil)
 
 	if len(calls) != 1 {
 		t.Fatalf("got %d calls, want 1 (deduplicated)", len(calls))
@@ -86,7 +86,7 @@ func TestToolCallsFromEventsLimit(t *testing.T) {
 			Data: json.RawMessage(`{"part":{"id":"prt_3","type":"tool","tool":"read","state":{"status":"completed","input":{"filePath":"/c"}}}}`)},
 	}
 
-	calls := ToolCallsFromEvents(events, 2)
+	calls := ToolCallsFromEvents(events, 2, nil)
 
 	if len(calls) != 2 {
 		t.Fatalf("got %d calls, want 2 (limited)", len(calls))
@@ -101,12 +101,12 @@ func TestToolCallsFromEventsLimit(t *testing.T) {
 }
 
 func TestToolCallsFromEventsEmptyEvents(t *testing.T) {
-	calls := ToolCallsFromEvents(nil, 0)
+	calls := ToolCallsFromEvents(nil, 0, nil)
 	if len(calls) != 0 {
 		t.Errorf("expected 0 calls for nil events, got %d", len(calls))
 	}
 
-	calls = ToolCallsFromEvents([]SessionEvent{}, 0)
+	calls = ToolCallsFromEvents([]SessionEvent{}, 0, nil)
 	if len(calls) != 0 {
 		t.Errorf("expected 0 calls for empty events, got %d", len(calls))
 	}
@@ -128,7 +128,7 @@ func TestToolCallsFromEventsSkipsNonToolParts(t *testing.T) {
 			Data: json.RawMessage(`{"part":{"id":"prt_4","type":"step-finish","reason":"tool-calls"}}`)},
 	}
 
-	calls := ToolCallsFromEvents(events, 0)
+	calls := ToolCallsFromEvents(events, 0, nil)
 	if len(calls) != 1 {
 		t.Fatalf("got %d calls, want 1 (only tool parts)", len(calls))
 	}
@@ -145,7 +145,7 @@ func TestToolCallsFromEventsSkipsNonPartEvents(t *testing.T) {
 		{EventType: "session.idle", SessionID: "ses-1", Timestamp: 4000},
 	}
 
-	calls := ToolCallsFromEvents(events, 0)
+	calls := ToolCallsFromEvents(events, 0, nil)
 	if len(calls) != 0 {
 		t.Errorf("expected 0 calls for non-part events, got %d", len(calls))
 	}
@@ -164,7 +164,7 @@ func TestToolCallsFromEventsHandlesMalformedData(t *testing.T) {
 			Data: json.RawMessage(`{"part":{"id":"prt_1","type":"tool","tool":"bash","state":{"status":"completed","input":{"command":"ls"}}}}`)},
 	}
 
-	calls := ToolCallsFromEvents(events, 0)
+	calls := ToolCallsFromEvents(events, 0, nil)
 	if len(calls) != 1 {
 		t.Fatalf("got %d calls, want 1 (skipping malformed)", len(calls))
 	}
@@ -184,7 +184,7 @@ func TestToolCallsFromEventsPreservesOrder(t *testing.T) {
 			Data: json.RawMessage(`{"part":{"id":"prt_3","type":"tool","tool":"edit","state":{"status":"completed","input":{"filePath":"/b"}}}}`)},
 	}
 
-	calls := ToolCallsFromEvents(events, 0)
+	calls := ToolCallsFromEvents(events, 0, nil)
 	if len(calls) != 3 {
 		t.Fatalf("got %d calls, want 3", len(calls))
 	}
@@ -222,7 +222,7 @@ func TestExtractKeyInput(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := extractKeyInput(tt.tool, []byte(tt.input))
+			got := extractKeyInput(tt.tool, []byte(tt.input), nil)
 			if got != tt.want {
 				t.Errorf("extractKeyInput(%q, %q) = %q, want %q", tt.tool, tt.input, got, tt.want)
 			}
@@ -230,13 +230,51 @@ func TestExtractKeyInput(t *testing.T) {
 	}
 }
 
+func TestExtractKeyInputCustomFields(t *testing.T) {
+	custom := ToolInputFields{
+		"deploy": {"service", "env"},
+		"bash":   {"description"},
+	}
+	tests := []struct {
+		name  string
+		tool  string
+		input string
+		want  string
+	}{
+		{"custom tool first field", "deploy", `{"service":"api","env":"prod"}`, "api"},
+		{"custom tool second field", "deploy", `{"env":"prod"}`, "prod"},
+		{"custom tool falls back to common fields", "deploy", `{"name":"nightly"}`, "nightly"},
+		{"override built-in", "bash", `{"command":"make","description":"build it"}`, "build it"},
+		{"override falls back to built-in", "bash", `{"command":"make"}`, "make"},
+		{"built-in untouched", "read", `{"filePath":"/foo/bar.go"}`, "/foo/bar.go"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := extractKeyInput(tt.tool, []byte(tt.input), custom)
+			if got != tt.want {
+				t.Errorf("extractKeyInput(%q, %q) = %q, want %q", tt.tool, tt.input, got, tt.want)
+			}
+		})
+	}
+
+	// The configured fields reach the tool calls shown in status.
+	events := []SessionEvent{{
+		EventType: "message.part.updated", SessionID: "ses-1", Timestamp: 1000,
+		Data: json.RawMessage(`{"part":{"id":"p1","type":"tool","tool":"deploy","state":{"status":"completed","input":{"service":"api"}}}}`),
+	}}
+	calls := ToolCallsFromEvents(events, 0, custom)
+	if len(calls) != 1 || calls[0].Input != "api" {
+		t.Errorf("calls = %+v, want one deploy call with input %q", calls, "api")
+	}
+}
+
 func TestToolCallsFromEventsNoDuration(t *testing.T) {
 	events := []SessionEvent{
 		{EventType: "message.part.updated", SessionID: "ses-1", Timestamp: 1000,
 			Data: json.RawMessage(`{"part":{"id":"prt_1","type":"tool","tool":"read","state":{"status":"completed","input":{"filePath":"/foo"}}}}`)},
 	}
 
-	calls := ToolCallsFromEvents(events, 0)
+	calls := ToolCallsFromEvents(events, 0, nil)
 	if len(calls) != 1 {
 		t.Fatalf("got %d calls, want 1", len(calls))
 	}
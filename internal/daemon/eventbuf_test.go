@@ -2,6 +2,10 @@ package daemon
 
 import (
 	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -320,3 +324,184 @@ func TestEventBufferLatestTimestamp(t *testing.T) {
 		t.Fatalf("LatestTimestamp(ses-1) = %d, want 250", ts)
 	}
 }
+
+func TestEventBufferTail(t *testing.T) {
+	buf := NewEventBuffer(100)
+	for i := range 5 {
+		buf.Push(SessionEvent{SessionID: "ses-1", Timestamp: int64(i)})
+	}
+
+	tail := buf.Tail("ses-1", 2)
+	if len(tail) != 2 || tail[0].Timestamp != 3 || tail[1].Timestamp != 4 {
+		t.Errorf("Tail(2) = %+v, want timestamps [3 4]", tail)
+	}
+	if got := buf.Tail("ses-1", 10); len(got) != 5 {
+		t.Errorf("Tail(10) returned %d events, want all 5", len(got))
+	}
+	if got := buf.Tail("unknown", 2); got != nil {
+		t.Errorf("Tail(unknown) = %+v, want nil", got)
+	}
+}
+
+func TestEventBufferSinkWritesJSONLInOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	sink, err := openEventSink(path)
+	if err != nil {
+		t.Fatalf("openEventSink: %v", err)
+	}
+
+	buf := NewEventBuffer(2)
+	buf.SetSink(sink)
+	pushed := []SessionEvent{
+		{EventType: "session.created", SessionID: "ses-1", Timestamp: 1},
+		{EventType: "message.part.updated", SessionID: "ses-2", Timestamp: 2, Data: json.RawMessage(`{"part":{"type":"text"}}`)},
+		{EventType: "session.idle", SessionID: "ses-1", Timestamp: 3},
+		{EventType: "session.idle", SessionID: "ses-1", Timestamp: 4}, // evicts from memory, still sunk
+	}
+	for _, ev := range pushed {
+		buf.Push(ev)
+	}
+	buf.SetSink(nil)
+	buf.Push(SessionEvent{EventType: "session.idle", SessionID: "ses-1", Timestamp: 5})
+	if err := sink.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("sink permissions = %o, want 600", perm)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != len(pushed) {
+		t.Fatalf("sink has %d lines, want %d:\n%s", len(lines), len(pushed), data)
+	}
+	for i, line := range lines {
+		var got SessionEvent
+		if err := json.Unmarshal([]byte(line), &got); err != nil {
+			t.Fatalf("line %d: %v", i, err)
+		}
+		if got.SessionID != pushed[i].SessionID || got.Timestamp != pushed[i].Timestamp || got.EventType != pushed[i].EventType {
+			t.Errorf("line %d = %+v, want %+v", i, got, pushed[i])
+		}
+	}
+}
+
+// blockingWriter holds every Write until unblock is closed.
+type blockingWriter struct {
+	writing chan struct{}
+	unblock chan struct{}
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	w.writing <- struct{}{}
+	<-w.unblock
+	return len(p), nil
+}
+
+func TestEventBufferSlowSinkDoesNotBlockReaders(t *testing.T) {
+	buf := NewEventBuffer(10)
+	buf.Push(SessionEvent{EventType: "session.created", SessionID: "ses-1", Timestamp: 1})
+	sink := &blockingWriter{writing: make(chan struct{}), unblock: make(chan struct{})}
+	buf.SetSink(sink)
+
+	pushed := make(chan struct{})
+	go func() {
+		buf.Push(SessionEvent{EventType: "session.idle", SessionID: "ses-1", Timestamp: 2})
+		close(pushed)
+	}()
+	<-sink.writing // the push is stuck writing the sink
+
+	read := make(chan []SessionEvent)
+	go func() { read <- buf.Tail("ses-1", 10) }()
+	select {
+	case got := <-read:
+		if len(got) != 1 {
+			t.Errorf("Tail during sink write = %d events, want 1", len(got))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Tail blocked behind a sink write")
+	}
+
+	close(sink.unblock)
+	<-pushed
+	if got := buf.Tail("ses-1", 10); len(got) != 2 {
+		t.Errorf("Tail after push = %d events, want 2", len(got))
+	}
+}
+
+func TestNewReplaysEventSink(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "events.jsonl")
+	now := time.Now()
+	lines := []string{
+		// Older than the retention window: skipped.
+		fmt.Sprintf(`{"event_type":"session.created","session_id":"ses-old","timestamp":%d}`, now.Add(-sessionIdleTTL-time.Hour).UnixMilli()),
+		fmt.Sprintf(`{"event_type":"session.created","session_id":"ses-1","timestamp":%d}`, now.Add(-time.Hour).UnixMilli()),
+		`{not json`,
+		fmt.Sprintf(`{"event_type":"message.part.updated","session_id":"ses-1","timestamp":%d,"data":{"part":{"type":"tool","tool":"read"}}}`, now.Add(-time.Minute).UnixMilli()),
+		fmt.Sprintf(`{"event_type":"session.idle","session_id":"ses-2","timestamp":%d}`, now.UnixMilli()),
+	}
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	d := New(Config{EventSink: path, SessionDir: dir, Logger: testLogger()})
+
+	got := d.events.Events("ses-1")
+	if len(got) != 2 || got[0].EventType != "session.created" || got[1].EventType != "message.part.updated" {
+		t.Fatalf("ses-1 events = %+v, want session.created then message.part.updated", got)
+	}
+	if string(got[1].Data) != `{"part":{"type":"tool","tool":"read"}}` {
+		t.Errorf("restored data = %s", got[1].Data)
+	}
+	if n := d.events.Len("ses-2"); n != 1 {
+		t.Errorf("ses-2 events = %d, want 1", n)
+	}
+	if n := d.events.Len("ses-old"); n != 0 {
+		t.Errorf("ses-old events = %d, want 0 (older than retention)", n)
+	}
+
+	// Replay doesn't write the events back to the sink.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := strings.Count(string(data), "\n"); n != len(lines) {
+		t.Errorf("sink has %d lines after replay, want %d", n, len(lines))
+	}
+}
+
+func TestLoadEventSinkReadsOnlyTheTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	var sb strings.Builder
+	for i := 1; i <= 10; i++ {
+		fmt.Fprintf(&sb, `{"event_type":"session.idle","session_id":"ses-1","timestamp":%d}`+"\n", 1000+i)
+	}
+	if err := os.WriteFile(path, []byte(sb.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	lineLen := int64(len(sb.String()) / 10)
+
+	// A window of three and a half lines holds three whole events.
+	buf := NewEventBuffer(100)
+	n, err := buf.LoadEventSink(path, 3*lineLen+lineLen/2, time.UnixMilli(0))
+	if err != nil {
+		t.Fatalf("LoadEventSink: %v", err)
+	}
+	got := buf.Events("ses-1")
+	if n != 3 || len(got) != 3 || got[0].Timestamp != 1008 || got[2].Timestamp != 1010 {
+		t.Errorf("restored %d events %+v, want timestamps 1008-1010", n, got)
+	}
+
+	if n, err := NewEventBuffer(100).LoadEventSink(filepath.Join(t.TempDir(), "missing.jsonl"), eventSinkReplayBytes, time.Time{}); n != 0 || err != nil {
+		t.Errorf("LoadEventSink(missing) = %d, %v; want 0, nil", n, err)
+	}
+}
@@ -0,0 +1,58 @@
+package daemon
+
+import "slices"
+
+// yieldedTask is a crashed task whose respawn was deferred so queued work
+// could take its slot first. See Config.FairRespawn.
+type yieldedTask struct {
+	taskID    string
+	role      Role
+	sessionID string
+}
+
+// queueWaiting reports whether any task other than taskID is waiting for a
+// slot: enqueued by a planner, or in the last polled queue snapshot and
+// neither running, retired, nor itself yielded.
+// Caller must hold p.mu.
+func (p *Pool) queueWaiting(taskID string) bool {
+	for _, id := range p.priority {
+		if id != taskID {
+			return true
+		}
+	}
+	for id := range p.seen {
+		if id == taskID || p.retired[id] || p.isYielded(id) {
+			continue
+		}
+		if _, running := p.agents[id]; running {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// isYielded reports whether taskID is waiting for a deferred respawn,
+// after a crash or preemption. Caller must hold p.mu.
+func (p *Pool) isYielded(taskID string) bool {
+	return slices.ContainsFunc(p.yielded, func(y yieldedTask) bool { return y.taskID == taskID }) ||
+		slices.ContainsFunc(p.preempted, func(y preemptedTask) bool { return y.taskID == taskID })
+}
+
+// resumeYielded respawns yielded tasks, oldest first, while slots are free.
+// Called after a scheduling pass so queued tasks get the first pick.
+func (p *Pool) resumeYielded() {
+	for {
+		p.mu.Lock()
+		if len(p.yielded) == 0 || p.runningCount() >= p.config.PoolSize {
+			p.mu.Unlock()
+			return
+		}
+		y := p.yielded[0]
+		p.yielded = p.yielded[1:]
+		p.mu.Unlock()
+
+		p.taskLog(y.taskID).Info("respawning yielded task", "task_id", y.taskID, "role", y.role)
+		p.respawn(y.taskID, y.role, y.sessionID)
+	}
+}
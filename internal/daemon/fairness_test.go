@@ -0,0 +1,85 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestPoolFairRespawnYieldsSlotToQueuedTask(t *testing.T) {
+	var mu sync.Mutex
+	spawns := map[string]int{}
+	var releaseFresh func()
+
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if strings.Contains(prompt, "ts-loop") {
+			// Crash-looping task: every agent dies at once.
+			spawns["ts-loop"]++
+			proc, release := newFakeProcessWithError(100, errors.New("crash"))
+			release()
+			return proc, nil
+		}
+		spawns["ts-fresh"]++
+		proc, release := newFakeProcess(200)
+		releaseFresh = release
+		return proc, nil
+	}
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if len(args) >= 1 && args[0] == "start" {
+			return []byte("Started"), nil
+		}
+		if len(args) >= 2 && args[0] == "show" {
+			return []byte(fmt.Sprintf(`{"id":"%s","type":"task","definition_of_done":"Do it","labels":[]}`, args[1])), nil
+		}
+		return nil, fmt.Errorf("unexpected: %v", args)
+	}
+	spawnCount := func(taskID string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return spawns[taskID]
+	}
+
+	pool := testPool(t, runner, starter)
+	pool.config.PoolSize = 1
+	pool.config.MaxRetries = 5
+	pool.config.FairRespawn = true
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// ts-fresh is seen in the queue while ts-loop holds the only slot, so
+	// ts-loop's crash yields instead of respawning into the slot.
+	taskCh := make(chan []Task)
+	go pool.Run(ctx, taskCh)
+	taskCh <- []Task{{ID: "ts-loop", Priority: 1}, {ID: "ts-fresh", Priority: 2}}
+
+	waitFor(t, func() bool {
+		pool.mu.RLock()
+		defer pool.mu.RUnlock()
+		return len(pool.yielded) == 1
+	})
+
+	// Next poll: ts-loop is in progress, so only ts-fresh is ready.
+	taskCh <- []Task{{ID: "ts-fresh", Priority: 2}}
+	waitFor(t, func() bool {
+		agents := pool.Status()
+		return len(agents) == 1 && agents[0].TaskID == "ts-fresh"
+	})
+	if got := spawnCount("ts-loop"); got != 1 {
+		t.Fatalf("ts-loop spawned %d times before ts-fresh got the slot, want 1", got)
+	}
+
+	// Once ts-fresh finishes, the yielded task gets the slot back.
+	mu.Lock()
+	releaseFresh()
+	mu.Unlock()
+	waitFor(t, func() bool { return len(pool.Status()) == 0 })
+	taskCh <- []Task{}
+	waitFor(t, func() bool { return spawnCount("ts-loop") >= 2 })
+}
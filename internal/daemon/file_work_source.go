@@ -0,0 +1,185 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+// QueueFileStdin is the QueueFile value that reads the task list from stdin.
+const QueueFileStdin = "-"
+
+// TaskQueue supplies the tasks that are ready to be scheduled. The poller
+// uses `prog ready` unless a TaskQueue is configured.
+type TaskQueue interface {
+	Ready(ctx context.Context) ([]Task, error)
+}
+
+// queueFileTask is one entry in a queue file. It carries both the queue
+// fields and the metadata prog would normally supply via `prog show`.
+type queueFileTask struct {
+	ID               string   `json:"id"`
+	Priority         int      `json:"priority"`
+	Title            string   `json:"title"`
+	Type             string   `json:"type"`
+	Status           string   `json:"status"`
+	DefinitionOfDone string   `json:"definition_of_done"`
+	Labels           []string `json:"labels"`
+	Dependencies     []string `json:"dependencies"`
+}
+
+// FileWorkSource is a WorkSource and TaskQueue backed by a JSON task list
+// instead of prog, for offline scheduling and replay. Claims are no-ops,
+// and each task is handed out at most once per daemon run.
+//
+// A file path is re-read on every call, so edits show up on the next poll.
+// Stdin is read once, when the source is created.
+type FileWorkSource struct {
+	path string
+
+	mu      sync.Mutex
+	cached  []queueFileTask // stdin contents; nil for file paths
+	claimed map[string]bool
+}
+
+// NewFileWorkSource creates a source reading from path, or from stdin when
+// path is QueueFileStdin.
+func NewFileWorkSource(path string, stdin io.Reader) (*FileWorkSource, error) {
+	s := &FileWorkSource{path: path, claimed: make(map[string]bool)}
+	if path == QueueFileStdin {
+		data, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("reading queue from stdin: %w", err)
+		}
+		tasks, err := parseQueueFile(data)
+		if err != nil {
+			return nil, fmt.Errorf("parsing queue from stdin: %w", err)
+		}
+		s.cached = tasks
+		return s, nil
+	}
+	if _, err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func parseQueueFile(data []byte) ([]queueFileTask, error) {
+	var tasks []queueFileTask
+	if err := json.Unmarshal(data, &tasks); err != nil {
+		return nil, err
+	}
+	for _, t := range tasks {
+		if !validTaskID.MatchString(t.ID) {
+			return nil, fmt.Errorf("invalid task ID %q", t.ID)
+		}
+	}
+	return tasks, nil
+}
+
+func (s *FileWorkSource) load() ([]queueFileTask, error) {
+	if s.path == QueueFileStdin {
+		return s.cached, nil
+	}
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("reading queue file: %w", err)
+	}
+	tasks, err := parseQueueFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing queue file %s: %w", s.path, err)
+	}
+	return tasks, nil
+}
+
+func (s *FileWorkSource) find(workRef string) (queueFileTask, bool, error) {
+	tasks, err := s.load()
+	if err != nil {
+		return queueFileTask{}, false, err
+	}
+	for _, t := range tasks {
+		if t.ID == workRef {
+			return t, true, nil
+		}
+	}
+	return queueFileTask{}, false, nil
+}
+
+// Ready returns unclaimed tasks whose status is empty or "open", highest
+// priority (lowest number) first.
+func (s *FileWorkSource) Ready(ctx context.Context) ([]Task, error) {
+	tasks, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ready []Task
+	for _, t := range tasks {
+		if s.claimed[t.ID] || (t.Status != "" && t.Status != "open") {
+			continue
+		}
+		ready = append(ready, Task{ID: t.ID, Priority: t.Priority, Title: t.Title})
+	}
+	sort.SliceStable(ready, func(i, j int) bool { return ready[i].Priority < ready[j].Priority })
+	return ready, nil
+}
+
+// Claim marks the task as handed out so Ready stops returning it.
+func (s *FileWorkSource) Claim(ctx context.Context, workRef, project string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.claimed[workRef] = true
+	return nil
+}
+
+// Block leaves the task claimed, so Ready never hands it out again this run.
+// The queue file itself is never written.
+func (s *FileWorkSource) Block(ctx context.Context, workRef, project, reason string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.claimed[workRef] = true
+	return nil
+}
+
+func (s *FileWorkSource) GetMeta(ctx context.Context, workRef, project string) (TaskMeta, error) {
+	t, ok, err := s.find(workRef)
+	if err != nil {
+		return TaskMeta{}, err
+	}
+	if !ok {
+		return TaskMeta{}, fmt.Errorf("%w in queue file: %s", ErrTaskNotFound, workRef)
+	}
+	return TaskMeta{
+		ID:               t.ID,
+		Type:             t.Type,
+		DefinitionOfDone: t.DefinitionOfDone,
+		Labels:           t.Labels,
+		Status:           t.Status,
+		Dependencies:     t.Dependencies,
+	}, nil
+}
+
+// Dependencies reports each dependency as done when the queue file marks it
+// "done" or doesn't list it at all (it is assumed to be finished elsewhere).
+func (s *FileWorkSource) Dependencies(ctx context.Context, workRef, project string) ([]Dependency, error) {
+	tasks, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]queueFileTask, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+	var deps []Dependency
+	for _, id := range byID[workRef].Dependencies {
+		dep, listed := byID[id]
+		deps = append(deps, Dependency{ID: id, Done: !listed || dep.Status == "done"})
+	}
+	return deps, nil
+}
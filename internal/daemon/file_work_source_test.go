@@ -0,0 +1,180 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const testQueueFile = `[
+	{"id": "ts-low", "priority": 2, "title": "Second", "type": "task", "definition_of_done": "Tests pass"},
+	{"id": "ts-high", "priority": 1, "title": "First", "type": "task", "definition_of_done": "Tests pass"},
+	{"id": "ts-done", "priority": 0, "title": "Finished", "status": "done"}
+]`
+
+func writeQueueFile(t *testing.T, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "queue.json")
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestFileWorkSourceFeedsPool(t *testing.T) {
+	src, err := NewFileWorkSource(writeQueueFile(t, testQueueFile), nil)
+	if err != nil {
+		t.Fatalf("NewFileWorkSource: %v", err)
+	}
+
+	// prog must never be called: the file supplies the queue and metadata.
+	var progCalls atomic.Int32
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		progCalls.Add(1)
+		return nil, fmt.Errorf("unexpected command: %s %v", name, args)
+	}
+
+	var pid atomic.Int32
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		proc, _ := newFakeProcess(int(pid.Add(1)))
+		return proc, nil
+	}
+
+	pool := testPool(t, runner, starter)
+	pool.work = src
+	poller := NewPoller("testproject", 10*time.Millisecond, runner, slog.Default())
+	poller.queue = src
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go pool.Run(ctx, poller.Start(ctx))
+
+	waitFor(t, func() bool {
+		return len(pool.Status()) == 2
+	})
+
+	got := map[string]bool{}
+	for _, a := range pool.Status() {
+		got[a.TaskID] = true
+	}
+	if !got["ts-high"] || !got["ts-low"] {
+		t.Errorf("running tasks = %v, want ts-high and ts-low", got)
+	}
+	if n := progCalls.Load(); n != 0 {
+		t.Errorf("prog called %d times, want 0", n)
+	}
+}
+
+func TestFileWorkSourceReadyOrdersAndSkipsClaimed(t *testing.T) {
+	src, err := NewFileWorkSource(QueueFileStdin, strings.NewReader(testQueueFile))
+	if err != nil {
+		t.Fatalf("NewFileWorkSource: %v", err)
+	}
+	ctx := context.Background()
+
+	tasks, err := src.Ready(ctx)
+	if err != nil {
+		t.Fatalf("Ready: %v", err)
+	}
+	if len(tasks) != 2 || tasks[0].ID != "ts-high" || tasks[1].ID != "ts-low" {
+		t.Fatalf("Ready = %+v, want [ts-high ts-low]", tasks)
+	}
+
+	if err := src.Claim(ctx, "ts-high", "testproject"); err != nil {
+		t.Fatalf("Claim: %v", err)
+	}
+	tasks, err = src.Ready(ctx)
+	if err != nil {
+		t.Fatalf("Ready: %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].ID != "ts-low" {
+		t.Errorf("Ready after claim = %+v, want [ts-low]", tasks)
+	}
+}
+
+func TestFileWorkSourceDependencies(t *testing.T) {
+	path := writeQueueFile(t, `[
+		{"id": "ts-a", "status": "done"},
+		{"id": "ts-b"},
+		{"id": "ts-c", "dependencies": ["ts-a", "ts-b", "ts-elsewhere"]}
+	]`)
+	src, err := NewFileWorkSource(path, nil)
+	if err != nil {
+		t.Fatalf("NewFileWorkSource: %v", err)
+	}
+
+	deps, err := src.Dependencies(context.Background(), "ts-c", "testproject")
+	if err != nil {
+		t.Fatalf("Dependencies: %v", err)
+	}
+	want := []Dependency{{ID: "ts-a", Done: true}, {ID: "ts-b", Done: false}, {ID: "ts-elsewhere", Done: true}}
+	if len(deps) != len(want) {
+		t.Fatalf("got %d dependencies, want %d", len(deps), len(want))
+	}
+	for i := range want {
+		if deps[i] != want[i] {
+			t.Errorf("deps[%d] = %+v, want %+v", i, deps[i], want[i])
+		}
+	}
+}
+
+func TestNewFileWorkSourceRejectsInvalidTaskID(t *testing.T) {
+	_, err := NewFileWorkSource(writeQueueFile(t, `[{"id": "../etc"}]`), nil)
+	if err == nil {
+		t.Fatal("expected error for invalid task ID")
+	}
+}
+
+func TestStatusListsQueueFileWithoutResettingAges(t *testing.T) {
+	src, err := NewFileWorkSource(writeQueueFile(t, testQueueFile), nil)
+	if err != nil {
+		t.Fatalf("NewFileWorkSource: %v", err)
+	}
+	var progCalls atomic.Int32
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		progCalls.Add(1)
+		return nil, fmt.Errorf("unexpected command: %s %v", name, args)
+	}
+
+	pool := testPool(t, runner, nil)
+	pool.work = src
+	pool.queue = src
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := newFakeClock(start)
+	pool.clock = clock
+	// Paused so schedule only observes the queue and never spawns.
+	pool.Pause()
+	tasks, err := src.Ready(context.Background())
+	if err != nil {
+		t.Fatalf("Ready: %v", err)
+	}
+	pool.schedule(context.Background(), tasks)
+
+	clock.Advance(time.Minute)
+	cfg := pool.config
+	cfg.SpawnPolicy = SpawnPolicyAuto
+	for range 2 {
+		status := BuildFullStatus(context.Background(), pool, nil, nil, nil, cfg, runner)
+		if len(status.Errors) != 0 {
+			t.Fatalf("status errors = %v, want none", status.Errors)
+		}
+		if len(status.Queue) != 2 {
+			t.Fatalf("status queue = %+v, want the two ready tasks from the file", status.Queue)
+		}
+		for _, task := range status.Queue {
+			if !task.ReadySince.Equal(start) {
+				t.Errorf("%s ReadySince = %v, want %v from the poll", task.ID, task.ReadySince, start)
+			}
+		}
+	}
+	if n := progCalls.Load(); n != 0 {
+		t.Errorf("prog calls = %d, want 0 in queue-file mode", n)
+	}
+}
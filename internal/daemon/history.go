@@ -0,0 +1,89 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// TaskOutcome is where a scheduled task ended up, or "running" while its
+// agent (or a respawn) is still live.
+type TaskOutcome string
+
+const (
+	TaskOutcomeRunning   TaskOutcome = "running"
+	TaskOutcomeCompleted TaskOutcome = "completed"
+	TaskOutcomeFailed    TaskOutcome = "failed"              // fatal exit code, not retried
+	TaskOutcomeCrashed   TaskOutcome = "crashed-max-retries" // retry budget exhausted
+	TaskOutcomeRetired   TaskOutcome = "retired"             // crashed after being retired
+	TaskOutcomeKilled    TaskOutcome = "killed"              // stopped on request (af session kill)
+	TaskOutcomeStranded  TaskOutcome = "stranded"            // respawn skipped or failed; left in_progress for the orphan scan
+)
+
+// TaskHistoryEntry records one task the pool scheduled since daemon startup.
+type TaskHistoryEntry struct {
+	TaskID    string      `json:"task_id"`
+	Role      string      `json:"role"`
+	Outcome   TaskOutcome `json:"outcome"`
+	Attempts  int         `json:"attempts"`            // agents launched, including respawns
+	QueuedAt  time.Time   `json:"queued_at,omitempty"` // first seen ready; zero if never polled
+	StartedAt time.Time   `json:"started_at"`
+	EndedAt   time.Time   `json:"ended_at,omitempty"`
+}
+
+// recordLaunch notes that an agent was launched for taskID. The first launch
+// adds the task to the history; respawns bump its attempt count.
+// Caller must hold p.mu.
+func (p *Pool) recordLaunch(taskID string, role Role) {
+	entry, ok := p.history[taskID]
+	if !ok {
+		entry = &TaskHistoryEntry{TaskID: taskID, QueuedAt: p.seen[taskID], StartedAt: p.clock.Now()}
+		p.history[taskID] = entry
+		p.historyOrder = append(p.historyOrder, taskID)
+	}
+	entry.Role = string(role)
+	entry.Outcome = TaskOutcomeRunning
+	entry.Attempts++
+	entry.EndedAt = time.Time{}
+}
+
+// recordOutcome marks taskID as finished with the given outcome.
+// Caller must hold p.mu.
+func (p *Pool) recordOutcome(taskID string, outcome TaskOutcome) {
+	entry, ok := p.history[taskID]
+	if !ok {
+		return
+	}
+	entry.Outcome = outcome
+	entry.EndedAt = p.clock.Now()
+}
+
+// History returns every task the pool has scheduled since startup, in the
+// order they were first scheduled.
+func (p *Pool) History() []TaskHistoryEntry {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	result := make([]TaskHistoryEntry, 0, len(p.historyOrder))
+	for _, id := range p.historyOrder {
+		result = append(result, *p.history[id])
+	}
+	return result
+}
+
+// HistoryTasksResult is the response for the task history handler.
+type HistoryTasksResult struct {
+	Tasks []TaskHistoryEntry `json:"tasks"`
+}
+
+// handleHistoryTasks returns the tasks scheduled since daemon startup.
+func (d *Daemon) handleHistoryTasks(params ProjectSelector) *Response {
+	pool, err := d.poolFor(params.Project)
+	if err != nil {
+		return &Response{Success: false, Error: err.Error()}
+	}
+	result, err := json.Marshal(HistoryTasksResult{Tasks: pool.History()})
+	if err != nil {
+		return &Response{Success: false, Error: fmt.Sprintf("marshal history: %v", err)}
+	}
+	return &Response{Success: true, Result: result}
+}
@@ -0,0 +1,73 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestPoolHistoryRecordsCompletedTask(t *testing.T) {
+	proc, release := newFakeProcess(1234)
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		return proc, nil
+	}
+
+	pool := testPool(t, progRunner(testTaskMeta), starter)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	taskCh := make(chan []Task, 1)
+	taskCh <- []Task{{ID: "ts-abc", Priority: 1, Title: "Do it"}}
+	go pool.Run(ctx, taskCh)
+
+	waitFor(t, func() bool {
+		h := pool.History()
+		return len(h) == 1 && h[0].Outcome == TaskOutcomeRunning
+	})
+
+	release()
+	waitFor(t, func() bool {
+		h := pool.History()
+		return len(h) == 1 && h[0].Outcome == TaskOutcomeCompleted
+	})
+
+	entry := pool.History()[0]
+	if entry.TaskID != "ts-abc" {
+		t.Errorf("TaskID = %q, want %q", entry.TaskID, "ts-abc")
+	}
+	if entry.Attempts != 1 {
+		t.Errorf("Attempts = %d, want 1", entry.Attempts)
+	}
+	if entry.StartedAt.IsZero() || entry.EndedAt.Before(entry.StartedAt) {
+		t.Errorf("timings = %v..%v, want a started time and an end not before it", entry.StartedAt, entry.EndedAt)
+	}
+}
+
+func TestPoolHistoryRecordsMaxRetries(t *testing.T) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		proc, release := newFakeProcessWithError(1234, fmt.Errorf("boom"))
+		release()
+		return proc, nil
+	}
+
+	pool := testPool(t, progRunner(testTaskMeta), starter)
+	pool.config.MaxRetries = 1
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	taskCh := make(chan []Task, 1)
+	taskCh <- []Task{{ID: "ts-abc", Priority: 1, Title: "Do it"}}
+	go pool.Run(ctx, taskCh)
+
+	waitFor(t, func() bool {
+		h := pool.History()
+		return len(h) == 1 && h[0].Outcome == TaskOutcomeCrashed
+	})
+
+	if got := pool.History()[0].Attempts; got != 2 {
+		t.Errorf("Attempts = %d, want 2 (initial spawn plus one respawn)", got)
+	}
+}
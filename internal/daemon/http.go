@@ -1,14 +1,19 @@
 package daemon
 
 import (
+	"context"
 	"crypto/subtle"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"syscall"
+	"time"
 )
 
 // newHTTPHandler builds the HTTP handler (mux) for the daemon API.
@@ -19,17 +24,50 @@ func (d *Daemon) newHTTPHandler() http.Handler {
 	mux.HandleFunc("/api/v1/events", d.routeEvents)
 	mux.HandleFunc("/api/v1/lifecycle", d.methodHandler(http.MethodGet, d.httpLifecycle))
 	mux.HandleFunc("/api/v1/status", d.methodHandler(http.MethodGet, d.httpStatusFull))
+	mux.HandleFunc("/api/v1/status/stream", d.methodHandler(http.MethodGet, d.httpStatusStream))
+	mux.HandleFunc("/api/v1/status/agents", d.methodHandler(http.MethodGet, d.httpStatusAgents))
 	mux.HandleFunc("/api/v1/status/agents/", d.methodHandler(http.MethodGet, d.httpStatusAgent))
+	mux.HandleFunc("/api/v1/agents/prompt", d.methodHandler(http.MethodGet, d.httpAgentPrompt))
+	mux.HandleFunc("/api/v1/agents/stdout", d.methodHandler(http.MethodGet, d.httpAgentStdout))
+	mux.HandleFunc("/api/v1/agents/kill", d.methodHandler(http.MethodPost, d.httpAgentKill))
+	mux.HandleFunc("/api/v1/history/tasks", d.methodHandler(http.MethodGet, d.httpHistoryTasks))
+	mux.HandleFunc("/api/v1/queue/latency", d.methodHandler(http.MethodGet, d.httpQueueLatency))
+	mux.HandleFunc("/api/v1/errors/recent", d.methodHandler(http.MethodGet, d.httpErrorsRecent))
+	mux.HandleFunc("/api/v1/debug/snapshot", d.methodHandler(http.MethodGet, d.httpDebugSnapshot))
 	mux.HandleFunc("/api/v1/pool/drain", d.methodHandler(http.MethodPost, d.httpPoolDrain))
 	mux.HandleFunc("/api/v1/pool/pause", d.methodHandler(http.MethodPost, d.httpPoolPause))
 	mux.HandleFunc("/api/v1/pool/resume", d.methodHandler(http.MethodPost, d.httpPoolResume))
+	mux.HandleFunc("/api/v1/pool/rolling-restart", d.methodHandler(http.MethodPost, d.httpPoolRollingRestart))
+	mux.HandleFunc("/api/v1/pool/retire", d.methodHandler(http.MethodPost, d.httpPoolRetire))
+	mux.HandleFunc("/api/v1/pool/loglevel", d.methodHandler(http.MethodPost, d.httpAgentLogLevel))
+	mux.HandleFunc("/api/v1/pool/config", d.methodHandler(http.MethodPost, d.httpPoolConfig))
+	mux.HandleFunc("/api/v1/tasks/enqueue", d.methodHandler(http.MethodPost, d.httpTaskEnqueue))
+	mux.HandleFunc("/api/v1/tasks/agent", d.methodHandler(http.MethodGet, d.httpTaskAgent))
+	mux.HandleFunc("/api/v1/sessions/backfill", d.methodHandler(http.MethodPost, d.httpSessionBackfill))
+	mux.HandleFunc("/api/v1/sessions/kill", d.methodHandler(http.MethodPost, d.httpSessionKill))
 	mux.HandleFunc("/api/v1/spawns", d.methodHandler(http.MethodPost, d.httpSpawnRegister))
+	mux.HandleFunc("/api/v1/spawns/stop-all", d.methodHandler(http.MethodPost, d.httpSpawnStopAll))
 	mux.HandleFunc("/api/v1/spawns/", d.methodHandler(http.MethodDelete, d.httpSpawnDeregister))
 	mux.HandleFunc("/api/v1/shutdown", d.methodHandler(http.MethodPost, d.httpShutdown))
 
 	return hostCheckMiddleware(browserBoundaryMiddleware(authTokenMiddleware(d.authToken, mux)))
 }
 
+// newHTTPServer creates the API server. ConnReadTimeout bounds the wait for
+// each request header, so a connection that opens and sends nothing is
+// closed instead of holding a goroutine. Long-lived streaming handlers
+// (httpStatusStream, httpAgentStdout) extend their own deadlines via http.ResponseController.
+func (d *Daemon) newHTTPServer() *http.Server {
+	return &http.Server{
+		Addr:              d.config.ListenAddr,
+		Handler:           d.newHTTPHandler(),
+		ReadHeaderTimeout: d.config.ConnReadTimeout,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+}
+
 func (d *Daemon) routeEvents(w http.ResponseWriter, r *http.Request) {
 	switch r.Method {
 	case http.MethodGet:
@@ -183,7 +221,45 @@ func (d *Daemon) httpEventsList(w http.ResponseWriter, r *http.Request) {
 }
 
 func (d *Daemon) httpStatusFull(w http.ResponseWriter, r *http.Request) {
-	writeResponse(w, d.handleStatusFull(r.Context()))
+	// The request context ends when the client hangs up, which stops the
+	// prog calls behind the status mid-build. A failed write cancels it
+	// too, so nothing started for this request outlives the response.
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+
+	resp := d.handleStatusFull(ctx, ProjectSelector{Project: r.URL.Query().Get("project")})
+	if ctx.Err() != nil {
+		return // handleStatusFull logged the disconnect
+	}
+	status := http.StatusOK
+	if !resp.Success {
+		status = http.StatusBadRequest
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		cancel()
+		d.logWriteError("status.full", r, err)
+	}
+}
+
+// logWriteError logs a failed response write, telling a client that hung
+// up apart from a failure on the daemon's side.
+func (d *Daemon) logWriteError(op string, r *http.Request, err error) {
+	if clientGone(err) {
+		d.log.Info(op+" client disconnected", "remote_addr", r.RemoteAddr, "error", err)
+		return
+	}
+	d.log.Warn(op+" write failed", "remote_addr", r.RemoteAddr, "error", err)
+}
+
+// clientGone reports whether a write error means the client closed the
+// connection (broken pipe, reset, or a cancelled request).
+func clientGone(err error) bool {
+	return errors.Is(err, syscall.EPIPE) ||
+		errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, net.ErrClosed) ||
+		errors.Is(err, context.Canceled)
 }
 
 func (d *Daemon) httpStatusAgent(w http.ResponseWriter, r *http.Request) {
@@ -208,19 +284,234 @@ func (d *Daemon) httpStatusAgent(w http.ResponseWriter, r *http.Request) {
 		}
 		params.Limit = l
 	}
+	if scan := r.URL.Query().Get("scan_limit"); scan != "" {
+		n, err := strconv.Atoi(scan)
+		if err != nil || n < 0 {
+			writeJSON(w, http.StatusBadRequest, &Response{Success: false, Error: "scan_limit must be a non-negative integer"})
+			return
+		}
+		params.ScanLimit = n
+	}
+	params.Tools = splitToolsParam(r.URL.Query().Get("tools"))
 	writeResponse(w, d.handleStatusAgent(r.Context(), params))
 }
 
+// splitToolsParam parses a comma-separated tools query value, dropping
+// empty names. An empty value means no filter.
+func splitToolsParam(v string) []string {
+	var tools []string
+	for _, name := range strings.Split(v, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			tools = append(tools, name)
+		}
+	}
+	return tools
+}
+
+func (d *Daemon) httpStatusAgents(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	params := StatusAgentsParams{AgentNames: q["name"]}
+	if limit := q.Get("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l < 0 {
+			writeJSON(w, http.StatusBadRequest, &Response{Success: false, Error: "limit must be a non-negative integer"})
+			return
+		}
+		params.Limit = l
+	}
+	if scan := q.Get("scan_limit"); scan != "" {
+		n, err := strconv.Atoi(scan)
+		if err != nil || n < 0 {
+			writeJSON(w, http.StatusBadRequest, &Response{Success: false, Error: "scan_limit must be a non-negative integer"})
+			return
+		}
+		params.ScanLimit = n
+	}
+	writeResponse(w, d.handleStatusAgents(r.Context(), params))
+}
+
 func (d *Daemon) httpPoolDrain(w http.ResponseWriter, r *http.Request) {
-	writeResponse(w, d.handlePoolDrain())
+	var params ProjectSelector
+	if !decodeOptionalBody(w, r, &params) {
+		return
+	}
+	writeResponse(w, d.handlePoolDrain(params))
 }
 
 func (d *Daemon) httpPoolPause(w http.ResponseWriter, r *http.Request) {
-	writeResponse(w, d.handlePoolPause())
+	var params PoolPauseParams
+	if !decodeOptionalBody(w, r, &params) {
+		return
+	}
+	writeResponse(w, d.handlePoolPause(params))
 }
 
 func (d *Daemon) httpPoolResume(w http.ResponseWriter, r *http.Request) {
-	writeResponse(w, d.handlePoolResume())
+	var params PoolPauseParams
+	if !decodeOptionalBody(w, r, &params) {
+		return
+	}
+	writeResponse(w, d.handlePoolResume(params))
+}
+
+// decodeOptionalBody reads the optional JSON body of a pool control
+// request into params. An empty body leaves params zero: the whole pool
+// of the primary project.
+func decodeOptionalBody(w http.ResponseWriter, r *http.Request, params any) bool {
+	r.Body = http.MaxBytesReader(w, r.Body, 4<<10)
+	if err := json.NewDecoder(r.Body).Decode(params); err != nil && !errors.Is(err, io.EOF) {
+		writeJSON(w, http.StatusBadRequest, &Response{
+			Success: false,
+			Error:   fmt.Sprintf("invalid request body: %v", err),
+		})
+		return false
+	}
+	return true
+}
+
+func (d *Daemon) httpPoolRollingRestart(w http.ResponseWriter, r *http.Request) {
+	var params ProjectSelector
+	if !decodeOptionalBody(w, r, &params) {
+		return
+	}
+	writeResponse(w, d.handlePoolRollingRestart(params))
+}
+
+func (d *Daemon) httpPoolRetire(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 4<<10)
+	var params PoolRetireParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		writeJSON(w, http.StatusBadRequest, &Response{
+			Success: false,
+			Error:   fmt.Sprintf("invalid request body: %v", err),
+		})
+		return
+	}
+	writeResponse(w, d.handlePoolRetire(params))
+}
+
+func (d *Daemon) httpPoolConfig(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 4<<10)
+	var params PoolConfigParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		writeJSON(w, http.StatusBadRequest, &Response{
+			Success: false,
+			Error:   fmt.Sprintf("invalid request body: %v", err),
+		})
+		return
+	}
+	writeResponse(w, d.handlePoolConfig(params))
+}
+
+func (d *Daemon) httpTaskEnqueue(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 4<<10)
+	var params TaskEnqueueParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		writeJSON(w, http.StatusBadRequest, &Response{
+			Success: false,
+			Error:   fmt.Sprintf("invalid request body: %v", err),
+		})
+		return
+	}
+	writeResponse(w, d.handleTaskEnqueue(params))
+}
+
+func (d *Daemon) httpHistoryTasks(w http.ResponseWriter, r *http.Request) {
+	writeResponse(w, d.handleHistoryTasks(ProjectSelector{Project: r.URL.Query().Get("project")}))
+}
+
+func (d *Daemon) httpQueueLatency(w http.ResponseWriter, r *http.Request) {
+	params := QueueLatencyParams{ProjectSelector: ProjectSelector{Project: r.URL.Query().Get("project")}}
+	if window := r.URL.Query().Get("window"); window != "" {
+		win, err := time.ParseDuration(window)
+		if err != nil || win <= 0 {
+			writeJSON(w, http.StatusBadRequest, &Response{Success: false, Error: "window must be a positive duration"})
+			return
+		}
+		params.Window = win
+	}
+	writeResponse(w, d.handleQueueLatency(params))
+}
+
+func (d *Daemon) httpErrorsRecent(w http.ResponseWriter, r *http.Request) {
+	var params ErrorsRecentParams
+	if limit := r.URL.Query().Get("limit"); limit != "" {
+		l, err := strconv.Atoi(limit)
+		if err != nil || l < 0 {
+			writeJSON(w, http.StatusBadRequest, &Response{Success: false, Error: "limit must be a non-negative integer"})
+			return
+		}
+		params.Limit = l
+	}
+	writeResponse(w, d.handleErrorsRecent(params))
+}
+
+func (d *Daemon) httpAgentPrompt(w http.ResponseWriter, r *http.Request) {
+	writeResponse(w, d.handleAgentPrompt(AgentPromptParams{AgentName: r.URL.Query().Get("agent_name")}))
+}
+
+func (d *Daemon) httpTaskAgent(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	writeResponse(w, d.handleTaskAgent(TaskAgentParams{
+		ProjectSelector: ProjectSelector{Project: q.Get("project")},
+		TaskID:          q.Get("task_id"),
+	}))
+}
+
+func (d *Daemon) httpDebugSnapshot(w http.ResponseWriter, r *http.Request) {
+	writeResponse(w, d.handleDebugSnapshot(r.Context()))
+}
+
+func (d *Daemon) httpAgentLogLevel(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 4<<10)
+	var params AgentLogLevelParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		writeJSON(w, http.StatusBadRequest, &Response{
+			Success: false,
+			Error:   fmt.Sprintf("invalid request body: %v", err),
+		})
+		return
+	}
+	writeResponse(w, d.handleAgentLogLevel(params))
+}
+
+func (d *Daemon) httpSessionBackfill(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 4<<10)
+	var params SessionBackfillParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		writeJSON(w, http.StatusBadRequest, &Response{
+			Success: false,
+			Error:   fmt.Sprintf("invalid request body: %v", err),
+		})
+		return
+	}
+	writeResponse(w, d.handleSessionBackfill(r.Context(), params))
+}
+
+func (d *Daemon) httpSessionKill(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 4<<10)
+	var params SessionKillParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		writeJSON(w, http.StatusBadRequest, &Response{
+			Success: false,
+			Error:   fmt.Sprintf("invalid request body: %v", err),
+		})
+		return
+	}
+	writeResponse(w, d.handleSessionKill(r.Context(), params))
+}
+
+func (d *Daemon) httpAgentKill(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 4<<10)
+	var params AgentKillParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		writeJSON(w, http.StatusBadRequest, &Response{
+			Success: false,
+			Error:   fmt.Sprintf("invalid request body: %v", err),
+		})
+		return
+	}
+	writeResponse(w, d.handleAgentKill(r.Context(), params))
 }
 
 func (d *Daemon) httpSpawnRegister(w http.ResponseWriter, r *http.Request) {
@@ -236,6 +527,19 @@ func (d *Daemon) httpSpawnRegister(w http.ResponseWriter, r *http.Request) {
 	writeResponse(w, d.handleSpawnRegister(params))
 }
 
+func (d *Daemon) httpSpawnStopAll(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, 4<<10)
+	var params SpawnStopAllParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		writeJSON(w, http.StatusBadRequest, &Response{
+			Success: false,
+			Error:   fmt.Sprintf("invalid request body: %v", err),
+		})
+		return
+	}
+	writeResponse(w, d.handleSpawnStopAll(params))
+}
+
 func (d *Daemon) httpSpawnDeregister(w http.ResponseWriter, r *http.Request) {
 	spawnID := strings.TrimPrefix(r.URL.Path, "/api/v1/spawns/")
 	spawnID = strings.Trim(spawnID, "/")
@@ -249,12 +553,25 @@ func (d *Daemon) httpSpawnDeregister(w http.ResponseWriter, r *http.Request) {
 		})
 		return
 	}
-	writeResponse(w, d.handleSpawnDeregister(SpawnDeregisterParams{SpawnID: spawnID}))
+	params := SpawnDeregisterParams{SpawnID: spawnID}
+	if code := r.URL.Query().Get("exit_code"); code != "" {
+		n, err := strconv.Atoi(code)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, &Response{Success: false, Error: "exit_code must be an integer"})
+			return
+		}
+		params.ExitCode = n
+	}
+	writeResponse(w, d.handleSpawnDeregister(params))
 }
 
 func (d *Daemon) httpShutdown(w http.ResponseWriter, r *http.Request) {
 	force := r.URL.Query().Get("force") == "true"
-	writeResponse(w, d.handleShutdown(force))
+	var params ShutdownParams
+	if !decodeOptionalBody(w, r, &params) {
+		return
+	}
+	writeResponse(w, d.handleShutdown(force, params))
 }
 
 func (d *Daemon) httpLifecycle(w http.ResponseWriter, _ *http.Request) {
@@ -1,8 +1,18 @@
 package daemon
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"strings"
+	"syscall"
 	"testing"
 	"time"
 
@@ -154,7 +164,7 @@ func TestHandleShutdownForcedSignalsShutdown(t *testing.T) {
 		ReconcileInterval: DefaultReconcileInterval,
 	}
 	d := New(cfg)
-	resp := d.handleShutdown(true)
+	resp := d.handleShutdown(true, ShutdownParams{})
 	if !resp.Success {
 		t.Fatalf("handleShutdown error: %s", resp.Error)
 	}
@@ -164,3 +174,251 @@ func TestHandleShutdownForcedSignalsShutdown(t *testing.T) {
 		t.Fatal("shutdown channel was not closed")
 	}
 }
+
+func TestHandleShutdownReasonAndToken(t *testing.T) {
+	tests := []struct {
+		name      string
+		token     string // configured shutdown_token
+		params    ShutdownParams
+		wantStop  bool
+		wantError string
+	}{
+		{"no token configured", "", ShutdownParams{Reason: "deploy v2"}, true, ""},
+		{"token matches", "s3cret", ShutdownParams{Reason: "deploy v2", Token: "s3cret"}, true, ""},
+		{"token missing", "s3cret", ShutdownParams{Reason: "deploy v2"}, false, "confirmation token"},
+		{"token wrong", "s3cret", ShutdownParams{Reason: "deploy v2", Token: "guess"}, false, "confirmation token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out syncBuffer
+			d := New(Config{
+				ListenAddr:        "127.0.0.1:7070",
+				Project:           "test",
+				PollInterval:      time.Second,
+				PoolSize:          1,
+				SpawnCmd:          "echo test",
+				SpawnPolicy:       SpawnPolicyManual,
+				ReconcileInterval: DefaultReconcileInterval,
+				ShutdownToken:     tt.token,
+				Logger:            slog.New(slog.NewTextHandler(&out, nil)),
+			})
+
+			resp := d.handleShutdown(true, tt.params)
+			if !strings.Contains(out.String(), `reason="deploy v2"`) {
+				t.Errorf("log missing shutdown reason:\n%s", out.String())
+			}
+			if !tt.wantStop {
+				if resp.Success || !strings.Contains(resp.Error, tt.wantError) {
+					t.Fatalf("handleShutdown = %+v, want error containing %q", resp, tt.wantError)
+				}
+				select {
+				case <-d.shutdown:
+					t.Fatal("shutdown channel closed for a rejected request")
+				case <-time.After(50 * time.Millisecond):
+				}
+				return
+			}
+
+			if !resp.Success {
+				t.Fatalf("handleShutdown error: %s", resp.Error)
+			}
+			select {
+			case <-d.shutdown:
+			case <-time.After(100 * time.Millisecond):
+				t.Fatal("shutdown channel was not closed")
+			}
+			if d.stopReason != "deploy v2" {
+				t.Errorf("stopReason = %q, want %q", d.stopReason, "deploy v2")
+			}
+		})
+	}
+}
+
+func TestHTTPServerClosesSilentConnection(t *testing.T) {
+	cfg := Config{
+		ListenAddr:        "127.0.0.1:7070",
+		Project:           "test",
+		PollInterval:      time.Second,
+		PoolSize:          1,
+		SpawnCmd:          "echo test",
+		SpawnPolicy:       SpawnPolicyManual,
+		ReconcileInterval: DefaultReconcileInterval,
+		ConnReadTimeout:   100 * time.Millisecond,
+	}
+	d := New(cfg)
+	srv := d.newHTTPServer()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() { _ = srv.Serve(ln) }()
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// Send nothing. The server should hang up once the read timeout passes;
+	// the client-side deadline only guards against the test hanging.
+	start := time.Now()
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	_, err = conn.Read(make([]byte, 1))
+	if !errors.Is(err, io.EOF) {
+		t.Fatalf("read error = %v, want EOF from server closing the connection", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("connection closed after %v, want about %v", elapsed, cfg.ConnReadTimeout)
+	}
+}
+
+func TestHTTPStatusStreamPushesFrameOnPoolChange(t *testing.T) {
+	cfg := Config{
+		ListenAddr:        "127.0.0.1:7070",
+		Project:           "test",
+		PollInterval:      time.Second,
+		PoolSize:          1,
+		SpawnCmd:          "echo test",
+		SpawnPolicy:       SpawnPolicyManual,
+		ReconcileInterval: DefaultReconcileInterval,
+		SessionDir:        t.TempDir(),
+	}
+	d := New(cfg)
+	d.authToken = "test-token"
+
+	srv := httptest.NewServer(d.newHTTPHandler())
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/api/v1/status/stream", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(daemonAuthHeader, d.authToken)
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+
+	frames := make(chan FullStatus)
+	go func() {
+		defer close(frames)
+		dec := json.NewDecoder(resp.Body)
+		for {
+			var status FullStatus
+			if err := dec.Decode(&status); err != nil {
+				return
+			}
+			frames <- status
+		}
+	}()
+	next := func() FullStatus {
+		t.Helper()
+		select {
+		case status, ok := <-frames:
+			if !ok {
+				t.Fatal("stream closed early")
+			}
+			return status
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for a status frame")
+		}
+		return FullStatus{}
+	}
+
+	if first := next(); first.PoolMode != PoolActive {
+		t.Fatalf("first frame pool_mode = %q, want %q", first.PoolMode, PoolActive)
+	}
+
+	d.pool.Pause()
+
+	if second := next(); second.PoolMode != PoolPaused {
+		t.Errorf("frame after pause pool_mode = %q, want %q", second.PoolMode, PoolPaused)
+	}
+}
+
+func TestHTTPStatusFullStopsWorkWhenClientDisconnects(t *testing.T) {
+	called := make(chan struct{})
+	cancelled := make(chan struct{})
+	prog := progRunner(testTaskMeta)
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if len(args) >= 1 && args[0] == "ready" {
+			close(called)
+			select {
+			case <-ctx.Done():
+				close(cancelled)
+				return nil, ctx.Err()
+			case <-time.After(5 * time.Second):
+				return []byte("No ready tasks"), nil
+			}
+		}
+		return prog(ctx, name, args...)
+	}
+	proc, release := newFakeProcess(1234)
+	defer release()
+	starter := func(context.Context, string, string, string, io.Writer, StartOptions) (Process, error) {
+		return proc, nil
+	}
+
+	var logs syncBuffer
+	pool := testPool(t, runner, starter)
+	pool.schedule(context.Background(), []Task{{ID: "ts-abc"}})
+	cfg := pool.config
+	cfg.SpawnPolicy = SpawnPolicyAuto
+	cfg.Runner = runner
+	d := &Daemon{
+		config:    cfg,
+		pool:      pool,
+		log:       slog.New(slog.NewTextHandler(&logs, nil)),
+		shutdown:  make(chan struct{}),
+		authToken: "test-token",
+	}
+	srv := httptest.NewServer(d.newHTTPHandler())
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Fprintf(conn, "GET /api/v1/status HTTP/1.1\r\nHost: 127.0.0.1\r\n%s: %s\r\n\r\n", daemonAuthHeader, d.authToken)
+
+	select {
+	case <-called:
+	case <-time.After(5 * time.Second):
+		t.Fatal("status build never reached prog ready")
+	}
+	// Hang up while the daemon is still building the response.
+	_ = conn.Close()
+
+	select {
+	case <-cancelled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("prog call kept running after the client disconnected")
+	}
+	waitFor(t, func() bool { return strings.Contains(logs.String(), "status.full client disconnected") })
+	if strings.Contains(logs.String(), "write failed") {
+		t.Errorf("disconnect logged as a write failure:\n%s", logs.String())
+	}
+}
+
+func TestClientGone(t *testing.T) {
+	for _, tt := range []struct {
+		err  error
+		want bool
+	}{
+		{&net.OpError{Op: "write", Err: os.NewSyscallError("write", syscall.EPIPE)}, true},
+		{&net.OpError{Op: "write", Err: os.NewSyscallError("write", syscall.ECONNRESET)}, true},
+		{context.Canceled, true},
+		{os.ErrDeadlineExceeded, false},
+		{errors.New("encode failed"), false},
+	} {
+		if got := clientGone(tt.err); got != tt.want {
+			t.Errorf("clientGone(%v) = %v, want %v", tt.err, got, tt.want)
+		}
+	}
+}
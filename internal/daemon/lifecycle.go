@@ -7,10 +7,10 @@ import (
 	"github.com/baiirun/aetherflow/internal/protocol"
 )
 
-func activeWorkSnapshot(pool *Pool, spawns *SpawnRegistry) (count int, ids []string) {
+func activeWorkSnapshot(pools []*Pool, spawns *SpawnRegistry) (count int, ids []string) {
 	sessionSet := make(map[string]struct{})
 
-	if pool != nil {
+	for _, pool := range pools {
 		for _, agent := range pool.Status() {
 			if agent.State != AgentRunning {
 				continue
@@ -49,7 +49,7 @@ func (d *Daemon) lifecycleStatus() protocol.DaemonLifecycleStatus {
 	status := d.life
 	d.lifeMu.RUnlock()
 
-	_, ids := activeWorkSnapshot(d.pool, d.spawns)
+	_, ids := activeWorkSnapshot(d.pools(), d.spawns)
 	status.ActiveSessionCount = len(ids)
 	status.ActiveSessionIDs = ids
 	if status.UpdatedAt.IsZero() {
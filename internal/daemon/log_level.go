@@ -0,0 +1,64 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// levelHandler lowers (or raises) the minimum level of the handler it wraps.
+// The wrapped handler's own level check is bypassed, so a debug override
+// still emits through a daemon logger configured at info.
+type levelHandler struct {
+	slog.Handler
+	level slog.Level
+}
+
+func (h levelHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return levelHandler{Handler: h.Handler.WithAttrs(attrs), level: h.level}
+}
+
+func (h levelHandler) WithGroup(name string) slog.Handler {
+	return levelHandler{Handler: h.Handler.WithGroup(name), level: h.level}
+}
+
+// ParseLogLevel parses debug, info, warn, or error (case-insensitive).
+func ParseLogLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("invalid log level %q (want debug, info, warn, or error)", s)
+	}
+	return level, nil
+}
+
+// SetLogLevel overrides the log level for taskID's spawn, reap, and respawn
+// lines. The override lasts until the task's agent exits without a respawn.
+// It reports the ID of the running agent, or "" when the task has none.
+func (p *Pool) SetLogLevel(taskID string, level slog.Level) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.logLevels[taskID] = level
+	agentID := ""
+	if agent, ok := p.agents[taskID]; ok {
+		agentID = string(agent.ID)
+	}
+	p.log.Info("task log level set", "task_id", taskID, "level", strings.ToLower(level.String()), "agent_id", agentID)
+	return agentID
+}
+
+// taskLog returns the logger for taskID's lifecycle lines: the pool logger,
+// or a child at the overridden level when one is set.
+func (p *Pool) taskLog(taskID string) *slog.Logger {
+	p.mu.RLock()
+	level, ok := p.logLevels[taskID]
+	p.mu.RUnlock()
+	if !ok {
+		return p.log
+	}
+	return slog.New(levelHandler{Handler: p.log.Handler(), level: level})
+}
@@ -0,0 +1,106 @@
+package daemon
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// syncBuffer is a bytes.Buffer safe for the pool's concurrent log writes.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestPoolTaskLogLevelOverride(t *testing.T) {
+	var out syncBuffer
+	log := slog.New(slog.NewTextHandler(&out, &slog.HandlerOptions{Level: slog.LevelInfo}))
+
+	procs := map[string]func(){}
+	var mu sync.Mutex
+	pid := 0
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		pid++
+		proc, release := newFakeProcess(pid)
+		procs[prompt] = release
+		return proc, nil
+	}
+
+	pool := testPool(t, progRunner(testTaskMeta), starter)
+	pool.log = log
+	pool.ctx = context.Background()
+
+	if agentID := pool.SetLogLevel("ts-loud", slog.LevelDebug); agentID != "" {
+		t.Errorf("SetLogLevel agent = %q, want none before spawn", agentID)
+	}
+
+	ctx := context.Background()
+	pool.spawn(ctx, Task{ID: "ts-loud", Priority: 1, Title: "Loud"})
+	pool.spawn(ctx, Task{ID: "ts-quiet", Priority: 1, Title: "Quiet"})
+	if len(pool.Status()) != 2 {
+		t.Fatalf("got %d agents, want 2", len(pool.Status()))
+	}
+
+	mu.Lock()
+	for _, release := range procs {
+		release()
+	}
+	mu.Unlock()
+	waitFor(t, func() bool {
+		return len(pool.Status()) == 0 && strings.Count(out.String(), "agent exited cleanly") == 2
+	})
+
+	logs := out.String()
+	found := false
+	for _, line := range strings.Split(logs, "\n") {
+		if !strings.Contains(line, "level=DEBUG") {
+			continue
+		}
+		if strings.Contains(line, "task_id=ts-quiet") {
+			t.Errorf("unexpected debug line for task without override: %s", line)
+		}
+		if strings.Contains(line, `msg="agent process exited"`) && strings.Contains(line, "task_id=ts-loud") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected task-scoped debug line for ts-loud, got:\n%s", logs)
+	}
+
+	pool.mu.RLock()
+	_, still := pool.logLevels["ts-loud"]
+	pool.mu.RUnlock()
+	if still {
+		t.Error("log level override not cleared after agent exited")
+	}
+}
+
+func TestParseLogLevel(t *testing.T) {
+	if level, err := ParseLogLevel("debug"); err != nil || level != slog.LevelDebug {
+		t.Errorf("ParseLogLevel(debug) = %v, %v", level, err)
+	}
+	if level, err := ParseLogLevel("WARN"); err != nil || level != slog.LevelWarn {
+		t.Errorf("ParseLogLevel(WARN) = %v, %v", level, err)
+	}
+	if _, err := ParseLogLevel("loud"); err == nil {
+		t.Error("expected error for unknown level")
+	}
+}
@@ -52,8 +52,8 @@ type LogEvent struct {
 // Plugin events use "message.part.updated" with data: {"part": {...}} where
 // the part has type "text", "tool", "step-start", "step-finish", etc. This
 // function handles text, tool, and step-finish event types from the plugin
-// event shape.
-func FormatEvent(ev SessionEvent) string {
+// event shape. fields adds tool input mappings for tool lines.
+func FormatEvent(ev SessionEvent, fields ToolInputFields) string {
 	if ev.EventType != "message.part.updated" {
 		return ""
 	}
@@ -90,7 +90,7 @@ func FormatEvent(ev SessionEvent) string {
 		return formatText(ts, logEv)
 	case "tool":
 		logEv.Type = "tool_use"
-		return formatToolUse(ts, logEv)
+		return formatToolUse(ts, logEv, fields)
 	case "step-finish":
 		logEv.Type = "step_finish"
 		return formatStepFinish(ts, logEv)
@@ -122,11 +122,11 @@ func formatText(ts string, ev LogEvent) string {
 	return fmt.Sprintf("%s%s%s  %s", ansiDim, ts, ansiReset, text)
 }
 
-func formatToolUse(ts string, ev LogEvent) string {
+func formatToolUse(ts string, ev LogEvent, fields ToolInputFields) string {
 	tool := ev.Part.Tool
 	status := ev.Part.State.Status
 	title := ev.Part.State.Title
-	input := extractKeyInput(tool, ev.Part.State.Input)
+	input := extractKeyInput(tool, ev.Part.State.Input, fields)
 
 	// Use title if available, otherwise input summary.
 	label := input
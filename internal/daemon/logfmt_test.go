@@ -15,7 +15,7 @@ func TestFormatEvent_Tool(t *testing.T) {
 		Data:      []byte(`{"part":{"type":"tool","tool":"bash","state":{"status":"completed","input":{"command":"cargo build","description":"Build project"},"title":"Build project","time":{"start":1770534051422,"end":1770534053453}}}}`),
 	}
 
-	result := FormatEvent(ev)
+	result := FormatEvent(ev, nil)
 	if result == "" {
 		t.Fatal("expected non-empty result for tool event")
 	}
@@ -38,7 +38,7 @@ func TestFormatEvent_Text(t *testing.T) {
 		Data:      []byte(`{"part":{"type":"text","text":"Starting implementation now."}}`),
 	}
 
-	result := FormatEvent(ev)
+	result := FormatEvent(ev, nil)
 	if result == "" {
 		t.Fatal("expected non-empty result for text event")
 	}
@@ -55,7 +55,7 @@ func TestFormatEvent_StepFinish(t *testing.T) {
 		Data:      []byte(`{"part":{"type":"step-finish","reason":"tool-calls","tokens":{"input":0,"output":126,"reasoning":0,"cache":{"read":101522,"write":273}}}}`),
 	}
 
-	result := FormatEvent(ev)
+	result := FormatEvent(ev, nil)
 	if result == "" {
 		t.Fatal("expected non-empty result for step-finish event")
 	}
@@ -75,7 +75,7 @@ func TestFormatEvent_StepStart(t *testing.T) {
 		Data:      []byte(`{"part":{"type":"step-start"}}`),
 	}
 
-	result := FormatEvent(ev)
+	result := FormatEvent(ev, nil)
 	if result != "" {
 		t.Errorf("step-start should be hidden, got: %s", result)
 	}
@@ -89,7 +89,7 @@ func TestFormatEvent_NonPartEvent(t *testing.T) {
 		Data:      []byte(`{"info":{"id":"ses-1"}}`),
 	}
 
-	result := FormatEvent(ev)
+	result := FormatEvent(ev, nil)
 	if result != "" {
 		t.Errorf("non-part events should be hidden, got: %s", result)
 	}
@@ -102,7 +102,7 @@ func TestFormatEvent_EmptyData(t *testing.T) {
 		Timestamp: 1000,
 	}
 
-	result := FormatEvent(ev)
+	result := FormatEvent(ev, nil)
 	if result != "" {
 		t.Errorf("empty data should return empty, got: %s", result)
 	}
@@ -116,7 +116,7 @@ func TestFormatEvent_InvalidJSON(t *testing.T) {
 		Data:      []byte(`not json`),
 	}
 
-	result := FormatEvent(ev)
+	result := FormatEvent(ev, nil)
 	if result != "" {
 		t.Errorf("invalid JSON should return empty, got: %s", result)
 	}
@@ -0,0 +1,152 @@
+package daemon
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// metaCache holds task metadata fetched ahead of spawn (see
+// Config.PrefetchMetaTTL), so a queued task whose slot frees up is claimed
+// without first waiting on `prog show`. It has its own lock because
+// prefetches run outside the pool's Run loop.
+type metaCache struct {
+	mu      sync.Mutex
+	entries map[string]metaCacheEntry
+
+	// prefetching guards against overlapping prefetch passes.
+	prefetching atomic.Bool
+}
+
+type metaCacheEntry struct {
+	meta    TaskMeta
+	fetched time.Time
+}
+
+// take removes and returns taskID's metadata if it was fetched within ttl.
+// An entry is used at most once: after a spawn attempt the task's status
+// has moved on, so later spawns fetch fresh metadata.
+func (c *metaCache) take(taskID string, now time.Time, ttl time.Duration) (TaskMeta, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[taskID]
+	if !ok {
+		return TaskMeta{}, false
+	}
+	delete(c.entries, taskID)
+	if now.Sub(e.fetched) > ttl {
+		return TaskMeta{}, false
+	}
+	return e.meta, true
+}
+
+// peek returns taskID's metadata if it was fetched within ttl, leaving the
+// entry in place.
+func (c *metaCache) peek(taskID string, now time.Time, ttl time.Duration) (TaskMeta, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[taskID]
+	if !ok || now.Sub(e.fetched) > ttl {
+		return TaskMeta{}, false
+	}
+	return e.meta, true
+}
+
+func (c *metaCache) put(taskID string, meta TaskMeta, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = make(map[string]metaCacheEntry)
+	}
+	c.entries[taskID] = metaCacheEntry{meta: meta, fetched: now}
+}
+
+// prune drops entries older than ttl.
+func (c *metaCache) prune(now time.Time, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for id, e := range c.entries {
+		if now.Sub(e.fetched) > ttl {
+			delete(c.entries, id)
+		}
+	}
+}
+
+// taskMeta returns the task's metadata for spawn, from the prefetch cache
+// when a fresh entry exists and from the work source otherwise.
+func (p *Pool) taskMeta(ctx context.Context, taskID string) (TaskMeta, error) {
+	if ttl := p.config.PrefetchMetaTTL; ttl > 0 {
+		if meta, ok := p.metas.take(taskID, p.clock.Now(), ttl); ok {
+			p.log.Debug("using prefetched task metadata", "task_id", taskID)
+			return meta, nil
+		}
+	}
+	return p.work.GetMeta(ctx, taskID, p.config.Project)
+}
+
+// prefetchedNoDeps reports whether the task's prefetched metadata lists no
+// dependencies, so the dependency check needs no prog call. Tasks with
+// dependencies are always checked live, since their status changes.
+func (p *Pool) prefetchedNoDeps(taskID string) bool {
+	ttl := p.config.PrefetchMetaTTL
+	if ttl <= 0 {
+		return false
+	}
+	meta, ok := p.metas.peek(taskID, p.clock.Now(), ttl)
+	return ok && len(meta.Dependencies) == 0
+}
+
+// startPrefetch fetches metadata for the queued tasks next in line for a
+// slot in the background, unless prefetching is off or a pass is already
+// running. It is called after each schedule pass with the polled queue.
+func (p *Pool) startPrefetch(ctx context.Context, tasks []Task) {
+	if p.config.PrefetchMetaTTL <= 0 || len(tasks) == 0 {
+		return
+	}
+	if !p.metas.prefetching.CompareAndSwap(false, true) {
+		return
+	}
+	go func() {
+		defer p.metas.prefetching.Store(false)
+		p.prefetchMeta(ctx, tasks)
+	}()
+}
+
+// prefetchMeta caches metadata for up to PoolSize of tasks that have no
+// agent yet and no fresh cache entry, in queue order. Fetch failures are
+// only logged: spawn fetches the metadata itself and reports the error.
+func (p *Pool) prefetchMeta(ctx context.Context, tasks []Task) {
+	ttl := p.config.PrefetchMetaTTL
+	p.metas.prune(p.clock.Now(), ttl)
+
+	tasks = p.prioritize(tasks)
+	p.mu.RLock()
+	limit := p.config.PoolSize
+	var pending []string
+	for _, task := range tasks {
+		if len(pending) >= limit {
+			break
+		}
+		if _, running := p.agents[task.ID]; running || p.retired[task.ID] {
+			continue
+		}
+		pending = append(pending, task.ID)
+	}
+	p.mu.RUnlock()
+
+	for _, id := range pending {
+		if ctx.Err() != nil {
+			return
+		}
+		if _, ok := p.metas.peek(id, p.clock.Now(), ttl); ok {
+			continue
+		}
+		meta, err := p.work.GetMeta(ctx, id, p.config.Project)
+		if err != nil {
+			p.log.Debug("task metadata prefetch failed", "task_id", id, "error", err)
+			continue
+		}
+		p.metas.put(id, meta, p.clock.Now())
+	}
+}
@@ -0,0 +1,84 @@
+package daemon
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSpawnUsesPrefetchedMeta(t *testing.T) {
+	tests := []struct {
+		name      string
+		age       time.Duration // how long after the prefetch the spawn runs
+		wantShows int32
+	}{
+		{"within ttl", 30 * time.Second, 1},
+		// Stale: the dependency check and spawn each run prog show again.
+		{"after ttl", 2 * time.Minute, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proc, release := newFakeProcess(1234)
+			defer release()
+
+			var shows atomic.Int32
+			base := progRunner(testTaskMeta)
+			runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+				if len(args) >= 1 && args[0] == "show" {
+					shows.Add(1)
+				}
+				return base(ctx, name, args...)
+			}
+			starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+				return proc, nil
+			}
+
+			pool := testPool(t, runner, starter)
+			pool.config.PrefetchMetaTTL = time.Minute
+			clock := newFakeClock(time.Now())
+			pool.clock = clock
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			tasks := []Task{{ID: "ts-abc", Priority: 1, Title: "Do it"}}
+			pool.prefetchMeta(ctx, tasks)
+			if got := shows.Load(); got != 1 {
+				t.Fatalf("prefetch ran prog show %d times, want 1", got)
+			}
+
+			clock.Advance(tt.age)
+			pool.schedule(ctx, tasks)
+
+			if len(pool.Status()) != 1 {
+				t.Fatalf("agents = %d, want 1", len(pool.Status()))
+			}
+			if got := shows.Load(); got != tt.wantShows {
+				t.Errorf("prog show ran %d times, want %d", got, tt.wantShows)
+			}
+		})
+	}
+}
+
+func TestPrefetchMetaSkipsRunningAndLimitsToPoolSize(t *testing.T) {
+	var shown []string
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if len(args) >= 2 && args[0] == "show" {
+			shown = append(shown, args[1])
+		}
+		return progRunner(testTaskMeta)(ctx, name, args...)
+	}
+
+	pool := testPool(t, runner, nil)
+	pool.config.PrefetchMetaTTL = time.Minute
+	pool.agents["ts-1"] = &Agent{ID: "a1", TaskID: "ts-1", State: AgentRunning}
+
+	pool.prefetchMeta(context.Background(), []Task{{ID: "ts-1"}, {ID: "ts-2"}, {ID: "ts-3"}, {ID: "ts-4"}})
+
+	// PoolSize is 2, so only the next two waiting tasks are fetched.
+	if len(shown) != 2 || shown[0] != "ts-2" || shown[1] != "ts-3" {
+		t.Errorf("prefetched %v, want [ts-2 ts-3]", shown)
+	}
+}
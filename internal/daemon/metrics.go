@@ -0,0 +1,95 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// MetricsSample is one line of the metrics file: a point-in-time view of
+// pool load for offline analysis.
+type MetricsSample struct {
+	Time        time.Time `json:"time"`
+	Project     string    `json:"project,omitempty"`
+	PoolSize    int       `json:"pool_size"`
+	Running     int       `json:"running"`
+	Utilization float64   `json:"utilization"` // running / pool_size
+	QueueDepth  int       `json:"queue_depth"` // ready tasks as of the last poll
+	// Crashes is cumulative since the pool started; diff consecutive
+	// samples for a per-interval count.
+	Crashes           int `json:"crashes"`
+	CompletedLastHour int `json:"completed_last_hour"`
+}
+
+// MetricsSample returns the pool's current load.
+func (p *Pool) MetricsSample() MetricsSample {
+	completed, _ := p.Throughput()
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	s := MetricsSample{
+		Time:              p.clock.Now(),
+		Project:           p.config.Project,
+		PoolSize:          p.config.PoolSize,
+		Running:           len(p.agents),
+		QueueDepth:        p.queueDepthLocked(),
+		Crashes:           p.crashes,
+		CompletedLastHour: completed,
+	}
+	if s.PoolSize > 0 {
+		s.Utilization = float64(s.Running) / float64(s.PoolSize)
+	}
+	return s
+}
+
+// queueDepthLocked counts ready tasks from the last poll that don't have
+// an agent yet. Caller must hold p.mu.
+func (p *Pool) queueDepthLocked() int {
+	n := 0
+	for id := range p.seen {
+		if _, running := p.agents[id]; !running {
+			n++
+		}
+	}
+	return n
+}
+
+// sampleMetrics appends a MetricsSample to w as a JSON line every interval
+// until ctx is cancelled.
+func (p *Pool) sampleMetrics(ctx context.Context, w io.Writer, interval time.Duration) {
+	ticker := p.clock.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+			line, err := json.Marshal(p.MetricsSample())
+			if err != nil {
+				continue
+			}
+			if _, err := w.Write(append(line, '\n')); err != nil {
+				p.log.Warn("writing metrics sample", "error", err)
+			}
+		}
+	}
+}
+
+// openMetricsFile opens path for appending samples, creating it if needed.
+// The file is owner-only, like the event sink.
+func openMetricsFile(path string) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening metrics file: %w", err)
+	}
+	// Tighten permissions on a file that already existed.
+	if err := f.Chmod(0o600); err != nil {
+		_ = f.Close()
+		return nil, fmt.Errorf("securing metrics file: %w", err)
+	}
+	return f, nil
+}
@@ -0,0 +1,58 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSampleMetricsAppendsLineEachInterval(t *testing.T) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		proc, _ := newFakeProcess(1234)
+		return proc, nil
+	}
+	pool := testPool(t, progRunner(testTaskMeta), starter)
+	clock := newFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	pool.clock = clock
+	pool.ctx = context.Background()
+
+	// Two agents fill the pool; the third task stays queued.
+	pool.schedule(context.Background(), []Task{{ID: "ts-aaa"}, {ID: "ts-bbb"}, {ID: "ts-ccc"}})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var out syncBuffer
+	go pool.sampleMetrics(ctx, &out, time.Minute)
+	waitFor(t, func() bool {
+		clock.mu.Lock()
+		defer clock.mu.Unlock()
+		return len(clock.tickers) == 1
+	})
+
+	lines := func() []string {
+		return strings.Split(strings.TrimSpace(out.String()), "\n")
+	}
+	clock.Advance(time.Minute)
+	waitFor(t, func() bool { return out.String() != "" })
+	clock.Advance(time.Minute)
+	waitFor(t, func() bool { return len(lines()) == 2 })
+
+	var sample map[string]any
+	if err := json.Unmarshal([]byte(lines()[1]), &sample); err != nil {
+		t.Fatalf("unmarshal %q: %v", lines()[1], err)
+	}
+	for _, field := range []string{"time", "pool_size", "running", "utilization", "queue_depth", "crashes", "completed_last_hour"} {
+		if _, ok := sample[field]; !ok {
+			t.Errorf("sample missing %q: %v", field, sample)
+		}
+	}
+	if sample["running"] != 2.0 || sample["utilization"] != 1.0 || sample["queue_depth"] != 1.0 || sample["crashes"] != 0.0 {
+		t.Errorf("sample = %v, want running 2, utilization 1, queue_depth 1, crashes 0", sample)
+	}
+	if want := clock.Now().Format(time.RFC3339); sample["time"] != want {
+		t.Errorf("time = %v, want %s", sample["time"], want)
+	}
+}
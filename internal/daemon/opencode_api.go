@@ -3,6 +3,7 @@ package daemon
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -64,3 +65,40 @@ func (c *opencodeClient) fetchSessionMessages(ctx context.Context, sessionID str
 
 	return messages, nil
 }
+
+// ping checks that the server answers HTTP. Any response counts: the probe
+// is about reachability, not about a particular endpoint's behavior.
+func (c *opencodeClient) ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/session", nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4<<10))
+	_ = resp.Body.Close()
+	return nil
+}
+
+// ErrServerUnreachable is returned by ProbeServer when no opencode server
+// answers at the given URL in time.
+var ErrServerUnreachable = errors.New("opencode server unreachable")
+
+// ProbeServer checks that an opencode server answers at serverURL within
+// timeout. Use it before handing the terminal to `opencode attach`, which
+// hangs rather than failing when the server is wedged.
+func ProbeServer(ctx context.Context, serverURL string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := newOpencodeClient(serverURL).ping(ctx); err != nil {
+		if ctx.Err() != nil {
+			return fmt.Errorf("%w: %s did not respond within %s", ErrServerUnreachable, serverURL, timeout)
+		}
+		return fmt.Errorf("%w: %s: %v", ErrServerUnreachable, serverURL, err)
+	}
+	return nil
+}
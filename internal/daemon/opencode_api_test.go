@@ -0,0 +1,64 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestProbeServerReachable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("[]"))
+	}))
+	defer srv.Close()
+
+	if err := ProbeServer(context.Background(), srv.URL, time.Second); err != nil {
+		t.Fatalf("ProbeServer: %v", err)
+	}
+}
+
+func TestProbeServerUnreachable(t *testing.T) {
+	// Grab a free port, then close it so nothing is listening.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	url := "http://" + ln.Addr().String()
+	_ = ln.Close()
+
+	err = ProbeServer(context.Background(), url, time.Second)
+	if !errors.Is(err, ErrServerUnreachable) {
+		t.Fatalf("err = %v, want ErrServerUnreachable", err)
+	}
+}
+
+func TestProbeServerTimeout(t *testing.T) {
+	// A server that accepts connections but never answers.
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	start := time.Now()
+	err := ProbeServer(context.Background(), srv.URL, 50*time.Millisecond)
+	if !errors.Is(err, ErrServerUnreachable) {
+		t.Fatalf("err = %v, want ErrServerUnreachable", err)
+	}
+	if !strings.Contains(err.Error(), "did not respond within 50ms") {
+		t.Errorf("err = %q, want timeout message", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("probe took %s, want it bounded by the timeout", elapsed)
+	}
+}
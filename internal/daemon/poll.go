@@ -17,6 +17,10 @@ type Task struct {
 	ID       string `json:"id"`
 	Priority int    `json:"priority"`
 	Title    string `json:"title"`
+
+	// ReadySince is when the daemon first saw the task in the ready queue.
+	// prog doesn't report it, so it is zero until the pool observes the task.
+	ReadySince time.Time `json:"ready_since,omitempty"`
 }
 
 // progListItem is the sparse parse target for `prog list --json`.
@@ -131,6 +135,12 @@ type Poller struct {
 	interval time.Duration
 	run      CommandRunner
 	log      *slog.Logger
+
+	// queue replaces `prog ready` as the task source when set.
+	queue TaskQueue
+
+	// reset carries a new interval to the running poll loop.
+	reset chan time.Duration
 }
 
 // NewPoller creates a poller that checks prog for ready tasks.
@@ -143,11 +153,61 @@ func NewPoller(project string, interval time.Duration, runner CommandRunner, log
 		interval: interval,
 		run:      runner,
 		log:      log,
+		reset:    make(chan time.Duration, 1),
+	}
+}
+
+// The poll interval floor grows by pollFloorPerSlot for each pool slot,
+// never dropping below minPollFloor. A pool of 20 lands on the 10s default.
+const (
+	minPollFloor     = time.Second
+	pollFloorPerSlot = 500 * time.Millisecond
+)
+
+// PollIntervalFloor returns the shortest poll interval recommended for a
+// pool of poolSize agents. Every poll shells out to prog, and a bigger pool
+// claims and inspects more tasks per batch, so it should poll less often.
+func PollIntervalFloor(poolSize int) time.Duration {
+	return max(minPollFloor, time.Duration(poolSize)*pollFloorPerSlot)
+}
+
+// warnAggressivePolling logs a warning when interval is below the floor
+// for poolSize. The interval is still honored.
+func warnAggressivePolling(log *slog.Logger, interval time.Duration, poolSize int) {
+	if log == nil {
+		return
+	}
+	if floor := PollIntervalFloor(poolSize); interval < floor {
+		log.Warn("poll interval is aggressively low for the pool size, expect heavy prog load",
+			"poll_interval", interval,
+			"pool_size", poolSize,
+			"recommended_min", floor,
+		)
+	}
+}
+
+// SetInterval changes the poll interval. A running loop picks up the new
+// interval on its next tick; the latest value wins if called repeatedly.
+func (p *Poller) SetInterval(d time.Duration) {
+	for {
+		select {
+		case p.reset <- d:
+			return
+		default:
+		}
+		// Drop a pending, not yet applied value and retry.
+		select {
+		case <-p.reset:
+		default:
+		}
 	}
 }
 
-// Poll fetches ready tasks from prog once.
+// Poll fetches ready tasks once, from the queue if set and prog otherwise.
 func (p *Poller) Poll(ctx context.Context) ([]Task, error) {
+	if p.queue != nil {
+		return p.queue.Ready(ctx)
+	}
 	output, err := p.run(ctx, "prog", "ready", "-p", p.project)
 	if err != nil {
 		return nil, fmt.Errorf("prog ready: %w (output: %s)", err, string(output))
@@ -175,7 +235,8 @@ func (p *Poller) Start(ctx context.Context) <-chan []Task {
 		)
 
 		// Poll immediately on start, then on interval.
-		ticker := time.NewTicker(p.interval)
+		interval := p.interval
+		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
 
 		p.pollAndSend(ctx, ch)
@@ -187,6 +248,12 @@ func (p *Poller) Start(ctx context.Context) <-chan []Task {
 				return
 			case <-ticker.C:
 				p.pollAndSend(ctx, ch)
+			case d := <-p.reset:
+				if d != interval {
+					p.log.Info("poll interval changed", "from", interval, "to", d)
+					interval = d
+					ticker.Reset(d)
+				}
 			}
 		}
 	}()
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"strings"
 	"testing"
 	"time"
 )
@@ -256,3 +257,62 @@ func TestPollerStartNoTasksNothingSent(t *testing.T) {
 		t.Fatal("timed out — channel should close when context expires")
 	}
 }
+
+func TestPollerSetIntervalKeepsLatest(t *testing.T) {
+	p := NewPoller("myproject", time.Hour, fakeRunner("", nil), slog.Default())
+	p.SetInterval(time.Minute)
+	p.SetInterval(time.Second)
+
+	select {
+	case d := <-p.reset:
+		if d != time.Second {
+			t.Errorf("pending interval = %v, want %v", d, time.Second)
+		}
+	default:
+		t.Fatal("no pending interval")
+	}
+}
+
+func TestPollIntervalFloor(t *testing.T) {
+	tests := []struct {
+		poolSize int
+		want     time.Duration
+	}{
+		{poolSize: 0, want: time.Second},
+		{poolSize: 1, want: time.Second},
+		{poolSize: 2, want: time.Second},
+		{poolSize: 3, want: 1500 * time.Millisecond},
+		{poolSize: 8, want: 4 * time.Second},
+		{poolSize: 20, want: DefaultPollInterval},
+		{poolSize: 50, want: 25 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := PollIntervalFloor(tt.poolSize); got != tt.want {
+			t.Errorf("PollIntervalFloor(%d) = %v, want %v", tt.poolSize, got, tt.want)
+		}
+	}
+}
+
+func TestWarnAggressivePolling(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		poolSize int
+		wantWarn bool
+	}{
+		{name: "default interval small pool", interval: DefaultPollInterval, poolSize: 3, wantWarn: false},
+		{name: "fast small pool", interval: time.Second, poolSize: 2, wantWarn: false},
+		{name: "fast large pool", interval: time.Second, poolSize: 16, wantWarn: true},
+		{name: "default interval huge pool", interval: DefaultPollInterval, poolSize: 40, wantWarn: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf syncBuffer
+			log := slog.New(slog.NewTextHandler(&buf, nil))
+			warnAggressivePolling(log, tt.interval, tt.poolSize)
+			if got := strings.Contains(buf.String(), "poll interval is aggressively low"); got != tt.wantWarn {
+				t.Errorf("warned = %v, want %v (log: %q)", got, tt.wantWarn, buf.String())
+			}
+		})
+	}
+}
@@ -2,13 +2,17 @@ package daemon
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"os"
 	"os/exec"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -43,14 +47,28 @@ const (
 
 // Agent tracks a spawned agent process in the pool.
 type Agent struct {
-	ID        protocol.AgentID `json:"id"`
-	TaskID    string           `json:"task_id"`
-	Role      Role             `json:"role"`
-	PID       int              `json:"pid"`
-	SessionID string           `json:"session_id,omitempty"`
-	SpawnTime time.Time        `json:"spawn_time"`
-	State     AgentState       `json:"state"`
-	ExitCode  int              `json:"exit_code,omitempty"`
+	ID           protocol.AgentID `json:"id"`
+	TaskID       string           `json:"task_id"`
+	Role         Role             `json:"role"`
+	PID          int              `json:"pid"`
+	SessionID    string           `json:"session_id,omitempty"`
+	SessionError string           `json:"session_error,omitempty"`
+	SpawnTime    time.Time        `json:"spawn_time"`
+	State        AgentState       `json:"state"`
+	ExitCode     int              `json:"exit_code,omitempty"`
+
+	// Prompt is the rendered prompt the agent was launched with. It is
+	// left out of status payloads and served by the agent prompt endpoint.
+	Prompt string `json:"-"`
+
+	// stdout fans the agent's stdout out to live subscribers (af logs
+	// --live). Nil for agents the pool did not start itself.
+	stdout *stdoutTee
+
+	// cancel cancels the context the agent's process was started with,
+	// which kills it (see CancelAgent). Nil for agents the pool did not
+	// start itself.
+	cancel context.CancelFunc
 }
 
 // Process is the handle to a spawned agent process.
@@ -66,8 +84,20 @@ type Process interface {
 // The prompt is the rendered role prompt passed as the message argument to the spawn command.
 // agentID is set as the AETHERFLOW_AGENT_ID environment variable on the spawned process.
 // stdout receives the process's standard output (typically a log file).
+// opts carries the per-launch settings beyond these, such as extra env.
 // This is the seam for testing — swap with a fake that returns immediately.
-type ProcessStarter func(ctx context.Context, spawnCmd string, prompt string, agentID string, stdout io.Writer) (Process, error)
+type ProcessStarter func(ctx context.Context, spawnCmd string, prompt string, agentID string, stdout io.Writer, opts StartOptions) (Process, error)
+
+// StartOptions holds the optional settings a ProcessStarter launches an
+// agent with. New settings are added here so starters that ignore them
+// need no change.
+type StartOptions struct {
+	// Env holds extra KEY=VALUE variables for the process, such as
+	// exported task metadata.
+	Env []string
+	// Limits caps the process's resources from before it runs.
+	Limits ResourceLimits
+}
 
 // execProcess wraps *exec.Cmd to implement Process.
 type execProcess struct {
@@ -80,20 +110,33 @@ func (p *execProcess) PID() int    { return p.cmd.Process.Pid }
 // ExecProcessStarter spawns a real OS process.
 // The prompt is appended as the final argument to the spawn command,
 // e.g. "opencode run --format json" becomes ["opencode", "run", "--format", "json", "<prompt>"].
-// agentID is exposed as the AETHERFLOW_AGENT_ID environment variable.
+// agentID is exposed as the AETHERFLOW_AGENT_ID environment variable, and
+// opts.Env is added on top of the daemon's environment. With opts.Limits
+// set, the command runs through limitArgs.
 // stdout receives the process's standard output (typically a log file).
-func ExecProcessStarter(ctx context.Context, spawnCmd string, prompt string, agentID string, stdout io.Writer) (Process, error) {
+// Cancelling ctx kills the process's whole group with SIGKILL.
+func ExecProcessStarter(ctx context.Context, spawnCmd string, prompt string, agentID string, stdout io.Writer, opts StartOptions) (Process, error) {
 	parts := strings.Fields(spawnCmd)
 	if len(parts) == 0 {
 		return nil, fmt.Errorf("empty spawn command")
 	}
 
 	parts = append(parts, prompt)
+	if !opts.Limits.IsZero() {
+		parts = limitArgs(parts, opts.Limits)
+	}
 	cmd := exec.CommandContext(ctx, parts[0], parts[1:]...)
 	cmd.Env = append(os.Environ(), "AETHERFLOW_AGENT_ID="+agentID)
+	cmd.Env = append(cmd.Env, opts.Env...)
 	cmd.SysProcAttr = &syscall.SysProcAttr{
 		Setsid: true, // Own process group so terminal signals don't propagate to daemon
 	}
+	// Cancelling ctx kills the whole process group, not just the leader,
+	// so tools the agent started don't outlive it. The child is unreaped
+	// until Wait returns, so its PID can't have been reused.
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
 	cmd.Stdout = stdout
 	cmd.Stderr = os.Stderr
 
@@ -107,21 +150,125 @@ func ExecProcessStarter(ctx context.Context, spawnCmd string, prompt string, age
 // Pool manages a fixed number of agent slots.
 type Pool struct {
 	mu      sync.RWMutex
-	mode    PoolMode          // controls scheduling behavior
-	agents  map[string]*Agent // keyed by task ID
-	retries map[string]int    // crash count per task ID
+	mode    PoolMode             // controls scheduling behavior
+	paused  map[Role]bool        // roles paused independently of mode
+	agents  map[string]*Agent    // keyed by task ID
+	retries map[string]int       // crash count per task ID
+	startup map[string]int       // startup failure count per task ID (crash before MinHealthyUptime)
+	retired map[string]bool      // task IDs excluded from scheduling and respawn
+	killed  map[string]bool      // task IDs whose agent is being killed; no respawn on exit
+	seen    map[string]time.Time // first time each queued task ID was seen ready
+	skips   map[string]string    // why each task in the last schedule pass wasn't started
+	taskEnv map[string][]string  // AETHERFLOW_TASK_* env per task, kept for respawns
+	probed  map[string]string    // task IDs whose agent the startup probe stopped, and why
+	prios   map[string]int       // prog priority per task ID when known, kept for respawns
 	names   *protocol.NameGenerator
 	config  Config
 	runner  CommandRunner
 	starter ProcessStarter
 	sstore  *sessions.Store
+	retryDB *retryStore // persists retries across restarts; nil = memory only
 	work    WorkSource
+	queue   TaskQueue // the poller's queue source when not prog ready; status lists it
 	log     *slog.Logger
 	ctx     context.Context // stored for respawn goroutines
 
+	// claiming holds task IDs between spawn's claim in prog and the agent
+	// being recorded, so the orphan scan doesn't mistake them for orphans.
+	// orphans holds the task IDs the previous orphan scan found without an
+	// agent; a task is only recovered once two scans agree. See RecoverOrphans.
+	// spawnClaimed holds tasks found carrying SpawnClaimLabel, which an
+	// af spawn --pick agent works outside the pool, and when the claim was
+	// last known to be live. See claimedBySpawn.
+	claiming     map[string]bool
+	orphans      map[string]bool
+	spawnClaimed map[string]time.Time
+
+	// stopping tracks agents being stopped intentionally (rolling restart,
+	// preemption), keyed by task ID. reap closes the channel instead of
+	// treating the exit as a crash, so no retry is counted and no automatic
+	// respawn happens.
+	stopping map[string]chan struct{}
+
+	// logLevels holds per-task log level overrides set via SetLogLevel.
+	// An entry is dropped when the task's agent exits without a respawn.
+	logLevels map[string]slog.Level
+
+	// history records every task scheduled since startup and how it ended,
+	// keyed by task ID; historyOrder keeps first-scheduled order.
+	history      map[string]*TaskHistoryEntry
+	historyOrder []string
+
+	// priority holds task IDs enqueued by planner agents. They are scheduled
+	// ahead of the polled queue and dropped once a spawn is attempted.
+	// wake nudges Run to schedule them without waiting for the next poll.
+	priority []string
+	wake     chan struct{}
+
+	// yielded holds crashed tasks waiting to respawn until queued work has
+	// had a turn at the free slot (FairRespawn), oldest first.
+	yielded []yieldedTask
+
+	// preempted holds tasks whose agent was stopped for an urgent task,
+	// waiting to resume ahead of queued work. preempting is the urgent task
+	// an agent is being stopped for, empty when no preemption is in
+	// flight; lastPreempt is when the latest one started. See preemptFor.
+	preempted   []preemptedTask
+	preempting  string
+	lastPreempt time.Time
+
+	// changed is closed and replaced whenever agents, the queue, or the
+	// mode change, waking status streams. See Changes.
+	changed chan struct{}
+
+	// onChange is called, under p.mu, on every notifyChange, so the
+	// daemon can wake status streams for any project. Nil means nobody
+	// outside the pool listens.
+	onChange func()
+
+	// rolling guards against overlapping rolling restarts.
+	rolling atomic.Bool
+
 	// pidAlive checks whether a process with the given PID is still running.
 	// Defaults to the real syscall check; overridden in tests.
 	pidAlive func(int) bool
+
+	// stopProcess asks the agent process with the given PID to exit.
+	// Defaults to SIGTERM on the process group; overridden in tests.
+	stopProcess func(int) error
+
+	// heldElsewhere reports whether something outside the pool (a running
+	// af spawn --pick) is working a task, so the orphan scan skips it.
+	// Nil means nothing is. releasedElsewhere reports whether such a spawn
+	// has since exited, so its claim no longer protects the task.
+	heldElsewhere     func(taskID string) bool
+	releasedElsewhere func(taskID string) bool
+
+	// errs keeps recent operational failures for the errors feed.
+	errs *errorFeed
+
+	// readyCache keeps the ready queue the last status build fetched; see
+	// statusQueue.
+	readyCache queueCache
+
+	// inferRole maps task metadata to a role. Defaults to InferRole;
+	// overridden in tests.
+	inferRole func(TaskMeta) Role
+
+	// completions records clean agent exits for throughput reporting.
+	completions completionRing
+	startedAt   time.Time
+
+	// crashes counts agents that exited with an error, other than ones
+	// stopped or killed on request, since the pool started.
+	crashes int
+
+	// metas caches task metadata prefetched for queued tasks.
+	metas metaCache
+
+	// clock is the source of time for spawn times, sweeps, and timeouts.
+	// Defaults to the wall clock; overridden in tests.
+	clock Clock
 }
 
 // defaultPIDAlive checks process liveness via kill(pid, 0).
@@ -137,6 +284,25 @@ func defaultPIDAlive(pid int) bool {
 	return err != syscall.ESRCH
 }
 
+// defaultStopProcess sends SIGTERM to the agent's process group.
+// Agents are started with Setsid, so the PID is also the process group ID
+// and the signal reaches any children the agent started. A PID that doesn't
+// lead its own group is refused: it was reused by an unrelated process, and
+// kill(-pid) would hit some other group (kill(-1) every process we own).
+func defaultStopProcess(pid int) error {
+	if pid <= 1 {
+		return fmt.Errorf("refusing to signal pid %d", pid)
+	}
+	pgid, err := syscall.Getpgid(pid)
+	if err != nil {
+		return err
+	}
+	if pgid != pid {
+		return fmt.Errorf("pid %d is not its process group leader (pgid %d); not signalling", pid, pgid)
+	}
+	return syscall.Kill(-pid, syscall.SIGTERM)
+}
+
 const sweepInterval = 30 * time.Second
 
 // retentionTTL is how long idle/exited data is kept before being swept.
@@ -154,18 +320,174 @@ func NewPool(cfg Config, runner CommandRunner, starter ProcessStarter, log *slog
 		starter = ExecProcessStarter
 	}
 
+	clock := realClock{}
 	return &Pool{
-		mode:     PoolActive,
-		agents:   make(map[string]*Agent),
-		retries:  make(map[string]int),
-		names:    protocol.NewNameGenerator(),
-		config:   cfg,
-		runner:   runner,
-		starter:  starter,
-		sstore:   nil,
-		work:     NewProgWorkSource(runner),
-		log:      log,
-		pidAlive: defaultPIDAlive,
+		startedAt:    clock.Now(),
+		clock:        clock,
+		mode:         PoolActive,
+		paused:       make(map[Role]bool),
+		agents:       make(map[string]*Agent),
+		retries:      make(map[string]int),
+		startup:      make(map[string]int),
+		retired:      make(map[string]bool),
+		killed:       make(map[string]bool),
+		seen:         make(map[string]time.Time),
+		taskEnv:      make(map[string][]string),
+		probed:       make(map[string]string),
+		prios:        make(map[string]int),
+		logLevels:    make(map[string]slog.Level),
+		history:      make(map[string]*TaskHistoryEntry),
+		wake:         make(chan struct{}, 1),
+		changed:      make(chan struct{}),
+		stopping:     make(map[string]chan struct{}),
+		claiming:     make(map[string]bool),
+		orphans:      make(map[string]bool),
+		spawnClaimed: make(map[string]time.Time),
+		names:        protocol.NewNameGenerator(),
+		config:       cfg,
+		runner:       runner,
+		starter:      starter,
+		sstore:       nil,
+		work:         NewProgWorkSource(runner),
+		log:          log,
+		pidAlive:     defaultPIDAlive,
+		stopProcess:  defaultStopProcess,
+		inferRole:    InferRole,
+		errs:         &errorFeed{},
+	}
+}
+
+// restoreRetries loads crash and startup failure counts persisted by a
+// previous daemon run, so MaxRetries and MaxStartupRetries hold across
+// restarts, and keeps rs for future updates.
+func (p *Pool) restoreRetries(rs *retryStore) error {
+	counts, err := rs.load()
+	if err != nil {
+		return err
+	}
+	p.mu.Lock()
+	p.retryDB = rs
+	for taskID, n := range counts.Retries {
+		p.retries[taskID] = n
+	}
+	for taskID, n := range counts.Startup {
+		p.startup[taskID] = n
+	}
+	p.mu.Unlock()
+	if len(counts.Retries) > 0 || len(counts.Startup) > 0 {
+		p.log.Info("restored crash retry counts", "tasks", len(counts.Retries), "startup_failures", len(counts.Startup))
+	}
+	return nil
+}
+
+// persistRetries writes the current crash and startup failure counts to
+// the retry store.
+// Failures are logged; the in-memory counts stay authoritative.
+func (p *Pool) persistRetries() {
+	p.mu.RLock()
+	rs := p.retryDB
+	p.mu.RUnlock()
+	if rs == nil {
+		return
+	}
+	err := rs.save(func() retryCounts {
+		p.mu.RLock()
+		defer p.mu.RUnlock()
+		return retryCounts{Retries: maps.Clone(p.retries), Startup: maps.Clone(p.startup)}
+	})
+	if err != nil {
+		p.log.Warn("failed to persist retry counts", "error", err)
+	}
+}
+
+// Size returns the number of agent slots.
+func (p *Pool) Size() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.config.PoolSize
+}
+
+// spawnCmd returns the command used to launch agents.
+func (p *Pool) spawnCmd() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.config.SpawnCmd
+}
+
+// maxPromptBytes returns the largest rendered prompt agents may launch with.
+func (p *Pool) maxPromptBytes() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.config.MaxPromptBytes
+}
+
+// taskEnvFields returns the prog task fields exported to agents.
+func (p *Pool) taskEnvFields() []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.config.TaskEnvFields
+}
+
+// Reconfigure applies the hot-reloadable settings from cfg: pool size,
+// spawn command, the crash retry policy (fatal exit codes included),
+// preemption, the prompt size cap, and exported task env fields. Running
+// agents are untouched; a smaller
+// pool size takes effect as agents finish, and a new spawn command applies
+// to the next spawn or respawn.
+func (p *Pool) Reconfigure(cfg Config) {
+	p.mu.Lock()
+	old := p.config
+	p.config.PoolSize = cfg.PoolSize
+	p.config.SpawnCmd = cfg.SpawnCmd
+	p.config.MaxRetries = cfg.MaxRetries
+	p.config.MinHealthyUptime = cfg.MinHealthyUptime
+	p.config.MaxStartupRetries = cfg.MaxStartupRetries
+	p.config.StartupProbe = cfg.StartupProbe
+	p.config.StartupProbeSession = cfg.StartupProbeSession
+	p.config.FairRespawn = cfg.FairRespawn
+	p.config.Preempt = cfg.Preempt
+	p.config.PreemptPriority = cfg.PreemptPriority
+	p.config.FatalExitCodes = cfg.FatalExitCodes
+	p.config.MaxPromptBytes = cfg.MaxPromptBytes
+	p.config.TaskEnvFields = cfg.TaskEnvFields
+	p.config.SpawnClaimTTL = cfg.SpawnClaimTTL
+	p.mu.Unlock()
+
+	if old.PoolSize != cfg.PoolSize {
+		p.log.Info("pool size changed", "from", old.PoolSize, "to", cfg.PoolSize)
+	}
+	if old.SpawnCmd != cfg.SpawnCmd {
+		p.log.Info("spawn command changed")
+	}
+	if old.MaxRetries != cfg.MaxRetries {
+		p.log.Info("max retries changed", "from", old.MaxRetries, "to", cfg.MaxRetries)
+	}
+	if old.MinHealthyUptime != cfg.MinHealthyUptime || old.MaxStartupRetries != cfg.MaxStartupRetries ||
+		old.StartupProbe != cfg.StartupProbe || old.StartupProbeSession != cfg.StartupProbeSession {
+		p.log.Info("startup failure policy changed",
+			"min_healthy_uptime", cfg.MinHealthyUptime,
+			"max_startup_retries", cfg.MaxStartupRetries,
+			"startup_probe", cfg.StartupProbe,
+			"startup_probe_session", cfg.StartupProbeSession,
+		)
+	}
+	if old.FairRespawn != cfg.FairRespawn {
+		p.log.Info("fair respawn changed", "from", old.FairRespawn, "to", cfg.FairRespawn)
+	}
+	if old.Preempt != cfg.Preempt || old.PreemptPriority != cfg.PreemptPriority {
+		p.log.Info("preemption changed", "preempt", cfg.Preempt, "preempt_priority", cfg.PreemptPriority)
+	}
+	if !slices.Equal(old.FatalExitCodes, cfg.FatalExitCodes) {
+		p.log.Info("fatal exit codes changed", "fatal_exit_codes", cfg.FatalExitCodes)
+	}
+	if old.MaxPromptBytes != cfg.MaxPromptBytes {
+		p.log.Info("max prompt bytes changed", "from", old.MaxPromptBytes, "to", cfg.MaxPromptBytes)
+	}
+	if !slices.Equal(old.TaskEnvFields, cfg.TaskEnvFields) {
+		p.log.Info("task env fields changed", "task_env_fields", cfg.TaskEnvFields)
+	}
+	if old.SpawnClaimTTL != cfg.SpawnClaimTTL {
+		p.log.Info("spawn claim ttl changed", "from", old.SpawnClaimTTL, "to", cfg.SpawnClaimTTL)
 	}
 }
 
@@ -177,6 +499,18 @@ func (p *Pool) SetContext(ctx context.Context) {
 	p.ctx = ctx
 }
 
+// SetWorkSource replaces the source the pool claims and inspects tasks
+// through, which defaults to prog. Call before the pool schedules anything.
+func (p *Pool) SetWorkSource(w WorkSource) {
+	p.work = w
+}
+
+// Schedule assigns tasks to free slots once, as Run does for each batch it
+// receives, for callers that drive the pool without a poller.
+func (p *Pool) Schedule(ctx context.Context, tasks []Task) {
+	p.schedule(ctx, tasks)
+}
+
 // Run consumes tasks from the channel and schedules them onto free slots.
 // It blocks until the channel is closed (context cancelled).
 // Caller must call SetContext before Run if Reclaim will run concurrently.
@@ -186,9 +520,9 @@ func (p *Pool) Run(ctx context.Context, taskCh <-chan []Task) {
 	if p.ctx == nil {
 		p.ctx = ctx
 	}
-	p.log.Info("pool started", "pool_size", p.config.PoolSize)
+	p.log.Info("pool started", "pool_size", p.Size())
 
-	sweepTicker := time.NewTicker(sweepInterval)
+	sweepTicker := p.clock.NewTicker(sweepInterval)
 	defer sweepTicker.Stop()
 
 	for {
@@ -202,102 +536,397 @@ func (p *Pool) Run(ctx context.Context, taskCh <-chan []Task) {
 				return
 			}
 			p.schedule(ctx, tasks)
-		case <-sweepTicker.C:
+		case <-p.wake:
+			p.assign(ctx, nil, true)
+		case <-sweepTicker.C():
 			p.sweepDead()
+			p.stopAgentsForGoneTasks(ctx)
 		}
 	}
 }
 
-// schedule assigns ready tasks to free slots.
-// Skips all scheduling when the pool is draining or paused.
+// schedule records a polled queue snapshot and assigns its tasks to free
+// slots, then prefetches metadata for the tasks still waiting.
 func (p *Pool) schedule(ctx context.Context, tasks []Task) {
+	p.assign(ctx, p.observeQueue(tasks), false)
+	p.startPrefetch(ctx, tasks)
+}
+
+// assign spawns agents for enqueued tasks, then for tasks, until the pool
+// is full. Skips all scheduling when the pool is draining or paused.
+// Each task left unscheduled gets a skip reason; see SkipReasons. With
+// enqueuedOnly (a wake from Enqueue, tasks nil) the pass covers only the
+// enqueued IDs, so their reasons are merged into those from the last poll
+// instead of replacing them.
+func (p *Pool) assign(ctx context.Context, tasks []Task, enqueuedOnly bool) {
+	tasks = p.prioritize(tasks)
+	skips := make(map[string]string)
+	if enqueuedOnly {
+		defer p.mergeSkipReasons(tasks, skips)
+	} else {
+		defer p.setSkipReasons(skips)
+	}
+
 	p.mu.RLock()
 	mode := p.mode
 	p.mu.RUnlock()
 
 	if mode != PoolActive {
 		p.log.Debug("schedule skipped, pool not active", "mode", mode, "task_count", len(tasks))
+		for _, task := range tasks {
+			skips[task.ID] = "pool " + string(mode)
+		}
+		if mode == PoolDraining {
+			// Yielded and preempted tasks are already claimed, so like
+			// crash respawns they still run while draining.
+			p.resumePreempted()
+			p.resumeYielded()
+		}
 		return
 	}
+	defer p.resumeYielded()
+	defer p.resumePreempted()
+	p.resumePreempted()
 
-	for _, task := range tasks {
+	for i, task := range tasks {
 		if ctx.Err() != nil {
 			return
 		}
 
 		p.mu.RLock()
 		_, alreadyRunning := p.agents[task.ID]
+		alreadyRunning = alreadyRunning || p.isYielded(task.ID)
+		retired := p.retired[task.ID]
 		count := p.runningCount()
+		size := p.config.PoolSize
 		p.mu.RUnlock()
 
 		if alreadyRunning {
+			skips[task.ID] = "already running"
+			p.dequeue(task.ID)
+			continue
+		}
+
+		if retired {
+			p.log.Debug("task retired, skipping", "task_id", task.ID)
+			skips[task.ID] = "retired"
+			p.dequeue(task.ID)
 			continue
 		}
 
-		if count >= p.config.PoolSize {
+		if count >= size {
 			p.log.Debug("pool full, skipping remaining tasks",
 				"running", count,
-				"pool_size", p.config.PoolSize,
+				"pool_size", size,
 			)
+			for _, rest := range tasks[i:] {
+				skips[rest.ID] = fmt.Sprintf("pool full (%d/%d)", count, size)
+			}
+			p.preemptFor(ctx, tasks[i:], skips)
 			return
 		}
 
-		p.spawn(ctx, task)
+		if !p.dependenciesDone(ctx, task.ID) {
+			skips[task.ID] = "blocked on dependencies"
+			continue
+		}
+
+		p.dequeue(task.ID)
+		if reason := p.spawn(ctx, task); reason != "" {
+			skips[task.ID] = reason
+		}
 	}
 }
 
+// Enqueue adds task IDs to the front of the scheduling order and wakes the
+// scheduler. IDs already enqueued are skipped. It reports how many were added.
+func (p *Pool) Enqueue(taskIDs []string) int {
+	p.mu.Lock()
+	added := 0
+	for _, id := range taskIDs {
+		if slices.Contains(p.priority, id) {
+			continue
+		}
+		p.priority = append(p.priority, id)
+		added++
+	}
+	p.mu.Unlock()
+
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+	return added
+}
+
+// prioritize returns tasks with any enqueued IDs moved to the front, in
+// enqueue order. Enqueued IDs missing from tasks are added.
+func (p *Pool) prioritize(tasks []Task) []Task {
+	p.mu.RLock()
+	ids := slices.Clone(p.priority)
+	p.mu.RUnlock()
+	if len(ids) == 0 {
+		return tasks
+	}
+
+	byID := make(map[string]Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+	out := make([]Task, 0, len(ids)+len(tasks))
+	for _, id := range ids {
+		t, ok := byID[id]
+		if !ok {
+			t = Task{ID: id}
+		}
+		out = append(out, t)
+		delete(byID, id)
+	}
+	for _, t := range tasks {
+		if _, ok := byID[t.ID]; ok {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// dequeue drops taskID from the enqueued IDs, if present.
+func (p *Pool) dequeue(taskID string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.priority = slices.DeleteFunc(p.priority, func(id string) bool { return id == taskID })
+}
+
+// observeQueue records when each task in a ready-queue snapshot was first
+// seen and returns a copy with ReadySince filled in. Tasks missing from the
+// snapshot are forgotten, so a task that leaves and re-enters the queue
+// starts aging again.
+func (p *Pool) observeQueue(tasks []Task) []Task {
+	now := p.clock.Now()
+	out := make([]Task, len(tasks))
+	current := make(map[string]bool, len(tasks))
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	changed := false
+	for i, t := range tasks {
+		since, ok := p.seen[t.ID]
+		if !ok {
+			since = now
+			p.seen[t.ID] = since
+			changed = true
+		}
+		t.ReadySince = since
+		out[i] = t
+		current[t.ID] = true
+	}
+	for id := range p.seen {
+		if !current[id] {
+			delete(p.seen, id)
+			changed = true
+		}
+	}
+	if changed {
+		p.notifyChange()
+	}
+	return out
+}
+
+// withReadySince returns a copy of tasks with ReadySince filled in from the
+// queue the pool schedules from. Unlike observeQueue it records nothing, so
+// a status snapshot can't reset the ages the poll loop tracks.
+func (p *Pool) withReadySince(tasks []Task) []Task {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	out := make([]Task, len(tasks))
+	for i, t := range tasks {
+		t.ReadySince = p.seen[t.ID]
+		out[i] = t
+	}
+	return out
+}
+
+// setSkipReasons replaces the skip reasons with those from the latest
+// schedule pass, so they only ever cover the current queue.
+func (p *Pool) setSkipReasons(skips map[string]string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if maps.Equal(p.skips, skips) {
+		return
+	}
+	p.skips = skips
+	p.notifyChange()
+}
+
+// mergeSkipReasons updates the skip reasons of the tasks a partial schedule
+// pass considered, leaving every other task's reason as it was. A
+// considered task with no reason in skips was started, so its entry goes.
+func (p *Pool) mergeSkipReasons(considered []Task, skips map[string]string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	merged := maps.Clone(p.skips)
+	if merged == nil {
+		merged = make(map[string]string)
+	}
+	for _, task := range considered {
+		if reason, ok := skips[task.ID]; ok {
+			merged[task.ID] = reason
+		} else {
+			delete(merged, task.ID)
+		}
+	}
+	if maps.Equal(p.skips, merged) {
+		return
+	}
+	p.skips = merged
+	p.notifyChange()
+}
+
+// SkipReasons returns why each task in the latest schedule pass was left
+// unscheduled (pool full, paused, blocked on dependencies, ...), keyed by
+// task ID. Tasks that were started have no entry.
+func (p *Pool) SkipReasons() map[string]string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return maps.Clone(p.skips)
+}
+
+// dependenciesDone reports whether every dependency of the task is complete.
+// Blocked tasks are left in the queue and reconsidered on the next poll.
+// A lookup failure also leaves the task queued rather than risk starting
+// work out of order.
+func (p *Pool) dependenciesDone(ctx context.Context, taskID string) bool {
+	if p.prefetchedNoDeps(taskID) {
+		return true
+	}
+	deps, err := p.work.Dependencies(ctx, taskID, p.config.Project)
+	if err != nil {
+		p.log.Warn("failed to check task dependencies, leaving queued",
+			"task_id", taskID,
+			"error", err,
+		)
+		return false
+	}
+	for _, dep := range deps {
+		if !dep.Done {
+			p.log.Debug("task blocked on dependency, leaving queued",
+				"task_id", taskID,
+				"dependency", dep.ID,
+			)
+			return false
+		}
+	}
+	return true
+}
+
 // spawn claims a task in prog and launches an agent process.
 //
 // The sequence is: prep (fetch metadata, render prompt, open log) → claim → spawn.
 // All fallible prep happens before claiming so a failure doesn't orphan
 // the task in "in_progress" state with no agent.
-func (p *Pool) spawn(ctx context.Context, task Task) {
+//
+// It returns why the task was not started, or "" once an agent is running.
+func (p *Pool) spawn(ctx context.Context, task Task) string {
+	log := p.taskLog(task.ID)
+
 	// Prep: fetch metadata and infer role before claiming.
-	meta, err := p.work.GetMeta(ctx, task.ID, p.config.Project)
+	meta, err := p.taskMeta(ctx, task.ID)
 	if err != nil {
-		p.log.Error("failed to fetch task metadata",
+		log.Error("failed to fetch task metadata",
 			"task_id", task.ID,
 			"error", err,
 		)
-		return
+		p.recordError("error", OpErrorSpawnFailed, task.ID, "", fmt.Errorf("fetching task metadata: %w", err))
+		return "metadata fetch failed"
+	}
+	role := p.inferRole(meta)
+	env := TaskEnv(meta, p.taskEnvFields())
+	log.Debug("task metadata fetched",
+		"task_id", task.ID,
+		"type", meta.Type,
+		"labels", meta.Labels,
+		"role", role,
+	)
+
+	// A paused role is checked here rather than in assign because the role
+	// is only known once metadata is fetched. The task stays unclaimed and
+	// is picked up again by a later poll once the role resumes.
+	if p.RolePaused(role) {
+		log.Debug("task skipped, role is paused",
+			"task_id", task.ID,
+			"role", role,
+		)
+		return fmt.Sprintf("role %s paused", role)
 	}
-	role := InferRole(meta)
 
 	// Prep: render the role prompt with the task ID baked in.
 	prompt, err := RenderPrompt(p.config.PromptDir, role, task.ID, p.config.Solo)
+	if err == nil {
+		err = CheckPromptSize(prompt, p.maxPromptBytes())
+	}
 	if err != nil {
-		p.log.Error("failed to render prompt",
+		log.Error("failed to render prompt",
 			"task_id", task.ID,
 			"role", role,
 			"error", err,
 		)
-		return
+		p.recordError("error", OpErrorSpawnFailed, task.ID, "", fmt.Errorf("rendering %s prompt: %w", role, err))
+		return "prompt render failed"
+	}
+
+	// Refuse a spawn command outside allowed_spawn_cmds before claiming,
+	// so the task stays ready instead of orphaned in_progress.
+	taskVars := SpawnCmdVars{TaskID: task.ID, Role: role}
+	if err := p.config.CheckSpawnCmd(ExpandSpawnCmd(EnsureAttachSpawnCmd(p.spawnCmd(), p.config.ServerURL), taskVars)); err != nil {
+		log.Error("refusing to spawn agent",
+			"task_id", task.ID,
+			"error", err,
+		)
+		p.recordError("error", OpErrorSpawnFailed, task.ID, "", err)
+		return "spawn command not allowed"
 	}
 
 	// Claim the task in prog. This is the point of no return — after this,
 	// the task is in_progress and we must either spawn an agent or leave it
-	// for manual recovery.
+	// for the orphan scan (RecoverOrphans) to pick up.
+	p.mu.Lock()
+	p.claiming[task.ID] = true
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		delete(p.claiming, task.ID)
+		p.mu.Unlock()
+	}()
 	err = p.work.Claim(ctx, task.ID, p.config.Project)
 	if err != nil {
-		p.log.Error("failed to claim task",
+		log.Error("failed to claim task",
 			"task_id", task.ID,
 			"error", err,
 		)
-		return
+		p.recordError("error", OpErrorSpawnFailed, task.ID, "", fmt.Errorf("claiming task: %w", err))
+		return "claim failed"
 	}
 
 	agentID := p.names.Generate()
 
-	launchCmd := EnsureAttachSpawnCmd(p.config.SpawnCmd, p.config.ServerURL)
-	proc, err := p.starter(ctx, launchCmd, prompt, string(agentID), io.Discard)
+	launchCmd := ExpandSpawnCmd(EnsureAttachSpawnCmd(p.spawnCmd(), p.config.ServerURL), SpawnCmdVars{
+		AgentID: string(agentID),
+		TaskID:  task.ID,
+		Role:    role,
+	})
+	stdout := newStdoutTee()
+	agentCtx, cancel := context.WithCancel(ctx)
+	proc, err := p.starter(agentCtx, launchCmd, prompt, string(agentID), stdout, StartOptions{Env: env, Limits: p.agentLimits(task.ID, agentID, role)})
 	if err != nil {
-		p.log.Error("failed to spawn agent",
+		cancel()
+		log.Error("failed to spawn agent",
 			"task_id", task.ID,
 			"agent_id", agentID,
 			"error", err,
 		)
+		p.recordError("error", OpErrorSpawnFailed, task.ID, string(agentID), fmt.Errorf("starting agent: %w", err))
 		p.names.Release(agentID)
-		return
+		return "agent start failed"
 	}
 
 	agent := &Agent{
@@ -305,15 +934,27 @@ func (p *Pool) spawn(ctx context.Context, task Task) {
 		TaskID:    task.ID,
 		Role:      role,
 		PID:       proc.PID(),
-		SpawnTime: time.Now(),
+		SpawnTime: p.clock.Now(),
 		State:     AgentRunning,
+		Prompt:    prompt,
+		stdout:    stdout,
+		cancel:    cancel,
 	}
 
 	p.mu.Lock()
 	p.agents[task.ID] = agent
+	if len(env) > 0 {
+		p.taskEnv[task.ID] = env
+	}
+	if !task.ReadySince.IsZero() {
+		// Only polled tasks carry their prog priority.
+		p.prios[task.ID] = task.Priority
+	}
+	p.recordLaunch(task.ID, role)
+	p.notifyChange()
 	p.mu.Unlock()
 
-	p.log.Info("agent spawned",
+	log.Info("agent spawned",
 		"agent_id", agentID,
 		"task_id", task.ID,
 		"role", role,
@@ -325,22 +966,34 @@ func (p *Pool) spawn(ctx context.Context, task Task) {
 
 	// Wait for process exit in background.
 	go p.reap(agent, proc)
+	p.probeSession(agent)
+	return ""
 }
 
 // reap waits for a process to exit, frees the slot, and respawns on crash.
 func (p *Pool) reap(agent *Agent, proc Process) {
 	err := proc.Wait()
+	if agent.cancel != nil {
+		agent.cancel()
+	}
+	if agent.stdout != nil {
+		agent.stdout.close()
+	}
+	log := p.taskLog(agent.TaskID)
 
 	exitCode := 0
 	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
+		var exitErr interface{ ExitCode() int }
+		if errors.As(err, &exitErr) {
 			exitCode = exitErr.ExitCode()
 		} else {
 			exitCode = -1
 		}
 	}
+	fatal := err != nil && p.isFatalExit(exitCode)
 
-	duration := time.Since(agent.SpawnTime).Round(time.Second)
+	uptime := p.clock.Now().Sub(agent.SpawnTime)
+	duration := uptime.Round(time.Second)
 
 	var targetStatus sessions.Status
 	var sessionID string
@@ -352,24 +1005,137 @@ func (p *Pool) reap(agent *Agent, proc Process) {
 	sessionID = agent.SessionID
 	delete(p.agents, agent.TaskID)
 	p.names.Release(agent.ID)
-
-	if err == nil {
+	p.notifyChange()
+
+	retriesChanged := false
+	startupBefore := p.startup[agent.TaskID]
+	startupFailure := false
+	killed := p.killed[agent.TaskID]
+	delete(p.killed, agent.TaskID)
+	stopped, intentional := p.stopping[agent.TaskID]
+	probeReason := p.failedProbe(agent.TaskID, exitCode, uptime)
+	probeFailed := probeReason != "" && !killed && !intentional && !fatal
+	// A clean exit inside the startup probe window still fails the agent.
+	failed := err != nil || probeFailed
+	if failed && !killed && !intentional {
+		p.crashes++
+	}
+	if intentional {
+		// Stopped on purpose (rolling restart, preemption) — the caller owns the respawn.
+		delete(p.stopping, agent.TaskID)
+	} else if killed {
+		// Killed on request — not a crash, so no retry is counted.
+		targetStatus = sessions.StatusTerminated
+	} else if probeFailed {
+		// Failed the startup probe — a startup failure whatever the exit
+		// code, so it gets the startup budget.
+		startupFailure = true
+		p.startup[agent.TaskID]++
+		targetStatus = sessions.StatusTerminated
+	} else if err == nil {
 		// Clean exit — clear retry count.
+		_, retriesChanged = p.retries[agent.TaskID]
 		delete(p.retries, agent.TaskID)
+		delete(p.startup, agent.TaskID)
+		p.completions.record(p.clock.Now())
 		targetStatus = sessions.StatusIdle
+	} else if fatal {
+		// Deterministic failure — retrying won't help, so don't burn retries.
+		targetStatus = sessions.StatusTerminated
+	} else if uptime < p.config.MinHealthyUptime {
+		// Startup failure — the agent died before doing any real work, most
+		// likely from bad config. It gets its own, smaller budget.
+		startupFailure = true
+		p.startup[agent.TaskID]++
+		targetStatus = sessions.StatusTerminated
 	} else {
-		// Crash — bump retry counter.
+		// Crash — bump retry counter. The agent was healthy, so earlier
+		// startup failures no longer count against it.
 		p.retries[agent.TaskID]++
+		retriesChanged = true
+		delete(p.startup, agent.TaskID)
 		targetStatus = sessions.StatusTerminated
 	}
 	attempts := p.retries[agent.TaskID]
+	maxRetries := p.config.MaxRetries
+	if startupFailure {
+		attempts = p.startup[agent.TaskID]
+		maxRetries = p.config.MaxStartupRetries
+	}
+	crashRespawn := p.config.MaxRetries != NoCrashRespawn
+	retired := p.retired[agent.TaskID]
+	// The override covers the task until its agent is gone for good, so
+	// keep it across crash respawns and rolling restarts.
+	respawning := intentional || (failed && !fatal && !retired && !killed && crashRespawn && attempts <= maxRetries)
+	// With FairRespawn, a crashed task that would respawn straight into its
+	// old slot waits behind queued work instead.
+	yield := respawning && !intentional && p.config.FairRespawn && p.queueWaiting(agent.TaskID)
+	if yield {
+		p.yielded = append(p.yielded, yieldedTask{taskID: agent.TaskID, role: agent.Role, sessionID: sessionID})
+	}
+	if !respawning {
+		delete(p.logLevels, agent.TaskID)
+		delete(p.taskEnv, agent.TaskID)
+		delete(p.prios, agent.TaskID)
+		delete(p.startup, agent.TaskID)
+		switch {
+		case killed:
+			p.recordOutcome(agent.TaskID, TaskOutcomeKilled)
+		case !failed:
+			p.recordOutcome(agent.TaskID, TaskOutcomeCompleted)
+		case fatal:
+			p.recordOutcome(agent.TaskID, TaskOutcomeFailed)
+		case retired:
+			p.recordOutcome(agent.TaskID, TaskOutcomeRetired)
+		default:
+			p.recordOutcome(agent.TaskID, TaskOutcomeCrashed)
+		}
+	}
+	if p.startup[agent.TaskID] != startupBefore {
+		retriesChanged = true
+	}
 	p.mu.Unlock()
 
+	log.Debug("agent process exited",
+		"agent_id", agent.ID,
+		"task_id", agent.TaskID,
+		"pid", agent.PID,
+		"exit_code", exitCode,
+		"error", err,
+		"duration", duration,
+	)
+
+	if retriesChanged {
+		p.persistRetries()
+	}
+
+	if intentional {
+		log.Info("agent stopped on purpose",
+			"agent_id", agent.ID,
+			"task_id", agent.TaskID,
+			"pid", agent.PID,
+			"duration", duration,
+		)
+		close(stopped)
+		return
+	}
+
 	p.updateSessionStatus(sessionID, sessions.OriginPool, agent.TaskID, targetStatus)
 
+	if killed {
+		log.Info("killed agent exited, not respawning",
+			"agent_id", agent.ID,
+			"task_id", agent.TaskID,
+			"pid", agent.PID,
+			"exit_code", exitCode,
+			"duration", duration,
+		)
+		return
+	}
+
 	// Clean exit — agent finished normally.
-	if err == nil {
-		p.log.Info("agent exited cleanly",
+	if !failed {
+		log.Info("agent exited cleanly",
 			"agent_id", agent.ID,
 			"task_id", agent.TaskID,
 			"pid", agent.PID,
@@ -380,26 +1146,107 @@ func (p *Pool) reap(agent *Agent, proc Process) {
 
 	// Crash — decide whether to respawn.
 
-	if attempts > p.config.MaxRetries {
-		p.log.Error("agent crashed, max retries exhausted",
+	if fatal {
+		log.Error("agent failed with fatal exit code, not respawning",
+			"agent_id", agent.ID,
+			"task_id", agent.TaskID,
+			"pid", agent.PID,
+			"exit_code", exitCode,
+			"duration", duration,
+		)
+		p.recordError("error", OpErrorFatalExit, agent.TaskID, string(agent.ID), fmt.Errorf("agent exited with fatal code %d after %v, not respawning", exitCode, duration))
+		return
+	}
+
+	if retired {
+		log.Warn("retired agent crashed, not respawning",
+			"agent_id", agent.ID,
+			"task_id", agent.TaskID,
+			"pid", agent.PID,
+			"exit_code", exitCode,
+			"duration", duration,
+		)
+		return
+	}
+
+	if !crashRespawn {
+		log.Error("agent crashed, crash respawn disabled",
+			"agent_id", agent.ID,
+			"task_id", agent.TaskID,
+			"pid", agent.PID,
+			"exit_code", exitCode,
+			"startup_failure", startupFailure,
+			"duration", duration,
+		)
+		p.recordError("error", OpErrorCrashMaxRetries, agent.TaskID, string(agent.ID), fmt.Errorf("agent crashed with exit code %d after %v, not respawning (max_retries: -1)", exitCode, duration))
+		return
+	}
+
+	if probeFailed && attempts > maxRetries {
+		log.Error("agent failed its startup probe, max startup retries exhausted",
+			"agent_id", agent.ID,
+			"task_id", agent.TaskID,
+			"pid", agent.PID,
+			"exit_code", exitCode,
+			"attempts", attempts,
+			"max_startup_retries", maxRetries,
+			"reason", probeReason,
+		)
+		p.recordError("error", OpErrorStartupFailed, agent.TaskID, string(agent.ID), fmt.Errorf("agent failed its startup probe (%s); max startup retries (%d) exhausted", probeReason, maxRetries))
+		return
+	}
+
+	if startupFailure && attempts > maxRetries {
+		log.Error("agent failed at startup, max startup retries exhausted",
+			"agent_id", agent.ID,
+			"task_id", agent.TaskID,
+			"pid", agent.PID,
+			"exit_code", exitCode,
+			"attempts", attempts,
+			"max_startup_retries", maxRetries,
+			"uptime", uptime,
+			"min_healthy_uptime", p.config.MinHealthyUptime,
+		)
+		p.recordError("error", OpErrorStartupFailed, agent.TaskID, string(agent.ID), fmt.Errorf("agent exited with code %d after %v, below min healthy uptime %v; max startup retries (%d) exhausted", exitCode, uptime, p.config.MinHealthyUptime, maxRetries))
+		return
+	}
+
+	if attempts > maxRetries {
+		log.Error("agent crashed, max retries exhausted",
 			"agent_id", agent.ID,
 			"task_id", agent.TaskID,
 			"pid", agent.PID,
 			"exit_code", exitCode,
 			"attempts", attempts,
-			"max_retries", p.config.MaxRetries,
+			"max_retries", maxRetries,
+			"duration", duration,
+		)
+		p.recordError("error", OpErrorCrashMaxRetries, agent.TaskID, string(agent.ID), fmt.Errorf("agent crashed with exit code %d, max retries (%d) exhausted", exitCode, maxRetries))
+		return
+	}
+
+	if yield {
+		log.Warn("agent crashed, yielding slot to queued tasks",
+			"agent_id", agent.ID,
+			"task_id", agent.TaskID,
+			"pid", agent.PID,
+			"exit_code", exitCode,
+			"attempt", attempts,
+			"max_retries", maxRetries,
 			"duration", duration,
 		)
 		return
 	}
 
-	p.log.Warn("agent crashed, respawning",
+	log.Warn("agent crashed, respawning",
 		"agent_id", agent.ID,
 		"task_id", agent.TaskID,
 		"pid", agent.PID,
 		"exit_code", exitCode,
 		"attempt", attempts,
-		"max_retries", p.config.MaxRetries,
+		"max_retries", maxRetries,
+		"startup_failure", startupFailure,
+		"startup_probe", probeReason,
 		"duration", duration,
 	)
 
@@ -410,10 +1257,18 @@ func (p *Pool) reap(agent *Agent, proc Process) {
 	p.respawn(agent.TaskID, agent.Role, sessionID)
 }
 
+// isFatalExit reports whether exitCode is configured as a deterministic
+// failure that should not be retried.
+func (p *Pool) isFatalExit(exitCode int) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return slices.Contains(p.config.FatalExitCodes, exitCode)
+}
+
 // respawn launches a new agent for a task that's already in_progress.
-// Respawns are blocked when the pool is paused. In draining mode,
-// respawns are allowed because the task is already claimed in prog
-// and leaving it without an agent would orphan it.
+// Respawns are blocked when the pool or the task's role is paused. In
+// draining mode, respawns are allowed because the task is already claimed
+// in prog and leaving it without an agent would orphan it.
 //
 // If sessionID is non-empty, the respawned agent resumes the existing
 // opencode session instead of creating a new one. This preserves the
@@ -422,43 +1277,93 @@ func (p *Pool) respawn(taskID string, role Role, sessionID string) {
 	if p.ctx.Err() != nil {
 		return
 	}
+	log := p.taskLog(taskID)
 
 	p.mu.RLock()
 	mode := p.mode
+	rolePaused := p.paused[role]
+	retired := p.retired[taskID]
 	p.mu.RUnlock()
 
 	if mode == PoolPaused {
-		p.log.Info("respawn skipped, pool is paused",
+		log.Info("respawn skipped, pool is paused",
 			"task_id", taskID,
 			"role", role,
 		)
+		p.abandonRespawn(taskID, TaskOutcomeStranded)
+		return
+	}
+	if rolePaused {
+		log.Info("respawn skipped, role is paused",
+			"task_id", taskID,
+			"role", role,
+		)
+		p.abandonRespawn(taskID, TaskOutcomeStranded)
+		return
+	}
+	if retired {
+		log.Info("respawn skipped, task is retired",
+			"task_id", taskID,
+			"role", role,
+		)
+		p.abandonRespawn(taskID, TaskOutcomeRetired)
 		return
 	}
 
+	log.Debug("respawning agent",
+		"task_id", taskID,
+		"role", role,
+		"resume_session", sessionID,
+	)
+
 	// Re-render the prompt from disk. This intentionally re-reads the template
 	// so prompt changes take effect on respawn without daemon restart.
 	prompt, err := RenderPrompt(p.config.PromptDir, role, taskID, p.config.Solo)
+	if err == nil {
+		err = CheckPromptSize(prompt, p.maxPromptBytes())
+	}
 	if err != nil {
-		p.log.Error("failed to render prompt for respawn",
+		log.Error("failed to render prompt for respawn",
 			"task_id", taskID,
 			"role", role,
 			"error", err,
 		)
+		p.recordError("error", OpErrorRespawnFailed, taskID, "", fmt.Errorf("rendering %s prompt: %w", role, err))
+		p.abandonRespawn(taskID, TaskOutcomeStranded)
 		return
 	}
 
 	agentID := p.names.Generate()
 
-	launchCmd := EnsureAttachSpawnCmd(p.config.SpawnCmd, p.config.ServerURL)
-	launchCmd = WithSessionFlag(launchCmd, sessionID)
-	proc, err := p.starter(p.ctx, launchCmd, prompt, string(agentID), io.Discard)
+	launchCmd := ExpandSpawnCmd(EnsureAttachSpawnCmd(p.spawnCmd(), p.config.ServerURL), SpawnCmdVars{
+		AgentID: string(agentID),
+		TaskID:  taskID,
+		Role:    role,
+		Session: sessionID,
+	})
+	if err := p.config.CheckSpawnCmd(launchCmd); err != nil {
+		log.Error("refusing to respawn agent",
+			"task_id", taskID,
+			"error", err,
+		)
+		p.recordError("error", OpErrorRespawnFailed, taskID, "", err)
+		p.names.Release(agentID)
+		p.abandonRespawn(taskID, TaskOutcomeStranded)
+		return
+	}
+	stdout := newStdoutTee()
+	agentCtx, cancel := context.WithCancel(p.ctx)
+	proc, err := p.starter(agentCtx, launchCmd, prompt, string(agentID), stdout, StartOptions{Env: p.taskEnvFor(taskID), Limits: p.agentLimits(taskID, agentID, role)})
 	if err != nil {
-		p.log.Error("failed to respawn agent",
+		cancel()
+		log.Error("failed to respawn agent",
 			"task_id", taskID,
 			"agent_id", agentID,
 			"error", err,
 		)
+		p.recordError("error", OpErrorRespawnFailed, taskID, string(agentID), fmt.Errorf("starting agent: %w", err))
 		p.names.Release(agentID)
+		p.abandonRespawn(taskID, TaskOutcomeStranded)
 		return
 	}
 
@@ -468,15 +1373,20 @@ func (p *Pool) respawn(taskID string, role Role, sessionID string) {
 		Role:      role,
 		PID:       proc.PID(),
 		SessionID: sessionID, // carry forward so next crash can resume too
-		SpawnTime: time.Now(),
+		SpawnTime: p.clock.Now(),
 		State:     AgentRunning,
+		Prompt:    prompt,
+		stdout:    stdout,
+		cancel:    cancel,
 	}
 
 	p.mu.Lock()
 	p.agents[taskID] = agent
+	p.recordLaunch(taskID, role)
+	p.notifyChange()
 	p.mu.Unlock()
 
-	p.log.Info("agent respawned",
+	log.Info("agent respawned",
 		"agent_id", agentID,
 		"task_id", taskID,
 		"role", role,
@@ -489,29 +1399,71 @@ func (p *Pool) respawn(taskID string, role Role, sessionID string) {
 	// arrives at the daemon — see session_events.go claimSession.
 
 	go p.reap(agent, proc)
+	p.probeSession(agent)
+}
+
+// abandonRespawn closes the task's history entry when respawn leaves it
+// without an agent, and drops its per-task overrides as reap does for an
+// agent that is gone for good. A stranded task stays in_progress, so the
+// orphan scan can pick it up again.
+func (p *Pool) abandonRespawn(taskID string, outcome TaskOutcome) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.logLevels, taskID)
+	delete(p.taskEnv, taskID)
+	delete(p.prios, taskID)
+	p.recordOutcome(taskID, outcome)
+	p.notifyChange()
 }
 
 func (p *Pool) updateSessionStatus(sessionID string, origin sessions.OriginType, workRef string, status sessions.Status) {
-	if p.sstore == nil {
+	sstore := p.sessionStore()
+	if sstore == nil {
 		return
 	}
 	if sessionID != "" {
-		if changed, err := p.sstore.SetStatusBySession(p.config.ServerURL, sessionID, status); err != nil {
+		if changed, err := sstore.SetStatusBySession(p.config.ServerURL, sessionID, status); err != nil {
 			p.log.Warn("failed to update session status by key", "session_id", sessionID, "status", status, "error", err)
 		} else if changed {
+			p.notifySessionChange()
 			return
 		}
 	}
 	if workRef == "" {
 		return
 	}
-	if changed, err := p.sstore.SetStatusByWorkRef(origin, workRef, status); err != nil {
+	if changed, err := sstore.SetStatusByWorkRef(origin, workRef, status); err != nil {
 		p.log.Warn("failed to update session status", "work_ref", workRef, "status", status, "error", err)
 	} else if !changed {
 		p.log.Debug("session status update skipped (record not found yet)", "work_ref", workRef, "status", status)
+	} else {
+		p.notifySessionChange()
 	}
 }
 
+// notifySessionChange signals a session record update, which status shows
+// but which changes nothing the pool itself tracks.
+func (p *Pool) notifySessionChange() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.notifyChange()
+}
+
+// sessionStore returns the session registry, or nil while it is unavailable.
+func (p *Pool) sessionStore() *sessions.Store {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.sstore
+}
+
+// setSessionStore hands the pool a session registry that opened after the
+// daemon started.
+func (p *Pool) setSessionStore(store *sessions.Store) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sstore = store
+}
+
 // SetSessionID assigns a session ID to the pool agent with the given name.
 // Returns false if the agent is not found.
 func (p *Pool) SetSessionID(agentName, sessionID string) bool {
@@ -520,6 +1472,21 @@ func (p *Pool) SetSessionID(agentName, sessionID string) bool {
 	for _, a := range p.agents {
 		if string(a.ID) == agentName {
 			a.SessionID = sessionID
+			a.SessionError = ""
+			return true
+		}
+	}
+	return false
+}
+
+// SetSessionError records why the pool agent with the given name could not
+// be assigned a session. Returns false if the agent is not found.
+func (p *Pool) SetSessionError(agentName, msg string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, a := range p.agents {
+		if string(a.ID) == agentName {
+			a.SessionError = msg
 			return true
 		}
 	}
@@ -536,10 +1503,11 @@ func (p *Pool) SetSessionID(agentName, sessionID string) bool {
 //
 // Returns empty string if no session is found or the registry is unavailable.
 func (p *Pool) lookupSessionForTask(taskID string) string {
-	if p.sstore == nil {
+	sstore := p.sessionStore()
+	if sstore == nil {
 		return ""
 	}
-	recs, err := p.sstore.List()
+	recs, err := sstore.List()
 	if err != nil {
 		p.log.Warn("session registry read failed during reclaim",
 			"task_id", taskID,
@@ -574,6 +1542,12 @@ func (p *Pool) TaskIDForAgent(agentName string) string {
 	return ""
 }
 
+// Project returns the prog project the pool schedules. It is fixed when
+// the pool is created, so no lock is needed.
+func (p *Pool) Project() string {
+	return p.config.Project
+}
+
 // runningCount returns the number of currently running agents.
 // Caller must hold at least a read lock.
 func (p *Pool) runningCount() int {
@@ -602,11 +1576,12 @@ func (p *Pool) sweepDead() {
 			"agent_id", agent.ID,
 			"task_id", taskID,
 			"pid", agent.PID,
-			"uptime", time.Since(agent.SpawnTime).Round(time.Second),
+			"uptime", p.clock.Now().Sub(agent.SpawnTime).Round(time.Second),
 		)
 
 		delete(p.agents, taskID)
 		p.names.Release(agent.ID)
+		p.notifyChange()
 	}
 }
 
@@ -638,6 +1613,7 @@ func (p *Pool) Drain() {
 	prev := p.mode
 	p.mode = PoolDraining
 	p.log.Info("pool mode changed", "from", prev, "to", PoolDraining)
+	p.notifyChange()
 }
 
 // Pause transitions the pool to paused mode. No new scheduling and
@@ -648,9 +1624,64 @@ func (p *Pool) Pause() {
 	prev := p.mode
 	p.mode = PoolPaused
 	p.log.Info("pool mode changed", "from", prev, "to", PoolPaused)
+	p.notifyChange()
+}
+
+// PauseRole stops scheduling and crash respawns for tasks of one role,
+// independently of the pool mode. Running agents of that role continue.
+func (p *Pool) PauseRole(role Role) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.paused[role] = true
+	p.log.Info("pool role paused", "role", role)
+	p.notifyChange()
+}
+
+// ResumeRole lifts a pause set by PauseRole.
+func (p *Pool) ResumeRole(role Role) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.paused, role)
+	p.log.Info("pool role resumed", "role", role)
+	p.notifyChange()
+}
+
+// RolePaused reports whether role is paused via PauseRole.
+func (p *Pool) RolePaused(role Role) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.paused[role]
+}
+
+// PausedRoles returns the roles paused via PauseRole, sorted.
+func (p *Pool) PausedRoles() []Role {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	roles := make([]Role, 0, len(p.paused))
+	for role := range p.paused {
+		roles = append(roles, role)
+	}
+	slices.Sort(roles)
+	return roles
+}
+
+// Retire lets the agent working taskID finish but never respawns it, and
+// excludes the task from future scheduling for the life of the daemon.
+// It reports the ID of the running agent, or "" when the task has none.
+func (p *Pool) Retire(taskID string) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.retired[taskID] = true
+	agentID := ""
+	if agent, ok := p.agents[taskID]; ok {
+		agentID = string(agent.ID)
+	}
+	p.log.Info("task retired", "task_id", taskID, "agent_id", agentID)
+	return agentID
 }
 
-// Resume transitions the pool back to active mode from any state.
+// Resume transitions the pool back to active mode from any state and
+// lifts every role pause.
 // Note: tasks dropped during drain/pause are not retroactively scheduled;
 // they will be picked up on the next poll cycle.
 func (p *Pool) Resume() {
@@ -658,5 +1689,7 @@ func (p *Pool) Resume() {
 	defer p.mu.Unlock()
 	prev := p.mode
 	p.mode = PoolActive
+	clear(p.paused)
 	p.log.Info("pool mode changed", "from", prev, "to", PoolActive)
+	p.notifyChange()
 }
@@ -0,0 +1,114 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// PoolConfigParams is the request shape for changing pool parameters at
+// runtime. Nil fields are left unchanged. A later config reload (SIGHUP)
+// replaces these values with the config file's.
+type PoolConfigParams struct {
+	ProjectSelector
+	PoolSize          *int           `json:"pool_size,omitempty"`
+	MaxRetries        *int           `json:"max_retries,omitempty"`
+	MaxStartupRetries *int           `json:"max_startup_retries,omitempty"`
+	MinHealthyUptime  *time.Duration `json:"min_healthy_uptime,omitempty"`
+}
+
+// PoolConfigResult reports the pool parameters in effect after a change.
+type PoolConfigResult struct {
+	Project           string        `json:"project,omitempty"`
+	PoolSize          int           `json:"pool_size"`
+	MaxRetries        int           `json:"max_retries"`
+	MaxStartupRetries int           `json:"max_startup_retries"`
+	MinHealthyUptime  time.Duration `json:"min_healthy_uptime"`
+}
+
+// validate rejects values the pool can't run with. Unlike the config
+// file, zero is not a request for the default here.
+func (params PoolConfigParams) validate() error {
+	if params.PoolSize != nil && *params.PoolSize <= 0 {
+		return fmt.Errorf("pool_size must be positive, got %d", *params.PoolSize)
+	}
+	if params.MaxRetries != nil && *params.MaxRetries <= 0 && *params.MaxRetries != NoCrashRespawn {
+		return fmt.Errorf("max_retries must be positive, or -1 to disable crash respawn, got %d", *params.MaxRetries)
+	}
+	if params.MaxStartupRetries != nil && *params.MaxStartupRetries <= 0 && *params.MaxStartupRetries != NoStartupRespawn {
+		return fmt.Errorf("max_startup_retries must be positive, or -1 to never respawn a startup failure, got %d", *params.MaxStartupRetries)
+	}
+	if params.MinHealthyUptime != nil && *params.MinHealthyUptime < 0 {
+		return fmt.Errorf("min_healthy_uptime must be non-negative, got %v", *params.MinHealthyUptime)
+	}
+	return nil
+}
+
+// SetParams validates params and applies the set fields to the pool. They
+// take effect for the next scheduling pass or crash, like a config reload.
+func (p *Pool) SetParams(params PoolConfigParams) (PoolConfigResult, error) {
+	if err := params.validate(); err != nil {
+		return PoolConfigResult{}, err
+	}
+
+	p.mu.Lock()
+	old := p.config
+	if params.PoolSize != nil {
+		p.config.PoolSize = *params.PoolSize
+	}
+	if params.MaxRetries != nil {
+		p.config.MaxRetries = *params.MaxRetries
+	}
+	if params.MaxStartupRetries != nil {
+		p.config.MaxStartupRetries = *params.MaxStartupRetries
+	}
+	if params.MinHealthyUptime != nil {
+		p.config.MinHealthyUptime = *params.MinHealthyUptime
+	}
+	cur := p.config
+	p.mu.Unlock()
+
+	if old.PoolSize != cur.PoolSize {
+		p.log.Info("pool size changed", "from", old.PoolSize, "to", cur.PoolSize)
+	}
+	if old.MaxRetries != cur.MaxRetries {
+		p.log.Info("max retries changed", "from", old.MaxRetries, "to", cur.MaxRetries)
+	}
+	if old.MaxStartupRetries != cur.MaxStartupRetries || old.MinHealthyUptime != cur.MinHealthyUptime {
+		p.log.Info("startup failure policy changed",
+			"min_healthy_uptime", cur.MinHealthyUptime,
+			"max_startup_retries", cur.MaxStartupRetries,
+		)
+	}
+	return p.params(), nil
+}
+
+// params returns the runtime-adjustable pool parameters in effect.
+func (p *Pool) params() PoolConfigResult {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return PoolConfigResult{
+		Project:           p.config.Project,
+		PoolSize:          p.config.PoolSize,
+		MaxRetries:        p.config.MaxRetries,
+		MaxStartupRetries: p.config.MaxStartupRetries,
+		MinHealthyUptime:  p.config.MinHealthyUptime,
+	}
+}
+
+// handlePoolConfig changes pool parameters without a restart.
+func (d *Daemon) handlePoolConfig(params PoolConfigParams) *Response {
+	pool, err := d.poolFor(params.Project)
+	if err != nil {
+		return &Response{Success: false, Error: err.Error()}
+	}
+	applied, err := pool.SetParams(params)
+	if err != nil {
+		return &Response{Success: false, Error: err.Error()}
+	}
+	result, err := json.Marshal(applied)
+	if err != nil {
+		return &Response{Success: false, Error: fmt.Sprintf("marshal pool config: %v", err)}
+	}
+	return &Response{Success: true, Result: result}
+}
@@ -0,0 +1,96 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPoolSetParamsMaxRetriesChangesRespawn(t *testing.T) {
+	// Started with MaxRetries=3, then lowered to 1 at runtime:
+	//   initial spawn → crash → retries=1 (1 <= 1) → respawn
+	//   respawn 1     → crash → retries=2 (2 > 1)  → stop
+	var spawnCount atomic.Int32
+	var mu sync.Mutex
+	releases := make([]func(), 0)
+
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		n := spawnCount.Add(1)
+		proc, release := newFakeProcessWithError(int(n)*100, fmt.Errorf("exit status 1"))
+		mu.Lock()
+		releases = append(releases, release)
+		mu.Unlock()
+		return proc, nil
+	}
+
+	cfg := Config{
+		Project:    "testproject",
+		PoolSize:   2,
+		SpawnCmd:   "fake-agent",
+		MaxRetries: 3,
+	}
+	cfg.ApplyDefaults()
+	pool := NewPool(cfg, progRunner(testTaskMeta), starter, slog.Default())
+
+	one := 1
+	got, err := pool.SetParams(PoolConfigParams{MaxRetries: &one})
+	if err != nil {
+		t.Fatalf("SetParams: %v", err)
+	}
+	if got.MaxRetries != 1 || got.PoolSize != 2 {
+		t.Errorf("SetParams result = %+v, want max retries 1 and pool size unchanged", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	taskCh := make(chan []Task, 1)
+	taskCh <- []Task{{ID: "ts-abc", Priority: 1, Title: "Do it"}}
+	go pool.Run(ctx, taskCh)
+
+	waitFor(t, func() bool { return spawnCount.Load() >= 1 })
+	mu.Lock()
+	releases[0]()
+	mu.Unlock()
+
+	waitFor(t, func() bool { return spawnCount.Load() >= 2 })
+	mu.Lock()
+	releases[1]()
+	mu.Unlock()
+
+	waitFor(t, func() bool { return len(pool.Status()) == 0 })
+	time.Sleep(50 * time.Millisecond) // room for an unexpected respawn
+
+	if got := spawnCount.Load(); got != 2 {
+		t.Errorf("spawn count = %d, want 2 (initial + 1 retry under the new budget)", got)
+	}
+	if status := BuildFullStatus(ctx, pool, nil, nil, nil, cfg, nil); status.MaxRetries != 1 {
+		t.Errorf("status max_retries = %d, want 1", status.MaxRetries)
+	}
+}
+
+func TestPoolSetParamsRejectsInvalidValues(t *testing.T) {
+	pool := testPool(t, progRunner(testTaskMeta), nil)
+	zero, negative := 0, -2
+	uptime := -time.Second
+
+	for _, params := range []PoolConfigParams{
+		{PoolSize: &zero},
+		{MaxRetries: &zero},
+		{MaxRetries: &negative},
+		{MaxStartupRetries: &zero},
+		{MaxStartupRetries: &negative},
+		{MinHealthyUptime: &uptime},
+	} {
+		if _, err := pool.SetParams(params); err == nil {
+			t.Errorf("SetParams(%+v) = nil error, want error", params)
+		}
+	}
+	if got := pool.params(); got.PoolSize != 2 || got.MaxRetries != DefaultMaxRetries {
+		t.Errorf("params after rejected changes = %+v, want unchanged", got)
+	}
+}
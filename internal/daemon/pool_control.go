@@ -3,20 +3,29 @@ package daemon
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 )
 
 // PoolModeResult is the response for pool control handlers.
 type PoolModeResult struct {
-	Mode    PoolMode `json:"mode"`
-	Running int      `json:"running"`
+	Mode        PoolMode `json:"mode"`
+	Running     int      `json:"running"`
+	PausedRoles []Role   `json:"paused_roles,omitempty"`
 }
 
-// poolModeResponse builds a response with the current pool mode and running count.
-// Callers must ensure d.pool is non-nil before calling.
-func (d *Daemon) poolModeResponse() *Response {
+// PoolPauseParams is the request shape for pausing or resuming the pool.
+// An empty Role applies to the whole pool.
+type PoolPauseParams struct {
+	ProjectSelector
+	Role Role `json:"role,omitempty"`
+}
+
+// poolModeResponse builds a response with the pool's current mode and running count.
+func poolModeResponse(pool *Pool) *Response {
 	result, err := json.Marshal(PoolModeResult{
-		Mode:    d.pool.Mode(),
-		Running: len(d.pool.Status()),
+		Mode:        pool.Mode(),
+		Running:     len(pool.Status()),
+		PausedRoles: pool.PausedRoles(),
 	})
 	if err != nil {
 		return &Response{Success: false, Error: fmt.Sprintf("marshal pool mode: %v", err)}
@@ -27,29 +36,221 @@ func (d *Daemon) poolModeResponse() *Response {
 // handlePoolDrain transitions the pool to draining mode.
 // New tasks from the queue are not scheduled, but current agents
 // run to completion and crash respawns are still allowed.
-func (d *Daemon) handlePoolDrain() *Response {
-	if d.pool == nil {
-		return &Response{Success: false, Error: "no pool configured"}
+func (d *Daemon) handlePoolDrain(params ProjectSelector) *Response {
+	pool, err := d.poolFor(params.Project)
+	if err != nil {
+		return &Response{Success: false, Error: err.Error()}
 	}
-	d.pool.Drain()
-	return d.poolModeResponse()
+	pool.Drain()
+	return poolModeResponse(pool)
 }
 
 // handlePoolPause transitions the pool to paused mode.
-// No new scheduling and no crash respawns.
-func (d *Daemon) handlePoolPause() *Response {
-	if d.pool == nil {
-		return &Response{Success: false, Error: "no pool configured"}
+// No new scheduling and no crash respawns. With a role, only tasks of
+// that role are held back and the pool mode is unchanged.
+func (d *Daemon) handlePoolPause(params PoolPauseParams) *Response {
+	pool, err := d.poolFor(params.Project)
+	if err != nil {
+		return &Response{Success: false, Error: err.Error()}
+	}
+	if params.Role == "" {
+		pool.Pause()
+		return poolModeResponse(pool)
+	}
+	if err := validatePoolRole(params.Role); err != nil {
+		return &Response{Success: false, Error: err.Error()}
+	}
+	pool.PauseRole(params.Role)
+	return poolModeResponse(pool)
+}
+
+// handlePoolResume transitions the pool back to active mode, lifting every
+// role pause. With a role, only that role's pause is lifted.
+func (d *Daemon) handlePoolResume(params PoolPauseParams) *Response {
+	pool, err := d.poolFor(params.Project)
+	if err != nil {
+		return &Response{Success: false, Error: err.Error()}
+	}
+	if params.Role == "" {
+		pool.Resume()
+		return poolModeResponse(pool)
+	}
+	if err := validatePoolRole(params.Role); err != nil {
+		return &Response{Success: false, Error: err.Error()}
+	}
+	pool.ResumeRole(params.Role)
+	return poolModeResponse(pool)
+}
+
+// validatePoolRole rejects roles the pool never schedules.
+func validatePoolRole(role Role) error {
+	if role != RolePlanner && role != RoleWorker {
+		return fmt.Errorf("invalid role %q (want %s or %s)", role, RolePlanner, RoleWorker)
+	}
+	return nil
+}
+
+// RollingRestartResult is the response for the rolling restart handler.
+type RollingRestartResult struct {
+	// Tasks lists the task IDs whose agents will be restarted, in order.
+	Tasks []string `json:"tasks"`
+}
+
+// handlePoolRollingRestart restarts all running agents one at a time so they
+// pick up the current spawn command. The restart runs in the background.
+func (d *Daemon) handlePoolRollingRestart(params ProjectSelector) *Response {
+	pool, err := d.poolFor(params.Project)
+	if err != nil {
+		return &Response{Success: false, Error: err.Error()}
+	}
+	tasks, err := pool.RollingRestart()
+	if err != nil {
+		return &Response{Success: false, Error: err.Error()}
+	}
+	d.log.Info("rolling restart requested", "project", pool.Project(), "agents", len(tasks))
+
+	result, err := json.Marshal(RollingRestartResult{Tasks: tasks})
+	if err != nil {
+		return &Response{Success: false, Error: fmt.Sprintf("marshal rolling restart: %v", err)}
+	}
+	return &Response{Success: true, Result: result}
+}
+
+// PoolRetireParams is the request shape for retiring a task's agent.
+type PoolRetireParams struct {
+	ProjectSelector
+	TaskID string `json:"task_id"`
+}
+
+// PoolRetireResult is the response for the retire handler.
+type PoolRetireResult struct {
+	TaskID  string `json:"task_id"`
+	AgentID string `json:"agent_id,omitempty"` // empty when no agent was running
+}
+
+// handlePoolRetire retires a single task: its agent runs to completion but
+// is not respawned, and the task is not scheduled again.
+func (d *Daemon) handlePoolRetire(params PoolRetireParams) *Response {
+	pool, err := d.poolFor(params.Project)
+	if err != nil {
+		return &Response{Success: false, Error: err.Error()}
 	}
-	d.pool.Pause()
-	return d.poolModeResponse()
+	if !validTaskID.MatchString(params.TaskID) {
+		return &Response{Success: false, Error: fmt.Sprintf("invalid task ID %q", params.TaskID)}
+	}
+	agentID := pool.Retire(params.TaskID)
+
+	result, err := json.Marshal(PoolRetireResult{TaskID: params.TaskID, AgentID: agentID})
+	if err != nil {
+		return &Response{Success: false, Error: fmt.Sprintf("marshal retire result: %v", err)}
+	}
+	return &Response{Success: true, Result: result}
 }
 
-// handlePoolResume transitions the pool back to active mode.
-func (d *Daemon) handlePoolResume() *Response {
+// AgentLogLevelParams is the request shape for overriding a task's log level.
+type AgentLogLevelParams struct {
+	ProjectSelector
+	TaskID string `json:"task_id"`
+	Level  string `json:"level"`
+}
+
+// AgentLogLevelResult is the response for the log level handler.
+type AgentLogLevelResult struct {
+	TaskID  string `json:"task_id"`
+	Level   string `json:"level"`
+	AgentID string `json:"agent_id,omitempty"` // empty when no agent is running yet
+}
+
+// handleAgentLogLevel sets a per-task log level so one misbehaving task can
+// be traced at debug without raising the level for the whole daemon.
+func (d *Daemon) handleAgentLogLevel(params AgentLogLevelParams) *Response {
+	pool, err := d.poolFor(params.Project)
+	if err != nil {
+		return &Response{Success: false, Error: err.Error()}
+	}
+	if !validTaskID.MatchString(params.TaskID) {
+		return &Response{Success: false, Error: fmt.Sprintf("invalid task ID %q", params.TaskID)}
+	}
+	level, err := ParseLogLevel(params.Level)
+	if err != nil {
+		return &Response{Success: false, Error: err.Error()}
+	}
+	agentID := pool.SetLogLevel(params.TaskID, level)
+
+	result, err := json.Marshal(AgentLogLevelResult{
+		TaskID:  params.TaskID,
+		Level:   strings.ToLower(level.String()),
+		AgentID: agentID,
+	})
+	if err != nil {
+		return &Response{Success: false, Error: fmt.Sprintf("marshal log level result: %v", err)}
+	}
+	return &Response{Success: true, Result: result}
+}
+
+// maxEnqueueTasks caps how many task IDs one enqueue request may name.
+const maxEnqueueTasks = 64
+
+// TaskEnqueueParams is the request shape for a planner injecting subtasks.
+// AgentID is the caller's AETHERFLOW_AGENT_ID.
+type TaskEnqueueParams struct {
+	AgentID string   `json:"agent_id"`
+	TaskIDs []string `json:"task_ids"`
+}
+
+// TaskEnqueueResult is the response for the enqueue handler.
+type TaskEnqueueResult struct {
+	Enqueued int `json:"enqueued"` // IDs added; already-enqueued IDs are not counted
+}
+
+// handleTaskEnqueue lets a running planner agent push the subtasks it just
+// created to the front of the schedule, ahead of the next prog poll.
+func (d *Daemon) handleTaskEnqueue(params TaskEnqueueParams) *Response {
 	if d.pool == nil {
 		return &Response{Success: false, Error: "no pool configured"}
 	}
-	d.pool.Resume()
-	return d.poolModeResponse()
+	if len(params.TaskIDs) == 0 {
+		return &Response{Success: false, Error: "task_ids is required"}
+	}
+	if len(params.TaskIDs) > maxEnqueueTasks {
+		return &Response{Success: false, Error: fmt.Sprintf("too many task IDs (%d, max %d)", len(params.TaskIDs), maxEnqueueTasks)}
+	}
+	for _, id := range params.TaskIDs {
+		if !validTaskID.MatchString(id) {
+			return &Response{Success: false, Error: fmt.Sprintf("invalid task ID %q", id)}
+		}
+	}
+
+	// The planner's own pool gets the subtasks: they belong to its project.
+	pool := d.poolForAgent(params.AgentID)
+	if pool == nil {
+		pool = d.pool
+	}
+	var caller *Agent
+	for _, a := range pool.Status() {
+		if string(a.ID) == params.AgentID && a.State == AgentRunning {
+			caller = &a
+			break
+		}
+	}
+	if caller == nil {
+		return &Response{Success: false, Error: fmt.Sprintf("agent %q is not running in the pool", params.AgentID)}
+	}
+	if caller.Role != RolePlanner {
+		return &Response{Success: false, Error: fmt.Sprintf("agent %q is a %s; only planners may enqueue tasks", params.AgentID, caller.Role)}
+	}
+
+	added := pool.Enqueue(params.TaskIDs)
+	d.log.Info("tasks enqueued by planner",
+		"agent_id", params.AgentID,
+		"task_id", caller.TaskID,
+		"task_ids", params.TaskIDs,
+		"added", added,
+	)
+
+	result, err := json.Marshal(TaskEnqueueResult{Enqueued: added})
+	if err != nil {
+		return &Response{Success: false, Error: fmt.Sprintf("marshal enqueue result: %v", err)}
+	}
+	return &Response{Success: true, Result: result}
 }
@@ -20,7 +20,7 @@ func TestHandlePoolDrainHappyPath(t *testing.T) {
 
 	d := &Daemon{config: cfg, pool: pool, log: testLogger()}
 
-	resp := d.handlePoolDrain()
+	resp := d.handlePoolDrain(ProjectSelector{})
 	if !resp.Success {
 		t.Fatalf("expected success, got error: %s", resp.Error)
 	}
@@ -51,7 +51,7 @@ func TestHandlePoolPauseHappyPath(t *testing.T) {
 
 	d := &Daemon{config: cfg, pool: pool, log: testLogger()}
 
-	resp := d.handlePoolPause()
+	resp := d.handlePoolPause(PoolPauseParams{})
 	if !resp.Success {
 		t.Fatalf("expected success, got error: %s", resp.Error)
 	}
@@ -82,7 +82,7 @@ func TestHandlePoolResumeHappyPath(t *testing.T) {
 
 	d := &Daemon{config: cfg, pool: pool, log: testLogger()}
 
-	resp := d.handlePoolResume()
+	resp := d.handlePoolResume(PoolPauseParams{})
 	if !resp.Success {
 		t.Fatalf("expected success, got error: %s", resp.Error)
 	}
@@ -96,13 +96,53 @@ func TestHandlePoolResumeHappyPath(t *testing.T) {
 	}
 }
 
+func TestHandlePoolPauseRole(t *testing.T) {
+	cfg := Config{
+		Project:  "testproject",
+		PoolSize: 2,
+		SpawnCmd: "fake-agent",
+	}
+	cfg.ApplyDefaults()
+
+	pool := NewPool(cfg, nil, nil, testLogger())
+	d := &Daemon{config: cfg, pool: pool, log: testLogger()}
+
+	resp := d.handlePoolPause(PoolPauseParams{Role: RolePlanner})
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	var result PoolModeResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if result.Mode != PoolActive {
+		t.Errorf("mode = %q, want %q (a role pause leaves the pool active)", result.Mode, PoolActive)
+	}
+	if len(result.PausedRoles) != 1 || result.PausedRoles[0] != RolePlanner {
+		t.Errorf("paused roles = %v, want [planner]", result.PausedRoles)
+	}
+
+	resp = d.handlePoolPause(PoolPauseParams{Role: "janitor"})
+	if resp.Success {
+		t.Error("expected error for unknown role")
+	}
+
+	resp = d.handlePoolResume(PoolPauseParams{Role: RolePlanner})
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	if roles := pool.PausedRoles(); len(roles) != 0 {
+		t.Errorf("paused roles after resume = %v, want none", roles)
+	}
+}
+
 func TestHandlePoolControlNilPool(t *testing.T) {
 	d := &Daemon{config: Config{}, pool: nil, log: testLogger()}
 
 	for _, handler := range []func() *Response{
-		d.handlePoolDrain,
-		d.handlePoolPause,
-		d.handlePoolResume,
+		func() *Response { return d.handlePoolDrain(ProjectSelector{}) },
+		func() *Response { return d.handlePoolPause(PoolPauseParams{}) },
+		func() *Response { return d.handlePoolResume(PoolPauseParams{}) },
 	} {
 		resp := handler()
 		if resp.Success {
@@ -113,3 +153,104 @@ func TestHandlePoolControlNilPool(t *testing.T) {
 		}
 	}
 }
+
+func TestHandlePoolRetire(t *testing.T) {
+	cfg := Config{
+		Project:  "testproject",
+		PoolSize: 2,
+		SpawnCmd: "fake-agent",
+	}
+	cfg.ApplyDefaults()
+
+	pool := NewPool(cfg, nil, nil, testLogger())
+	d := &Daemon{config: cfg, pool: pool, log: testLogger()}
+
+	resp := d.handlePoolRetire(PoolRetireParams{TaskID: "ts-abc"})
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	var result PoolRetireResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if result.TaskID != "ts-abc" || result.AgentID != "" {
+		t.Errorf("result = %+v, want task ts-abc with no agent", result)
+	}
+	pool.mu.RLock()
+	retired := pool.retired["ts-abc"]
+	pool.mu.RUnlock()
+	if !retired {
+		t.Error("task not marked retired")
+	}
+
+	if resp := d.handlePoolRetire(PoolRetireParams{TaskID: "../etc"}); resp.Success {
+		t.Error("expected error for invalid task ID")
+	}
+}
+
+func TestHandleAgentLogLevel(t *testing.T) {
+	cfg := Config{
+		Project:  "testproject",
+		PoolSize: 2,
+		SpawnCmd: "fake-agent",
+	}
+	cfg.ApplyDefaults()
+
+	pool := NewPool(cfg, nil, nil, testLogger())
+	d := &Daemon{config: cfg, pool: pool, log: testLogger()}
+
+	resp := d.handleAgentLogLevel(AgentLogLevelParams{TaskID: "ts-abc", Level: "debug"})
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	var result AgentLogLevelResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if result.TaskID != "ts-abc" || result.Level != "debug" || result.AgentID != "" {
+		t.Errorf("result = %+v, want task ts-abc at debug with no agent", result)
+	}
+
+	if resp := d.handleAgentLogLevel(AgentLogLevelParams{TaskID: "../etc", Level: "debug"}); resp.Success {
+		t.Error("expected error for invalid task ID")
+	}
+	if resp := d.handleAgentLogLevel(AgentLogLevelParams{TaskID: "ts-abc", Level: "loud"}); resp.Success {
+		t.Error("expected error for invalid level")
+	}
+}
+
+func TestHandleTaskEnqueueRequiresPlanner(t *testing.T) {
+	cfg := Config{
+		Project:  "testproject",
+		PoolSize: 2,
+		SpawnCmd: "fake-agent",
+	}
+	cfg.ApplyDefaults()
+
+	pool := NewPool(cfg, nil, nil, testLogger())
+	pool.agents["ts-epic"] = &Agent{ID: "calm_heron", TaskID: "ts-epic", Role: RolePlanner, State: AgentRunning}
+	pool.agents["ts-work"] = &Agent{ID: "busy_otter", TaskID: "ts-work", Role: RoleWorker, State: AgentRunning}
+	d := &Daemon{config: cfg, pool: pool, log: testLogger()}
+
+	resp := d.handleTaskEnqueue(TaskEnqueueParams{AgentID: "calm_heron", TaskIDs: []string{"ts-sub1", "ts-sub2"}})
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	var result TaskEnqueueResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if result.Enqueued != 2 {
+		t.Errorf("Enqueued = %d, want 2", result.Enqueued)
+	}
+
+	if resp := d.handleTaskEnqueue(TaskEnqueueParams{AgentID: "busy_otter", TaskIDs: []string{"ts-sub3"}}); resp.Success {
+		t.Error("expected error for worker caller")
+	}
+	if resp := d.handleTaskEnqueue(TaskEnqueueParams{AgentID: "", TaskIDs: []string{"ts-sub3"}}); resp.Success {
+		t.Error("expected error for missing agent ID")
+	}
+	if resp := d.handleTaskEnqueue(TaskEnqueueParams{AgentID: "calm_heron", TaskIDs: []string{"../etc"}}); resp.Success {
+		t.Error("expected error for invalid task ID")
+	}
+}
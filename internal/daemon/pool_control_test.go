@@ -19,7 +19,7 @@ func TestPoolModeDefaults(t *testing.T) {
 
 func TestPoolDrainStopsScheduling(t *testing.T) {
 	var spawnCount atomic.Int32
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		spawnCount.Add(1)
 		proc, _ := newFakeProcess(int(spawnCount.Load()) * 100)
 		return proc, nil
@@ -62,7 +62,7 @@ func TestDrainAllowsCrashRespawn(t *testing.T) {
 	procs := make([]*fakeProcess, 2)
 	releases := make([]func(), 2)
 
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		n := spawnCount.Add(1)
 		proc, release := newFakeProcess(int(n) * 100)
 		idx := int(n) - 1
@@ -107,7 +107,7 @@ func TestDrainAllowsCrashRespawn(t *testing.T) {
 
 func TestPauseStopsScheduling(t *testing.T) {
 	var spawnCount atomic.Int32
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		spawnCount.Add(1)
 		proc, _ := newFakeProcess(int(spawnCount.Load()) * 100)
 		return proc, nil
@@ -146,7 +146,7 @@ func TestPauseBlocksCrashRespawn(t *testing.T) {
 	var spawnCount atomic.Int32
 	proc, release := newFakeProcessWithError(1234, fmt.Errorf("exit status 1"))
 
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		spawnCount.Add(1)
 		return proc, nil
 	}
@@ -191,7 +191,7 @@ func TestPauseBlocksCrashRespawn(t *testing.T) {
 
 func TestResumeFromDrain(t *testing.T) {
 	var spawnCount atomic.Int32
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		spawnCount.Add(1)
 		proc, _ := newFakeProcess(int(spawnCount.Load()) * 100)
 		return proc, nil
@@ -260,7 +260,7 @@ func TestFullStatusIncludesPoolMode(t *testing.T) {
 	proc, release := newFakeProcess(1234)
 	defer release()
 
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		return proc, nil
 	}
 
@@ -324,3 +324,105 @@ func progRunnerWithShowAndReady(showJSON, readyOutput string) CommandRunner {
 		return nil, fmt.Errorf("unexpected command: %s %v", name, args)
 	}
 }
+
+func TestRetiredAgentCrashNotRespawned(t *testing.T) {
+	var spawnCount atomic.Int32
+	procs := make([]*fakeProcess, 2)
+	releases := make([]func(), 2)
+	procs[0], releases[0] = newFakeProcessWithError(100, fmt.Errorf("exit status 1"))
+	procs[1], releases[1] = newFakeProcess(200)
+	defer releases[1]()
+
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		n := spawnCount.Add(1)
+		if int(n) > len(procs) {
+			return nil, fmt.Errorf("unexpected spawn #%d", n)
+		}
+		return procs[n-1], nil
+	}
+
+	pool := testPool(t, progRunner(testTaskMeta), starter)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	taskCh := make(chan []Task, 2)
+	taskCh <- []Task{{ID: "ts-abc", Priority: 1, Title: "Do it"}}
+	go pool.Run(ctx, taskCh)
+
+	waitFor(t, func() bool { return spawnCount.Load() >= 1 })
+
+	if agentID := pool.Retire("ts-abc"); agentID == "" {
+		t.Error("Retire returned no agent ID for a running task")
+	}
+
+	// Crash the retired agent.
+	releases[0]()
+	waitFor(t, func() bool { return len(pool.Status()) == 0 })
+
+	// The task is still "ready" in prog; it must not be rescheduled either.
+	taskCh <- []Task{{ID: "ts-abc", Priority: 1, Title: "Do it"}}
+	time.Sleep(50 * time.Millisecond)
+
+	if got := spawnCount.Load(); got != 1 {
+		t.Errorf("spawn count = %d, want 1 (retired task must not respawn or reschedule)", got)
+	}
+}
+
+func TestRespawnSkipAndFailureCloseHistoryAndClearOverrides(t *testing.T) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		return nil, fmt.Errorf("exec: fake-agent: not found")
+	}
+	pool := testPool(t, progRunner(testTaskMeta), starter)
+	pool.SetContext(context.Background())
+
+	seed := func(taskID string) {
+		pool.mu.Lock()
+		pool.recordLaunch(taskID, RoleWorker)
+		pool.logLevels[taskID] = slog.LevelDebug
+		pool.taskEnv[taskID] = []string{"AETHERFLOW_TASK_ID=" + taskID}
+		pool.prios[taskID] = 1
+		pool.mu.Unlock()
+	}
+	check := func(taskID string, want TaskOutcome) {
+		t.Helper()
+		pool.mu.RLock()
+		defer pool.mu.RUnlock()
+		if got := pool.history[taskID].Outcome; got != want {
+			t.Errorf("%s outcome = %q, want %q", taskID, got, want)
+		}
+		_, level := pool.logLevels[taskID]
+		_, env := pool.taskEnv[taskID]
+		_, prio := pool.prios[taskID]
+		if level || env || prio {
+			t.Errorf("%s overrides left behind: log level %v, env %v, prio %v", taskID, level, env, prio)
+		}
+	}
+
+	seed("ts-failed")
+	pool.respawn("ts-failed", RoleWorker, "")
+	check("ts-failed", TaskOutcomeStranded)
+
+	seed("ts-retired")
+	pool.mu.Lock()
+	pool.retired["ts-retired"] = true
+	pool.mu.Unlock()
+	pool.respawn("ts-retired", RoleWorker, "")
+	check("ts-retired", TaskOutcomeRetired)
+
+	seed("ts-paused")
+	pool.Pause()
+	pool.respawn("ts-paused", RoleWorker, "")
+	check("ts-paused", TaskOutcomeStranded)
+
+	// A stranded task is still in_progress with no agent, so the orphan
+	// scan must be free to recover it.
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+	if !pool.isOrphan("ts-failed") {
+		t.Error("stranded task should count as an orphan")
+	}
+	if pool.isOrphan("ts-retired") {
+		t.Error("retired task should not count as an orphan")
+	}
+}
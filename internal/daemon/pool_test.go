@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -83,7 +84,7 @@ func TestPoolScheduleSpawnsAgent(t *testing.T) {
 	defer release()
 
 	var spawnedPrompt string
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		spawnedPrompt = prompt
 		return proc, nil
 	}
@@ -128,7 +129,7 @@ func TestPoolSkipsAlreadyRunning(t *testing.T) {
 	defer release()
 
 	var spawnCount atomic.Int32
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		spawnCount.Add(1)
 		return proc, nil
 	}
@@ -162,7 +163,7 @@ func TestPoolSkipsAlreadyRunning(t *testing.T) {
 func TestPoolRespectsPoolSize(t *testing.T) {
 	var spawnCount atomic.Int32
 
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		spawnCount.Add(1)
 		proc, _ := newFakeProcess(100)
 		return proc, nil
@@ -206,10 +207,78 @@ func TestPoolRespectsPoolSize(t *testing.T) {
 	}
 }
 
+// fakeWorkSource is an in-memory WorkSource. deps maps a task to the IDs
+// it depends on; done lists tasks that are complete.
+type fakeWorkSource struct {
+	deps map[string][]string
+	done map[string]bool
+}
+
+func (f *fakeWorkSource) Claim(ctx context.Context, workRef, project string) error {
+	return nil
+}
+
+func (f *fakeWorkSource) Block(ctx context.Context, workRef, project, reason string) error {
+	return nil
+}
+
+func (f *fakeWorkSource) GetMeta(ctx context.Context, workRef, project string) (TaskMeta, error) {
+	return TaskMeta{ID: workRef, Type: "task", DefinitionOfDone: "Do it"}, nil
+}
+
+func (f *fakeWorkSource) Dependencies(ctx context.Context, workRef, project string) ([]Dependency, error) {
+	var deps []Dependency
+	for _, id := range f.deps[workRef] {
+		deps = append(deps, Dependency{ID: id, Done: f.done[id]})
+	}
+	return deps, nil
+}
+
+func TestPoolSkipsTaskWithIncompleteDependencies(t *testing.T) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		proc, _ := newFakeProcess(100)
+		return proc, nil
+	}
+
+	pool := testPool(t, progRunner(testTaskMeta), starter)
+	pool.work = &fakeWorkSource{
+		deps: map[string][]string{
+			"ts-b": {"ts-a"},
+			"ts-c": {"ts-done"},
+		},
+		done: map[string]bool{"ts-done": true},
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	taskCh := make(chan []Task, 1)
+	taskCh <- []Task{
+		{ID: "ts-b", Priority: 1, Title: "Blocked on A"},
+		{ID: "ts-c", Priority: 2, Title: "Dependency done"},
+	}
+
+	go pool.Run(ctx, taskCh)
+
+	waitFor(t, func() bool {
+		return len(pool.Status()) == 1
+	})
+	// Give the scheduler a moment in case it would also spawn ts-b.
+	time.Sleep(50 * time.Millisecond)
+
+	agents := pool.Status()
+	if len(agents) != 1 {
+		t.Fatalf("running agents = %d, want 1 (ts-b must stay queued)", len(agents))
+	}
+	if agents[0].TaskID != "ts-c" {
+		t.Errorf("spawned %q, want ts-c (ts-b depends on incomplete ts-a)", agents[0].TaskID)
+	}
+}
+
 func TestPoolReapsExitedProcess(t *testing.T) {
 	proc, release := newFakeProcess(1234)
 
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		return proc, nil
 	}
 
@@ -240,7 +309,7 @@ func TestPoolReapsExitedProcess(t *testing.T) {
 func TestPoolReapsProcessWithError(t *testing.T) {
 	proc, release := newFakeProcessWithError(1234, fmt.Errorf("exit status 1"))
 
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		return proc, nil
 	}
 
@@ -269,7 +338,7 @@ func TestPoolStatus(t *testing.T) {
 	proc, release := newFakeProcess(1234)
 	defer release()
 
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		return proc, nil
 	}
 
@@ -310,7 +379,7 @@ func TestPoolProgStartFailure(t *testing.T) {
 	}
 
 	var spawned atomic.Int32
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		spawned.Add(1)
 		proc, _ := newFakeProcess(1)
 		return proc, nil
@@ -345,7 +414,7 @@ func TestCrashRespawnsAgent(t *testing.T) {
 	procs := make([]*fakeProcess, 0)
 	releases := make([]func(), 0)
 
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		spawnCount.Add(1)
 		proc, release := newFakeProcess(int(spawnCount.Load()) * 100)
 		mu.Lock()
@@ -410,7 +479,7 @@ func TestCrashMaxRetriesExhausted(t *testing.T) {
 	procs := make([]*fakeProcess, 0)
 	releases := make([]func(), 0)
 
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		spawnCount.Add(1)
 		proc, release := newFakeProcessWithError(int(spawnCount.Load())*100, fmt.Errorf("exit status 1"))
 		mu.Lock()
@@ -479,11 +548,264 @@ func TestCrashMaxRetriesExhausted(t *testing.T) {
 	}
 }
 
+func TestCrashBeforeMinHealthyUptimeIsStartupFailure(t *testing.T) {
+	// With MinHealthyUptime=1m and MaxStartupRetries=1:
+	//   initial spawn → runs 10m, crash → mid-task crash, retries=1 → respawn
+	//   respawn 1     → crash at once  → startup failure 1 (1 <= 1) → respawn
+	//   respawn 2     → crash at once  → startup failure 2 (2 > 1)  → stop
+	// Startup failures never touch the normal retry count.
+
+	var spawnCount atomic.Int32
+	var mu sync.Mutex
+	releases := make([]func(), 0)
+
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		n := spawnCount.Add(1)
+		proc, release := newFakeProcessWithError(int(n)*100, fmt.Errorf("exit status 1"))
+		mu.Lock()
+		releases = append(releases, release)
+		mu.Unlock()
+		return proc, nil
+	}
+	crash := func(i int) {
+		mu.Lock()
+		release := releases[i]
+		mu.Unlock()
+		release()
+	}
+
+	cfg := Config{
+		Project:           "testproject",
+		PoolSize:          2,
+		SpawnCmd:          "fake-agent",
+		MaxRetries:        3,
+		MinHealthyUptime:  time.Minute,
+		MaxStartupRetries: 1,
+	}
+	cfg.ApplyDefaults()
+	pool := NewPool(cfg, progRunner(testTaskMeta), starter, slog.Default())
+	clock := newFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	pool.clock = clock
+	pool.ctx = context.Background()
+
+	pool.schedule(context.Background(), []Task{{ID: "ts-abc", Priority: 1, Title: "Do it"}})
+	if got := spawnCount.Load(); got != 1 {
+		t.Fatalf("spawn count = %d, want 1", got)
+	}
+	retries := func() (int, int) {
+		pool.mu.RLock()
+		defer pool.mu.RUnlock()
+		return pool.retries["ts-abc"], pool.startup["ts-abc"]
+	}
+
+	// A crash after a long run is a normal crash.
+	clock.Advance(10 * time.Minute)
+	crash(0)
+	waitFor(t, func() bool { return spawnCount.Load() >= 2 })
+	if r, s := retries(); r != 1 || s != 0 {
+		t.Errorf("after long-lived crash: retries=%d startup=%d, want 1 and 0", r, s)
+	}
+
+	// An immediate crash is a startup failure with its own budget.
+	crash(1)
+	waitFor(t, func() bool { return spawnCount.Load() >= 3 })
+	if r, s := retries(); r != 1 || s != 1 {
+		t.Errorf("after immediate crash: retries=%d startup=%d, want 1 and 1", r, s)
+	}
+
+	// A second immediate crash exhausts the startup budget, well before
+	// MaxRetries would have.
+	crash(2)
+	waitFor(t, func() bool { return len(pool.Status()) == 0 })
+	time.Sleep(50 * time.Millisecond)
+	if got := spawnCount.Load(); got != 3 {
+		t.Errorf("spawn count = %d, want 3 (no respawn after startup retries exhausted)", got)
+	}
+	errs := pool.RecentErrors(1)
+	if len(errs) != 1 || errs[0].Kind != OpErrorStartupFailed {
+		t.Errorf("recent errors = %+v, want one %s", errs, OpErrorStartupFailed)
+	}
+}
+
+// exitCodeError mimics *exec.ExitError for fake processes.
+type exitCodeError int
+
+func (e exitCodeError) Error() string { return fmt.Sprintf("exit status %d", int(e)) }
+func (e exitCodeError) ExitCode() int { return int(e) }
+
+func TestCrashFatalExitCodeNotRespawned(t *testing.T) {
+	tests := []struct {
+		name        string
+		exitCode    int
+		wantSpawns  int32
+		wantRetries int
+	}{
+		{name: "fatal", exitCode: 2, wantSpawns: 1, wantRetries: 0},
+		{name: "transient", exitCode: 137, wantSpawns: 2, wantRetries: 1},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var spawnCount atomic.Int32
+			var mu sync.Mutex
+			var releases []func()
+
+			starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+				n := spawnCount.Add(1)
+				var proc *fakeProcess
+				var release func()
+				if n == 1 {
+					proc, release = newFakeProcessWithError(100, exitCodeError(tc.exitCode))
+				} else {
+					proc, release = newFakeProcess(int(n) * 100)
+				}
+				mu.Lock()
+				releases = append(releases, release)
+				mu.Unlock()
+				return proc, nil
+			}
+
+			cfg := Config{
+				Project:        "testproject",
+				PoolSize:       2,
+				SpawnCmd:       "fake-agent",
+				MaxRetries:     3,
+				FatalExitCodes: []int{2},
+			}
+			cfg.ApplyDefaults()
+			pool := NewPool(cfg, progRunner(testTaskMeta), starter, slog.Default())
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			taskCh := make(chan []Task, 1)
+			taskCh <- []Task{{ID: "ts-abc", Priority: 1, Title: "Do it"}}
+			go pool.Run(ctx, taskCh)
+
+			waitFor(t, func() bool { return spawnCount.Load() >= 1 })
+
+			mu.Lock()
+			releases[0]()
+			mu.Unlock()
+
+			if tc.wantSpawns > 1 {
+				waitFor(t, func() bool { return spawnCount.Load() >= tc.wantSpawns })
+			} else {
+				waitFor(t, func() bool { return len(pool.Status()) == 0 })
+			}
+
+			// Give extra time for any unexpected respawn.
+			time.Sleep(50 * time.Millisecond)
+
+			if got := spawnCount.Load(); got != tc.wantSpawns {
+				t.Errorf("spawn count = %d, want %d", got, tc.wantSpawns)
+			}
+			pool.mu.RLock()
+			retries := pool.retries["ts-abc"]
+			pool.mu.RUnlock()
+			if retries != tc.wantRetries {
+				t.Errorf("retries = %d, want %d", retries, tc.wantRetries)
+			}
+
+			mu.Lock()
+			for _, release := range releases[1:] {
+				release()
+			}
+			mu.Unlock()
+		})
+	}
+}
+
+func TestCrashNotRespawnedWhenCrashRespawnDisabled(t *testing.T) {
+	var spawnCount atomic.Int32
+	var release func()
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		spawnCount.Add(1)
+		var proc *fakeProcess
+		proc, release = newFakeProcessWithError(100, exitCodeError(1))
+		return proc, nil
+	}
+
+	cfg := Config{
+		Project:    "testproject",
+		PoolSize:   2,
+		SpawnCmd:   "fake-agent",
+		MaxRetries: NoCrashRespawn,
+	}
+	cfg.ApplyDefaults()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	pool := NewPool(cfg, progRunner(testTaskMeta), starter, slog.Default())
+	pool.ctx = context.Background()
+
+	pool.schedule(context.Background(), []Task{{ID: "ts-abc"}})
+	release()
+	waitFor(t, func() bool { return len(pool.Status()) == 0 })
+	time.Sleep(50 * time.Millisecond) // give an unexpected respawn time to happen
+
+	if got := spawnCount.Load(); got != 1 {
+		t.Errorf("spawn count = %d, want 1 (no respawn)", got)
+	}
+	if got := len(pool.Status()); got != 0 {
+		t.Errorf("agents = %d, want 0", got)
+	}
+	errs := pool.RecentErrors(1)
+	if len(errs) != 1 || errs[0].Kind != OpErrorCrashMaxRetries || errs[0].TaskID != "ts-abc" {
+		t.Errorf("recent errors = %+v, want one %s for ts-abc", errs, OpErrorCrashMaxRetries)
+	}
+	history := pool.History()
+	if len(history) != 1 || history[0].Outcome != TaskOutcomeCrashed {
+		t.Errorf("history = %+v, want ts-abc %s", history, TaskOutcomeCrashed)
+	}
+}
+
+func TestStartupFailureNotRespawnedWithNoStartupRespawn(t *testing.T) {
+	var spawnCount atomic.Int32
+	var release func()
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		spawnCount.Add(1)
+		var proc *fakeProcess
+		proc, release = newFakeProcessWithError(100, exitCodeError(1))
+		return proc, nil
+	}
+
+	cfg := Config{
+		Project:           "testproject",
+		PoolSize:          2,
+		SpawnCmd:          "fake-agent",
+		MinHealthyUptime:  time.Minute,
+		MaxStartupRetries: NoStartupRespawn,
+	}
+	cfg.ApplyDefaults()
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if cfg.MaxStartupRetries != NoStartupRespawn {
+		t.Fatalf("MaxStartupRetries = %d after defaults, want %d", cfg.MaxStartupRetries, NoStartupRespawn)
+	}
+	pool := NewPool(cfg, progRunner(testTaskMeta), starter, slog.Default())
+	pool.ctx = context.Background()
+
+	pool.schedule(context.Background(), []Task{{ID: "ts-abc"}})
+	release() // dies at once: a startup failure
+	waitFor(t, func() bool { return len(pool.Status()) == 0 })
+	time.Sleep(50 * time.Millisecond) // give an unexpected respawn time to happen
+
+	if got := spawnCount.Load(); got != 1 {
+		t.Errorf("spawn count = %d, want 1 (no respawn)", got)
+	}
+	errs := pool.RecentErrors(1)
+	if len(errs) != 1 || errs[0].Kind != OpErrorStartupFailed {
+		t.Errorf("recent errors = %+v, want one %s", errs, OpErrorStartupFailed)
+	}
+}
+
 func TestCrashCleanExitNoRespawn(t *testing.T) {
 	var spawnCount atomic.Int32
 	proc, release := newFakeProcess(1234) // Clean exit (no error).
 
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		spawnCount.Add(1)
 		return proc, nil
 	}
@@ -530,7 +852,7 @@ func TestCrashRetryCountResetsOnSuccess(t *testing.T) {
 	procs := make([]*fakeProcess, 0)
 	releases := make([]func(), 0)
 
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		spawnCount.Add(1)
 		proc, release := newFakeProcess(int(spawnCount.Load()) * 100)
 		mu.Lock()
@@ -586,7 +908,7 @@ func TestCrashRetryCountResetsOnSuccess(t *testing.T) {
 
 func TestSpawnFailsGracefullyOnStarterError(t *testing.T) {
 	var attempted atomic.Bool
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		attempted.Store(true)
 		return nil, fmt.Errorf("spawn failed")
 	}
@@ -612,6 +934,36 @@ func TestSpawnFailsGracefullyOnStarterError(t *testing.T) {
 	}
 }
 
+func TestSpawnRejectsOversizedPromptBeforeClaim(t *testing.T) {
+	var started, claimed atomic.Bool
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		started.Store(true)
+		return nil, fmt.Errorf("should not start")
+	}
+	show := progRunner(testTaskMeta)
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if len(args) >= 1 && args[0] == "start" {
+			claimed.Store(true)
+		}
+		return show(ctx, name, args...)
+	}
+
+	pool := testPool(t, runner, starter)
+	pool.config.MaxPromptBytes = 64 // far below any rendered role prompt
+
+	pool.spawn(context.Background(), Task{ID: "ts-abc", Priority: 1, Title: "Do it"})
+
+	if claimed.Load() {
+		t.Error("task was claimed despite an oversized prompt")
+	}
+	if started.Load() {
+		t.Error("agent was started despite an oversized prompt")
+	}
+	if got := len(pool.Status()); got != 0 {
+		t.Errorf("pool has %d agents, want 0", got)
+	}
+}
+
 // --- AETHERFLOW_AGENT_ID env var tests ---
 
 func TestExecProcessStarterSetsAgentIDEnv(t *testing.T) {
@@ -624,6 +976,7 @@ func TestExecProcessStarterSetsAgentIDEnv(t *testing.T) {
 		"printenv AETHERFLOW_AGENT_ID", // prompt (becomes the shell command)
 		"steel_gloom",                  // agentID
 		&buf,                           // stdout
+		StartOptions{},                 // opts
 	)
 	if err != nil {
 		t.Fatalf("ExecProcessStarter: %v", err)
@@ -638,12 +991,103 @@ func TestExecProcessStarterSetsAgentIDEnv(t *testing.T) {
 	}
 }
 
+func TestSpawnExportsTaskLabelsAsEnv(t *testing.T) {
+	// The pool's starter runs a real process printing the exported label
+	// env var, with the env the pool computed from the task's metadata.
+	meta := `{"id": "ts-abc", "type": "task", "labels": ["backend", "urgent"], "estimate": 3, "owner": {"name": "x"}}`
+	var mu sync.Mutex
+	var buf strings.Builder
+	var gotEnv []string
+	starter := func(ctx context.Context, _ string, _ string, agentID string, _ io.Writer, opts StartOptions) (Process, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		gotEnv = opts.Env
+		return ExecProcessStarter(ctx, "sh -c", "printenv AETHERFLOW_TASK_LABELS", agentID, &buf, opts)
+	}
+
+	cfg := Config{
+		Project:       "testproject",
+		PoolSize:      1,
+		SpawnCmd:      "fake-agent",
+		TaskEnvFields: []string{"labels", "estimate", "owner", "missing"},
+	}
+	cfg.ApplyDefaults()
+	pool := NewPool(cfg, progRunner(meta), starter, slog.Default())
+
+	pool.schedule(context.Background(), []Task{{ID: "ts-abc"}})
+	waitFor(t, func() bool { return len(pool.Status()) == 0 })
+
+	mu.Lock()
+	defer mu.Unlock()
+	if got := strings.TrimSpace(buf.String()); got != "backend,urgent" {
+		t.Errorf("AETHERFLOW_TASK_LABELS = %q, want %q", got, "backend,urgent")
+	}
+	// Objects and missing fields are skipped; only configured fields export.
+	want := []string{"AETHERFLOW_TASK_LABELS=backend,urgent", "AETHERFLOW_TASK_ESTIMATE=3"}
+	if !slices.Equal(gotEnv, want) {
+		t.Errorf("env = %v, want %v", gotEnv, want)
+	}
+}
+
+func TestSpawnRejectsCommandOutsideAllowlist(t *testing.T) {
+	tests := []struct {
+		name       string
+		spawnCmd   string
+		wantSpawns int32
+	}{
+		{name: "allowed", spawnCmd: "opencode run", wantSpawns: 1},
+		{name: "disallowed", spawnCmd: "curl run", wantSpawns: 0},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var spawns atomic.Int32
+			starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+				spawns.Add(1)
+				proc, _ := newFakeProcess(1234)
+				return proc, nil
+			}
+			var claims atomic.Int32
+			show := progRunner(testTaskMeta)
+			runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+				if args[0] == "start" {
+					claims.Add(1)
+				}
+				return show(ctx, name, args...)
+			}
+
+			cfg := Config{
+				Project:          "testproject",
+				PoolSize:         1,
+				SpawnCmd:         tc.spawnCmd,
+				AllowedSpawnCmds: []string{"opencode"},
+			}
+			cfg.ApplyDefaults()
+			pool := NewPool(cfg, runner, starter, slog.Default())
+			pool.schedule(context.Background(), []Task{{ID: "ts-abc"}})
+
+			if got := spawns.Load(); got != tc.wantSpawns {
+				t.Errorf("spawns = %d, want %d", got, tc.wantSpawns)
+			}
+			// A refused spawn never claims, so the task stays ready.
+			if got := claims.Load(); got != tc.wantSpawns {
+				t.Errorf("claims = %d, want %d", got, tc.wantSpawns)
+			}
+			if tc.wantSpawns == 0 {
+				errs := pool.RecentErrors(1)
+				if len(errs) != 1 || !strings.Contains(errs[0].Message, "allowed_spawn_cmds") {
+					t.Errorf("recent errors = %+v, want a spawn_failed naming allowed_spawn_cmds", errs)
+				}
+			}
+		})
+	}
+}
+
 func TestSpawnPassesAgentIDToStarter(t *testing.T) {
 	proc, release := newFakeProcess(1234)
 	defer release()
 
 	var gotAgentID string
-	starter := func(ctx context.Context, spawnCmd string, prompt string, agentID string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, agentID string, _ io.Writer, _ StartOptions) (Process, error) {
 		gotAgentID = agentID
 		return proc, nil
 	}
@@ -681,7 +1125,7 @@ func TestPoolSweepsDeadProcess(t *testing.T) {
 	// sweepDead should remove it from the pool.
 	proc := &fakeProcess{pid: 99999, waitCh: make(chan struct{})} // never closed → Wait blocks forever
 
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		return proc, nil
 	}
 
@@ -716,7 +1160,7 @@ func TestPoolSweepKeepsAliveProcess(t *testing.T) {
 	proc, release := newFakeProcess(1234)
 	defer release()
 
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		return proc, nil
 	}
 
@@ -749,7 +1193,7 @@ func TestPoolSweepFreesSlot(t *testing.T) {
 	// to be scheduled on the next poll cycle.
 	var spawnCount atomic.Int32
 
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		spawnCount.Add(1)
 		// All processes block forever (simulating hung Wait).
 		proc := &fakeProcess{pid: int(spawnCount.Load()) * 100, waitCh: make(chan struct{})}
@@ -819,7 +1263,7 @@ func TestCrashRespawnCarriesSessionID(t *testing.T) {
 	releases := make([]func(), 0)
 	spawnCmds := make([]string, 0)
 
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		spawnCount.Add(1)
 		proc, release := newFakeProcess(int(spawnCount.Load()) * 100)
 		mu.Lock()
@@ -892,7 +1336,7 @@ func TestCrashRespawnWithoutSessionIDOmitsFlag(t *testing.T) {
 	releases := make([]func(), 0)
 	spawnCmds := make([]string, 0)
 
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		spawnCount.Add(1)
 		proc, release := newFakeProcess(int(spawnCount.Load()) * 100)
 		mu.Lock()
@@ -957,7 +1401,7 @@ func TestRespawnCarriesSessionIDForward(t *testing.T) {
 	releases := make([]func(), 0)
 	spawnCmds := make([]string, 0)
 
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		spawnCount.Add(1)
 		proc, release := newFakeProcess(int(spawnCount.Load()) * 100)
 		mu.Lock()
@@ -1194,3 +1638,225 @@ func waitFor(t *testing.T, cond func() bool) {
 	}
 	t.Fatal("timed out waiting for condition")
 }
+
+func TestPoolTracksQueueAgeAcrossPolls(t *testing.T) {
+	pool := testPool(t, progRunner(testTaskMeta), nil)
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := newFakeClock(start)
+	pool.clock = clock
+	// Paused so schedule only observes the queue and never spawns.
+	pool.Pause()
+	ctx := context.Background()
+
+	readySince := func(tasks []Task) map[string]time.Time {
+		out := make(map[string]time.Time, len(tasks))
+		for _, task := range tasks {
+			out[task.ID] = task.ReadySince
+		}
+		return out
+	}
+
+	// Cycle 1: both tasks first seen now.
+	pool.schedule(ctx, []Task{{ID: "ts-a"}, {ID: "ts-b"}})
+
+	// Cycle 2: ts-a keeps its age, ts-b left the queue, ts-c is new.
+	clock.Advance(time.Minute)
+	pool.schedule(ctx, []Task{{ID: "ts-a"}, {ID: "ts-c"}})
+
+	// Cycle 3: ts-b is back and starts aging again.
+	clock.Advance(time.Minute)
+	got := readySince(pool.observeQueue([]Task{{ID: "ts-a"}, {ID: "ts-b"}, {ID: "ts-c"}}))
+
+	want := map[string]time.Time{
+		"ts-a": start,
+		"ts-b": start.Add(2 * time.Minute),
+		"ts-c": start.Add(time.Minute),
+	}
+	for id, w := range want {
+		if !got[id].Equal(w) {
+			t.Errorf("%s ReadySince = %v, want %v", id, got[id], w)
+		}
+	}
+}
+
+func TestPoolSchedulesEnqueuedTaskAheadOfQueue(t *testing.T) {
+	var spawnCount atomic.Int32
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		spawnCount.Add(1)
+		proc, _ := newFakeProcess(100)
+		return proc, nil
+	}
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if len(args) >= 1 && args[0] == "start" {
+			return []byte("Started"), nil
+		}
+		if len(args) >= 2 && args[0] == "show" {
+			return []byte(fmt.Sprintf(`{"id":"%s","type":"task","definition_of_done":"Do it","labels":[]}`, args[1])), nil
+		}
+		return nil, fmt.Errorf("unexpected: %v", args)
+	}
+
+	pool := testPool(t, runner, starter)
+	pool.config.PoolSize = 1
+
+	// A planner enqueues its subtask before the next poll delivers the queue.
+	if n := pool.Enqueue([]string{"ts-sub"}); n != 1 {
+		t.Fatalf("Enqueue added %d, want 1", n)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	taskCh := make(chan []Task, 1)
+	taskCh <- []Task{{ID: "ts-normal", Priority: 1, Title: "Polled"}}
+	go pool.Run(ctx, taskCh)
+
+	waitFor(t, func() bool {
+		return len(pool.Status()) == 1
+	})
+	// Let the polled batch be processed too; the pool is full by then.
+	time.Sleep(50 * time.Millisecond)
+
+	agents := pool.Status()
+	if len(agents) != 1 || agents[0].TaskID != "ts-sub" {
+		t.Fatalf("running = %+v, want only ts-sub", agents)
+	}
+	if got := spawnCount.Load(); got != 1 {
+		t.Errorf("spawn count = %d, want 1", got)
+	}
+	pool.mu.RLock()
+	pending := len(pool.priority)
+	pool.mu.RUnlock()
+	if pending != 0 {
+		t.Errorf("enqueued IDs left = %d, want 0 after spawn", pending)
+	}
+}
+
+func TestPoolSkipsPausedRole(t *testing.T) {
+	var mu sync.Mutex
+	var claimed []string
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if len(args) >= 2 && args[0] == "start" {
+			mu.Lock()
+			claimed = append(claimed, args[1])
+			mu.Unlock()
+			return []byte("Started"), nil
+		}
+		if len(args) >= 2 && args[0] == "show" {
+			return fmt.Appendf(nil, `{"id":"%s","type":"task","labels":[]}`, args[1]), nil
+		}
+		return nil, fmt.Errorf("unexpected: %v", args)
+	}
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		proc, _ := newFakeProcess(100)
+		return proc, nil
+	}
+
+	pool := testPool(t, runner, starter)
+	pool.inferRole = func(meta TaskMeta) Role {
+		if strings.HasPrefix(meta.ID, "ts-plan") {
+			return RolePlanner
+		}
+		return RoleWorker
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool.ctx = ctx
+
+	pool.PauseRole(RolePlanner)
+	pool.schedule(ctx, []Task{{ID: "ts-plan1"}, {ID: "ts-work1"}})
+
+	agents := pool.Status()
+	if len(agents) != 1 || agents[0].TaskID != "ts-work1" {
+		t.Fatalf("agents = %+v, want only ts-work1", agents)
+	}
+	mu.Lock()
+	if len(claimed) != 1 || claimed[0] != "ts-work1" {
+		t.Errorf("claimed = %v, want [ts-work1] (paused planner must stay unclaimed)", claimed)
+	}
+	mu.Unlock()
+	if pool.Mode() != PoolActive {
+		t.Errorf("mode = %q, want %q", pool.Mode(), PoolActive)
+	}
+
+	pool.ResumeRole(RolePlanner)
+	pool.schedule(ctx, []Task{{ID: "ts-plan1"}})
+	waitFor(t, func() bool { return len(pool.Status()) == 2 })
+}
+
+func TestPoolRecordsPoolFullSkipReason(t *testing.T) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		proc, _ := newFakeProcess(100)
+		return proc, nil
+	}
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if len(args) >= 1 && args[0] == "start" {
+			return []byte("Started"), nil
+		}
+		if len(args) >= 2 && args[0] == "show" {
+			return fmt.Appendf(nil, `{"id":"%s","type":"task","labels":[]}`, args[1]), nil
+		}
+		return nil, fmt.Errorf("unexpected: %v", args)
+	}
+
+	pool := testPool(t, runner, starter) // PoolSize 2
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool.ctx = ctx
+
+	pool.schedule(ctx, []Task{{ID: "ts-a"}, {ID: "ts-b"}, {ID: "ts-c"}})
+
+	skips := pool.SkipReasons()
+	if got, want := skips["ts-c"], "pool full (2/2)"; got != want {
+		t.Errorf("skip reason for ts-c = %q, want %q", got, want)
+	}
+	for _, id := range []string{"ts-a", "ts-b"} {
+		if reason, ok := skips[id]; ok {
+			t.Errorf("scheduled task %s has skip reason %q", id, reason)
+		}
+	}
+
+	// The next pass replaces the reasons, so they stay bounded to the queue.
+	pool.Pause()
+	pool.schedule(ctx, []Task{{ID: "ts-d"}})
+	skips = pool.SkipReasons()
+	if len(skips) != 1 || skips["ts-d"] != "pool paused" {
+		t.Errorf("skip reasons after paused pass = %v, want only ts-d: pool paused", skips)
+	}
+}
+
+func TestPoolEnqueueWakeMergesSkipReasons(t *testing.T) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		proc, _ := newFakeProcess(100)
+		return proc, nil
+	}
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if len(args) >= 1 && args[0] == "start" {
+			return []byte("Started"), nil
+		}
+		if len(args) >= 2 && args[0] == "show" {
+			return fmt.Appendf(nil, `{"id":"%s","type":"task","labels":[]}`, args[1]), nil
+		}
+		return nil, fmt.Errorf("unexpected: %v", args)
+	}
+
+	pool := testPool(t, runner, starter) // PoolSize 2
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool.ctx = ctx
+
+	pool.schedule(ctx, []Task{{ID: "ts-a"}, {ID: "ts-b"}, {ID: "ts-c"}})
+
+	// A wake from Enqueue only looks at the enqueued IDs; the reasons the
+	// last poll recorded for the rest of the queue must survive it.
+	pool.Enqueue([]string{"ts-urgent"})
+	pool.assign(ctx, nil, true)
+
+	skips := pool.SkipReasons()
+	if got, want := skips["ts-c"], "pool full (2/2)"; got != want {
+		t.Errorf("skip reason for ts-c = %q, want %q", got, want)
+	}
+	if got := skips["ts-urgent"]; !strings.HasPrefix(got, "pool full") {
+		t.Errorf("skip reason for ts-urgent = %q, want pool full", got)
+	}
+}
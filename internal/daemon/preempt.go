@@ -0,0 +1,174 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// preemptCooldown is the minimum time between preemptions, so a burst of
+// urgent tasks can't churn the pool.
+const preemptCooldown = 5 * time.Minute
+
+// preemptedTask is a task whose agent was stopped to free a slot for
+// urgentID. It resumes once the urgent task has had its spawn attempt.
+type preemptedTask struct {
+	yieldedTask
+	urgentID string
+}
+
+// preemptFor frees a slot in a full pool for the first urgent task in
+// waiting (see Config.Preempt) by stopping the running agent with the
+// lowest priority. The urgent task is enqueued so it takes the freed slot;
+// the stopped task keeps its claim and session and resumes, ahead of queued
+// work, as soon as another slot frees up.
+//
+// Preemption is bounded: one at a time, at most one per preemptCooldown,
+// and only agents whose tasks are strictly lower priority than both the
+// urgent task and PreemptPriority are stopped, so urgent tasks never
+// preempt each other. Only polled tasks, whose priority is known, preempt
+// or are preempted. The skip reason of the task preempted for is updated.
+func (p *Pool) preemptFor(ctx context.Context, waiting []Task, skips map[string]string) {
+	p.mu.RLock()
+	enabled := p.config.Preempt
+	threshold := p.config.PreemptPriority
+	busy := p.preempting != "" || (!p.lastPreempt.IsZero() && p.clock.Now().Sub(p.lastPreempt) < preemptCooldown)
+	p.mu.RUnlock()
+	if !enabled || busy {
+		return
+	}
+
+	for _, task := range waiting {
+		if task.ReadySince.IsZero() || task.Priority > threshold {
+			continue
+		}
+		if !p.dependenciesDone(ctx, task.ID) {
+			continue
+		}
+		if victim, ok := p.startPreemption(task); ok {
+			skips[task.ID] = fmt.Sprintf("preempting %s on %s", victim.ID, victim.TaskID)
+		}
+		return
+	}
+}
+
+// startPreemption stops the lowest-priority running agent for urgent task
+// and hands the slot over once it exits. Among equal priorities the most
+// recently spawned agent is chosen, losing the least work. It reports false
+// when no agent may be preempted or stopping it failed.
+func (p *Pool) startPreemption(task Task) (Agent, bool) {
+	p.mu.Lock()
+	var victim *Agent
+	for _, a := range p.agents {
+		prio, known := p.prios[a.TaskID]
+		if !known || a.State != AgentRunning || prio <= task.Priority || prio <= p.config.PreemptPriority {
+			continue
+		}
+		if _, stopping := p.stopping[a.TaskID]; stopping || p.killed[a.TaskID] {
+			continue
+		}
+		if victim == nil || prio > p.prios[victim.TaskID] ||
+			(prio == p.prios[victim.TaskID] && a.SpawnTime.After(victim.SpawnTime)) {
+			victim = a
+		}
+	}
+	if victim == nil {
+		p.mu.Unlock()
+		return Agent{}, false
+	}
+	snapshot := *victim
+	victimPrio := p.prios[snapshot.TaskID]
+	exited := make(chan struct{})
+	p.stopping[snapshot.TaskID] = exited
+	p.preempting = task.ID
+	p.lastPreempt = p.clock.Now()
+	p.mu.Unlock()
+
+	p.taskLog(snapshot.TaskID).Info("preempting agent for urgent task",
+		"agent_id", snapshot.ID,
+		"task_id", snapshot.TaskID,
+		"priority", victimPrio,
+		"urgent_task_id", task.ID,
+		"urgent_priority", task.Priority,
+	)
+	if err := p.stopProcess(snapshot.PID); err != nil {
+		p.clearStopping(snapshot.TaskID, exited)
+		p.mu.Lock()
+		p.preempting = ""
+		p.mu.Unlock()
+		p.taskLog(snapshot.TaskID).Warn("failed to stop agent for preemption",
+			"agent_id", snapshot.ID,
+			"pid", snapshot.PID,
+			"error", err,
+		)
+		return Agent{}, false
+	}
+
+	go p.finishPreemption(task.ID, victim, exited)
+	return snapshot, true
+}
+
+// finishPreemption waits for the preempted agent to exit, parks its task
+// to resume its session later, and schedules the urgent task. If the agent
+// doesn't exit in time it is left to normal crash handling.
+func (p *Pool) finishPreemption(urgentID string, victim *Agent, exited chan struct{}) {
+	select {
+	case <-exited:
+	case <-p.clock.After(rollingRestartExitTimeout):
+		p.clearStopping(victim.TaskID, exited)
+		p.mu.Lock()
+		p.preempting = ""
+		p.mu.Unlock()
+		p.taskLog(victim.TaskID).Warn("preempted agent did not exit in time",
+			"agent_id", victim.ID,
+			"task_id", victim.TaskID,
+			"timeout", rollingRestartExitTimeout,
+		)
+		return
+	case <-p.ctx.Done():
+		p.clearStopping(victim.TaskID, exited)
+		p.mu.Lock()
+		p.preempting = ""
+		p.mu.Unlock()
+		return
+	}
+
+	p.mu.Lock()
+	p.preempting = ""
+	p.preempted = append(p.preempted, preemptedTask{
+		yieldedTask: yieldedTask{taskID: victim.TaskID, role: victim.Role, sessionID: victim.SessionID},
+		urgentID:    urgentID,
+	})
+	p.mu.Unlock()
+
+	p.taskLog(victim.TaskID).Info("agent preempted, task waits for a free slot",
+		"agent_id", victim.ID,
+		"task_id", victim.TaskID,
+		"session_id", victim.SessionID,
+		"urgent_task_id", urgentID,
+	)
+	p.Enqueue([]string{urgentID})
+}
+
+// resumePreempted respawns preempted tasks, oldest first, while slots are
+// free. A task waits while the urgent task it made room for is still
+// enqueued, so the urgent task gets the slot first.
+func (p *Pool) resumePreempted() {
+	for {
+		p.mu.Lock()
+		i := slices.IndexFunc(p.preempted, func(t preemptedTask) bool {
+			return !slices.Contains(p.priority, t.urgentID)
+		})
+		if i < 0 || p.runningCount() >= p.config.PoolSize {
+			p.mu.Unlock()
+			return
+		}
+		t := p.preempted[i]
+		p.preempted = slices.Delete(p.preempted, i, i+1)
+		p.mu.Unlock()
+
+		p.taskLog(t.taskID).Info("resuming preempted task", "task_id", t.taskID, "role", t.role, "session_id", t.sessionID)
+		p.respawn(t.taskID, t.role, t.sessionID)
+	}
+}
@@ -0,0 +1,142 @@
+package daemon
+
+import (
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPreemptionStopsLowestPriorityAgent(t *testing.T) {
+	var mu sync.Mutex
+	releases := map[int]func(){}
+	nextPID := 100
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		proc, release := newFakeProcess(nextPID)
+		releases[nextPID] = sync.OnceFunc(release)
+		nextPID++
+		return proc, nil
+	}
+	release := func(pid int) {
+		mu.Lock()
+		rel := releases[pid]
+		mu.Unlock()
+		rel()
+	}
+
+	pool := testPool(t, progRunner(testTaskMeta), starter)
+	pool.config.Preempt = true
+	var stopped []int
+	pool.stopProcess = func(pid int) error {
+		mu.Lock()
+		stopped = append(stopped, pid)
+		mu.Unlock()
+		release(pid)
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	taskCh := make(chan []Task)
+	go pool.Run(ctx, taskCh)
+
+	// Fill the pool with a P2 and a P3 agent.
+	taskCh <- []Task{{ID: "ts-mid", Priority: 2}, {ID: "ts-low", Priority: 3}}
+	waitFor(t, func() bool { return len(pool.Status()) == 2 })
+	pool.mu.Lock()
+	pool.agents["ts-low"].SessionID = "ses-low"
+	pool.mu.Unlock()
+
+	// A P1 task waits like any other; a P0 task preempts the P3 agent.
+	taskCh <- []Task{{ID: "ts-high", Priority: 1}, {ID: "ts-urgent", Priority: 0}}
+	waitFor(t, func() bool {
+		_, running := runningAgent(pool, "ts-urgent")
+		return running
+	})
+	mu.Lock()
+	if len(stopped) != 1 || stopped[0] != 101 {
+		t.Errorf("stopped pids = %v, want only the P3 agent (101)", stopped)
+	}
+	mu.Unlock()
+	if _, running := runningAgent(pool, "ts-low"); running {
+		t.Error("preempted task still running")
+	}
+	pool.mu.RLock()
+	yielded := pool.isYielded("ts-low")
+	pool.mu.RUnlock()
+	if !yielded {
+		t.Error("preempted task is not waiting to resume")
+	}
+	if _, running := runningAgent(pool, "ts-high"); running {
+		t.Error("P1 task started, want it left waiting")
+	}
+
+	// Within the cooldown, another urgent task waits instead of preempting.
+	taskCh <- []Task{{ID: "ts-urgent2", Priority: 0}}
+	time.Sleep(50 * time.Millisecond)
+	mu.Lock()
+	if len(stopped) != 1 {
+		t.Errorf("stopped pids = %v, want no second preemption within the cooldown", stopped)
+	}
+	mu.Unlock()
+
+	// When the urgent agent finishes, the preempted task resumes its
+	// session ahead of the queued tasks.
+	urgent, _ := runningAgent(pool, "ts-urgent")
+	release(urgent.PID)
+	waitFor(t, func() bool { return len(pool.Status()) == 1 })
+	taskCh <- []Task{{ID: "ts-high", Priority: 1}, {ID: "ts-urgent2", Priority: 0}}
+	waitFor(t, func() bool {
+		_, running := runningAgent(pool, "ts-low")
+		return running
+	})
+	if _, running := runningAgent(pool, "ts-high"); running {
+		t.Error("queued task took the slot before the preempted task resumed")
+	}
+	if low, _ := runningAgent(pool, "ts-low"); low.SessionID != "ses-low" {
+		t.Errorf("resumed session = %q, want ses-low", low.SessionID)
+	}
+	if got := pool.History(); len(got) < 3 {
+		t.Errorf("history = %+v, want ts-mid, ts-low, and ts-urgent", got)
+	}
+	pool.mu.RLock()
+	crashes := pool.crashes
+	pool.mu.RUnlock()
+	if n := crashes; n != 0 {
+		t.Errorf("crashes = %d, want 0 (preemption is not a crash)", n)
+	}
+}
+
+func TestPreemptionOffByDefault(t *testing.T) {
+	stopped := false
+	pool := testPool(t, progRunner(testTaskMeta), func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		proc, _ := newFakeProcess(100)
+		return proc, nil
+	})
+	pool.stopProcess = func(int) error { stopped = true; return nil }
+	pool.ctx = context.Background()
+
+	pool.schedule(context.Background(), []Task{{ID: "ts-a", Priority: 3}, {ID: "ts-b", Priority: 3}})
+	pool.schedule(context.Background(), []Task{{ID: "ts-urgent", Priority: 0}})
+	if stopped {
+		t.Error("agent stopped with preemption off")
+	}
+	if got := pool.SkipReasons()["ts-urgent"]; !strings.HasPrefix(got, "pool full") {
+		t.Errorf("skip reason = %q, want pool full", got)
+	}
+}
+
+// runningAgent returns a copy of the agent working on taskID.
+func runningAgent(p *Pool, taskID string) (Agent, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	a, ok := p.agents[taskID]
+	if !ok {
+		return Agent{}, false
+	}
+	return *a, true
+}
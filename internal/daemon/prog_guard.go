@@ -0,0 +1,42 @@
+package daemon
+
+import "context"
+
+// progReadCommands are the prog subcommands that only read the database.
+// Everything else (start, done, block, ...) is treated as a write.
+var progReadCommands = map[string]bool{
+	"show":  true,
+	"list":  true,
+	"ready": true,
+}
+
+// isProgWrite reports whether a command invocation mutates prog's database.
+func isProgWrite(name string, args []string) bool {
+	if name != "prog" || len(args) == 0 {
+		return false
+	}
+	return !progReadCommands[args[0]]
+}
+
+// guardProgWrites wraps runner so at most limit prog write commands run at
+// once. prog keeps its tasks in SQLite, and concurrent writers from the
+// pool's claims and the reconciler fail with "database is locked". Reads
+// and non-prog commands are not limited.
+func guardProgWrites(runner CommandRunner, limit int) CommandRunner {
+	if limit <= 0 {
+		limit = DefaultProgWriteConcurrency
+	}
+	sem := make(chan struct{}, limit)
+	return func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if !isProgWrite(name, args) {
+			return runner(ctx, name, args...)
+		}
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		defer func() { <-sem }()
+		return runner(ctx, name, args...)
+	}
+}
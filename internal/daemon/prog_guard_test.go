@@ -0,0 +1,64 @@
+package daemon
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGuardProgWritesSerializesClaims(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	release := make(chan struct{})
+	entered := make(chan string, 2)
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if args[0] != "start" {
+			return []byte("{}"), nil
+		}
+		n := inFlight.Add(1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		entered <- args[1]
+		<-release
+		inFlight.Add(-1)
+		return []byte("Started"), nil
+	}
+
+	work := NewProgWorkSource(guardProgWrites(runner, 1))
+	var wg sync.WaitGroup
+	for _, id := range []string{"ts-aaa", "ts-bbb"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := work.Claim(context.Background(), id, "testproject"); err != nil {
+				t.Errorf("Claim(%s): %v", id, err)
+			}
+		}()
+	}
+
+	<-entered // the first claim holds the guard
+	select {
+	case id := <-entered:
+		t.Fatalf("claim %s entered prog while another claim was running", id)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Reads are not held up by a write in progress.
+	if _, err := work.GetMeta(context.Background(), "ts-ccc", "testproject"); err != nil {
+		t.Fatalf("GetMeta while a claim is running: %v", err)
+	}
+
+	release <- struct{}{}
+	<-entered // the second claim gets its turn
+	release <- struct{}{}
+	wg.Wait()
+
+	if got := maxInFlight.Load(); got != 1 {
+		t.Errorf("max concurrent claims = %d, want 1", got)
+	}
+}
@@ -0,0 +1,67 @@
+package daemon
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/baiirun/aetherflow/internal/sessions"
+)
+
+// projectLockPath returns the lock file a daemon holds for project in dir.
+// An empty dir uses the session registry's default directory.
+func projectLockPath(dir, project string) (string, error) {
+	if dir == "" {
+		var err error
+		dir, err = sessions.DefaultDir()
+		if err != nil {
+			return "", err
+		}
+	}
+	return filepath.Join(dir, "daemon-"+project+".lock"), nil
+}
+
+// acquireProjectLock takes an exclusive flock on project's lock file, so
+// two daemons on different listen addresses can't both schedule the same
+// prog project. It fails at once when another daemon holds the lock,
+// naming that daemon's PID when the file records it. The lock is released
+// by the returned func, or by the kernel if the daemon dies.
+func acquireProjectLock(dir, project string) (func(), error) {
+	path, err := projectLockPath(dir, project)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("creating project lock dir: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("opening project lock: %w", err)
+	}
+	if err := syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		_ = f.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			holder := "another daemon"
+			if data, readErr := os.ReadFile(path); readErr == nil {
+				if pid, convErr := strconv.Atoi(strings.TrimSpace(string(data))); convErr == nil {
+					holder = fmt.Sprintf("another daemon (pid %d)", pid)
+				}
+			}
+			return nil, fmt.Errorf("%s is already running for project %q (lock %s)", holder, project, path)
+		}
+		return nil, fmt.Errorf("locking project %q: %w", project, err)
+	}
+	// Record the holder for the error above. Best-effort: the flock, not
+	// the contents, is what excludes other daemons.
+	if err := f.Truncate(0); err == nil {
+		_, _ = f.WriteAt([]byte(strconv.Itoa(os.Getpid())+"\n"), 0)
+	}
+	return func() {
+		_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+		_ = f.Close()
+	}, nil
+}
@@ -0,0 +1,156 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/baiirun/aetherflow/internal/protocol"
+	"github.com/baiirun/aetherflow/internal/sessions"
+)
+
+// ProjectSelector picks the project a pool RPC acts on. Empty means the
+// primary project (Config.Project).
+type ProjectSelector struct {
+	Project string `json:"project,omitempty"`
+}
+
+// projectRuntime is the scheduling machinery for one prog project: its
+// poller and the pool that runs its tasks.
+type projectRuntime struct {
+	project string
+	poller  *Poller
+	pool    *Pool
+}
+
+// newProjectRuntime builds the poller and pool for project from the shared
+// daemon config. Crash retry counts persist per project.
+func newProjectRuntime(cfg Config, project string, store *sessions.Store, names *protocol.NameGenerator, log *slog.Logger) *projectRuntime {
+	cfg.Project = project
+	pool := NewPool(cfg, cfg.Runner, cfg.Starter, log)
+	pool.sstore = store
+	pool.names = names
+	rs, err := openRetryStore(cfg.SessionDir, project)
+	if err == nil {
+		err = pool.restoreRetries(rs)
+	}
+	if err != nil && log != nil {
+		log.Warn("crash retry counts will not persist across restarts", "project", project, "error", err)
+	}
+	return &projectRuntime{
+		project: project,
+		poller:  NewPoller(project, cfg.PollInterval, cfg.Runner, log),
+		pool:    pool,
+	}
+}
+
+// projectNames lists the scheduled projects, primary first.
+func (d *Daemon) projectNames() []string {
+	if d.config.Project == "" {
+		return nil
+	}
+	return append([]string{d.config.Project}, d.config.Projects...)
+}
+
+// pools returns every project's pool, primary first. The primary is always
+// d.pool; d.projects supplies the rest.
+func (d *Daemon) pools() []*Pool {
+	var out []*Pool
+	if d.pool != nil {
+		out = append(out, d.pool)
+	}
+	for _, rt := range d.projects[min(1, len(d.projects)):] {
+		out = append(out, rt.pool)
+	}
+	return out
+}
+
+// poolFor resolves an RPC's project selector to that project's pool. An
+// empty selector means the primary project.
+func (d *Daemon) poolFor(project string) (*Pool, error) {
+	if project == "" || project == d.config.Project {
+		if d.pool == nil {
+			return nil, fmt.Errorf("no pool configured")
+		}
+		return d.pool, nil
+	}
+	for _, rt := range d.projects {
+		if rt.project == project {
+			return rt.pool, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown project %q", project)
+}
+
+// poolForAgent returns the pool running the named agent, or nil.
+func (d *Daemon) poolForAgent(agentName string) *Pool {
+	for _, pool := range d.pools() {
+		if pool.TaskIDForAgent(agentName) != "" {
+			return pool
+		}
+	}
+	return nil
+}
+
+// buildAgentDetail runs BuildAgentDetail against the pool running the
+// agent, with prog lookups scoped to that pool's project.
+func (d *Daemon) buildAgentDetail(ctx context.Context, params StatusAgentParams) (*AgentDetail, error) {
+	pool, cfg := d.pool, d.config
+	if p := d.poolForAgent(params.AgentName); p != nil {
+		pool = p
+		cfg.Project = p.Project()
+	}
+	return BuildAgentDetail(ctx, pool, d.spawns, d.sessionStore(), d.events, cfg, d.config.Runner, params)
+}
+
+// buildProjectStatus builds the status for the selected project: the full
+// daemon status, with the per-project breakdown, for the primary project,
+// or that project's pool alone for any other. The spawn registry is
+// daemon-wide, so it is included either way.
+func (d *Daemon) buildProjectStatus(ctx context.Context, project string) (FullStatus, error) {
+	if project == "" || project == d.config.Project {
+		return d.buildFullStatus(ctx), nil
+	}
+	pool, err := d.poolFor(project)
+	if err != nil {
+		return FullStatus{}, err
+	}
+	cfg := d.config
+	cfg.Project = pool.Project()
+	status := BuildFullStatus(ctx, pool, d.spawns, d.sessionStore(), d.events, cfg, d.config.Runner)
+	if err := d.sessionStoreError(); err != nil {
+		status.SessionStoreError = err.Error()
+		status.Errors = append(status.Errors, fmt.Sprintf("session persistence degraded, registry unavailable: %v", err))
+	}
+	return status, nil
+}
+
+// addProjectBreakdown fills status.Projects: the primary project from the
+// top-level fields, then each other project's pool. Their partial errors
+// are added to status.Errors with the project name.
+func (d *Daemon) addProjectBreakdown(ctx context.Context, status *FullStatus) {
+	status.Projects = []ProjectStatus{{
+		Project:           status.Project,
+		PoolSize:          status.PoolSize,
+		PoolMode:          status.PoolMode,
+		Agents:            status.Agents,
+		Queue:             status.Queue,
+		CompletedLastHour: status.CompletedLastHour,
+	}}
+	for _, rt := range d.projects[1:] {
+		cfg := d.config
+		cfg.Project = rt.project
+		ps := BuildFullStatus(ctx, rt.pool, nil, d.sessionStore(), d.events, cfg, d.config.Runner)
+		for _, e := range ps.Errors {
+			status.Errors = append(status.Errors, fmt.Sprintf("%s: %s", rt.project, e))
+		}
+		status.Projects = append(status.Projects, ProjectStatus{
+			Project:           rt.project,
+			PoolSize:          ps.PoolSize,
+			PoolMode:          ps.PoolMode,
+			Agents:            ps.Agents,
+			Queue:             ps.Queue,
+			CompletedLastHour: ps.CompletedLastHour,
+		})
+	}
+}
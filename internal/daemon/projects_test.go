@@ -0,0 +1,158 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDaemonSchedulesEachProjectInItsOwnPool(t *testing.T) {
+	proc, release := newFakeProcess(1234)
+	defer release()
+
+	var mu sync.Mutex
+	claims := map[string]string{} // task ID -> project passed to prog start
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if len(args) >= 4 && args[0] == "start" && args[2] == "-p" {
+			mu.Lock()
+			claims[args[1]] = args[3]
+			mu.Unlock()
+			return []byte("Started"), nil
+		}
+		return progRunner(testTaskMeta)(ctx, name, args...)
+	}
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		return proc, nil
+	}
+
+	d := New(Config{
+		Project:      "alpha",
+		Projects:     []string{"beta"},
+		PoolSize:     1,
+		PollInterval: time.Second,
+		SpawnCmd:     "fake-agent",
+		SessionDir:   t.TempDir(),
+		Runner:       runner,
+		Starter:      starter,
+		Logger:       testLogger(),
+	})
+	if got := d.projectNames(); strings.Join(got, ",") != "alpha,beta" {
+		t.Fatalf("projectNames() = %v, want [alpha beta]", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	alpha, err := d.poolFor("")
+	if err != nil {
+		t.Fatalf("poolFor(\"\"): %v", err)
+	}
+	beta, err := d.poolFor("beta")
+	if err != nil {
+		t.Fatalf("poolFor(beta): %v", err)
+	}
+	if alpha == beta {
+		t.Fatal("projects share a pool")
+	}
+	if alpha.names != beta.names {
+		t.Error("pools have separate name generators; agent names can collide across projects")
+	}
+	if _, err := d.poolFor("gamma"); err == nil || !strings.Contains(err.Error(), `unknown project "gamma"`) {
+		t.Errorf("poolFor(gamma) error = %v, want unknown project", err)
+	}
+
+	// Each pool has its own slot, so both projects run a task at once.
+	alpha.schedule(ctx, []Task{{ID: "ts-a", Priority: 1, Title: "A"}})
+	beta.schedule(ctx, []Task{{ID: "ts-b", Priority: 1, Title: "B"}})
+
+	if len(alpha.Status()) != 1 || len(beta.Status()) != 1 {
+		t.Fatalf("agents = %d/%d, want 1/1", len(alpha.Status()), len(beta.Status()))
+	}
+	mu.Lock()
+	if claims["ts-a"] != "alpha" || claims["ts-b"] != "beta" {
+		t.Errorf("claims = %v, want ts-a on alpha and ts-b on beta", claims)
+	}
+	mu.Unlock()
+
+	agent := beta.Status()[0].ID
+	if got := d.poolForAgent(string(agent)); got != beta {
+		t.Errorf("poolForAgent(%s) did not return the beta pool", agent)
+	}
+}
+
+func TestStatusSelectsProjectAndStreamsEveryPool(t *testing.T) {
+	d := New(Config{
+		Project:      "alpha",
+		Projects:     []string{"beta"},
+		PoolSize:     1,
+		PollInterval: time.Second,
+		SpawnCmd:     "fake-agent",
+		SpawnPolicy:  SpawnPolicyAuto,
+		SessionDir:   t.TempDir(),
+		Runner:       progRunner(testTaskMeta),
+		Logger:       testLogger(),
+	})
+	d.authToken = "test-token"
+	beta, err := d.poolFor("beta")
+	if err != nil {
+		t.Fatalf("poolFor(beta): %v", err)
+	}
+
+	resp := d.handleStatusFull(context.Background(), ProjectSelector{Project: "beta"})
+	if !resp.Success {
+		t.Fatalf("status for beta failed: %s", resp.Error)
+	}
+	var status FullStatus
+	if err := json.Unmarshal(resp.Result, &status); err != nil {
+		t.Fatal(err)
+	}
+	if status.Project != "beta" || len(status.Projects) != 0 {
+		t.Errorf("status for beta = project %q with %d project rows, want beta alone", status.Project, len(status.Projects))
+	}
+	if resp := d.handleStatusFull(context.Background(), ProjectSelector{Project: "gamma"}); resp.Success {
+		t.Error("status for an unknown project succeeded")
+	}
+
+	// The unscoped stream follows every project's pool, not just the primary.
+	srv := httptest.NewServer(d.newHTTPHandler())
+	defer srv.Close()
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/api/v1/status/stream", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set(daemonAuthHeader, d.authToken)
+	stream, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Body.Close()
+
+	frames := make(chan FullStatus)
+	go func() {
+		defer close(frames)
+		dec := json.NewDecoder(stream.Body)
+		for {
+			var f FullStatus
+			if err := dec.Decode(&f); err != nil {
+				return
+			}
+			frames <- f
+		}
+	}()
+	<-frames
+	beta.Pause()
+	select {
+	case f := <-frames:
+		if len(f.Projects) != 2 || f.Projects[1].PoolMode != PoolPaused {
+			t.Errorf("frame after pausing beta = %+v, want beta paused", f.Projects)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no frame after a change in the beta pool")
+	}
+}
@@ -1,8 +1,10 @@
 package daemon
 
 import (
+	"errors"
 	"fmt"
 	"io/fs"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"strings"
@@ -82,7 +84,9 @@ const (
 //
 // When promptDir is empty, prompts are read from the embedded filesystem
 // compiled into the binary. When promptDir is set, prompts are read from
-// that filesystem path instead (for development/customization).
+// that filesystem path instead (for development/customization). An override
+// directory may be partial: a role whose template it lacks falls back to
+// the embedded prompt with a warning, so the spawn still goes ahead.
 //
 // Recognized variables:
 //   - {{task_id}} — the task identifier
@@ -101,23 +105,33 @@ func RenderPrompt(promptDir string, role Role, taskID string, solo bool) (string
 
 	filename := string(role) + ".md"
 
+	source := "embedded"
 	var data []byte
 	var err error
 
-	if promptDir == "" {
-		// Read from embedded filesystem.
-		data, err = fs.ReadFile(promptsFS, "prompts/"+filename)
-		if err != nil {
-			return "", fmt.Errorf("reading embedded prompt %s: %w", filename, err)
-		}
-	} else {
+	if promptDir != "" {
 		// Read from filesystem override.
 		path := filepath.Join(promptDir, filename)
 		data, err = os.ReadFile(path)
-		if err != nil {
+		switch {
+		case err == nil:
+			source = path
+		case errors.Is(err, fs.ErrNotExist):
+			slog.Warn("prompt override missing, using embedded prompt",
+				"role", role,
+				"path", path,
+			)
+		default:
 			return "", fmt.Errorf("reading prompt %s: %w", path, err)
 		}
 	}
+	if data == nil {
+		// Read from embedded filesystem.
+		data, err = fs.ReadFile(promptsFS, "prompts/"+filename)
+		if err != nil {
+			return "", fmt.Errorf("reading embedded prompt %s: %w", filename, err)
+		}
+	}
 
 	// Select landing instructions based on mode.
 	landSteps := landStepsNormal
@@ -135,10 +149,6 @@ func RenderPrompt(promptDir string, role Role, taskID string, solo bool) (string
 	// Catch template typos (e.g., "{{ task_id }}" with spaces) that would
 	// leave unresolved variables in the prompt.
 	if strings.Contains(rendered, "{{") {
-		source := "embedded"
-		if promptDir != "" {
-			source = filepath.Join(promptDir, filename)
-		}
 		return "", fmt.Errorf("unresolved template variable in %s", source)
 	}
 
@@ -0,0 +1,50 @@
+package daemon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// PromptRedaction controls how much of an af spawn prompt the daemon keeps
+// in its spawn registry, where af status and the TUI show it. The agent
+// always receives the full prompt; only the daemon's copy is redacted.
+type PromptRedaction string
+
+const (
+	// PromptRedactionNone keeps the whole prompt (up to maxSpawnPromptLen).
+	PromptRedactionNone PromptRedaction = "none"
+	// PromptRedactionFirstLine keeps only the first line of the prompt.
+	PromptRedactionFirstLine PromptRedaction = "first_line"
+	// PromptRedactionHash keeps only a SHA-256 of the prompt, enough to
+	// tell whether two spawns ran the same prompt.
+	PromptRedactionHash PromptRedaction = "hash"
+)
+
+// Validate reports whether r is a known redaction mode. Empty means none.
+func (r PromptRedaction) Validate() error {
+	switch r {
+	case "", PromptRedactionNone, PromptRedactionFirstLine, PromptRedactionHash:
+		return nil
+	}
+	return fmt.Errorf("spawn_prompt_redaction must be one of [%s, %s, %s], got %q",
+		PromptRedactionNone, PromptRedactionFirstLine, PromptRedactionHash, r)
+}
+
+// Apply returns the part of prompt the daemon should store.
+func (r PromptRedaction) Apply(prompt string) string {
+	switch r {
+	case PromptRedactionFirstLine:
+		line, rest, _ := strings.Cut(prompt, "\n")
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(rest) != "" {
+			line += " [...]"
+		}
+		return line
+	case PromptRedactionHash:
+		sum := sha256.Sum256([]byte(prompt))
+		return "sha256:" + hex.EncodeToString(sum[:])
+	}
+	return prompt
+}
@@ -124,15 +124,46 @@ func TestRenderPromptFilesystemOverride(t *testing.T) {
 	}
 }
 
-func TestRenderPromptFilesystemMissingFile(t *testing.T) {
+func TestRenderPromptFilesystemMissingFileFallsBackToEmbedded(t *testing.T) {
 	dir := t.TempDir()
 
-	_, err := RenderPrompt(dir, RoleWorker, "ts-abc123", false)
-	if err == nil {
-		t.Fatal("expected error for missing prompt file, got nil")
+	got, err := RenderPrompt(dir, RoleWorker, "ts-abc123", false)
+	if err != nil {
+		t.Fatalf("RenderPrompt returned error: %v", err)
+	}
+	want, err := RenderPrompt("", RoleWorker, "ts-abc123", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Error("missing override should render the embedded worker prompt")
+	}
+}
+
+func TestRenderPromptPartialOverrideDir(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "worker.md"), []byte("custom worker {{task_id}}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	worker, err := RenderPrompt(dir, RoleWorker, "ts-abc123", false)
+	if err != nil {
+		t.Fatalf("RenderPrompt(worker) returned error: %v", err)
+	}
+	if worker != "custom worker ts-abc123\n" {
+		t.Errorf("worker prompt = %q, want the override", worker)
+	}
+
+	planner, err := RenderPrompt(dir, RolePlanner, "ts-abc123", false)
+	if err != nil {
+		t.Fatalf("RenderPrompt(planner) returned error: %v", err)
+	}
+	want, err := RenderPrompt("", RolePlanner, "ts-abc123", false)
+	if err != nil {
+		t.Fatal(err)
 	}
-	if !strings.Contains(err.Error(), "worker.md") {
-		t.Errorf("error should mention the filename, got: %v", err)
+	if planner != want {
+		t.Error("planner prompt should render from the embedded template")
 	}
 }
 
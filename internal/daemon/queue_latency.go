@@ -0,0 +1,91 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// DefaultQueueLatencyWindow is the span queue latency covers when the
+// caller doesn't pick one.
+const DefaultQueueLatencyWindow = time.Hour
+
+// QueueLatencyParams selects the project and window for queue latency.
+// A zero Window means DefaultQueueLatencyWindow.
+type QueueLatencyParams struct {
+	ProjectSelector
+	Window time.Duration `json:"window,omitempty"`
+}
+
+// QueueLatencyResult summarizes how long tasks waited in the ready queue
+// before their first agent started. Only tasks first started within the
+// window and seen by a poll count as samples.
+type QueueLatencyResult struct {
+	Project       string  `json:"project"`
+	WindowSeconds float64 `json:"window_seconds"`
+	Samples       int     `json:"samples"`
+	P50Seconds    float64 `json:"p50_seconds"`
+	P90Seconds    float64 `json:"p90_seconds"`
+	P99Seconds    float64 `json:"p99_seconds"`
+}
+
+// QueueLatency computes wait-time percentiles for tasks the pool first
+// started within the last window.
+func (p *Pool) QueueLatency(window time.Duration) QueueLatencyResult {
+	cutoff := p.clock.Now().Add(-window)
+
+	p.mu.RLock()
+	var waits []time.Duration
+	for _, entry := range p.history {
+		if entry.QueuedAt.IsZero() || entry.StartedAt.Before(cutoff) {
+			continue
+		}
+		waits = append(waits, entry.StartedAt.Sub(entry.QueuedAt))
+	}
+	p.mu.RUnlock()
+
+	result := QueueLatencyResult{
+		Project:       p.Project(),
+		WindowSeconds: window.Seconds(),
+		Samples:       len(waits),
+	}
+	if len(waits) == 0 {
+		return result
+	}
+	slices.Sort(waits)
+	result.P50Seconds = percentile(waits, 50).Seconds()
+	result.P90Seconds = percentile(waits, 90).Seconds()
+	result.P99Seconds = percentile(waits, 99).Seconds()
+	return result
+}
+
+// percentile returns the nearest-rank pct-th percentile of sorted, which
+// must be non-empty and in ascending order.
+func percentile(sorted []time.Duration, pct float64) time.Duration {
+	rank := int(math.Ceil(pct / 100 * float64(len(sorted))))
+	rank = min(max(rank, 1), len(sorted))
+	return sorted[rank-1]
+}
+
+// handleQueueLatency reports queue wait-time percentiles for a project.
+func (d *Daemon) handleQueueLatency(params QueueLatencyParams) *Response {
+	if params.Window < 0 {
+		return &Response{Success: false, Error: "window must be positive"}
+	}
+	pool, err := d.poolFor(params.Project)
+	if err != nil {
+		return &Response{Success: false, Error: err.Error()}
+	}
+	window := params.Window
+	if window == 0 {
+		window = DefaultQueueLatencyWindow
+	}
+
+	data, err := json.Marshal(pool.QueueLatency(window))
+	if err != nil {
+		return &Response{Success: false, Error: fmt.Sprintf("marshal queue latency: %v", err)}
+	}
+	return &Response{Success: true, Result: data}
+}
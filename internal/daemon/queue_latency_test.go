@@ -0,0 +1,108 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestPercentile(t *testing.T) {
+	// 1s..100s, so the nearest-rank pth percentile is p seconds.
+	waits := make([]time.Duration, 100)
+	for i := range waits {
+		waits[i] = time.Duration(i+1) * time.Second
+	}
+
+	tests := []struct {
+		name   string
+		sorted []time.Duration
+		pct    float64
+		want   time.Duration
+	}{
+		{"p50 of 100", waits, 50, 50 * time.Second},
+		{"p90 of 100", waits, 90, 90 * time.Second},
+		{"p99 of 100", waits, 99, 99 * time.Second},
+		{"single sample", []time.Duration{7 * time.Second}, 99, 7 * time.Second},
+		{"p50 of 4 rounds down to rank 2", []time.Duration{1, 2, 3, 4}, 50, 2},
+		{"p90 of 4 rounds up to the max", []time.Duration{1, 2, 3, 4}, 90, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentile(tt.sorted, tt.pct); got != tt.want {
+				t.Errorf("percentile(%v) = %v, want %v", tt.pct, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPoolQueueLatency(t *testing.T) {
+	pool := testPool(t, progRunner(testTaskMeta), nil)
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	pool.clock = newFakeClock(now)
+
+	// Waits of 10s..100s for tasks started in the last hour, plus ones the
+	// result must ignore: started before the window, and never queued.
+	for i := 1; i <= 10; i++ {
+		started := now.Add(-time.Duration(i) * time.Minute)
+		id := fmt.Sprintf("ts-%d", i)
+		pool.history[id] = &TaskHistoryEntry{
+			TaskID:    id,
+			QueuedAt:  started.Add(-time.Duration(i*10) * time.Second),
+			StartedAt: started,
+		}
+	}
+	pool.history["old"] = &TaskHistoryEntry{TaskID: "old", QueuedAt: now.Add(-3 * time.Hour), StartedAt: now.Add(-2 * time.Hour)}
+	pool.history["reclaimed"] = &TaskHistoryEntry{TaskID: "reclaimed", StartedAt: now.Add(-time.Minute)}
+
+	got := pool.QueueLatency(time.Hour)
+	want := QueueLatencyResult{
+		Project:       "testproject",
+		WindowSeconds: 3600,
+		Samples:       10,
+		P50Seconds:    50,
+		P90Seconds:    90,
+		P99Seconds:    100,
+	}
+	if got != want {
+		t.Errorf("QueueLatency = %+v, want %+v", got, want)
+	}
+
+	if empty := pool.QueueLatency(30 * time.Second); empty.Samples != 0 || empty.P50Seconds != 0 {
+		t.Errorf("QueueLatency(30s) = %+v, want no samples", empty)
+	}
+}
+
+func TestScheduleRecordsQueuedAt(t *testing.T) {
+	proc, release := newFakeProcess(1234)
+	defer release()
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		return proc, nil
+	}
+
+	pool := testPool(t, progRunner(testTaskMeta), starter)
+	pool.config.PoolSize = 0
+	clock := newFakeClock(time.Now())
+	pool.clock = clock
+	ctx := context.Background()
+
+	// Seen while the pool is full, started 45s later once a slot opens.
+	tasks := []Task{{ID: "ts-abc", Priority: 1, Title: "Do it"}}
+	pool.schedule(ctx, tasks)
+	queued := clock.Now()
+	clock.Advance(45 * time.Second)
+	pool.config.PoolSize = 1
+	pool.schedule(ctx, tasks)
+
+	history := pool.History()
+	if len(history) != 1 {
+		t.Fatalf("history = %d entries, want 1", len(history))
+	}
+	if !history[0].QueuedAt.Equal(queued) {
+		t.Errorf("QueuedAt = %v, want %v", history[0].QueuedAt, queued)
+	}
+	if got := pool.QueueLatency(time.Hour); got.Samples != 1 || got.P50Seconds != 45 {
+		t.Errorf("QueueLatency = %+v, want one 45s sample", got)
+	}
+}
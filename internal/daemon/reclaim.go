@@ -2,9 +2,21 @@ package daemon
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"slices"
 )
 
+// SpawnClaimLabel is the prog label af spawn --pick puts on the task it
+// claims. The spawn registry is in memory and only knows about spawns that
+// registered since the daemon started, so the label is what keeps Reclaim
+// and the orphan scan from starting a pool agent on a task a spawn is
+// already working. The label is never removed; the claim ends when the
+// registered spawn exits, or after Config.SpawnClaimTTL with no running
+// spawn holding the task.
+const SpawnClaimLabel = "af-spawn"
+
 // fetchInProgressTasks queries prog for tasks currently in_progress for this project.
 // Returns only tasks (not epics) since epics don't have agents assigned to them.
 func fetchInProgressTasks(ctx context.Context, project string, runner CommandRunner, log *slog.Logger) ([]Task, error) {
@@ -46,6 +58,7 @@ func (p *Pool) Reclaim(ctx context.Context) {
 	tasks, err := fetchInProgressTasks(ctx, p.config.Project, p.runner, p.log)
 	if err != nil {
 		p.log.Error("reclaim: failed to fetch in_progress tasks", "error", err)
+		p.recordError("error", OpErrorReclaimFailed, "", "", fmt.Errorf("fetching in_progress tasks: %w", err))
 		return
 	}
 
@@ -54,16 +67,29 @@ func (p *Pool) Reclaim(ctx context.Context) {
 		return
 	}
 
+	reclaimed := p.reclaimTasks(ctx, tasks)
+	if reclaimed > 0 {
+		p.log.Info("reclaim complete", "reclaimed", reclaimed, "total_orphans", len(tasks))
+	}
+}
+
+// reclaimTasks respawns agents for orphaned in_progress tasks, skipping
+// tasks that already have an agent or exhausted their retries, until the
+// pool is full. It reports how many agents were respawned.
+func (p *Pool) reclaimTasks(ctx context.Context, tasks []Task) int {
 	reclaimed := 0
 	skipped := 0
 	for _, task := range tasks {
 		if ctx.Err() != nil {
-			return
+			return reclaimed
 		}
 
 		p.mu.RLock()
 		_, alreadyRunning := p.agents[task.ID]
 		count := p.runningCount()
+		size := p.config.PoolSize
+		attempts := p.retries[task.ID]
+		maxRetries := p.config.MaxRetries
 		p.mu.RUnlock()
 
 		if alreadyRunning {
@@ -71,7 +97,21 @@ func (p *Pool) Reclaim(ctx context.Context) {
 			continue
 		}
 
-		if count >= p.config.PoolSize {
+		// Crash counts survive restarts, so a task that exhausted its
+		// retries before the daemon went down stays down. A task that never
+		// crashed is reclaimed even with crash respawn disabled: the daemon
+		// going down is not an agent crash.
+		if attempts > 0 && attempts > maxRetries {
+			p.log.Warn("reclaim: skipping task, max retries exhausted",
+				"task_id", task.ID,
+				"attempts", attempts,
+				"max_retries", maxRetries,
+			)
+			skipped++
+			continue
+		}
+
+		if count >= size {
 			p.log.Info("reclaim: pool full, deferring remaining orphans",
 				"reclaimed", reclaimed,
 				"deferred", len(tasks)-reclaimed-skipped,
@@ -86,9 +126,16 @@ func (p *Pool) Reclaim(ctx context.Context) {
 				"task_id", task.ID,
 				"error", err,
 			)
+			p.recordError("error", OpErrorRespawnFailed, task.ID, "", fmt.Errorf("fetching task metadata: %w", err))
 			continue
 		}
-		role := InferRole(meta)
+		if p.claimedBySpawn(task.ID, meta) {
+			p.log.Info("reclaim: skipping task claimed by af spawn", "task_id", task.ID)
+			skipped++
+			continue
+		}
+		role := p.inferRole(meta)
+		p.setTaskEnv(task.ID, TaskEnv(meta, p.taskEnvFields()))
 
 		// Look up the session ID from the registry so the reclaimed agent
 		// can resume the existing opencode session instead of starting fresh.
@@ -103,7 +150,132 @@ func (p *Pool) Reclaim(ctx context.Context) {
 		reclaimed++
 	}
 
-	if reclaimed > 0 {
-		p.log.Info("reclaim complete", "reclaimed", reclaimed, "total_orphans", len(tasks))
+	return reclaimed
+}
+
+// RecoverOrphans is the periodic counterpart to Reclaim. It finds tasks
+// that are in_progress in prog but have no agent in the pool — for
+// example when spawn claimed a task and the agent then failed to start —
+// and respawns them without waiting for a daemon restart.
+//
+// A task is only recovered once it has looked orphaned on two consecutive
+// scans, so the short windows where a task is legitimately between agents
+// (a crash awaiting respawn, a rolling restart) are not mistaken for
+// orphans. Tasks the pool ran to an end this session (completed, failed,
+// retired, or out of retries) are left alone, as are retired and yielded
+// tasks, tasks mid-claim, and tasks an af spawn --pick claimed. Stranded
+// tasks, whose respawn was skipped or failed, are recovered.
+func (p *Pool) RecoverOrphans(ctx context.Context) {
+	p.mu.RLock()
+	mode := p.mode
+	p.mu.RUnlock()
+	if mode == PoolPaused {
+		return
 	}
+
+	tasks, err := fetchInProgressTasks(ctx, p.config.Project, p.runner, p.log)
+	if err != nil {
+		p.log.Warn("orphan scan: failed to fetch in_progress tasks", "error", err)
+		p.recordError("warn", OpErrorReclaimFailed, "", "", fmt.Errorf("fetching in_progress tasks: %w", err))
+		return
+	}
+
+	p.mu.Lock()
+	candidates := make(map[string]bool)
+	var confirmed []Task
+	for _, task := range tasks {
+		if !p.isOrphan(task.ID) {
+			continue
+		}
+		candidates[task.ID] = true
+		if p.orphans[task.ID] {
+			confirmed = append(confirmed, task)
+		}
+	}
+	p.orphans = candidates
+	p.mu.Unlock()
+
+	// Drop tasks an af spawn --pick claimed, which the registry can't vouch
+	// for after a restart or when the spawn never registered. A failed
+	// lookup keeps the task; reclaimTasks reports the error.
+	confirmed = slices.DeleteFunc(confirmed, func(task Task) bool {
+		meta, err := FetchTaskMeta(ctx, task.ID, p.config.Project, p.runner)
+		return err == nil && p.claimedBySpawn(task.ID, meta)
+	})
+	if len(confirmed) == 0 {
+		return
+	}
+	p.log.Warn("orphan scan: found claimed tasks with no agent", "count", len(confirmed))
+	for _, task := range confirmed {
+		p.recordError("warn", OpErrorOrphanFound, task.ID, "", errors.New("task is in_progress in prog but has no agent; recovering"))
+	}
+	if reclaimed := p.reclaimTasks(ctx, confirmed); reclaimed > 0 {
+		p.log.Info("orphan scan complete", "reclaimed", reclaimed, "total_orphans", len(confirmed))
+	}
+}
+
+// isOrphan reports whether an in_progress task has no agent and nothing in
+// the pool accounts for it. Caller must hold p.mu.
+func (p *Pool) isOrphan(taskID string) bool {
+	if _, ok := p.agents[taskID]; ok {
+		return false
+	}
+	if _, ok := p.stopping[taskID]; ok {
+		return false
+	}
+	if p.claiming[taskID] || p.retired[taskID] || p.isYielded(taskID) {
+		return false
+	}
+	if entry, ok := p.history[taskID]; ok && entry.Outcome != TaskOutcomeRunning && entry.Outcome != TaskOutcomeStranded {
+		return false
+	}
+	if p.heldElsewhere != nil && p.heldElsewhere(taskID) {
+		return false
+	}
+	if _, ok := p.spawnClaimed[taskID]; ok && p.spawnClaimHolds(taskID) {
+		return false
+	}
+	return true
+}
+
+// claimedBySpawn reports whether meta carries SpawnClaimLabel and the claim
+// still holds, remembering the task so later orphan scans skip it without
+// asking prog.
+func (p *Pool) claimedBySpawn(taskID string, meta TaskMeta) bool {
+	if !slices.Contains(meta.Labels, SpawnClaimLabel) {
+		return false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.spawnClaimed[taskID]; !ok {
+		p.spawnClaimed[taskID] = p.clock.Now()
+	}
+	if p.spawnClaimHolds(taskID) {
+		return true
+	}
+	p.log.Info("spawn claim released or expired", "task_id", taskID)
+	return false
+}
+
+// spawnClaimHolds reports whether a spawn claim on taskID still keeps the
+// pool off the task: a running registered spawn holds it, one the daemon
+// saw exit lets it go, and a claim no registered spawn accounts for expires
+// SpawnClaimTTL after the daemon last saw it live. A running spawn renews
+// the claim. Caller must hold p.mu.
+func (p *Pool) spawnClaimHolds(taskID string) bool {
+	now := p.clock.Now()
+	if p.heldElsewhere != nil && p.heldElsewhere(taskID) {
+		p.spawnClaimed[taskID] = now
+		return true
+	}
+	if p.spawnReleased(taskID) {
+		return false
+	}
+	return now.Sub(p.spawnClaimed[taskID]) < p.config.SpawnClaimTTL
+}
+
+// spawnReleased reports whether the spawn that claimed taskID is known to
+// have exited.
+func (p *Pool) spawnReleased(taskID string) bool {
+	return p.releasedElsewhere != nil && p.releasedElsewhere(taskID)
 }
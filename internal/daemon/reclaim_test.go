@@ -6,10 +6,12 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"path/filepath"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/baiirun/aetherflow/internal/sessions"
 )
@@ -68,7 +70,7 @@ func TestFetchInProgressTasksCommandError(t *testing.T) {
 
 func TestReclaimSpawnsOrphanedTasks(t *testing.T) {
 	var spawnCount atomic.Int32
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		n := spawnCount.Add(1)
 		proc, _ := newFakeProcess(int(n) * 100)
 		return proc, nil
@@ -130,7 +132,7 @@ func TestReclaimSkipsAlreadyRunning(t *testing.T) {
 	defer release()
 
 	var spawnCount atomic.Int32
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		spawnCount.Add(1)
 		return proc, nil
 	}
@@ -176,7 +178,7 @@ func TestReclaimSkipsAlreadyRunning(t *testing.T) {
 
 func TestReclaimRespectsPoolSize(t *testing.T) {
 	var spawnCount atomic.Int32
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		n := spawnCount.Add(1)
 		proc, _ := newFakeProcess(int(n) * 100)
 		return proc, nil
@@ -225,7 +227,7 @@ func TestReclaimRespectsPoolSize(t *testing.T) {
 
 func TestReclaimPartialMetadataFailure(t *testing.T) {
 	var spawnCount atomic.Int32
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		n := spawnCount.Add(1)
 		proc, _ := newFakeProcess(int(n) * 100)
 		return proc, nil
@@ -288,7 +290,7 @@ func TestReclaimPartialMetadataFailure(t *testing.T) {
 
 func TestReclaimSkipsWhenPaused(t *testing.T) {
 	var spawnCount atomic.Int32
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		spawnCount.Add(1)
 		proc, _ := newFakeProcess(999)
 		return proc, nil
@@ -349,7 +351,7 @@ func TestReclaimResumesSessionFromRegistry(t *testing.T) {
 	var spawnCount atomic.Int32
 	spawnCmds := make([]string, 0)
 
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		spawnCount.Add(1)
 		proc, _ := newFakeProcess(int(spawnCount.Load()) * 100)
 		mu.Lock()
@@ -424,7 +426,7 @@ func TestReclaimWithoutRegistryOmitsSessionFlag(t *testing.T) {
 	var spawnCount atomic.Int32
 	spawnCmds := make([]string, 0)
 
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		spawnCount.Add(1)
 		proc, _ := newFakeProcess(int(spawnCount.Load()) * 100)
 		mu.Lock()
@@ -481,3 +483,427 @@ func TestReclaimWithoutRegistryOmitsSessionFlag(t *testing.T) {
 		t.Errorf("reclaim spawn should not have --session when no registry record exists, got: %q", spawnCmds[0])
 	}
 }
+
+func TestReclaimRespectsPersistedRetries(t *testing.T) {
+	dir := t.TempDir()
+
+	orphanedTasks := []progListItem{
+		{ID: "ts-loop", Title: "Crash loop", Type: "task", Status: "in_progress"},
+		{ID: "ts-fresh", Title: "Fresh", Type: "task", Status: "in_progress"},
+	}
+	orphanedJSON, _ := json.Marshal(orphanedTasks)
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if len(args) >= 1 && args[0] == "list" {
+			return orphanedJSON, nil
+		}
+		if len(args) >= 1 && args[0] == "show" {
+			meta := fmt.Sprintf(`{"id":"%s","type":"task","definition_of_done":"Do it","labels":[]}`, args[1])
+			return []byte(meta), nil
+		}
+		return nil, fmt.Errorf("unexpected: %v", args)
+	}
+
+	cfg := Config{
+		Project:    "testproject",
+		PoolSize:   3,
+		SpawnCmd:   "fake-agent",
+		MaxRetries: 1,
+	}
+	cfg.ApplyDefaults()
+
+	// First daemon run: ts-loop crashes until its retries are exhausted.
+	crashing := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		proc, release := newFakeProcessWithError(100, fmt.Errorf("exit status 1"))
+		release()
+		return proc, nil
+	}
+	first := NewPool(cfg, runner, crashing, slog.Default())
+	rs, err := openRetryStore(dir, cfg.Project)
+	if err != nil {
+		t.Fatalf("openRetryStore: %v", err)
+	}
+	if err := first.restoreRetries(rs); err != nil {
+		t.Fatalf("restoreRetries: %v", err)
+	}
+	first.SetContext(context.Background())
+	first.respawn("ts-loop", RoleWorker, "")
+
+	waitFor(t, func() bool {
+		first.mu.RLock()
+		defer first.mu.RUnlock()
+		return first.retries["ts-loop"] > cfg.MaxRetries && len(first.agents) == 0
+	})
+
+	// Second daemon run: a fresh pool restores the counts from disk.
+	var mu sync.Mutex
+	var spawned []string
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		mu.Lock()
+		spawned = append(spawned, prompt)
+		mu.Unlock()
+		proc, _ := newFakeProcess(200)
+		return proc, nil
+	}
+	second := NewPool(cfg, runner, starter, slog.Default())
+	rs2, err := openRetryStore(dir, cfg.Project)
+	if err != nil {
+		t.Fatalf("openRetryStore: %v", err)
+	}
+	if err := second.restoreRetries(rs2); err != nil {
+		t.Fatalf("restoreRetries: %v", err)
+	}
+	second.SetContext(context.Background())
+	second.Reclaim(context.Background())
+
+	waitFor(t, func() bool { return len(second.Status()) == 1 })
+
+	agents := second.Status()
+	if agents[0].TaskID != "ts-fresh" {
+		t.Errorf("reclaimed %q, want only ts-fresh (ts-loop exhausted its retries before restart)", agents[0].TaskID)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(spawned) != 1 {
+		t.Errorf("spawn count = %d, want 1", len(spawned))
+	}
+}
+
+func TestCleanExitClearsPersistedRetries(t *testing.T) {
+	dir := t.TempDir()
+	rs, err := openRetryStore(dir, "testproject")
+	if err != nil {
+		t.Fatalf("openRetryStore: %v", err)
+	}
+	if err := rs.save(func() retryCounts { return retryCounts{Retries: map[string]int{"ts-abc": 1}} }); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	proc, release := newFakeProcess(1234)
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		return proc, nil
+	}
+	pool := testPool(t, progRunner(testTaskMeta), starter)
+	if err := pool.restoreRetries(rs); err != nil {
+		t.Fatalf("restoreRetries: %v", err)
+	}
+	pool.SetContext(context.Background())
+	pool.respawn("ts-abc", RoleWorker, "")
+	waitFor(t, func() bool { return len(pool.Status()) == 1 })
+
+	release() // clean exit
+	waitFor(t, func() bool {
+		counts, err := rs.load()
+		return err == nil && len(counts.Retries) == 0
+	})
+}
+
+func TestRetryStorePersistsStartupFailures(t *testing.T) {
+	dir := t.TempDir()
+	rs, err := openRetryStore(dir, "testproject")
+	if err != nil {
+		t.Fatalf("openRetryStore: %v", err)
+	}
+
+	var release func()
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		var proc *fakeProcess
+		proc, release = newFakeProcessWithError(100, exitCodeError(1))
+		return proc, nil
+	}
+	pool := testPool(t, progRunner(testTaskMeta), starter)
+	pool.config.MinHealthyUptime = time.Minute
+	pool.config.MaxStartupRetries = 2
+	if err := pool.restoreRetries(rs); err != nil {
+		t.Fatalf("restoreRetries: %v", err)
+	}
+	pool.SetContext(context.Background())
+	pool.respawn("ts-abc", RoleWorker, "")
+	waitFor(t, func() bool { return len(pool.Status()) == 1 })
+
+	release() // dies at once: startup failure 1, respawned
+	waitFor(t, func() bool {
+		counts, err := rs.load()
+		return err == nil && counts.Startup["ts-abc"] == 1
+	})
+
+	// A fresh pool picks the count up where the last one left off.
+	next := testPool(t, progRunner(testTaskMeta), nil)
+	if err := next.restoreRetries(rs); err != nil {
+		t.Fatalf("restoreRetries: %v", err)
+	}
+	next.mu.RLock()
+	got := next.startup["ts-abc"]
+	next.mu.RUnlock()
+	if got != 1 {
+		t.Errorf("restored startup failures = %d, want 1", got)
+	}
+}
+
+func TestOpenRetryStoreRejectsUnsafeProjectNames(t *testing.T) {
+	dir := t.TempDir()
+	for _, project := range []string{"../escape", "a/b", ".hidden", "with space"} {
+		if _, err := openRetryStore(dir, project); err == nil {
+			t.Errorf("openRetryStore(%q) = nil error, want invalid project name", project)
+		}
+	}
+	rs, err := openRetryStore(dir, "")
+	if err != nil {
+		t.Fatalf("openRetryStore(\"\"): %v", err)
+	}
+	if got := filepath.Base(rs.path); got != "retries.json" {
+		t.Errorf("retry file for no project = %s, want retries.json", got)
+	}
+}
+
+func TestRecoverOrphansRespawnsClaimedTaskMidRun(t *testing.T) {
+	var starts atomic.Int32
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		// The first start fails after the claim, leaving the task orphaned.
+		if starts.Add(1) == 1 {
+			return nil, fmt.Errorf("exec: agent binary missing")
+		}
+		proc, _ := newFakeProcess(300)
+		return proc, nil
+	}
+
+	inProgress, _ := json.Marshal([]progListItem{
+		{ID: "ts-orphan", Title: "Claimed, never started", Type: "task", Status: "in_progress"},
+	})
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		switch {
+		case len(args) >= 1 && args[0] == "list":
+			return inProgress, nil
+		case len(args) >= 1 && args[0] == "start":
+			return []byte("Started"), nil
+		case len(args) >= 2 && args[0] == "show":
+			return fmt.Appendf(nil, `{"id":"%s","type":"task","labels":[]}`, args[1]), nil
+		}
+		return nil, fmt.Errorf("unexpected: %v", args)
+	}
+
+	pool := testPool(t, runner, starter)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool.ctx = ctx
+
+	pool.schedule(ctx, []Task{{ID: "ts-orphan"}})
+	if got := len(pool.Status()); got != 0 {
+		t.Fatalf("agents after failed start = %d, want 0", got)
+	}
+
+	// The first scan only marks the task as a candidate.
+	pool.RecoverOrphans(ctx)
+	if got := len(pool.Status()); got != 0 {
+		t.Fatalf("agents after first scan = %d, want 0", got)
+	}
+
+	// The second scan confirms it and respawns.
+	pool.RecoverOrphans(ctx)
+	agents := pool.Status()
+	if len(agents) != 1 || agents[0].TaskID != "ts-orphan" {
+		t.Fatalf("agents after second scan = %+v, want ts-orphan", agents)
+	}
+
+	// With the agent running, later scans leave the task alone.
+	pool.RecoverOrphans(ctx)
+	pool.RecoverOrphans(ctx)
+	if got := starts.Load(); got != 2 {
+		t.Errorf("starts = %d, want 2", got)
+	}
+}
+
+func TestRecoverOrphansSkipsFinishedTasks(t *testing.T) {
+	var starts atomic.Int32
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		starts.Add(1)
+		proc, release := newFakeProcess(400)
+		release() // exits cleanly right away
+		return proc, nil
+	}
+
+	inProgress, _ := json.Marshal([]progListItem{
+		{ID: "ts-done", Title: "Exited but still in_progress", Type: "task", Status: "in_progress"},
+	})
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		switch {
+		case len(args) >= 1 && args[0] == "list":
+			return inProgress, nil
+		case len(args) >= 1 && args[0] == "start":
+			return []byte("Started"), nil
+		case len(args) >= 2 && args[0] == "show":
+			return fmt.Appendf(nil, `{"id":"%s","type":"task","labels":[]}`, args[1]), nil
+		}
+		return nil, fmt.Errorf("unexpected: %v", args)
+	}
+
+	pool := testPool(t, runner, starter)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool.ctx = ctx
+
+	pool.schedule(ctx, []Task{{ID: "ts-done"}})
+	waitFor(t, func() bool { return len(pool.Status()) == 0 && starts.Load() == 1 })
+
+	pool.RecoverOrphans(ctx)
+	pool.RecoverOrphans(ctx)
+	if got := starts.Load(); got != 1 {
+		t.Errorf("starts = %d, want 1 (a task that exited cleanly is not an orphan)", got)
+	}
+}
+
+func TestRecoverOrphansSkipsTaskHeldBySpawn(t *testing.T) {
+	var starts atomic.Int32
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		starts.Add(1)
+		proc, _ := newFakeProcess(500)
+		return proc, nil
+	}
+
+	inProgress, _ := json.Marshal([]progListItem{
+		{ID: "ts-picked", Title: "Claimed by af spawn --pick", Type: "task", Status: "in_progress"},
+	})
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		switch {
+		case len(args) >= 1 && args[0] == "list":
+			return inProgress, nil
+		case len(args) >= 2 && args[0] == "show":
+			return fmt.Appendf(nil, `{"id":"%s","type":"task","labels":[]}`, args[1]), nil
+		}
+		return nil, fmt.Errorf("unexpected: %v", args)
+	}
+
+	spawns := NewSpawnRegistry()
+	if err := spawns.Register(SpawnEntry{SpawnID: "spawn-1", PID: 600, State: SpawnRunning, TaskID: "ts-picked"}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	pool := testPool(t, runner, starter)
+	pool.heldElsewhere = spawns.HoldsTask
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool.ctx = ctx
+
+	pool.RecoverOrphans(ctx)
+	pool.RecoverOrphans(ctx)
+	if got := starts.Load(); got != 0 {
+		t.Fatalf("starts while spawn holds the task = %d, want 0", got)
+	}
+
+	// Once the spawn exits without finishing, the task is an orphan again.
+	spawns.MarkExited("spawn-1")
+	pool.RecoverOrphans(ctx)
+	pool.RecoverOrphans(ctx)
+	if got := starts.Load(); got != 1 {
+		t.Fatalf("starts after spawn exited = %d, want 1", got)
+	}
+}
+
+func TestReclaimSkipsTaskLabeledBySpawn(t *testing.T) {
+	var starts atomic.Int32
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		starts.Add(1)
+		proc, _ := newFakeProcess(700)
+		return proc, nil
+	}
+
+	inProgress, _ := json.Marshal([]progListItem{
+		{ID: "ts-picked", Title: "Claimed by af spawn --pick", Type: "task", Status: "in_progress"},
+	})
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		switch {
+		case len(args) >= 1 && args[0] == "list":
+			return inProgress, nil
+		case len(args) >= 2 && args[0] == "show":
+			return fmt.Appendf(nil, `{"id":"%s","type":"task","labels":["%s"]}`, args[1], SpawnClaimLabel), nil
+		}
+		return nil, fmt.Errorf("unexpected: %v", args)
+	}
+
+	// An empty registry, as after a restart or with af spawn --no-register.
+	spawns := NewSpawnRegistry()
+	pool := testPool(t, runner, starter)
+	pool.heldElsewhere = spawns.HoldsTask
+	pool.releasedElsewhere = spawns.ReleasedTask
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool.ctx = ctx
+
+	pool.Reclaim(ctx)
+	pool.RecoverOrphans(ctx)
+	pool.RecoverOrphans(ctx)
+	pool.RecoverOrphans(ctx)
+	if got := starts.Load(); got != 0 {
+		t.Fatalf("starts for a task labeled %s = %d, want 0", SpawnClaimLabel, got)
+	}
+
+	// A spawn the daemon saw exit no longer protects the task.
+	if err := spawns.Register(SpawnEntry{SpawnID: "spawn-1", PID: 800, State: SpawnExited, ExitedAt: time.Now(), TaskID: "ts-picked"}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	pool.RecoverOrphans(ctx)
+	pool.RecoverOrphans(ctx)
+	if got := starts.Load(); got != 1 {
+		t.Fatalf("starts after the spawn exited = %d, want 1", got)
+	}
+}
+
+func TestOrphanScanRecoversAbandonedSpawnClaim(t *testing.T) {
+	var starts atomic.Int32
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		starts.Add(1)
+		proc, _ := newFakeProcess(700)
+		return proc, nil
+	}
+
+	inProgress, _ := json.Marshal([]progListItem{
+		{ID: "ts-picked", Title: "Claimed by af spawn --pick", Type: "task", Status: "in_progress"},
+	})
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		switch {
+		case len(args) >= 1 && args[0] == "list":
+			return inProgress, nil
+		case len(args) >= 2 && args[0] == "show":
+			return fmt.Appendf(nil, `{"id":"%s","type":"task","labels":["%s"]}`, args[1], SpawnClaimLabel), nil
+		}
+		return nil, fmt.Errorf("unexpected: %v", args)
+	}
+
+	spawns := NewSpawnRegistry()
+	pool := testPool(t, runner, starter)
+	pool.heldElsewhere = spawns.HoldsTask
+	pool.releasedElsewhere = spawns.ReleasedTask
+	clock := newFakeClock(time.Now())
+	pool.clock = clock
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	pool.ctx = ctx
+
+	// A registered spawn still running renews the claim past the TTL.
+	if err := spawns.Register(SpawnEntry{SpawnID: "spawn-1", PID: 800, State: SpawnRunning, TaskID: "ts-picked"}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	pool.RecoverOrphans(ctx)
+	clock.Advance(pool.config.SpawnClaimTTL + time.Minute)
+	pool.RecoverOrphans(ctx)
+	pool.RecoverOrphans(ctx)
+	if got := starts.Load(); got != 0 {
+		t.Fatalf("starts while the spawn runs = %d, want 0", got)
+	}
+
+	// The daemon restarts while the spawn dies unseen, so the new registry
+	// has nothing to account for the label.
+	restarted := NewSpawnRegistry()
+	pool.heldElsewhere = restarted.HoldsTask
+	pool.releasedElsewhere = restarted.ReleasedTask
+	pool.RecoverOrphans(ctx)
+	pool.RecoverOrphans(ctx)
+	if got := starts.Load(); got != 0 {
+		t.Fatalf("starts within the claim TTL = %d, want 0", got)
+	}
+
+	clock.Advance(pool.config.SpawnClaimTTL)
+	pool.RecoverOrphans(ctx)
+	pool.RecoverOrphans(ctx)
+	if got := starts.Load(); got != 1 {
+		t.Fatalf("starts after the claim expired = %d, want 1", got)
+	}
+}
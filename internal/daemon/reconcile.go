@@ -24,7 +24,7 @@ type mergeResult struct {
 //
 // Returns merged=false if the branch exists but hasn't been merged yet.
 func isBranchMerged(ctx context.Context, taskID string, runner CommandRunner) (mergeResult, error) {
-	branch := "af/" + taskID
+	branch := agentBranch(taskID)
 
 	// Check if the branch exists first.
 	_, err := runner(ctx, "git", "rev-parse", "--verify", branch)
@@ -52,7 +52,7 @@ func isBranchMerged(ctx context.Context, taskID string, runner CommandRunner) (m
 // its terminal `done` state after the branch lands on main.
 func (d *Daemon) reconcileReviewing(ctx context.Context) {
 	d.log.Info("reconciler started",
-		"project", d.config.Project,
+		"projects", d.projectNames(),
 		"interval", d.config.ReconcileInterval,
 	)
 
@@ -70,30 +70,91 @@ func (d *Daemon) reconcileReviewing(ctx context.Context) {
 	}
 }
 
-// reconcileOnce runs a single reconciliation pass.
-func (d *Daemon) reconcileOnce(ctx context.Context) {
+// ReviewingTask is a task in "reviewing" status with the merge state of its
+// branch, as isBranchMerged sees it.
+type ReviewingTask struct {
+	ID            string `json:"id"`
+	Title         string `json:"title"`
+	Branch        string `json:"branch"`
+	Merged        bool   `json:"merged"`
+	BranchMissing bool   `json:"branch_missing,omitempty"` // counted as merged
+}
+
+// CheckReviewingTasks lists the project's reviewing tasks and checks each
+// one's branch against main, without changing any task. It first fetches
+// origin/main so the check reflects merges made on the remote.
+func CheckReviewingTasks(ctx context.Context, project string, runner CommandRunner, log *slog.Logger) ([]ReviewingTask, error) {
 	// Update local main ref from remote so merge-base checks reflect actual
 	// state. Without this, local main is stale and the reconciler becomes a
 	// permanent no-op for PR-based workflows where merges happen on GitHub.
-	_, err := d.config.Runner(ctx, "git", "fetch", "origin", "main")
-	if err != nil {
+	if _, err := runner(ctx, "git", "fetch", "origin", "main"); err != nil {
 		// No remote configured (e.g. local-only repos) — continue with
 		// local state. This is expected for solo-mode setups.
-		d.log.Debug("reconcile: git fetch origin main failed (no remote?)", "error", err)
+		log.Debug("reconcile: git fetch origin main failed (no remote?)", "error", err)
 	}
 
-	tasks, err := fetchReviewingTasks(ctx, d.config.Project, d.config.Runner, d.log)
+	tasks, err := fetchReviewingTasks(ctx, project, runner, log)
+	if err != nil {
+		return nil, err
+	}
+
+	checked := make([]ReviewingTask, 0, len(tasks))
+	for _, task := range tasks {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		result, err := isBranchMerged(ctx, task.ID, runner)
+		if err != nil {
+			log.Warn("reconcile: failed to check branch status",
+				"task", task.ID,
+				"error", err,
+			)
+			continue
+		}
+		checked = append(checked, ReviewingTask{
+			ID:            task.ID,
+			Title:         task.Title,
+			Branch:        agentBranch(task.ID),
+			Merged:        result.merged,
+			BranchMissing: result.branchMissing,
+		})
+	}
+	return checked, nil
+}
+
+// FilterReviewing returns the tasks whose Merged field equals merged:
+// true for tasks the reconciler would mark done, false for open reviews.
+func FilterReviewing(tasks []ReviewingTask, merged bool) []ReviewingTask {
+	var out []ReviewingTask
+	for _, t := range tasks {
+		if t.Merged == merged {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// reconcileOnce runs a single reconciliation pass over every project.
+func (d *Daemon) reconcileOnce(ctx context.Context) {
+	for _, project := range d.projectNames() {
+		d.reconcileProject(ctx, project)
+	}
+}
+
+// reconcileProject reconciles one project's reviewing tasks.
+func (d *Daemon) reconcileProject(ctx context.Context, project string) {
+	tasks, err := CheckReviewingTasks(ctx, project, d.config.Runner, d.log)
 	if err != nil {
 		// Context cancellation is expected during shutdown.
 		if ctx.Err() != nil {
 			return
 		}
-		d.log.Error("reconcile: failed to fetch reviewing tasks", "error", err)
+		d.log.Error("reconcile: failed to fetch reviewing tasks", "project", project, "error", err)
 		return
 	}
 
 	if len(tasks) == 0 {
-		d.log.Debug("reconcile: no reviewing tasks")
+		d.log.Debug("reconcile: no reviewing tasks", "project", project)
 		return
 	}
 
@@ -103,27 +164,18 @@ func (d *Daemon) reconcileOnce(ctx context.Context) {
 			return
 		}
 
-		result, err := isBranchMerged(ctx, task.ID, d.config.Runner)
-		if err != nil {
-			d.log.Warn("reconcile: failed to check branch status",
-				"task", task.ID,
-				"error", err,
-			)
-			continue
-		}
-
-		if !result.merged {
+		if !task.Merged {
 			d.log.Debug("reconcile: branch not yet merged",
 				"task", task.ID,
-				"branch", "af/"+task.ID,
+				"branch", task.Branch,
 			)
 			continue
 		}
 
-		if result.branchMissing {
+		if task.BranchMissing {
 			d.log.Warn("reconcile: branch missing, treating as merged",
 				"task", task.ID,
-				"branch", "af/"+task.ID,
+				"branch", task.Branch,
 			)
 		}
 
@@ -146,6 +198,7 @@ func (d *Daemon) reconcileOnce(ctx context.Context) {
 
 	if completed > 0 {
 		d.log.Info("reconcile complete",
+			"project", project,
 			"completed", completed,
 			"total_reviewing", len(tasks),
 		)
@@ -238,6 +238,34 @@ func TestReconcileOnce_MultipleTasks_MixedState(t *testing.T) {
 	}
 }
 
+func TestCheckReviewingTasksOpenReport(t *testing.T) {
+	r := &reconcileRunner{
+		reviewingTasks: []progListItem{
+			{ID: "ts-merged", Title: "Merged one", Status: "reviewing"},
+			{ID: "ts-pending", Title: "Still pending", Status: "reviewing"},
+		},
+		branchExists:   map[string]bool{"af/ts-merged": true, "af/ts-pending": true},
+		mergedBranches: map[string]bool{"af/ts-merged": true},
+	}
+
+	tasks, err := CheckReviewingTasks(context.Background(), "testproject", r.run, slog.Default())
+	if err != nil {
+		t.Fatalf("CheckReviewingTasks: %v", err)
+	}
+
+	open := FilterReviewing(tasks, false)
+	if len(open) != 1 || open[0].ID != "ts-pending" || open[0].Branch != "af/ts-pending" {
+		t.Errorf("open = %+v, want only ts-pending", open)
+	}
+	merged := FilterReviewing(tasks, true)
+	if len(merged) != 1 || merged[0].ID != "ts-merged" {
+		t.Errorf("merged = %+v, want only ts-merged", merged)
+	}
+	if calls := r.getDoneCalls(); len(calls) != 0 {
+		t.Errorf("expected no prog done calls from a report, got %v", calls)
+	}
+}
+
 func TestReconcileOnce_ContextCancellation(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel() // cancel immediately
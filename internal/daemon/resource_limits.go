@@ -0,0 +1,84 @@
+package daemon
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/baiirun/aetherflow/internal/protocol"
+)
+
+// ResourceLimits caps what one agent process may use, so a runaway agent
+// can't starve the host. Zero fields are unlimited.
+type ResourceLimits struct {
+	// MemoryMB caps the process's virtual address space (RLIMIT_AS).
+	// Runtimes that reserve large address ranges up front need headroom
+	// well above their resident size.
+	MemoryMB int `yaml:"memory_mb"`
+	// CPUSeconds caps the process's total CPU time (RLIMIT_CPU). The kernel
+	// sends SIGXCPU once it is used up.
+	CPUSeconds int `yaml:"cpu_seconds"`
+}
+
+// RoleLimits maps a pool role to the limits its agents run under.
+type RoleLimits map[Role]ResourceLimits
+
+// IsZero reports whether no limit is set.
+func (l ResourceLimits) IsZero() bool {
+	return l.MemoryMB == 0 && l.CPUSeconds == 0
+}
+
+// agentLimits returns the role's resource limits for an agent about to
+// start. Where they aren't supported a warning is logged and the agent
+// runs unlimited.
+func (p *Pool) agentLimits(taskID string, agentID protocol.AgentID, role Role) ResourceLimits {
+	limits := p.config.RoleLimits[role]
+	if limits.IsZero() {
+		return limits
+	}
+	if !resourceLimitsSupported {
+		p.taskLog(taskID).Warn("resource limits not supported on this platform, agent runs unlimited",
+			"task_id", taskID,
+			"agent_id", agentID,
+			"role", role,
+		)
+		return ResourceLimits{}
+	}
+	p.taskLog(taskID).Debug("starting agent under resource limits",
+		"task_id", taskID,
+		"agent_id", agentID,
+		"memory_mb", limits.MemoryMB,
+		"cpu_seconds", limits.CPUSeconds,
+	)
+	return limits
+}
+
+// limitArgs wraps an agent's argv in a shell that sets the limits with
+// ulimit and then execs it, so they are in place before the agent runs and
+// cover everything it forks. ulimit without -S or -H lowers the hard limit
+// too, so the agent can't raise them again. If a limit can't be set the
+// shell exits instead of running the agent unlimited.
+func limitArgs(args []string, limits ResourceLimits) []string {
+	var script []string
+	if limits.MemoryMB > 0 {
+		script = append(script, fmt.Sprintf("ulimit -v %d", limits.MemoryMB<<10)) // KiB
+	}
+	if limits.CPUSeconds > 0 {
+		script = append(script, fmt.Sprintf("ulimit -t %d", limits.CPUSeconds))
+	}
+	script = append(script, `exec "$@"`)
+	return append([]string{"sh", "-c", strings.Join(script, " && "), "sh"}, args...)
+}
+
+// validateRoleLimits checks role_limits names a pool role and holds no
+// negative limits.
+func validateRoleLimits(limits RoleLimits) error {
+	for role, l := range limits {
+		if role != RolePlanner && role != RoleWorker {
+			return fmt.Errorf("role_limits: unknown role %q (want %s or %s)", role, RolePlanner, RoleWorker)
+		}
+		if l.MemoryMB < 0 || l.CPUSeconds < 0 {
+			return fmt.Errorf("role_limits: %s limits must be non-negative", role)
+		}
+	}
+	return nil
+}
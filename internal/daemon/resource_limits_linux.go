@@ -0,0 +1,8 @@
+//go:build linux
+
+package daemon
+
+// resourceLimitsSupported reports whether role_limits can be applied here.
+// limitArgs relies on the shell's ulimit -v and -t, which only take effect
+// as RLIMIT_AS and RLIMIT_CPU on Linux.
+const resourceLimitsSupported = true
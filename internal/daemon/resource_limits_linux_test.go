@@ -0,0 +1,45 @@
+//go:build linux
+
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestExecProcessStarterSetsLimitsBeforeExec(t *testing.T) {
+	// The agent reads its own limits: they are in place by the time its
+	// command runs, not applied from outside after it started.
+	var buf strings.Builder
+	proc, err := ExecProcessStarter(context.Background(), "sh -c", "cat /proc/self/limits", "steel_gloom", &buf,
+		StartOptions{Limits: ResourceLimits{MemoryMB: 512, CPUSeconds: 90}})
+	if err != nil {
+		t.Fatalf("ExecProcessStarter: %v", err)
+	}
+	if err := proc.Wait(); err != nil {
+		t.Fatalf("process exited with error: %v", err)
+	}
+
+	data := buf.String()
+	want := map[string]string{
+		"Max address space": fmt.Sprint(512 << 20),
+		"Max cpu time":      "90",
+	}
+	for _, line := range strings.Split(data, "\n") {
+		for name, limit := range want {
+			if !strings.HasPrefix(line, name) {
+				continue
+			}
+			fields := strings.Fields(strings.TrimPrefix(line, name))
+			if len(fields) < 2 || fields[0] != limit || fields[1] != limit {
+				t.Errorf("%s = %v, want soft and hard %s", name, fields, limit)
+			}
+			delete(want, name)
+		}
+	}
+	for name := range want {
+		t.Errorf("%s missing from /proc limits", name)
+	}
+}
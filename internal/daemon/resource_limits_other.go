@@ -0,0 +1,7 @@
+//go:build !linux
+
+package daemon
+
+// resourceLimitsSupported is false off Linux; the pool logs a warning and
+// runs agents without limits.
+const resourceLimitsSupported = false
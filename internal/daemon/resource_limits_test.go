@@ -0,0 +1,66 @@
+package daemon
+
+import (
+	"context"
+	"io"
+	"slices"
+	"testing"
+)
+
+func TestSpawnPassesRoleLimitsToStarter(t *testing.T) {
+	workerLimits := ResourceLimits{MemoryMB: 2048, CPUSeconds: 600}
+	tests := []struct {
+		name   string
+		limits RoleLimits
+		want   ResourceLimits
+	}{
+		{"limits for the task's role", RoleLimits{RoleWorker: workerLimits}, workerLimits},
+		{"no limits for the role", RoleLimits{RolePlanner: {MemoryMB: 512}}, ResourceLimits{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proc, release := newFakeProcess(4321)
+			defer release()
+			var got ResourceLimits
+			starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, opts StartOptions) (Process, error) {
+				got = opts.Limits
+				return proc, nil
+			}
+
+			pool := testPool(t, progRunner(testTaskMeta), starter)
+			pool.config.RoleLimits = tt.limits
+			pool.schedule(context.Background(), []Task{{ID: "ts-abc", Priority: 1, Title: "Do it"}})
+
+			if len(pool.Status()) != 1 {
+				t.Fatalf("agents = %d, want 1", len(pool.Status()))
+			}
+			want := tt.want
+			if !resourceLimitsSupported {
+				want = ResourceLimits{} // logged and run unlimited
+			}
+			if got != want {
+				t.Errorf("starter limits = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestLimitArgsSetsLimitsThenExecs(t *testing.T) {
+	got := limitArgs([]string{"opencode", "run", "do it"}, ResourceLimits{MemoryMB: 2, CPUSeconds: 60})
+	want := []string{"sh", "-c", `ulimit -v 2048 && ulimit -t 60 && exec "$@"`, "sh", "opencode", "run", "do it"}
+	if !slices.Equal(got, want) {
+		t.Errorf("limitArgs = %q, want %q", got, want)
+	}
+}
+
+func TestValidateRoleLimits(t *testing.T) {
+	if err := validateRoleLimits(RoleLimits{RoleWorker: {MemoryMB: 1024}, RolePlanner: {CPUSeconds: 60}}); err != nil {
+		t.Errorf("valid limits: %v", err)
+	}
+	if err := validateRoleLimits(RoleLimits{"reviewer": {MemoryMB: 1024}}); err == nil {
+		t.Error("unknown role accepted")
+	}
+	if err := validateRoleLimits(RoleLimits{RoleWorker: {CPUSeconds: -1}}); err == nil {
+		t.Error("negative limit accepted")
+	}
+}
@@ -0,0 +1,109 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/baiirun/aetherflow/internal/sessions"
+)
+
+// retryStore persists per-task crash and startup failure counts so a
+// daemon restart doesn't give a crash-looping task a fresh retry budget.
+// Counts live in one JSON file per project inside the session directory.
+type retryStore struct {
+	mu   sync.Mutex
+	dir  string
+	path string
+}
+
+// openRetryStore opens the retry file for project in dir. An empty dir
+// uses the session registry's default directory. The project name becomes
+// part of the file name, so it must be a valid project name.
+func openRetryStore(dir, project string) (*retryStore, error) {
+	if project != "" && !validProjectName.MatchString(project) {
+		return nil, fmt.Errorf("invalid project name %q for retry store", project)
+	}
+	if dir == "" {
+		var err error
+		dir, err = sessions.DefaultDir()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("creating retry store dir %s: %w", dir, err)
+	}
+	name := "retries.json"
+	if project != "" {
+		name = "retries-" + project + ".json"
+	}
+	return &retryStore{dir: dir, path: filepath.Join(dir, name)}, nil
+}
+
+// retryCounts is what the retry store holds: crash counts (Pool.retries)
+// and startup failure counts (Pool.startup), keyed by task ID.
+type retryCounts struct {
+	Retries map[string]int `json:"retries"`
+	Startup map[string]int `json:"startup,omitempty"`
+}
+
+// load returns the persisted counts. A missing file means no crashes have
+// been recorded.
+func (s *retryStore) load() (retryCounts, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	counts := retryCounts{Retries: map[string]int{}, Startup: map[string]int{}}
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return counts, nil
+	}
+	if err != nil {
+		return retryCounts{}, fmt.Errorf("reading retry store: %w", err)
+	}
+	if err := json.Unmarshal(data, &counts); err != nil {
+		return retryCounts{}, fmt.Errorf("parsing retry store %s: %w", s.path, err)
+	}
+	if counts.Retries == nil {
+		counts.Retries = map[string]int{}
+	}
+	if counts.Startup == nil {
+		counts.Startup = map[string]int{}
+	}
+	return counts, nil
+}
+
+// save replaces the persisted counts with snapshot(). The snapshot is taken
+// while holding the store lock so concurrent saves land in order.
+func (s *retryStore) save(snapshot func() retryCounts) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := json.MarshalIndent(snapshot(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling retry store: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".retries-*.json")
+	if err != nil {
+		return fmt.Errorf("creating temp retry file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("writing temp retry file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("closing temp retry file: %w", err)
+	}
+	if err := os.Rename(tmpPath, s.path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("renaming retry file: %w", err)
+	}
+	return nil
+}
@@ -3,7 +3,9 @@ package daemon
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 )
 
 // Role is the agent role assigned to a task.
@@ -22,6 +24,12 @@ type TaskMeta struct {
 	Type             string   `json:"type"`
 	DefinitionOfDone string   `json:"definition_of_done"`
 	Labels           []string `json:"labels"`
+	Status           string   `json:"status"`
+	Dependencies     []string `json:"dependencies"`
+
+	// Fields holds every top-level field of prog's JSON, for exporting
+	// fields TaskMeta doesn't model (see TaskEnv). Nil for the queue file.
+	Fields map[string]json.RawMessage `json:"-"`
 }
 
 // InferRole determines the agent role for a task.
@@ -34,7 +42,15 @@ func InferRole(_ TaskMeta) Role {
 	return RoleWorker
 }
 
+// ErrTaskNotFound is returned by task metadata lookups when the task no
+// longer exists (e.g. it was deleted from prog).
+var ErrTaskNotFound = errors.New("task not found")
+
 // FetchTaskMeta retrieves task metadata from prog via `prog show --json`.
+// A task prog reports as not found yields an error wrapping ErrTaskNotFound.
+// Only prog's own message for this task counts: other "not found" output,
+// such as a missing project or database, is a transient failure, and
+// treating it as gone would stop every running agent.
 func FetchTaskMeta(ctx context.Context, taskID string, project string, runner CommandRunner) (TaskMeta, error) {
 	if runner == nil {
 		runner = ExecCommandRunner
@@ -47,6 +63,9 @@ func FetchTaskMeta(ctx context.Context, taskID string, project string, runner Co
 
 	output, err := runner(ctx, "prog", args...)
 	if err != nil {
+		if isProgTaskNotFound(output, taskID) {
+			return TaskMeta{}, fmt.Errorf("prog show %s: %w (output: %s)", taskID, ErrTaskNotFound, string(output))
+		}
 		return TaskMeta{}, fmt.Errorf("prog show %s: %w (output: %s)", taskID, err, string(output))
 	}
 
@@ -54,6 +73,21 @@ func FetchTaskMeta(ctx context.Context, taskID string, project string, runner Co
 	if err := json.Unmarshal(output, &meta); err != nil {
 		return TaskMeta{}, fmt.Errorf("parsing prog show output for %s: %w", taskID, err)
 	}
+	// The same output decoded as an object, so the unmarshal above already
+	// proved it is valid JSON; a non-object just leaves Fields empty.
+	_ = json.Unmarshal(output, &meta.Fields)
 
 	return meta, nil
 }
+
+// isProgTaskNotFound reports whether prog's output is its task-not-found
+// error for taskID ("Error: task not found: <id>").
+func isProgTaskNotFound(output []byte, taskID string) bool {
+	for _, line := range strings.Split(string(output), "\n") {
+		line = strings.TrimPrefix(strings.TrimSpace(line), "Error: ")
+		if line == "task not found: "+taskID {
+			return true
+		}
+	}
+	return false
+}
@@ -2,6 +2,7 @@ package daemon
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
 )
@@ -115,3 +116,25 @@ func TestFetchTaskMetaNullDoD(t *testing.T) {
 		t.Errorf("DefinitionOfDone = %q, want empty string for null", meta.DefinitionOfDone)
 	}
 }
+
+func TestFetchTaskMetaOnlyTaskNotFoundIsGone(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		gone   bool
+	}{
+		{"task not found", "Error: task not found: ts-nope", true},
+		{"other task", "Error: task not found: ts-other", false},
+		{"project not found", "Error: project not found: myproject", false},
+		{"database not found", "Error: database not found at ~/.prog/prog.db", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := fakeRunner(tt.output, fmt.Errorf("exit status 1"))
+			_, err := FetchTaskMeta(context.Background(), "ts-nope", "myproject", runner)
+			if got := errors.Is(err, ErrTaskNotFound); got != tt.gone {
+				t.Errorf("errors.Is(err, ErrTaskNotFound) = %v, want %v (err: %v)", got, tt.gone, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// rollingRestartExitTimeout bounds how long a rolling restart waits for a
+// stopped agent to exit before giving up on the remaining agents.
+const rollingRestartExitTimeout = 30 * time.Second
+
+// errRollingRestartInProgress is returned when a rolling restart is requested
+// while another one is still working through the pool.
+var errRollingRestartInProgress = errors.New("rolling restart already in progress")
+
+// RollingRestart restarts every running agent one at a time so agents pick
+// up the current spawn command (e.g. after upgrading the agent binary).
+//
+// Each agent is stopped, then respawned on the same task with its session ID
+// carried forward, and the next agent is only touched once the replacement
+// is running. The restart runs in the background on the pool context; the
+// returned task IDs are the agents scheduled for restart, oldest first.
+func (p *Pool) RollingRestart() ([]string, error) {
+	if p.Mode() == PoolPaused {
+		return nil, fmt.Errorf("pool is paused; resume before restarting agents")
+	}
+	if p.ctx == nil {
+		return nil, fmt.Errorf("pool is not running")
+	}
+	if !p.rolling.CompareAndSwap(false, true) {
+		return nil, errRollingRestartInProgress
+	}
+
+	agents := p.Status()
+	sort.Slice(agents, func(i, j int) bool {
+		return agents[i].SpawnTime.Before(agents[j].SpawnTime)
+	})
+	taskIDs := make([]string, 0, len(agents))
+	for _, a := range agents {
+		taskIDs = append(taskIDs, a.TaskID)
+	}
+
+	go func() {
+		defer p.rolling.Store(false)
+		restarted, err := p.rollingRestart(p.ctx, taskIDs)
+		if err != nil {
+			p.log.Error("rolling restart aborted",
+				"restarted", restarted,
+				"total", len(taskIDs),
+				"error", err,
+			)
+			return
+		}
+		p.log.Info("rolling restart complete", "restarted", restarted, "total", len(taskIDs))
+	}()
+
+	return taskIDs, nil
+}
+
+// rollingRestart restarts the agents for the given tasks sequentially.
+// Tasks whose agent has already exited are skipped. Returns the number of
+// agents restarted; an error aborts the remaining restarts.
+func (p *Pool) rollingRestart(ctx context.Context, taskIDs []string) (int, error) {
+	restarted := 0
+	for _, taskID := range taskIDs {
+		if ctx.Err() != nil {
+			return restarted, ctx.Err()
+		}
+		ok, err := p.restartAgent(ctx, taskID)
+		if err != nil {
+			return restarted, fmt.Errorf("restarting %s: %w", taskID, err)
+		}
+		if ok {
+			restarted++
+		}
+	}
+	return restarted, nil
+}
+
+// restartAgent stops the agent working on taskID, waits for it to exit, and
+// respawns it with the same role and session. Returns false without error
+// when the task no longer has a running agent.
+func (p *Pool) restartAgent(ctx context.Context, taskID string) (bool, error) {
+	p.mu.Lock()
+	agent, ok := p.agents[taskID]
+	if !ok || agent.State != AgentRunning {
+		p.mu.Unlock()
+		return false, nil
+	}
+	snapshot := *agent
+	exited := make(chan struct{})
+	p.stopping[taskID] = exited
+	p.mu.Unlock()
+
+	p.log.Info("rolling restart: stopping agent",
+		"agent_id", snapshot.ID,
+		"task_id", taskID,
+		"pid", snapshot.PID,
+	)
+
+	if err := p.stopProcess(snapshot.PID); err != nil {
+		p.clearStopping(taskID, exited)
+		return false, fmt.Errorf("stopping agent %s (pid %d): %w", snapshot.ID, snapshot.PID, err)
+	}
+
+	select {
+	case <-exited:
+	case <-p.clock.After(rollingRestartExitTimeout):
+		// Fall back to normal crash handling if the agent exits later.
+		p.clearStopping(taskID, exited)
+		return false, fmt.Errorf("agent %s did not exit within %s", snapshot.ID, rollingRestartExitTimeout)
+	case <-ctx.Done():
+		p.clearStopping(taskID, exited)
+		return false, ctx.Err()
+	}
+
+	p.respawn(taskID, snapshot.Role, snapshot.SessionID)
+
+	p.mu.RLock()
+	replacement, up := p.agents[taskID]
+	p.mu.RUnlock()
+	if !up {
+		return false, fmt.Errorf("replacement agent for %s did not start", taskID)
+	}
+
+	p.log.Info("rolling restart: agent replaced",
+		"old_agent_id", snapshot.ID,
+		"agent_id", replacement.ID,
+		"task_id", taskID,
+		"resumed_session", snapshot.SessionID,
+	)
+	return true, nil
+}
+
+// clearStopping removes the intentional-stop marker for taskID if it is
+// still the one registered by the caller.
+func (p *Pool) clearStopping(taskID string, marker chan struct{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.stopping[taskID] == marker {
+		delete(p.stopping, taskID)
+	}
+}
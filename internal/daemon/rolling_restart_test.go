@@ -0,0 +1,136 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestRollingRestartRestartsAgentsOneAtATime(t *testing.T) {
+	var mu sync.Mutex
+	procs := make(map[int]*fakeProcess)
+	releases := make(map[int]func())
+	var events []string
+	var spawnCmds []string
+	nextPID := 100
+
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		nextPID++
+		proc, release := newFakeProcess(nextPID)
+		procs[nextPID] = proc
+		releases[nextPID] = release
+		events = append(events, "spawn")
+		spawnCmds = append(spawnCmds, spawnCmd)
+		return proc, nil
+	}
+
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if len(args) >= 1 && args[0] == "start" {
+			return []byte("Started"), nil
+		}
+		if len(args) >= 2 && args[0] == "show" {
+			return []byte(fmt.Sprintf(`{"id":"%s","type":"task","definition_of_done":"Do it","labels":[]}`, args[1])), nil
+		}
+		return nil, fmt.Errorf("unexpected: %v", args)
+	}
+
+	cfg := Config{
+		Project:    "testproject",
+		PoolSize:   2,
+		SpawnCmd:   "fake-agent",
+		MaxRetries: 3,
+	}
+	cfg.ApplyDefaults()
+	pool := NewPool(cfg, runner, starter, slog.Default())
+
+	// Record how many agents are running whenever one is stopped. If the
+	// restart were simultaneous, the second stop would see only one agent.
+	var runningAtStop []int
+	pool.stopProcess = func(pid int) error {
+		mu.Lock()
+		events = append(events, "stop")
+		runningAtStop = append(runningAtStop, len(pool.Status()))
+		proc, release := procs[pid], releases[pid]
+		mu.Unlock()
+
+		proc.err = fmt.Errorf("signal: terminated")
+		release()
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	taskCh := make(chan []Task, 1)
+	taskCh <- []Task{
+		{ID: "ts-1", Priority: 1, Title: "First"},
+		{ID: "ts-2", Priority: 1, Title: "Second"},
+	}
+	go pool.Run(ctx, taskCh)
+
+	waitFor(t, func() bool { return len(pool.Status()) == 2 })
+	pool.SetSessionID(string(pool.Status()[0].ID), "ses_first")
+
+	// Upgrade the spawn command before restarting.
+	pool.config.SpawnCmd = "fake-agent-v2"
+
+	tasks, err := pool.RollingRestart()
+	if err != nil {
+		t.Fatalf("RollingRestart: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("tasks = %v, want 2 entries", tasks)
+	}
+
+	waitFor(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(events) == 6 && !pool.rolling.Load()
+	})
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	want := []string{"spawn", "spawn", "stop", "spawn", "stop", "spawn"}
+	if strings.Join(events, ",") != strings.Join(want, ",") {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+	for i, n := range runningAtStop {
+		if n != 2 {
+			t.Errorf("stop %d saw %d running agents, want 2 (restarts must not overlap)", i, n)
+		}
+	}
+	for _, cmd := range spawnCmds[2:] {
+		if !strings.HasPrefix(cmd, "fake-agent-v2") {
+			t.Errorf("respawn cmd = %q, want current spawn command", cmd)
+		}
+	}
+	if !strings.Contains(strings.Join(spawnCmds[2:], "\n"), "--session ses_first") {
+		t.Errorf("respawn cmds = %v, want session carried forward", spawnCmds[2:])
+	}
+	if got := len(pool.Status()); got != 2 {
+		t.Errorf("running agents = %d, want 2", got)
+	}
+
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+	if len(pool.retries) != 0 {
+		t.Errorf("retries = %v, want none (intentional stops are not crashes)", pool.retries)
+	}
+}
+
+func TestRollingRestartRefusedWhenPaused(t *testing.T) {
+	pool := testPool(t, progRunner(testTaskMeta), nil)
+	pool.SetContext(context.Background())
+	pool.Pause()
+
+	if _, err := pool.RollingRestart(); err == nil {
+		t.Fatal("expected error when pool is paused")
+	}
+}
@@ -115,7 +115,7 @@ func (d *Daemon) handleEventsList(params EventsListParams) *Response {
 	// Format events into human-readable lines.
 	var lines []string
 	for _, ev := range evs {
-		line := FormatEvent(ev)
+		line := FormatEvent(ev, d.config.ToolInputFields)
 		if line != "" {
 			lines = append(lines, line)
 		}
@@ -137,16 +137,16 @@ func (d *Daemon) handleEventsList(params EventsListParams) *Response {
 // registry and returns the session routing metadata needed by clients.
 // Returns a zero value when the agent is not found or has no session ID yet.
 func (d *Daemon) resolveSessionMetadata(agentName string) SessionMetadata {
-	// Check pool first.
-	if d.pool != nil {
-		for _, a := range d.pool.Status() {
+	// Check pools first.
+	for _, pool := range d.pools() {
+		for _, a := range pool.Status() {
 			if string(a.ID) != agentName || a.SessionID == "" {
 				continue
 			}
-			return buildSessionMetadata(d.sstore, sessionMetadataFallback{
+			return buildSessionMetadata(d.sessionStore(), sessionMetadataFallback{
 				serverRef: d.config.ServerURL,
 				sessionID: a.SessionID,
-				project:   d.config.Project,
+				project:   pool.Project(),
 				origin:    sessions.OriginPool,
 				workRef:   a.TaskID,
 				agentID:   string(a.ID),
@@ -157,7 +157,7 @@ func (d *Daemon) resolveSessionMetadata(agentName string) SessionMetadata {
 	// Check spawn registry.
 	if d.spawns != nil {
 		if entry := d.spawns.Get(agentName); entry != nil && entry.SessionID != "" {
-			return buildSessionMetadata(d.sstore, sessionMetadataFallback{
+			return buildSessionMetadata(d.sessionStore(), sessionMetadataFallback{
 				serverRef: d.config.ServerURL,
 				sessionID: entry.SessionID,
 				project:   d.config.Project,
@@ -177,19 +177,22 @@ func (d *Daemon) resolveSessionMetadata(agentName string) SessionMetadata {
 // If exactly one unclaimed candidate exists across pool and spawns, the
 // session ID is assigned to it and persisted in the session registry.
 // If zero or multiple candidates exist, the event is logged but no
-// assignment happens — the common case is one agent at a time.
+// assignment happens — the common case is one agent at a time. With
+// multiple candidates, each one records a session error so status can
+// tell "capture failed" apart from "no session yet".
 func (d *Daemon) claimSession(sessionID string) {
 	type candidate struct {
 		kind    string // "pool" or "spawn"
 		agentID string // pool agent name or spawn ID
+		pool    *Pool  // the agent's pool, for kind "pool"
 	}
 	var candidates []candidate
 
-	// Check pool for agents without a session ID.
-	if d.pool != nil {
-		for _, a := range d.pool.Status() {
+	// Check pools for agents without a session ID.
+	for _, pool := range d.pools() {
+		for _, a := range pool.Status() {
 			if a.SessionID == "" && a.State == AgentRunning {
-				candidates = append(candidates, candidate{kind: "pool", agentID: string(a.ID)})
+				candidates = append(candidates, candidate{kind: "pool", agentID: string(a.ID), pool: pool})
 			}
 		}
 	}
@@ -213,10 +216,20 @@ func (d *Daemon) claimSession(sessionID string) {
 			"session_id", sessionID,
 			"candidates", len(candidates),
 		)
+		msg := fmt.Sprintf("session %s created while %d agents awaited one; not assigned", sessionID, len(candidates))
+		for _, c := range candidates {
+			switch c.kind {
+			case "pool":
+				c.pool.SetSessionError(c.agentID, msg)
+			case "spawn":
+				d.spawns.SetSessionError(c.agentID, msg)
+			}
+		}
 		return
 	}
 
 	c := candidates[0]
+	sstore := d.sessionStore()
 	d.log.Info("session claimed",
 		"session_id", sessionID,
 		"kind", c.kind,
@@ -225,21 +238,19 @@ func (d *Daemon) claimSession(sessionID string) {
 
 	switch c.kind {
 	case "pool":
-		if d.pool != nil {
-			d.pool.SetSessionID(c.agentID, sessionID)
-		}
-		if d.sstore != nil {
+		c.pool.SetSessionID(c.agentID, sessionID)
+		if sstore != nil {
 			rec := sessions.Record{
 				ServerRef:  d.config.ServerURL,
 				SessionID:  sessionID,
-				Project:    d.config.Project,
+				Project:    c.pool.Project(),
 				Origin:     sessions.OriginPool,
-				WorkRef:    d.pool.TaskIDForAgent(c.agentID),
+				WorkRef:    c.pool.TaskIDForAgent(c.agentID),
 				AgentID:    c.agentID,
 				Status:     sessions.StatusActive,
 				LastSeenAt: time.Now(),
 			}
-			if err := d.sstore.Upsert(rec); err != nil {
+			if err := sstore.Upsert(rec); err != nil {
 				d.log.Warn("failed to persist pool session record",
 					"session_id", sessionID,
 					"agent_id", c.agentID,
@@ -250,7 +261,7 @@ func (d *Daemon) claimSession(sessionID string) {
 
 	case "spawn":
 		d.spawns.SetSessionID(c.agentID, sessionID)
-		if d.sstore != nil {
+		if sstore != nil {
 			rec := sessions.Record{
 				ServerRef:  d.config.ServerURL,
 				SessionID:  sessionID,
@@ -260,7 +271,7 @@ func (d *Daemon) claimSession(sessionID string) {
 				Status:     sessions.StatusActive,
 				LastSeenAt: time.Now(),
 			}
-			if err := d.sstore.Upsert(rec); err != nil {
+			if err := sstore.Upsert(rec); err != nil {
 				d.log.Warn("failed to persist spawn session record",
 					"session_id", sessionID,
 					"spawn_id", c.agentID,
@@ -269,4 +280,5 @@ func (d *Daemon) claimSession(sessionID string) {
 			}
 		}
 	}
+	d.notifyChange()
 }
@@ -1,6 +1,7 @@
 package daemon
 
 import (
+	"context"
 	"encoding/json"
 	"log/slog"
 	"os"
@@ -570,3 +571,65 @@ func TestClaimSessionMultipleCandidatesSkips(t *testing.T) {
 		t.Errorf("spawn-b SessionID = %q, want empty", b.SessionID)
 	}
 }
+
+func TestClaimSessionFailureSurfacesInStatus(t *testing.T) {
+	pool := testPoolForClaim(t)
+	pool.mu.Lock()
+	pool.agents["ts-def"] = &Agent{
+		ID:        "quiet_owl",
+		TaskID:    "ts-def",
+		Role:      RoleWorker,
+		PID:       5678,
+		SpawnTime: time.Now(),
+		State:     AgentRunning,
+	}
+	pool.mu.Unlock()
+
+	d := newTestDaemonForEvents()
+	d.pool = pool
+
+	// Two agents await a session, so capture never assigns an ID.
+	resp := d.handleSessionEvent(SessionEventParams{
+		EventType: "session.created",
+		SessionID: "ses-ambiguous",
+		Timestamp: 1000,
+	})
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+
+	detail, err := BuildAgentDetail(context.Background(), pool, d.spawns, nil, d.events, d.config, nil, StatusAgentParams{
+		AgentName: "ghost_wolf",
+	})
+	if err != nil {
+		t.Fatalf("BuildAgentDetail: %v", err)
+	}
+	if detail.SessionCaptured {
+		t.Error("SessionCaptured = true, want false")
+	}
+	if detail.SessionError == "" {
+		t.Error("SessionError is empty, want capture failure recorded")
+	}
+
+	status := BuildFullStatus(context.Background(), pool, d.spawns, nil, d.events, d.config, nil)
+	if len(status.Agents) != 2 {
+		t.Fatalf("got %d agents in status, want 2", len(status.Agents))
+	}
+	for _, a := range status.Agents {
+		if a.SessionCaptured || a.SessionError == "" {
+			t.Errorf("agent %s: SessionCaptured = %v, SessionError = %q; want false and non-empty", a.ID, a.SessionCaptured, a.SessionError)
+		}
+	}
+
+	// A later successful capture clears the error.
+	pool.SetSessionID("ghost_wolf", "ses-late")
+	detail, err = BuildAgentDetail(context.Background(), pool, d.spawns, nil, d.events, d.config, nil, StatusAgentParams{
+		AgentName: "ghost_wolf",
+	})
+	if err != nil {
+		t.Fatalf("BuildAgentDetail: %v", err)
+	}
+	if !detail.SessionCaptured || detail.SessionError != "" {
+		t.Errorf("after capture: SessionCaptured = %v, SessionError = %q; want true and empty", detail.SessionCaptured, detail.SessionError)
+	}
+}
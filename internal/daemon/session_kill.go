@@ -0,0 +1,134 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/baiirun/aetherflow/internal/sessions"
+)
+
+// sessionKillBlockTimeout bounds the prog call that releases a killed pool
+// agent's task.
+const sessionKillBlockTimeout = 10 * time.Second
+
+// KillBySession stops the running pool agent bound to sessionID. The agent
+// is signalled like a rolling restart stops one, but when it exits it is
+// not respawned and no crash retry is counted. It reports false when no
+// running pool agent owns the session.
+func (p *Pool) KillBySession(sessionID string) (Agent, bool, error) {
+	p.mu.Lock()
+	var target *Agent
+	for _, a := range p.agents {
+		if a.State == AgentRunning && a.SessionID == sessionID {
+			target = a
+			break
+		}
+	}
+	if target == nil {
+		p.mu.Unlock()
+		return Agent{}, false, nil
+	}
+	snapshot := *target
+	p.killed[snapshot.TaskID] = true
+	p.mu.Unlock()
+
+	p.taskLog(snapshot.TaskID).Info("killing agent by session",
+		"agent_id", snapshot.ID,
+		"task_id", snapshot.TaskID,
+		"pid", snapshot.PID,
+		"session_id", sessionID,
+	)
+	if err := p.stopProcess(snapshot.PID); err != nil {
+		p.mu.Lock()
+		delete(p.killed, snapshot.TaskID)
+		p.mu.Unlock()
+		return snapshot, true, fmt.Errorf("stopping agent %s (pid %d): %w", snapshot.ID, snapshot.PID, err)
+	}
+	return snapshot, true, nil
+}
+
+// SessionKillParams is the request shape for killing an agent by session.
+type SessionKillParams struct {
+	SessionID string `json:"session_id"`
+}
+
+// SessionKillResult is the response for the session kill handler.
+type SessionKillResult struct {
+	SessionID string `json:"session_id"`
+	Origin    string `json:"origin"`            // "pool" or "spawn"
+	AgentID   string `json:"agent_id"`          // pool agent name or spawn ID
+	TaskID    string `json:"task_id,omitempty"` // pool agents only
+	PID       int    `json:"pid"`
+	Released  bool   `json:"released,omitempty"` // pool task moved out of in_progress
+	// ReleaseError is set when the agent was stopped but its task could not
+	// be released; the task is left in_progress for the operator.
+	ReleaseError string `json:"release_error,omitempty"`
+}
+
+// handleSessionKill stops the agent that owns an opencode session, whether
+// it is a pool agent or an af spawn. A pool agent's task is then blocked in
+// prog, releasing the claim so it is not respawned or rescheduled.
+func (d *Daemon) handleSessionKill(ctx context.Context, params SessionKillParams) *Response {
+	if params.SessionID == "" {
+		return &Response{Success: false, Error: "session_id is required"}
+	}
+	if !isValidSessionID(params.SessionID) {
+		return &Response{Success: false, Error: fmt.Sprintf("invalid session_id %q", params.SessionID)}
+	}
+
+	var result SessionKillResult
+	for _, pool := range d.pools() {
+		agent, ok, err := pool.KillBySession(params.SessionID)
+		if err != nil {
+			return &Response{Success: false, Error: err.Error()}
+		}
+		if ok {
+			result = SessionKillResult{
+				SessionID: params.SessionID,
+				Origin:    "pool",
+				AgentID:   string(agent.ID),
+				TaskID:    agent.TaskID,
+				PID:       agent.PID,
+			}
+			bctx, cancel := context.WithTimeout(ctx, sessionKillBlockTimeout)
+			reason := fmt.Sprintf("agent %s killed via af session kill (session %s)", agent.ID, params.SessionID)
+			if err := pool.work.Block(bctx, agent.TaskID, pool.Project(), reason); err != nil {
+				d.log.Warn("failed to release killed agent's task", "task_id", agent.TaskID, "error", err)
+				result.ReleaseError = err.Error()
+			} else {
+				result.Released = true
+			}
+			cancel()
+			break
+		}
+	}
+
+	if result.Origin == "" && d.spawns != nil {
+		stopped, ok := d.spawns.StopBySession(params.SessionID)
+		if ok {
+			if stopped.Error != "" {
+				return &Response{Success: false, Error: fmt.Sprintf("stopping spawn %s (pid %d): %s", stopped.SpawnID, stopped.PID, stopped.Error)}
+			}
+			d.log.Info("spawn killed by session", "spawn_id", stopped.SpawnID, "pid", stopped.PID, "session_id", params.SessionID)
+			d.markSpawnSession(stopped.SpawnID, sessions.StatusIdle)
+			result = SessionKillResult{
+				SessionID: params.SessionID,
+				Origin:    "spawn",
+				AgentID:   stopped.SpawnID,
+				PID:       stopped.PID,
+			}
+		}
+	}
+
+	if result.Origin == "" {
+		return &Response{Success: false, Error: fmt.Sprintf("session %s does not belong to a running agent", params.SessionID)}
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		return &Response{Success: false, Error: fmt.Sprintf("marshal session kill result: %v", err)}
+	}
+	return &Response{Success: true, Result: data}
+}
@@ -0,0 +1,139 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestHandleSessionKillStopsPoolAgent(t *testing.T) {
+	var starts atomic.Int32
+	var release func()
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		starts.Add(1)
+		proc, rel := newFakeProcessWithError(4321, fmt.Errorf("signal: terminated"))
+		release = rel
+		return proc, nil
+	}
+
+	var mu sync.Mutex
+	var blocked [][]string
+	show := progRunner(testTaskMeta)
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if len(args) >= 1 && args[0] == "block" {
+			mu.Lock()
+			blocked = append(blocked, args)
+			mu.Unlock()
+			return nil, nil
+		}
+		return show(ctx, name, args...)
+	}
+
+	pool := testPool(t, runner, starter)
+	pool.ctx = context.Background()
+	var stoppedPID int
+	pool.stopProcess = func(pid int) error {
+		stoppedPID = pid
+		release() // the agent exits on SIGTERM
+		return nil
+	}
+
+	pool.schedule(context.Background(), []Task{{ID: "ts-abc"}})
+	agents := pool.Status()
+	if len(agents) != 1 {
+		t.Fatalf("agents = %d, want 1", len(agents))
+	}
+	if !pool.SetSessionID(string(agents[0].ID), "ses_kill123") {
+		t.Fatal("SetSessionID failed")
+	}
+
+	d := &Daemon{config: pool.config, pool: pool, spawns: NewSpawnRegistry(), log: testLogger()}
+	resp := d.handleSessionKill(context.Background(), SessionKillParams{SessionID: "ses_kill123"})
+	if !resp.Success {
+		t.Fatalf("expected success, got error: %s", resp.Error)
+	}
+	var result SessionKillResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if result.Origin != "pool" || result.TaskID != "ts-abc" || result.AgentID != string(agents[0].ID) || !result.Released {
+		t.Errorf("result = %+v, want released pool agent on ts-abc", result)
+	}
+	if stoppedPID != 4321 {
+		t.Errorf("stopped pid = %d, want 4321", stoppedPID)
+	}
+	mu.Lock()
+	if len(blocked) != 1 || blocked[0][1] != "ts-abc" || !slices.Contains(blocked[0], "testproject") {
+		t.Errorf("prog block calls = %v, want one for ts-abc in testproject", blocked)
+	}
+	mu.Unlock()
+
+	// The agent exits and is not respawned, and no crash retry is counted.
+	waitFor(t, func() bool { return len(pool.Status()) == 0 })
+	history := pool.History()
+	if len(history) != 1 || history[0].Outcome != TaskOutcomeKilled {
+		t.Errorf("history = %+v, want ts-abc killed", history)
+	}
+	if got := starts.Load(); got != 1 {
+		t.Errorf("starts = %d, want 1 (killed agent must not respawn)", got)
+	}
+	pool.mu.RLock()
+	retries := pool.retries["ts-abc"]
+	pool.mu.RUnlock()
+	if retries != 0 {
+		t.Errorf("retries = %d, want 0", retries)
+	}
+}
+
+func TestHandleSessionKillUnknownSession(t *testing.T) {
+	pool := testPool(t, progRunner(testTaskMeta), nil)
+	d := &Daemon{config: pool.config, pool: pool, spawns: NewSpawnRegistry(), log: testLogger()}
+
+	resp := d.handleSessionKill(context.Background(), SessionKillParams{SessionID: "ses_nobody"})
+	if resp.Success {
+		t.Fatal("expected error for a session no agent owns")
+	}
+	if want := "session ses_nobody does not belong to a running agent"; resp.Error != want {
+		t.Errorf("error = %q, want %q", resp.Error, want)
+	}
+}
+
+func TestSpawnRegistryStopBySession(t *testing.T) {
+	r := NewSpawnRegistry()
+	r.pidAlive = func(int) bool { return true }
+	var signalled []int
+	r.stopProcess = func(pid int) error {
+		signalled = append(signalled, pid)
+		return nil
+	}
+	for _, e := range []SpawnEntry{
+		{SpawnID: "spawn-a-0001", PID: 101, SessionID: "ses_a", State: SpawnRunning},
+		{SpawnID: "spawn-b-0002", PID: 102, SessionID: "ses_b", State: SpawnRunning},
+	} {
+		if err := r.Register(e); err != nil {
+			t.Fatalf("register: %v", err)
+		}
+	}
+
+	result, ok := r.StopBySession("ses_b")
+	if !ok || !result.Stopped || result.SpawnID != "spawn-b-0002" {
+		t.Fatalf("StopBySession = %+v, %v; want spawn-b-0002 stopped", result, ok)
+	}
+	if !slices.Equal(signalled, []int{102}) {
+		t.Errorf("signalled = %v, want [102]", signalled)
+	}
+	if got := r.Get("spawn-b-0002").State; got != SpawnExited {
+		t.Errorf("spawn-b state = %q, want exited", got)
+	}
+	if got := r.Get("spawn-a-0001").State; got != SpawnRunning {
+		t.Errorf("spawn-a state = %q, want running", got)
+	}
+	if _, ok := r.StopBySession("ses_missing"); ok {
+		t.Error("StopBySession found a spawn for an unknown session")
+	}
+}
@@ -0,0 +1,120 @@
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/baiirun/aetherflow/internal/sessions"
+)
+
+const (
+	// sessionStoreOpenAttempts and sessionStoreOpenBackoff bound the retry
+	// when the session registry fails to open at startup.
+	sessionStoreOpenAttempts = 3
+	sessionStoreOpenBackoff  = 200 * time.Millisecond
+
+	// sessionStoreReopenInterval is how often a daemon running without a
+	// session registry tries to open it again.
+	sessionStoreReopenInterval = 30 * time.Second
+)
+
+// openSessionStore opens the session registry. Tests replace it to simulate
+// a registry that fails to open.
+var openSessionStore = sessions.Open
+
+// openSessionStoreWithRetry opens the session registry, retrying briefly so
+// a transient failure at startup doesn't disable session persistence.
+func openSessionStoreWithRetry(dir string) (*sessions.Store, error) {
+	var err error
+	for attempt := range sessionStoreOpenAttempts {
+		if attempt > 0 {
+			time.Sleep(sessionStoreOpenBackoff)
+		}
+		var store *sessions.Store
+		if store, err = openSessionStore(dir); err == nil {
+			return store, nil
+		}
+	}
+	return nil, err
+}
+
+// sessionStore returns the session registry, or nil while it is unavailable.
+func (d *Daemon) sessionStore() *sessions.Store {
+	d.sstoreMu.RLock()
+	defer d.sstoreMu.RUnlock()
+	return d.sstore
+}
+
+// sessionStoreError reports why session persistence is degraded, or nil
+// when the registry is open.
+func (d *Daemon) sessionStoreError() error {
+	d.sstoreMu.RLock()
+	defer d.sstoreMu.RUnlock()
+	if d.sstore != nil {
+		return nil
+	}
+	return d.sstoreErr
+}
+
+// reopenSessionStore makes one attempt to open a missing session registry
+// and hands it to the pool on success. It reports whether the registry is
+// available afterwards.
+func (d *Daemon) reopenSessionStore() bool {
+	if d.sessionStore() != nil {
+		return true
+	}
+	store, err := openSessionStore(d.config.SessionDir)
+	d.sstoreMu.Lock()
+	if err != nil {
+		d.sstoreErr = err
+		d.sstoreMu.Unlock()
+		d.log.Debug("session registry still unavailable", "error", err)
+		return false
+	}
+	d.sstore = store
+	d.sstoreErr = nil
+	d.sstoreMu.Unlock()
+
+	for _, pool := range d.pools() {
+		pool.setSessionStore(store)
+	}
+	d.log.Info("session registry recovered", "path", store.Path())
+	return true
+}
+
+// buildFullStatus builds the swarm status, flagging degraded session
+// persistence alongside the other partial errors.
+func (d *Daemon) buildFullStatus(ctx context.Context) FullStatus {
+	status := BuildFullStatus(ctx, d.pool, d.spawns, d.sessionStore(), d.events, d.config, d.config.Runner)
+	if len(d.projects) > 1 {
+		d.addProjectBreakdown(ctx, &status)
+	}
+	if err := d.sessionStoreError(); err != nil {
+		status.SessionStoreError = err.Error()
+		status.Errors = append(status.Errors, fmt.Sprintf("session persistence degraded, registry unavailable: %v", err))
+	}
+	return status
+}
+
+// watchSessionStore retries opening the session registry every
+// sessionStoreReopenInterval until it succeeds, so a transient failure
+// (e.g. permissions fixed later) recovers without a restart.
+func (d *Daemon) watchSessionStore(ctx context.Context) {
+	if d.sessionStore() != nil {
+		return
+	}
+	ticker := time.NewTicker(sessionStoreReopenInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if d.reopenSessionStore() {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/baiirun/aetherflow/internal/sessions"
+)
+
+func TestSessionStoreRecoversAfterFailedOpen(t *testing.T) {
+	var healthy atomic.Bool
+	var opens atomic.Int32
+	orig := openSessionStore
+	openSessionStore = func(dir string) (*sessions.Store, error) {
+		opens.Add(1)
+		if !healthy.Load() {
+			return nil, errors.New("permission denied")
+		}
+		return sessions.Open(dir)
+	}
+	t.Cleanup(func() { openSessionStore = orig })
+
+	cfg := Config{
+		Project:    "testproject",
+		SpawnCmd:   "fake-agent",
+		SessionDir: t.TempDir(),
+		Runner:     progRunner(testTaskMeta),
+		Logger:     testLogger(),
+	}
+	d := New(cfg)
+
+	// New retries the open before giving up.
+	if got := opens.Load(); got != sessionStoreOpenAttempts {
+		t.Errorf("open attempts = %d, want %d", got, sessionStoreOpenAttempts)
+	}
+	if d.sessionStore() != nil || d.pool.sessionStore() != nil {
+		t.Fatal("session store is set after a failed open")
+	}
+	status := d.buildFullStatus(context.Background())
+	if status.SessionStoreError != "permission denied" {
+		t.Errorf("SessionStoreError = %q, want %q", status.SessionStoreError, "permission denied")
+	}
+	if len(status.Errors) != 1 || !strings.Contains(status.Errors[0], "session persistence degraded") {
+		t.Errorf("status errors = %v, want a degraded session persistence warning", status.Errors)
+	}
+
+	// Still failing: the periodic attempt leaves the daemon degraded.
+	if d.reopenSessionStore() {
+		t.Fatal("reopenSessionStore succeeded while open still fails")
+	}
+
+	// The failure clears and the next attempt picks the store up.
+	healthy.Store(true)
+	if !d.reopenSessionStore() {
+		t.Fatal("reopenSessionStore failed after open recovered")
+	}
+	if d.sessionStore() == nil {
+		t.Fatal("daemon session store is nil after recovery")
+	}
+	if d.pool.sessionStore() != d.sessionStore() {
+		t.Error("pool did not receive the recovered session store")
+	}
+	if err := d.sessionStoreError(); err != nil {
+		t.Errorf("sessionStoreError = %v, want nil after recovery", err)
+	}
+	if got := d.buildFullStatus(context.Background()).SessionStoreError; got != "" {
+		t.Errorf("SessionStoreError = %q after recovery, want empty", got)
+	}
+}
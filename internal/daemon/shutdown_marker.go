@@ -0,0 +1,82 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/baiirun/aetherflow/internal/sessions"
+)
+
+// shutdownMarker is written as the last step of a graceful stop and
+// removed at the next start. Its absence at startup means the previous
+// daemon crashed or was killed before it could flush its state.
+type shutdownMarker struct {
+	StoppedAt time.Time `json:"stopped_at"`
+	PID       int       `json:"pid"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// shutdownMarkerPath returns the marker file for project in dir. An empty
+// dir uses the session registry's default directory.
+func shutdownMarkerPath(dir, project string) (string, error) {
+	if dir == "" {
+		var err error
+		dir, err = sessions.DefaultDir()
+		if err != nil {
+			return "", err
+		}
+	}
+	name := "clean-shutdown.json"
+	if project != "" {
+		name = "clean-shutdown-" + project + ".json"
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// writeShutdownMarker records a clean stop of the daemon for project,
+// with the reason given for it (empty when none was).
+func writeShutdownMarker(dir, project string, now time.Time, reason string) error {
+	path, err := shutdownMarkerPath(dir, project)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("creating shutdown marker dir: %w", err)
+	}
+	data, err := json.Marshal(shutdownMarker{StoppedAt: now, PID: os.Getpid(), Reason: reason})
+	if err != nil {
+		return fmt.Errorf("encoding shutdown marker: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("writing shutdown marker: %w", err)
+	}
+	return nil
+}
+
+// consumeShutdownMarker reports whether the previous daemon for project
+// stopped cleanly, and removes the marker so a crash of this run is not
+// mistaken for a clean stop. An unreadable marker counts as a crash.
+func consumeShutdownMarker(dir, project string) (shutdownMarker, bool, error) {
+	path, err := shutdownMarkerPath(dir, project)
+	if err != nil {
+		return shutdownMarker{}, false, err
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return shutdownMarker{}, false, nil
+	}
+	if err != nil {
+		return shutdownMarker{}, false, fmt.Errorf("reading shutdown marker: %w", err)
+	}
+	if err := os.Remove(path); err != nil {
+		return shutdownMarker{}, false, fmt.Errorf("removing shutdown marker: %w", err)
+	}
+	var m shutdownMarker
+	if err := json.Unmarshal(data, &m); err != nil {
+		return shutdownMarker{}, false, fmt.Errorf("parsing shutdown marker %s: %w", path, err)
+	}
+	return m, true, nil
+}
@@ -0,0 +1,77 @@
+package daemon
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/baiirun/aetherflow/internal/client"
+)
+
+func TestDaemonCleanShutdownMarker(t *testing.T) {
+	dir := t.TempDir()
+	start := func() (*Daemon, *client.Client, chan error) {
+		listenAddr := testListenAddr(t)
+		d := New(Config{
+			ListenAddr:        listenAddr,
+			Project:           "marker-test",
+			PollInterval:      10 * time.Millisecond,
+			PoolSize:          1,
+			SpawnCmd:          "echo test",
+			SpawnPolicy:       SpawnPolicyManual,
+			ReconcileInterval: DefaultReconcileInterval,
+			ServerStarter:     noopServerStarter,
+			SessionDir:        dir,
+		})
+		done := make(chan error, 1)
+		go func() { done <- d.Run() }()
+		c := client.New(fmt.Sprintf("http://%s", listenAddr))
+		waitForDaemonStatus(t, c, 2*time.Second)
+		return d, c, done
+	}
+	path, err := shutdownMarkerPath(dir, "marker-test")
+	if err != nil {
+		t.Fatalf("shutdownMarkerPath: %v", err)
+	}
+
+	// First run: no previous daemon, so no clean start.
+	d, c, done := start()
+	if d.cleanStart {
+		t.Error("cleanStart = true on first run, want false")
+	}
+	if err := c.Shutdown(false); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	waitForDaemonExit(t, done, 2*time.Second)
+
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("marker missing after graceful stop: %v", err)
+	}
+
+	// Second run sees the clean stop and consumes the marker, so if it
+	// crashed now (never reaching the flush) the next start would not
+	// find one.
+	d, c, done = start()
+	if !d.cleanStart {
+		t.Error("cleanStart = false after graceful stop, want true")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("marker still present while running, stat err = %v", err)
+	}
+	if _, clean, err := consumeShutdownMarker(dir, "marker-test"); err != nil || clean {
+		t.Errorf("consumeShutdownMarker after simulated crash = %v, %v; want false, nil", clean, err)
+	}
+
+	if _, err := c.StopDaemon(false, client.StopDaemonParams{Reason: "upgrade"}); err != nil {
+		t.Fatalf("shutdown: %v", err)
+	}
+	waitForDaemonExit(t, done, 2*time.Second)
+	marker, clean, err := consumeShutdownMarker(dir, "marker-test")
+	if err != nil || !clean {
+		t.Errorf("consumeShutdownMarker after graceful stop = %v, %v; want true, nil", clean, err)
+	}
+	if marker.Reason != "upgrade" {
+		t.Errorf("marker reason = %q, want %q", marker.Reason, "upgrade")
+	}
+}
@@ -1,7 +1,9 @@
 package daemon
 
 import (
+	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"time"
 
 	"github.com/baiirun/aetherflow/internal/sessions"
@@ -18,6 +20,18 @@ type SpawnRegisterParams struct {
 	SpawnID string `json:"spawn_id"`
 	PID     int    `json:"pid"`
 	Prompt  string `json:"prompt"`
+
+	// WorktreePath is the absolute path of the git worktree the agent is
+	// expected to work in. Optional; used to report orphaned worktrees.
+	WorktreePath string `json:"worktree_path,omitempty"`
+
+	// Ephemeral shortens how long the entry is kept after the agent exits
+	// (ephemeralSpawnTTL instead of exitedSpawnTTL).
+	Ephemeral bool `json:"ephemeral,omitempty"`
+
+	// TaskID is the prog task the spawn claimed (af spawn --pick). While
+	// the spawn runs, the pool's orphan scan leaves that task alone.
+	TaskID string `json:"task_id,omitempty"`
 }
 
 // handleSpawnRegister registers a spawned agent with the daemon for observability.
@@ -28,22 +42,43 @@ func (d *Daemon) handleSpawnRegister(params SpawnRegisterParams) *Response {
 	if len(params.SpawnID) > maxSpawnIDLen {
 		return &Response{Success: false, Error: fmt.Sprintf("spawn_id too long (%d > %d)", len(params.SpawnID), maxSpawnIDLen)}
 	}
-	if params.PID <= 0 {
-		return &Response{Success: false, Error: "pid must be positive"}
+	// PID 1 is init, and stopping a spawn signals its process group:
+	// kill(-1) would reach every process the daemon's user owns.
+	if params.PID <= 1 {
+		return &Response{Success: false, Error: "pid must be greater than 1"}
+	}
+	if params.TaskID != "" && !validTaskID.MatchString(params.TaskID) {
+		return &Response{Success: false, Error: fmt.Sprintf("invalid task_id %q", params.TaskID)}
+	}
+	worktreePath := params.WorktreePath
+	if worktreePath != "" {
+		if !filepath.IsAbs(worktreePath) {
+			return &Response{Success: false, Error: "worktree_path must be absolute"}
+		}
+		worktreePath = filepath.Clean(worktreePath)
 	}
 
-	// Truncate prompt to cap memory usage — only used for display.
-	prompt := params.Prompt
+	// Redact and truncate the prompt to cap memory usage — only used for
+	// display.
+	prompt := d.config.SpawnPromptRedaction.Apply(params.Prompt)
 	if len(prompt) > maxSpawnPromptLen {
 		prompt = prompt[:maxSpawnPromptLen]
 	}
 
+	var ttl time.Duration
+	if params.Ephemeral {
+		ttl = ephemeralSpawnTTL
+	}
+
 	if err := d.spawns.Register(SpawnEntry{
-		SpawnID:   params.SpawnID,
-		PID:       params.PID,
-		State:     SpawnRunning,
-		Prompt:    prompt,
-		SpawnTime: time.Now(),
+		SpawnID:      params.SpawnID,
+		PID:          params.PID,
+		State:        SpawnRunning,
+		Prompt:       prompt,
+		WorktreePath: worktreePath,
+		TaskID:       params.TaskID,
+		SpawnTime:    time.Now(),
+		TTL:          ttl,
 	}); err != nil {
 		return &Response{Success: false, Error: err.Error()}
 	}
@@ -51,6 +86,8 @@ func (d *Daemon) handleSpawnRegister(params SpawnRegisterParams) *Response {
 	d.log.Info("spawn registered",
 		"spawn_id", params.SpawnID,
 		"pid", params.PID,
+		"task_id", params.TaskID,
+		"ephemeral", params.Ephemeral,
 	)
 
 	// Session ID is captured when the session.created plugin event arrives
@@ -62,6 +99,9 @@ func (d *Daemon) handleSpawnRegister(params SpawnRegisterParams) *Response {
 // SpawnDeregisterParams is the HTTP payload for deregistering a tracked spawn.
 type SpawnDeregisterParams struct {
 	SpawnID string `json:"spawn_id"`
+	// ExitCode is the agent process's exit status. Non-zero marks the
+	// spawn's session terminated instead of idle.
+	ExitCode int `json:"exit_code,omitempty"`
 }
 
 // handleSpawnDeregister marks a spawned agent as exited in the registry.
@@ -79,18 +119,64 @@ func (d *Daemon) handleSpawnDeregister(params SpawnDeregisterParams) *Response {
 	}
 
 	// Update session status regardless — the session store may have a record
-	// even if the spawn registry entry was already cleaned up.
-	entry := d.spawns.Get(params.SpawnID)
-	if d.sstore != nil {
-		if entry != nil && entry.SessionID != "" {
-			if _, err := d.sstore.SetStatusBySession(d.config.ServerURL, entry.SessionID, sessions.StatusIdle); err != nil {
-				d.log.Warn("failed to update spawn session status by key", "spawn_id", params.SpawnID, "session_id", entry.SessionID, "status", sessions.StatusIdle, "error", err)
-			}
+	// even if the spawn registry entry was already cleaned up. Mirrors the
+	// pool: a clean exit leaves the session idle (resumable), a crash
+	// terminates it.
+	status := sessions.StatusIdle
+	if params.ExitCode != 0 {
+		status = sessions.StatusTerminated
+	}
+	d.markSpawnSession(params.SpawnID, status)
+
+	return &Response{Success: true}
+}
+
+// markSpawnSession sets the status of an exited spawn's session records.
+func (d *Daemon) markSpawnSession(spawnID string, status sessions.Status) {
+	sstore := d.sessionStore()
+	if sstore == nil {
+		return
+	}
+	entry := d.spawns.Get(spawnID)
+	if entry != nil && entry.SessionID != "" {
+		if _, err := sstore.SetStatusBySession(d.config.ServerURL, entry.SessionID, status); err != nil {
+			d.log.Warn("failed to update spawn session status by key", "spawn_id", spawnID, "session_id", entry.SessionID, "status", status, "error", err)
 		}
-		if _, err := d.sstore.SetStatusByWorkRef(sessions.OriginSpawn, params.SpawnID, sessions.StatusIdle); err != nil {
-			d.log.Warn("failed to update spawn session status", "spawn_id", params.SpawnID, "status", sessions.StatusIdle, "error", err)
+	}
+	if _, err := sstore.SetStatusByWorkRef(sessions.OriginSpawn, spawnID, status); err != nil {
+		d.log.Warn("failed to update spawn session status", "spawn_id", spawnID, "status", status, "error", err)
+	}
+	d.notifyChange()
+}
+
+// SpawnStopAllParams is the HTTP payload for stopping every running spawn.
+type SpawnStopAllParams struct {
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// SpawnStopAllResult is the response for the stop-all handler.
+type SpawnStopAllResult struct {
+	DryRun bool              `json:"dry_run,omitempty"`
+	Spawns []SpawnStopResult `json:"spawns"`
+}
+
+// handleSpawnStopAll signals every running spawn to exit and marks it exited.
+func (d *Daemon) handleSpawnStopAll(params SpawnStopAllParams) *Response {
+	results := d.spawns.StopAll(params.DryRun)
+	for _, r := range results {
+		switch {
+		case params.DryRun:
+		case r.Stopped:
+			d.log.Info("spawn stopped", "spawn_id", r.SpawnID, "pid", r.PID)
+			d.markSpawnSession(r.SpawnID, sessions.StatusIdle)
+		default:
+			d.log.Warn("failed to stop spawn", "spawn_id", r.SpawnID, "pid", r.PID, "error", r.Error)
 		}
 	}
 
-	return &Response{Success: true}
+	data, err := json.Marshal(SpawnStopAllResult{DryRun: params.DryRun, Spawns: results})
+	if err != nil {
+		return &Response{Success: false, Error: fmt.Sprintf("marshal error: %v", err)}
+	}
+	return &Response{Success: true, Result: data}
 }
@@ -0,0 +1,145 @@
+package daemon
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/baiirun/aetherflow/internal/sessions"
+)
+
+// newSpawnSessionDaemon returns a daemon with one running spawn whose
+// session record is active.
+func newSpawnSessionDaemon(t *testing.T, spawnID string) (*Daemon, *sessions.Store) {
+	t.Helper()
+	store := newTestSessionStore(t)
+	now := time.Now()
+	if err := store.Upsert(sessions.Record{
+		ServerRef:  "http://127.0.0.1:4096",
+		SessionID:  "ses-" + spawnID,
+		Project:    "manual",
+		Origin:     sessions.OriginSpawn,
+		WorkRef:    spawnID,
+		Status:     sessions.StatusActive,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		UpdatedAt:  now,
+	}); err != nil {
+		t.Fatalf("store.Upsert: %v", err)
+	}
+
+	spawns := NewSpawnRegistry()
+	if err := spawns.Register(SpawnEntry{SpawnID: spawnID, PID: 4242, State: SpawnRunning, SessionID: "ses-" + spawnID}); err != nil {
+		t.Fatalf("Register: %v", err)
+	}
+	d := &Daemon{
+		config: Config{ServerURL: "http://127.0.0.1:4096"},
+		spawns: spawns,
+		sstore: store,
+		log:    testLogger(),
+	}
+	return d, store
+}
+
+func spawnSessionStatus(t *testing.T, store *sessions.Store, spawnID string) sessions.Status {
+	t.Helper()
+	recs, err := store.List()
+	if err != nil {
+		t.Fatalf("store.List: %v", err)
+	}
+	for _, rec := range recs {
+		if rec.WorkRef == spawnID {
+			return rec.Status
+		}
+	}
+	t.Fatalf("no session record for %s", spawnID)
+	return ""
+}
+
+func TestSpawnDeregisterSetsSessionStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		exitCode int
+		want     sessions.Status
+	}{
+		{name: "clean exit", exitCode: 0, want: sessions.StatusIdle},
+		{name: "crash", exitCode: 2, want: sessions.StatusTerminated},
+		{name: "killed by signal", exitCode: -1, want: sessions.StatusTerminated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, store := newSpawnSessionDaemon(t, "spawn-abc")
+
+			resp := d.handleSpawnDeregister(SpawnDeregisterParams{SpawnID: "spawn-abc", ExitCode: tt.exitCode})
+			if !resp.Success {
+				t.Fatalf("deregister failed: %s", resp.Error)
+			}
+			if got := spawnSessionStatus(t, store, "spawn-abc"); got != tt.want {
+				t.Errorf("session status = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSweepDeadSpawnsMarksSessionIdle(t *testing.T) {
+	d, store := newSpawnSessionDaemon(t, "spawn-abc")
+	d.spawns.pidAlive = func(int) bool { return false }
+
+	d.sweepDeadSpawns()
+
+	if entry := d.spawns.Get("spawn-abc"); entry == nil || entry.State != SpawnExited {
+		t.Fatalf("spawn entry = %+v, want exited", entry)
+	}
+	if got := spawnSessionStatus(t, store, "spawn-abc"); got != sessions.StatusIdle {
+		t.Errorf("session status = %q, want %q", got, sessions.StatusIdle)
+	}
+}
+
+func TestSpawnRegisterRedactsPrompt(t *testing.T) {
+	prompt := "fix the login bug\ncustomer token is abc123"
+	tests := []struct {
+		redaction PromptRedaction
+		want      string
+	}{
+		{"", prompt},
+		{PromptRedactionNone, prompt},
+		{PromptRedactionFirstLine, "fix the login bug [...]"},
+		{PromptRedactionHash, "sha256:"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.redaction), func(t *testing.T) {
+			d := &Daemon{
+				config: Config{SpawnPromptRedaction: tt.redaction},
+				spawns: NewSpawnRegistry(),
+				log:    testLogger(),
+			}
+			resp := d.handleSpawnRegister(SpawnRegisterParams{SpawnID: "spawn-a", PID: 4242, Prompt: prompt})
+			if !resp.Success {
+				t.Fatalf("register failed: %s", resp.Error)
+			}
+			got := d.spawns.Get("spawn-a").Prompt
+			if tt.redaction == PromptRedactionHash {
+				if !strings.HasPrefix(got, tt.want) || strings.Contains(got, "abc123") {
+					t.Errorf("Prompt = %q, want a sha256 digest", got)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Prompt = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpawnRegisterRejectsInitAndNonPositivePIDs(t *testing.T) {
+	d := &Daemon{spawns: NewSpawnRegistry(), log: testLogger()}
+	for _, pid := range []int{-1, 0, 1} {
+		resp := d.handleSpawnRegister(SpawnRegisterParams{SpawnID: "spawn-a", PID: pid})
+		if resp.Success {
+			t.Errorf("register with pid %d succeeded, want rejected", pid)
+		}
+	}
+	if got := d.spawns.Get("spawn-a"); got != nil {
+		t.Errorf("rejected registration left entry %+v", got)
+	}
+}
@@ -1,6 +1,10 @@
 package daemon
 
-import "strings"
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
 
 // EnsureAttachSpawnCmd returns spawnCmd with an attach target.
 // If spawnCmd already includes --attach, it is returned unchanged.
@@ -29,6 +33,55 @@ func WithSessionFlag(spawnCmd, sessionID string) string {
 	return strings.TrimSpace(spawnCmd + " --session " + sessionID)
 }
 
+// SpawnCmdVars are the values substituted for placeholders in a spawn
+// command. Fields that don't apply (e.g. TaskID for a manual spawn) are empty.
+type SpawnCmdVars struct {
+	AgentID string
+	TaskID  string
+	Role    Role
+	Session string
+}
+
+// spawnCmdPlaceholders lists the placeholders ExpandSpawnCmd understands,
+// for error messages.
+const spawnCmdPlaceholders = "{{agent_id}}, {{task_id}}, {{role}}, or {{session}}"
+
+// ExpandSpawnCmd replaces {{agent_id}}, {{task_id}}, {{role}}, and
+// {{session}} in spawnCmd. {{session}} expands to the whole "--session <id>"
+// flag when resuming (see WithSessionFlag) and to nothing otherwise. A
+// command without {{session}} gets the flag appended, as before templating.
+func ExpandSpawnCmd(spawnCmd string, vars SpawnCmdVars) string {
+	if !strings.Contains(spawnCmd, "{{session}}") {
+		spawnCmd = WithSessionFlag(spawnCmd, vars.Session)
+	}
+	r := strings.NewReplacer(
+		"{{agent_id}}", vars.AgentID,
+		"{{task_id}}", vars.TaskID,
+		"{{role}}", string(vars.Role),
+		"{{session}}", WithSessionFlag("", vars.Session),
+	)
+	return strings.Join(strings.Fields(r.Replace(spawnCmd)), " ")
+}
+
+// maxArgBytes is the largest single argv string Linux accepts
+// (MAX_ARG_STRLEN, 128 KiB including the terminating NUL). The prompt is
+// passed as one argument, so max_prompt_bytes may not exceed it.
+const maxArgBytes = 128<<10 - 1
+
+// CheckPromptSize returns a descriptive error when prompt is larger than
+// max bytes, so an oversized prompt fails before the agent is started
+// instead of as an opaque exec error ("argument list too long").
+// max <= 0 uses DefaultMaxPromptBytes.
+func CheckPromptSize(prompt string, max int) error {
+	if max <= 0 {
+		max = DefaultMaxPromptBytes
+	}
+	if len(prompt) > max {
+		return fmt.Errorf("prompt is %d bytes, over the %d-byte limit (max_prompt_bytes); shorten the prompt or raise the limit", len(prompt), max)
+	}
+	return nil
+}
+
 // isValidSessionID checks that a session ID contains only safe characters.
 // Session IDs from opencode follow the ses_<random> format (alphanumeric
 // with underscores). This rejects whitespace, shell metacharacters, and
@@ -47,6 +100,39 @@ func isValidSessionID(id string) bool {
 	return true
 }
 
+// AttachURL returns the server URL spawnCmd attaches to (its --attach
+// value), or "" when it has none.
+func AttachURL(spawnCmd string) string {
+	toks := strings.Fields(spawnCmd)
+	for i, tok := range toks {
+		if v, ok := strings.CutPrefix(tok, "--attach="); ok {
+			return v
+		}
+		if tok == "--attach" && i+1 < len(toks) {
+			return toks[i+1]
+		}
+	}
+	return ""
+}
+
+// CheckSpawnCmd reports an error when AllowedSpawnCmds is set and the
+// program spawnCmd runs (its first word) is not one of the entries. Pass
+// the command after placeholder expansion, as it will be executed.
+func (c Config) CheckSpawnCmd(spawnCmd string) error {
+	if len(c.AllowedSpawnCmds) == 0 {
+		return nil
+	}
+	fields := strings.Fields(spawnCmd)
+	if len(fields) > 0 && slices.Contains(c.AllowedSpawnCmds, fields[0]) {
+		return nil
+	}
+	bin := ""
+	if len(fields) > 0 {
+		bin = fields[0]
+	}
+	return fmt.Errorf("spawn command %q is not in allowed_spawn_cmds %v", bin, c.AllowedSpawnCmds)
+}
+
 func spawnCmdHasAttach(spawnCmd string) bool {
 	for _, tok := range strings.Fields(spawnCmd) {
 		if tok == "--attach" || strings.HasPrefix(tok, "--attach=") {
@@ -36,6 +36,20 @@ func TestEnsureAttachSpawnCmdDoesNotFalseMatch(t *testing.T) {
 	}
 }
 
+func TestAttachURL(t *testing.T) {
+	tests := []struct{ cmd, want string }{
+		{"opencode run --attach http://127.0.0.1:4096 --format json", "http://127.0.0.1:4096"},
+		{"opencode run --attach=http://127.0.0.1:5000", "http://127.0.0.1:5000"},
+		{"opencode run --format json", ""},
+		{"opencode run --attach", ""},
+	}
+	for _, tc := range tests {
+		if got := AttachURL(tc.cmd); got != tc.want {
+			t.Errorf("AttachURL(%q) = %q, want %q", tc.cmd, got, tc.want)
+		}
+	}
+}
+
 func TestWithSessionFlag(t *testing.T) {
 	t.Parallel()
 
@@ -99,3 +113,55 @@ func TestIsValidSessionID(t *testing.T) {
 		}
 	}
 }
+
+func TestExpandSpawnCmdRole(t *testing.T) {
+	t.Parallel()
+
+	cmd := "opencode run --agent {{role}} --title {{task_id}}-{{agent_id}} --format json"
+	tests := []struct {
+		role Role
+		want string
+	}{
+		{RoleWorker, "opencode run --agent worker --title ts-abc-ghost_wolf --format json"},
+		{RolePlanner, "opencode run --agent planner --title ts-abc-ghost_wolf --format json"},
+	}
+	for _, tt := range tests {
+		got := ExpandSpawnCmd(cmd, SpawnCmdVars{AgentID: "ghost_wolf", TaskID: "ts-abc", Role: tt.role})
+		if got != tt.want {
+			t.Errorf("ExpandSpawnCmd(role=%s) = %q, want %q", tt.role, got, tt.want)
+		}
+	}
+}
+
+func TestExpandSpawnCmdSession(t *testing.T) {
+	t.Parallel()
+
+	placed := "opencode run {{session}} --format json"
+	if got, want := ExpandSpawnCmd(placed, SpawnCmdVars{Session: "ses_abc123"}), "opencode run --session ses_abc123 --format json"; got != want {
+		t.Errorf("with session = %q, want %q", got, want)
+	}
+	if got, want := ExpandSpawnCmd(placed, SpawnCmdVars{}), "opencode run --format json"; got != want {
+		t.Errorf("without session = %q, want %q", got, want)
+	}
+
+	// No placeholder: the flag is appended, as WithSessionFlag always did.
+	plain := "opencode run --format json"
+	if got, want := ExpandSpawnCmd(plain, SpawnCmdVars{Session: "ses_abc123"}), "opencode run --format json --session ses_abc123"; got != want {
+		t.Errorf("appended session = %q, want %q", got, want)
+	}
+}
+
+func TestCheckPromptSize(t *testing.T) {
+	t.Parallel()
+
+	if err := CheckPromptSize(strings.Repeat("x", 64), 64); err != nil {
+		t.Errorf("prompt at the limit: %v, want nil", err)
+	}
+	err := CheckPromptSize(strings.Repeat("x", 65), 64)
+	if err == nil || !strings.Contains(err.Error(), "prompt is 65 bytes, over the 64-byte limit (max_prompt_bytes)") {
+		t.Errorf("oversized prompt: err = %v, want a max_prompt_bytes error", err)
+	}
+	if err := CheckPromptSize(strings.Repeat("x", DefaultMaxPromptBytes+1), 0); err == nil {
+		t.Error("zero max should fall back to DefaultMaxPromptBytes")
+	}
+}
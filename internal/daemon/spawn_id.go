@@ -0,0 +1,90 @@
+package daemon
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/baiirun/aetherflow/internal/protocol"
+)
+
+const (
+	DefaultSpawnIDPrefix   = "spawn-"
+	DefaultSpawnIDTemplate = "{{prefix}}{{name}}-{{hex}}"
+)
+
+// spawnIDPlaceholders lists the placeholders a spawn ID template understands,
+// for error messages.
+const spawnIDPlaceholders = "{{prefix}}, {{name}}, or {{hex}}"
+
+// NewSpawnID generates the identifier for an af spawn agent, which also
+// names its worktree and branch (af/<id>). template may use {{prefix}},
+// {{name}} (a random adjective_noun), and {{hex}} (4 random hex digits);
+// empty values fall back to DefaultSpawnIDPrefix and DefaultSpawnIDTemplate,
+// giving e.g. "spawn-ghost_wolf-a3f2".
+//
+// The random hex suffix expands the namespace from ~14K names to ~943M
+// combinations, so it is required. Pool agent IDs never contain a hyphen,
+// so requiring one keeps the two namespaces apart.
+func NewSpawnID(prefix, template string) (string, error) {
+	prefix, template = spawnIDDefaults(prefix, template)
+	suffix := make([]byte, 2)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("generating spawn ID: %w", err)
+	}
+	id := expandSpawnID(template, prefix, protocol.GenerateAgentName(), hex.EncodeToString(suffix))
+	if err := checkSpawnID(template, id); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// validateSpawnIDTemplate checks prefix and template by rendering a sample ID.
+func validateSpawnIDTemplate(prefix, template string) error {
+	prefix, template = spawnIDDefaults(prefix, template)
+	return checkSpawnID(template, expandSpawnID(template, prefix, "ghost_wolf", "a3f2"))
+}
+
+func spawnIDDefaults(prefix, template string) (string, string) {
+	if prefix == "" {
+		prefix = DefaultSpawnIDPrefix
+	}
+	if template == "" {
+		template = DefaultSpawnIDTemplate
+	}
+	return prefix, template
+}
+
+func expandSpawnID(template, prefix, name, hexSuffix string) string {
+	return strings.NewReplacer(
+		"{{prefix}}", prefix,
+		"{{name}}", name,
+		"{{hex}}", hexSuffix,
+	).Replace(template)
+}
+
+// checkSpawnID rejects IDs that could collide or that are unsafe as a
+// worktree directory, branch name, or URL path segment.
+func checkSpawnID(template, id string) error {
+	if !strings.Contains(template, "{{hex}}") {
+		return fmt.Errorf("spawn_id_template must include {{hex}} so spawn IDs stay unique")
+	}
+	if strings.Contains(id, "{{") {
+		return fmt.Errorf("spawn_id_template has an unknown placeholder (want %s)", spawnIDPlaceholders)
+	}
+	if !strings.Contains(id, "-") {
+		return fmt.Errorf("spawn ID %q must contain a hyphen so it can't collide with pool agent IDs", id)
+	}
+	if len(id) > 128 {
+		return fmt.Errorf("spawn ID %q is longer than 128 characters", id)
+	}
+	for _, c := range id {
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') ||
+			(c >= '0' && c <= '9') || c == '-' || c == '_' || c == '.' {
+			continue
+		}
+		return fmt.Errorf("spawn ID %q may only contain letters, digits, '-', '_', and '.'", id)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package daemon
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestNewSpawnIDAppliesPrefix(t *testing.T) {
+	t.Parallel()
+
+	pattern := regexp.MustCompile(`^PROJ-42-[a-z]+_[a-z]+-[0-9a-f]{4}$`)
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		id, err := NewSpawnID("PROJ-42-", "")
+		if err != nil {
+			t.Fatalf("NewSpawnID: %v", err)
+		}
+		if !pattern.MatchString(id) {
+			t.Fatalf("id = %q, want PROJ-42-<name>-<4hex>", id)
+		}
+		if seen[id] {
+			t.Fatalf("duplicate spawn ID %q after %d draws", id, i)
+		}
+		seen[id] = true
+	}
+}
+
+func TestNewSpawnIDTemplate(t *testing.T) {
+	t.Parallel()
+
+	id, err := NewSpawnID("team", "{{prefix}}-{{hex}}-{{name}}")
+	if err != nil {
+		t.Fatalf("NewSpawnID: %v", err)
+	}
+	if !regexp.MustCompile(`^team-[0-9a-f]{4}-[a-z]+_[a-z]+$`).MatchString(id) {
+		t.Errorf("id = %q, want team-<4hex>-<name>", id)
+	}
+}
+
+func TestNewSpawnIDRejectsUnsafeTemplates(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		prefix   string
+		template string
+		wantErr  string
+	}{
+		{"missing hex", "", "{{prefix}}{{name}}", "must include {{hex}}"},
+		{"unknown placeholder", "", "{{prefix}}{{ticket}}-{{hex}}", "unknown placeholder"},
+		{"no hyphen", "x", "{{prefix}}{{name}}_{{hex}}", "must contain a hyphen"},
+		{"path separator", "team/", "", "may only contain"},
+	}
+	for _, tt := range tests {
+		if _, err := NewSpawnID(tt.prefix, tt.template); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: err = %v, want containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -1,8 +1,12 @@
 package daemon
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"sync"
+	"syscall"
 	"time"
 )
 
@@ -12,6 +16,10 @@ const (
 	// busy team won't run 128 concurrent ad-hoc agents.
 	maxSpawnEntries = 128
 
+	// spawnPruneThreshold is the running-entry count at which Register
+	// starts pruning before it admits a new spawn: see pruneLocked.
+	spawnPruneThreshold = maxSpawnEntries * 3 / 4
+
 	// maxSpawnPromptLen caps the stored prompt to prevent large payloads
 	// from inflating daemon memory. The prompt is only used for display
 	// (truncated to 80 runes in status views), so 8 KiB is generous.
@@ -22,8 +30,17 @@ const (
 	// so af status <agent> works after the agent process exits.
 	// Uses the shared retentionTTL so all daemon data expires together.
 	exitedSpawnTTL = retentionTTL
+
+	// ephemeralSpawnTTL is the retention for spawns registered as
+	// ephemeral (af spawn --ephemeral): throwaway experiments whose
+	// history isn't worth keeping around for days.
+	ephemeralSpawnTTL = 15 * time.Minute
 )
 
+// ErrSpawnRegistryFull is returned by Register when the registry already
+// holds maxSpawnEntries running spawns.
+var ErrSpawnRegistryFull = errors.New("spawn registry full")
+
 // SpawnState is the lifecycle state of a spawn entry.
 type SpawnState string
 
@@ -41,13 +58,27 @@ const (
 // Exited entries are kept for exitedSpawnTTL so af status <agent> works
 // after exit. The periodic sweep removes them after the TTL expires.
 type SpawnEntry struct {
-	SpawnID   string     `json:"spawn_id"`
-	PID       int        `json:"pid"`
-	SessionID string     `json:"session_id,omitempty"`
-	State     SpawnState `json:"state"`
-	Prompt    string     `json:"prompt"`
-	SpawnTime time.Time  `json:"spawn_time"`
-	ExitedAt  time.Time  `json:"exited_at,omitempty"`
+	SpawnID      string     `json:"spawn_id"`
+	PID          int        `json:"pid"`
+	SessionID    string     `json:"session_id,omitempty"`
+	SessionError string     `json:"session_error,omitempty"`
+	State        SpawnState `json:"state"`
+	Prompt       string     `json:"prompt"`
+	WorktreePath string     `json:"worktree_path,omitempty"`
+	TaskID       string     `json:"task_id,omitempty"` // prog task claimed by af spawn --pick
+	SpawnTime    time.Time  `json:"spawn_time"`
+	ExitedAt     time.Time  `json:"exited_at,omitempty"`
+
+	// TTL overrides exitedSpawnTTL for this entry when positive.
+	TTL time.Duration `json:"ttl,omitempty"`
+}
+
+// retention returns how long the entry is kept after it exits.
+func (e *SpawnEntry) retention() time.Duration {
+	if e.TTL > 0 {
+		return e.TTL
+	}
+	return exitedSpawnTTL
 }
 
 // SpawnRegistry tracks spawned agents for observability.
@@ -56,13 +87,26 @@ type SpawnRegistry struct {
 	mu       sync.RWMutex
 	entries  map[string]*SpawnEntry // keyed by spawn ID
 	pidAlive func(int) bool
+
+	// stopProcess asks the spawn process with the given PID to exit.
+	// Defaults to SIGTERM on the process group; overridden in tests.
+	stopProcess func(int) error
+
+	// rejected counts new registrations turned away because the registry
+	// was full, since it last admitted one.
+	rejected int
+
+	// onChange, when set, is called after every change to the entries, so
+	// the daemon's status streams show spawns without waiting to refresh.
+	onChange func()
 }
 
 // NewSpawnRegistry creates an empty registry.
 func NewSpawnRegistry() *SpawnRegistry {
 	return &SpawnRegistry{
-		entries:  make(map[string]*SpawnEntry),
-		pidAlive: defaultPIDAlive,
+		entries:     make(map[string]*SpawnEntry),
+		pidAlive:    defaultPIDAlive,
+		stopProcess: defaultStopProcess,
 	}
 }
 
@@ -91,21 +135,72 @@ func (r *SpawnRegistry) Register(entry SpawnEntry) error {
 	// new entries when running entries are at capacity. Exited entries don't
 	// count — they're observability artifacts bounded by TTL, not resources.
 	if _, exists := r.entries[entry.SpawnID]; !exists {
-		running := 0
-		for _, e := range r.entries {
-			if e.State == SpawnRunning {
-				running++
-			}
+		running := r.runningLocked()
+		if running >= spawnPruneThreshold {
+			r.pruneLocked(time.Now())
+			running = r.runningLocked()
 		}
 		if running >= maxSpawnEntries {
-			return fmt.Errorf("spawn registry full (%d running entries)", maxSpawnEntries)
+			r.rejected++
+			return fmt.Errorf("%w (%d running entries)", ErrSpawnRegistryFull, maxSpawnEntries)
 		}
+		r.rejected = 0
 	}
 
 	r.entries[entry.SpawnID] = &entry
+	r.notifyChange()
 	return nil
 }
 
+// notifyChange calls onChange, if set. Caller must hold r.mu for writing.
+func (r *SpawnRegistry) notifyChange() {
+	if r.onChange != nil {
+		r.onChange()
+	}
+}
+
+// runningLocked counts running entries. Caller must hold r.mu.
+func (r *SpawnRegistry) runningLocked() int {
+	running := 0
+	for _, e := range r.entries {
+		if e.State == SpawnRunning {
+			running++
+		}
+	}
+	return running
+}
+
+// pruneLocked makes room in a registry near capacity without waiting for
+// the periodic sweep: running entries whose process is gone are marked
+// exited, and exited entries are dropped once past ephemeralSpawnTTL
+// rather than their full retention. Unlike SweepDead it checks PIDs under
+// the write lock, which is acceptable on this rare path.
+// Caller must hold r.mu.
+func (r *SpawnRegistry) pruneLocked(now time.Time) {
+	for id, entry := range r.entries {
+		switch entry.State {
+		case SpawnRunning:
+			if !r.pidAlive(entry.PID) {
+				entry.State = SpawnExited
+				entry.ExitedAt = now
+			}
+		case SpawnExited:
+			if now.Sub(entry.ExitedAt) > min(entry.retention(), ephemeralSpawnTTL) {
+				delete(r.entries, id)
+			}
+		}
+	}
+}
+
+// Saturation reports whether the registry is at capacity, and how many new
+// registrations it has rejected since it last admitted one. While it is
+// full, new af spawn agents still run but the daemon does not track them.
+func (r *SpawnRegistry) Saturation() (full bool, rejected int) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.runningLocked() >= maxSpawnEntries, r.rejected
+}
+
 // MarkExited transitions a spawn entry from running to exited.
 // The entry remains in the registry (preserving the agent→session mapping)
 // until the periodic sweep removes it after exitedSpawnTTL.
@@ -124,6 +219,7 @@ func (r *SpawnRegistry) MarkExited(spawnID string) bool {
 	}
 	entry.State = SpawnExited
 	entry.ExitedAt = now
+	r.notifyChange()
 	return true
 }
 
@@ -148,9 +244,55 @@ func (r *SpawnRegistry) SetSessionID(spawnID, sessionID string) bool {
 		return false
 	}
 	entry.SessionID = sessionID
+	entry.SessionError = ""
+	r.notifyChange()
 	return true
 }
 
+// SetSessionError records why an existing spawn entry could not be assigned
+// a session. Returns false when the spawn is not registered.
+func (r *SpawnRegistry) SetSessionError(spawnID, msg string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	entry, ok := r.entries[spawnID]
+	if !ok {
+		return false
+	}
+	entry.SessionError = msg
+	r.notifyChange()
+	return true
+}
+
+// HoldsTask reports whether a running spawn claimed taskID.
+func (r *SpawnRegistry) HoldsTask(taskID string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, e := range r.entries {
+		if e.State == SpawnRunning && e.TaskID == taskID {
+			return true
+		}
+	}
+	return false
+}
+
+// ReleasedTask reports whether a spawn that claimed taskID has exited and
+// no running spawn holds it any more.
+func (r *SpawnRegistry) ReleasedTask(taskID string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	released := false
+	for _, e := range r.entries {
+		if e.TaskID != taskID {
+			continue
+		}
+		if e.State == SpawnRunning {
+			return false
+		}
+		released = true
+	}
+	return released
+}
+
 // List returns all registered spawn entries.
 func (r *SpawnRegistry) List() []SpawnEntry {
 	r.mu.RLock()
@@ -166,13 +308,99 @@ func (r *SpawnRegistry) List() []SpawnEntry {
 type SweepResult struct {
 	Marked  int // running entries transitioned to exited
 	Removed int // exited entries deleted past TTL
+
+	// Exited lists the IDs of the entries marked exited.
+	Exited []string
 }
 
 // Total returns the number of entries affected by the sweep.
 func (r SweepResult) Total() int { return r.Marked + r.Removed }
 
+// SpawnStopResult reports what StopAll or StopBySession did with one
+// running spawn.
+type SpawnStopResult struct {
+	SpawnID string `json:"spawn_id"`
+	PID     int    `json:"pid"`
+	Stopped bool   `json:"stopped"`         // signalled and marked exited
+	Error   string `json:"error,omitempty"` // signal failure; entry left running
+}
+
+// StopAll signals every running spawn to exit and marks it exited.
+// A spawn whose process is already gone is marked exited too. With dryRun,
+// nothing is signalled or changed; the running spawns are only reported.
+// Results are sorted by spawn ID.
+//
+// Signals are sent outside the lock, like SweepDead's liveness checks, and
+// an entry is only marked if it is still the same running registration.
+func (r *SpawnRegistry) StopAll(dryRun bool) []SpawnStopResult {
+	r.mu.RLock()
+	var running []SpawnEntry
+	for _, entry := range r.entries {
+		if entry.State == SpawnRunning {
+			running = append(running, *entry)
+		}
+	}
+	r.mu.RUnlock()
+	slices.SortFunc(running, func(a, b SpawnEntry) int { return strings.Compare(a.SpawnID, b.SpawnID) })
+
+	results := make([]SpawnStopResult, 0, len(running))
+	for _, entry := range running {
+		if dryRun {
+			results = append(results, SpawnStopResult{SpawnID: entry.SpawnID, PID: entry.PID})
+			continue
+		}
+		results = append(results, r.stop(entry))
+	}
+	return results
+}
+
+// StopBySession stops the running spawn whose opencode session is
+// sessionID, as StopAll does for each spawn. It reports false when no
+// running spawn owns the session.
+func (r *SpawnRegistry) StopBySession(sessionID string) (SpawnStopResult, bool) {
+	r.mu.RLock()
+	var target *SpawnEntry
+	for _, entry := range r.entries {
+		if entry.State == SpawnRunning && entry.SessionID == sessionID {
+			e := *entry
+			target = &e
+			break
+		}
+	}
+	r.mu.RUnlock()
+	if target == nil {
+		return SpawnStopResult{}, false
+	}
+	return r.stop(*target), true
+}
+
+// stop signals one running spawn and marks it exited. A spawn whose
+// process is already gone is marked without a signal, so a reused PID is
+// never signalled. The caller must not hold r.mu.
+func (r *SpawnRegistry) stop(entry SpawnEntry) SpawnStopResult {
+	result := SpawnStopResult{SpawnID: entry.SpawnID, PID: entry.PID}
+	if r.pidAlive(entry.PID) {
+		if err := r.stopProcess(entry.PID); err != nil && !errors.Is(err, syscall.ESRCH) {
+			result.Error = err.Error()
+			return result
+		}
+	}
+
+	now := time.Now()
+	r.mu.Lock()
+	if current, ok := r.entries[entry.SpawnID]; ok && current.State == SpawnRunning && current.PID == entry.PID {
+		current.State = SpawnExited
+		current.ExitedAt = now
+		r.notifyChange()
+	}
+	r.mu.Unlock()
+	result.Stopped = true
+	return result
+}
+
 // SweepDead marks running entries whose PID is no longer alive as exited,
-// and removes exited entries that have exceeded exitedSpawnTTL.
+// and removes exited entries that have exceeded their retention
+// (exitedSpawnTTL unless the entry sets its own TTL).
 // Called periodically by the daemon.
 //
 // Uses a two-phase approach: collect candidates under read lock (so
@@ -193,7 +421,7 @@ func (r *SpawnRegistry) SweepDead() SweepResult {
 				toMark = append(toMark, id)
 			}
 		case SpawnExited:
-			if now.Sub(entry.ExitedAt) > exitedSpawnTTL {
+			if now.Sub(entry.ExitedAt) > entry.retention() {
 				toRemove = append(toRemove, id)
 			}
 		default:
@@ -218,6 +446,7 @@ func (r *SpawnRegistry) SweepDead() SweepResult {
 			entry.State = SpawnExited
 			entry.ExitedAt = now
 			result.Marked++
+			result.Exited = append(result.Exited, id)
 		}
 	}
 	for _, id := range toRemove {
@@ -226,5 +455,63 @@ func (r *SpawnRegistry) SweepDead() SweepResult {
 			result.Removed++
 		}
 	}
+	if result.Total() > 0 {
+		r.notifyChange()
+	}
 	return result
 }
+
+// SweepIdle marks running entries exited when their session has had no
+// events for longer than timeout, even though the PID is still alive.
+// This catches detached spawns that finished their work but never exited.
+//
+// Activity is the latest event timestamp for the entry's session, falling
+// back to the spawn time when the buffer holds no events. Entries without
+// a session ID are skipped — there is nothing to judge idleness by yet.
+// Returns copies of the entries that were marked exited.
+func (r *SpawnRegistry) SweepIdle(events *EventBuffer, timeout time.Duration) []SpawnEntry {
+	if timeout <= 0 {
+		return nil
+	}
+	now := time.Now()
+
+	// Phase 1: identify candidates under read lock (event lookups take
+	// the buffer's own lock, so keep them out of the write section).
+	r.mu.RLock()
+	var candidates []string
+	for id, entry := range r.entries {
+		if entry.State != SpawnRunning || entry.SessionID == "" {
+			continue
+		}
+		last := entry.SpawnTime
+		if ts, ok := latestEventTime(events, entry.SessionID); ok && ts.After(last) {
+			last = ts
+		}
+		if now.Sub(last) > timeout {
+			candidates = append(candidates, id)
+		}
+	}
+	r.mu.RUnlock()
+
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	// Phase 2: mark under write lock, re-checking state.
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var marked []SpawnEntry
+	for _, id := range candidates {
+		entry, exists := r.entries[id]
+		if !exists || entry.State != SpawnRunning {
+			continue
+		}
+		entry.State = SpawnExited
+		entry.ExitedAt = now
+		marked = append(marked, *entry)
+	}
+	if len(marked) > 0 {
+		r.notifyChange()
+	}
+	return marked
+}
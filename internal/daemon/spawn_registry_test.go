@@ -2,6 +2,8 @@ package daemon
 
 import (
 	"fmt"
+	"os/exec"
+	"syscall"
 	"testing"
 	"time"
 )
@@ -124,6 +126,7 @@ func TestSpawnRegistryRegisterOverwrites(t *testing.T) {
 
 func TestSpawnRegistryRegisterFullCountsOnlyRunning(t *testing.T) {
 	r := NewSpawnRegistry()
+	r.pidAlive = func(pid int) bool { return true }
 
 	// Fill the registry to capacity with running entries.
 	for i := 0; i < maxSpawnEntries; i++ {
@@ -176,6 +179,34 @@ func TestSpawnRegistryRegisterFullExitedDontCount(t *testing.T) {
 	}
 }
 
+func TestSpawnRegistryRegisterPrunesNearCapacity(t *testing.T) {
+	r := NewSpawnRegistry()
+	alive := map[int]bool{}
+	r.pidAlive = func(pid int) bool { return alive[pid] }
+
+	for i := 0; i < maxSpawnEntries; i++ {
+		alive[i+1] = true
+		if err := r.Register(SpawnEntry{SpawnID: fmt.Sprintf("spawn-%d", i), PID: i + 1, State: SpawnRunning}); err != nil {
+			t.Fatalf("Register(%d) returned unexpected error: %v", i, err)
+		}
+	}
+	r.entries["spawn-old-exit"] = &SpawnEntry{SpawnID: "spawn-old-exit", PID: 5000, State: SpawnExited, ExitedAt: time.Now().Add(-2 * ephemeralSpawnTTL)}
+	alive[1] = false // spawn-0's process is gone but not yet swept
+
+	// The registry is full of running entries, but one is dead: pruning
+	// frees its slot instead of rejecting the new spawn.
+	alive[9999] = true
+	if err := r.Register(SpawnEntry{SpawnID: "spawn-new", PID: 9999, State: SpawnRunning}); err != nil {
+		t.Fatalf("Register should prune the dead entry and succeed, got: %v", err)
+	}
+	if got := r.Get("spawn-0"); got == nil || got.State != SpawnExited {
+		t.Errorf("spawn-0 = %+v, want marked exited", got)
+	}
+	if r.Get("spawn-old-exit") != nil {
+		t.Error("exited entry past ephemeralSpawnTTL should be pruned near capacity")
+	}
+}
+
 func TestSpawnRegistryRegisterPanicsOnInvalidState(t *testing.T) {
 	r := NewSpawnRegistry()
 
@@ -280,6 +311,39 @@ func TestSpawnRegistrySweepRemovesExpiredExited(t *testing.T) {
 	}
 }
 
+func TestSpawnRegistrySweepRemovesEphemeralSooner(t *testing.T) {
+	r := NewSpawnRegistry()
+	r.pidAlive = func(pid int) bool { return true }
+
+	// Both exited at the same time: past the ephemeral TTL but well within
+	// the default one.
+	exitedAt := time.Now().Add(-2 * ephemeralSpawnTTL)
+	_ = r.Register(SpawnEntry{
+		SpawnID:  "spawn-ephemeral",
+		PID:      100,
+		State:    SpawnExited,
+		ExitedAt: exitedAt,
+		TTL:      ephemeralSpawnTTL,
+	})
+	_ = r.Register(SpawnEntry{
+		SpawnID:  "spawn-normal",
+		PID:      200,
+		State:    SpawnExited,
+		ExitedAt: exitedAt,
+	})
+
+	result := r.SweepDead()
+	if result.Removed != 1 {
+		t.Errorf("SweepDead removed %d, want 1", result.Removed)
+	}
+	if r.Get("spawn-ephemeral") != nil {
+		t.Error("ephemeral entry past its TTL should have been removed")
+	}
+	if r.Get("spawn-normal") == nil {
+		t.Error("normal entry should be kept until exitedSpawnTTL")
+	}
+}
+
 func TestSpawnRegistrySweepDoesNotRemoveReRegisteredEntry(t *testing.T) {
 	// Regression test for TOCTOU race: if an entry is identified for removal
 	// in phase 1 but re-registered (as running) before phase 2, the sweep
@@ -400,3 +464,165 @@ func TestSpawnRegistrySweepDeadEmpty(t *testing.T) {
 		t.Errorf("SweepDead total %d from empty registry, want 0", result.Total())
 	}
 }
+
+func TestSpawnRegistrySweepIdleMarksExited(t *testing.T) {
+	r := NewSpawnRegistry()
+	r.pidAlive = func(pid int) bool { return true }
+	events := NewEventBuffer(DefaultEventBufSize)
+
+	old := time.Now().Add(-2 * time.Hour)
+	_ = r.Register(SpawnEntry{SpawnID: "spawn-idle", PID: 100, SessionID: "ses-idle", State: SpawnRunning, SpawnTime: old})
+	_ = r.Register(SpawnEntry{SpawnID: "spawn-busy", PID: 200, SessionID: "ses-busy", State: SpawnRunning, SpawnTime: old})
+	_ = r.Register(SpawnEntry{SpawnID: "spawn-nosession", PID: 300, State: SpawnRunning, SpawnTime: old})
+
+	events.Push(SessionEvent{EventType: "message.updated", SessionID: "ses-idle", Timestamp: old.Add(time.Minute).UnixMilli()})
+	events.Push(SessionEvent{EventType: "message.updated", SessionID: "ses-busy", Timestamp: time.Now().UnixMilli()})
+
+	marked := r.SweepIdle(events, 30*time.Minute)
+	if len(marked) != 1 || marked[0].SpawnID != "spawn-idle" {
+		t.Fatalf("SweepIdle marked %v, want only spawn-idle", marked)
+	}
+
+	idle := r.Get("spawn-idle")
+	if idle == nil || idle.State != SpawnExited {
+		t.Fatalf("spawn-idle = %+v, want exited", idle)
+	}
+	if idle.ExitedAt.IsZero() {
+		t.Error("ExitedAt should be set")
+	}
+	if b := r.Get("spawn-busy"); b == nil || b.State != SpawnRunning {
+		t.Error("spawn-busy should still be running")
+	}
+	if n := r.Get("spawn-nosession"); n == nil || n.State != SpawnRunning {
+		t.Error("spawn without a session should still be running")
+	}
+}
+
+func TestSpawnRegistrySweepIdleFallsBackToSpawnTime(t *testing.T) {
+	r := NewSpawnRegistry()
+	events := NewEventBuffer(DefaultEventBufSize)
+
+	_ = r.Register(SpawnEntry{SpawnID: "spawn-fresh", PID: 100, SessionID: "ses-fresh", State: SpawnRunning, SpawnTime: time.Now()})
+	_ = r.Register(SpawnEntry{SpawnID: "spawn-silent", PID: 200, SessionID: "ses-silent", State: SpawnRunning, SpawnTime: time.Now().Add(-time.Hour)})
+
+	marked := r.SweepIdle(events, 30*time.Minute)
+	if len(marked) != 1 || marked[0].SpawnID != "spawn-silent" {
+		t.Fatalf("SweepIdle marked %v, want only spawn-silent", marked)
+	}
+	if got := r.SweepIdle(events, 0); got != nil {
+		t.Errorf("SweepIdle with zero timeout = %v, want nil", got)
+	}
+}
+
+func TestSpawnRegistryStopAll(t *testing.T) {
+	r := NewSpawnRegistry()
+	r.pidAlive = func(int) bool { return true }
+	var signalled []int
+	r.stopProcess = func(pid int) error {
+		signalled = append(signalled, pid)
+		return nil
+	}
+	for _, e := range []SpawnEntry{
+		{SpawnID: "spawn-a", PID: 101, State: SpawnRunning},
+		{SpawnID: "spawn-b", PID: 102, State: SpawnRunning},
+		{SpawnID: "spawn-done", PID: 103, State: SpawnExited, ExitedAt: time.Now()},
+	} {
+		if err := r.Register(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	preview := r.StopAll(true)
+	if len(preview) != 2 || len(signalled) != 0 {
+		t.Fatalf("dry run: results = %+v, signalled = %v; want 2 results and no signals", preview, signalled)
+	}
+	if got := r.Get("spawn-a"); got.State != SpawnRunning {
+		t.Errorf("dry run changed spawn-a state to %q", got.State)
+	}
+
+	results := r.StopAll(false)
+	if len(results) != 2 {
+		t.Fatalf("results = %+v, want 2", results)
+	}
+	for i, want := range []string{"spawn-a", "spawn-b"} {
+		if results[i].SpawnID != want || !results[i].Stopped {
+			t.Errorf("results[%d] = %+v, want %s stopped", i, results[i], want)
+		}
+		if got := r.Get(want); got.State != SpawnExited || got.ExitedAt.IsZero() {
+			t.Errorf("%s state = %q exited_at = %v, want exited", want, got.State, got.ExitedAt)
+		}
+	}
+	if len(signalled) != 2 || signalled[0] != 101 || signalled[1] != 102 {
+		t.Errorf("signalled = %v, want [101 102]", signalled)
+	}
+}
+
+func TestSpawnRegistryStopSkipsSignalForDeadPID(t *testing.T) {
+	r := NewSpawnRegistry()
+	r.pidAlive = func(int) bool { return false }
+	r.stopProcess = func(pid int) error {
+		t.Errorf("signalled pid %d, whose process is gone", pid)
+		return nil
+	}
+	if err := r.Register(SpawnEntry{SpawnID: "spawn-gone", PID: 101, State: SpawnRunning}); err != nil {
+		t.Fatal(err)
+	}
+
+	results := r.StopAll(false)
+	if len(results) != 1 || !results[0].Stopped {
+		t.Fatalf("results = %+v, want spawn-gone marked stopped", results)
+	}
+	if got := r.Get("spawn-gone").State; got != SpawnExited {
+		t.Errorf("state = %q, want exited", got)
+	}
+}
+
+func TestDefaultStopProcessOnlySignalsGroupLeaders(t *testing.T) {
+	if err := defaultStopProcess(1); err == nil {
+		t.Error("defaultStopProcess(1) succeeded, want refused")
+	}
+
+	// A child in our own process group doesn't lead it, so signalling
+	// its PID as a group must be refused.
+	member := exec.Command("sleep", "30")
+	if err := member.Start(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = member.Process.Kill(); _ = member.Wait() })
+	if err := defaultStopProcess(member.Process.Pid); err == nil {
+		t.Error("defaultStopProcess signalled a process that does not lead its group")
+	}
+
+	leader := exec.Command("sleep", "30")
+	leader.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	if err := leader.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := defaultStopProcess(leader.Process.Pid); err != nil {
+		t.Fatalf("defaultStopProcess(group leader): %v", err)
+	}
+	if err := leader.Wait(); err == nil {
+		t.Error("group leader exited cleanly, want terminated by SIGTERM")
+	}
+}
+
+func TestSpawnRegistryNotifiesChanges(t *testing.T) {
+	r := NewSpawnRegistry()
+	notified := 0
+	r.onChange = func() { notified++ }
+
+	if err := r.Register(SpawnEntry{SpawnID: "spawn-a", PID: 101, State: SpawnRunning}); err != nil {
+		t.Fatal(err)
+	}
+	r.SetSessionID("spawn-a", "ses_a")
+	r.MarkExited("spawn-a")
+	if notified != 3 {
+		t.Errorf("onChange called %d times, want 3 (register, session, exit)", notified)
+	}
+
+	r.MarkExited("spawn-a") // already exited: no change
+	r.SetSessionID("spawn-missing", "ses_b")
+	if notified != 3 {
+		t.Errorf("onChange called %d times after no-op updates, want 3", notified)
+	}
+}
@@ -0,0 +1,66 @@
+package daemon
+
+import (
+	"fmt"
+	"time"
+)
+
+// failedProbe reports why the agent for taskID, which just exited after
+// uptime, failed its startup probe, or "" if it passed or no probe is set.
+// It clears the record of a session probe stop.
+// Caller must hold p.mu for writing.
+func (p *Pool) failedProbe(taskID string, exitCode int, uptime time.Duration) string {
+	if reason, ok := p.probed[taskID]; ok {
+		delete(p.probed, taskID)
+		return reason
+	}
+	if window := p.config.StartupProbe; window > 0 && uptime < window {
+		return fmt.Sprintf("exited with code %d after %v, within startup probe %v", exitCode, uptime.Round(time.Millisecond), window)
+	}
+	return ""
+}
+
+// probeSession stops agent if it has not opened its opencode session by
+// the end of the startup probe window, when StartupProbeSession is set.
+// reap then counts the exit as a startup failure. An agent resuming a
+// session already has one and is not probed.
+func (p *Pool) probeSession(agent *Agent) {
+	p.mu.RLock()
+	window := p.config.StartupProbe
+	probe := window > 0 && p.config.StartupProbeSession && agent.SessionID == ""
+	p.mu.RUnlock()
+	if !probe {
+		return
+	}
+
+	expired := p.clock.After(window)
+	go func() {
+		<-expired
+
+		p.mu.Lock()
+		if p.agents[agent.TaskID] != agent || agent.State != AgentRunning || agent.SessionID != "" {
+			p.mu.Unlock()
+			return
+		}
+		reason := fmt.Sprintf("no session within startup probe %v", window)
+		p.probed[agent.TaskID] = reason
+		p.mu.Unlock()
+
+		p.taskLog(agent.TaskID).Warn("agent failed its startup probe, stopping it",
+			"agent_id", agent.ID,
+			"task_id", agent.TaskID,
+			"pid", agent.PID,
+			"reason", reason,
+		)
+		if err := p.stopProcess(agent.PID); err != nil {
+			p.mu.Lock()
+			delete(p.probed, agent.TaskID)
+			p.mu.Unlock()
+			p.taskLog(agent.TaskID).Warn("failed to stop agent after startup probe",
+				"agent_id", agent.ID,
+				"pid", agent.PID,
+				"error", err,
+			)
+		}
+	}()
+}
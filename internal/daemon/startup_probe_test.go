@@ -0,0 +1,134 @@
+package daemon
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// probePool returns a pool with a startup probe whose starter hands out
+// fake processes; release(i) makes the i-th spawned agent exit cleanly.
+func probePool(t *testing.T, session bool) (pool *Pool, clock *fakeClock, spawns func() int, release func(i int)) {
+	t.Helper()
+	var mu sync.Mutex
+	var releases []func()
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		proc, rel := newFakeProcess(100 * (len(releases) + 1))
+		releases = append(releases, sync.OnceFunc(rel))
+		return proc, nil
+	}
+
+	cfg := Config{
+		Project:             "testproject",
+		PoolSize:            2,
+		SpawnCmd:            "fake-agent",
+		MaxStartupRetries:   1,
+		StartupProbe:        10 * time.Second,
+		StartupProbeSession: session,
+	}
+	cfg.ApplyDefaults()
+	pool = NewPool(cfg, progRunner(testTaskMeta), starter, slog.Default())
+	clock = newFakeClock(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	pool.clock = clock
+	pool.ctx = context.Background()
+
+	spawns = func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(releases)
+	}
+	release = func(i int) {
+		mu.Lock()
+		rel := releases[i]
+		mu.Unlock()
+		rel()
+	}
+	// Stopping an agent makes its fake process exit.
+	pool.stopProcess = func(pid int) error {
+		release(pid/100 - 1)
+		return nil
+	}
+	return pool, clock, spawns, release
+}
+
+func TestStartupProbeImmediateExitIsStartupFailure(t *testing.T) {
+	pool, _, spawns, release := probePool(t, false)
+	pool.schedule(context.Background(), []Task{{ID: "ts-abc"}})
+
+	// Exiting cleanly inside the probe window is still a startup failure.
+	release(0)
+	waitFor(t, func() bool { return spawns() == 2 })
+	pool.mu.RLock()
+	startup, retries := pool.startup["ts-abc"], pool.retries["ts-abc"]
+	pool.mu.RUnlock()
+	if startup != 1 || retries != 0 {
+		t.Errorf("startup=%d retries=%d, want 1 and 0", startup, retries)
+	}
+
+	// The second one exhausts MaxStartupRetries.
+	release(1)
+	waitFor(t, func() bool { return len(pool.Status()) == 0 })
+	time.Sleep(50 * time.Millisecond)
+	if got := spawns(); got != 2 {
+		t.Errorf("spawns = %d, want 2 (no respawn after startup retries exhausted)", got)
+	}
+	errs := pool.RecentErrors(1)
+	if len(errs) != 1 || errs[0].Kind != OpErrorStartupFailed || !strings.Contains(errs[0].Message, "startup probe") {
+		t.Errorf("recent errors = %+v, want one %s about the startup probe", errs, OpErrorStartupFailed)
+	}
+	if got := pool.History()[0].Outcome; got != TaskOutcomeCrashed {
+		t.Errorf("outcome = %q, want %q", got, TaskOutcomeCrashed)
+	}
+}
+
+func TestStartupProbeCleanExitAfterWindowCompletes(t *testing.T) {
+	pool, clock, spawns, release := probePool(t, false)
+	pool.schedule(context.Background(), []Task{{ID: "ts-abc"}})
+
+	clock.Advance(time.Minute)
+	release(0)
+	waitFor(t, func() bool { return len(pool.Status()) == 0 })
+	time.Sleep(50 * time.Millisecond)
+	if got := spawns(); got != 1 {
+		t.Errorf("spawns = %d, want 1", got)
+	}
+	if got := pool.History()[0].Outcome; got != TaskOutcomeCompleted {
+		t.Errorf("outcome = %q, want %q", got, TaskOutcomeCompleted)
+	}
+}
+
+func TestStartupProbeStopsAgentWithoutSession(t *testing.T) {
+	pool, clock, spawns, _ := probePool(t, true)
+	pool.schedule(context.Background(), []Task{{ID: "ts-abc"}})
+
+	// No session by the end of the window: stopped and respawned as a
+	// startup failure.
+	clock.Advance(10 * time.Second)
+	waitFor(t, func() bool { return spawns() == 2 })
+	pool.mu.RLock()
+	startup := pool.startup["ts-abc"]
+	pool.mu.RUnlock()
+	if startup != 1 {
+		t.Errorf("startup failures = %d, want 1", startup)
+	}
+
+	// The respawn opens its session in time and is left alone.
+	waitFor(t, func() bool { return clock.waiterCount() == 1 })
+	pool.mu.Lock()
+	pool.agents["ts-abc"].SessionID = "ses-1"
+	pool.mu.Unlock()
+	clock.Advance(10 * time.Second)
+	time.Sleep(50 * time.Millisecond)
+	if got := spawns(); got != 2 {
+		t.Errorf("spawns = %d, want 2 (agent with a session kept running)", got)
+	}
+	if agents := pool.Status(); len(agents) != 1 || agents[0].State != AgentRunning {
+		t.Errorf("agents = %+v, want one running", agents)
+	}
+}
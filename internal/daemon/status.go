@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 	"sort"
 	"sync"
 	"time"
@@ -16,12 +18,49 @@ import (
 type FullStatus struct {
 	PoolSize    int           `json:"pool_size"`
 	PoolMode    PoolMode      `json:"pool_mode"`
+	MaxRetries  int           `json:"max_retries,omitempty"`
 	Project     string        `json:"project"`
+	Instance    string        `json:"instance,omitempty"`
 	SpawnPolicy SpawnPolicy   `json:"spawn_policy"`
 	Agents      []AgentStatus `json:"agents"`
 	Spawns      []SpawnStatus `json:"spawns,omitempty"`
 	Queue       []Task        `json:"queue"`
 	Errors      []string      `json:"errors,omitempty"`
+
+	// SkipReasons says why queued tasks aren't running, keyed by task ID,
+	// as of the last schedule pass. Only tasks still in Queue are included.
+	SkipReasons map[string]string `json:"skip_reasons,omitempty"`
+
+	// SessionStoreError is set while the session registry cannot be opened:
+	// session capture and resume are degraded until it recovers.
+	SessionStoreError string `json:"session_store_error,omitempty"`
+
+	// SpawnsFull is set while the spawn registry is at capacity: new af
+	// spawn agents still run but don't show in status or logs.
+	// SpawnsRejected counts the registrations turned away since the
+	// registry last admitted one.
+	SpawnsFull     bool `json:"spawns_full,omitempty"`
+	SpawnsRejected int  `json:"spawns_rejected,omitempty"`
+
+	// CompletedLastHour counts pool agents that exited cleanly in the last
+	// hour; RatePerHour is the matching hourly completion rate.
+	CompletedLastHour int     `json:"completed_last_hour"`
+	RatePerHour       float64 `json:"rate_per_hour"`
+
+	// Projects breaks status down per project when the daemon schedules
+	// more than one (see Config.Projects), primary first. The top-level
+	// pool, agent, and queue fields cover the primary project only.
+	Projects []ProjectStatus `json:"projects,omitempty"`
+}
+
+// ProjectStatus is one project's share of a multi-project daemon.
+type ProjectStatus struct {
+	Project           string        `json:"project"`
+	PoolSize          int           `json:"pool_size"`
+	PoolMode          PoolMode      `json:"pool_mode"`
+	Agents            []AgentStatus `json:"agents"`
+	Queue             []Task        `json:"queue"`
+	CompletedLastHour int           `json:"completed_last_hour"`
 }
 
 // SpawnStatus is the status of a spawned agent registered with the daemon.
@@ -34,6 +73,8 @@ type SpawnStatus struct {
 	LastActivityAt  time.Time  `json:"last_activity_at,omitempty"`
 	AttentionNeeded bool       `json:"attention_needed,omitempty"`
 	Prompt          string     `json:"prompt"`
+	WorktreePath    string     `json:"worktree_path,omitempty"`
+	Branch          string     `json:"branch,omitempty"`
 	SpawnTime       time.Time  `json:"spawn_time"`
 	ExitedAt        time.Time  `json:"exited_at,omitempty"`
 }
@@ -46,14 +87,24 @@ type AgentStatus struct {
 	PID             int       `json:"pid"`
 	SpawnTime       time.Time `json:"spawn_time"`
 	TaskTitle       string    `json:"task_title"`
+	Branch          string    `json:"branch,omitempty"`
 	LastLog         string    `json:"last_log,omitempty"`
 	SessionID       string    `json:"session_id,omitempty"`
+	SessionCaptured bool      `json:"session_captured"`
+	SessionError    string    `json:"session_error,omitempty"`
 	State           string    `json:"state,omitempty"`
 	LifecycleState  string    `json:"lifecycle_state,omitempty"`
 	LastActivityAt  time.Time `json:"last_activity_at,omitempty"`
 	AttentionNeeded bool      `json:"attention_needed,omitempty"`
 }
 
+// agentBranch returns the git branch an agent works on. Agents name their
+// worktree branch af/<task-id> (pool) or af/<spawn-id> (spawn), as the
+// prompts instruct.
+func agentBranch(workRef string) string {
+	return "af/" + workRef
+}
+
 // taskShowResponse is the sparse parse target for `prog show --json`.
 // Only the fields needed for status display are included.
 type taskShowResponse struct {
@@ -65,8 +116,8 @@ type taskShowResponse struct {
 }
 
 // BuildFullStatus assembles the full swarm status by enriching pool data
-// with task metadata from prog. Each prog show call runs in its own goroutine
-// with a per-call timeout. Partial failures are captured in the Errors slice
+// with task metadata from prog, fetched alongside the queue with per-call
+// timeouts (see fetchTaskSummaries). Partial failures are captured in the Errors slice
 // rather than failing the entire request.
 func BuildFullStatus(ctx context.Context, pool *Pool, spawns *SpawnRegistry, sstore *sessions.Store, events *EventBuffer, cfg Config, runner CommandRunner) FullStatus {
 	policy := cfg.SpawnPolicy.Normalized()
@@ -74,8 +125,16 @@ func BuildFullStatus(ctx context.Context, pool *Pool, spawns *SpawnRegistry, sst
 	status := FullStatus{
 		PoolSize:    cfg.PoolSize,
 		Project:     cfg.Project,
+		Instance:    cfg.InstanceName,
 		SpawnPolicy: policy,
 	}
+	if pool != nil {
+		// The pool's size and retry budget can change on config reload or
+		// af pool set; cfg is the startup value.
+		params := pool.params()
+		status.PoolSize = params.PoolSize
+		status.MaxRetries = params.MaxRetries
+	}
 	sessionIndex, sessionIndexErr := loadSessionIndex(sstore, cfg.ServerURL)
 	if sessionIndexErr != nil {
 		status.Errors = append(status.Errors, fmt.Sprintf("session index: %v", sessionIndexErr))
@@ -83,19 +142,23 @@ func BuildFullStatus(ctx context.Context, pool *Pool, spawns *SpawnRegistry, sst
 
 	if pool != nil {
 		status.PoolMode = pool.Mode()
+		status.CompletedLastHour, status.RatePerHour = pool.Throughput()
 
 		agents := pool.Status()
 		enriched := make([]AgentStatus, len(agents))
 		for i, agent := range agents {
 			enriched[i] = AgentStatus{
-				ID:             string(agent.ID),
-				TaskID:         agent.TaskID,
-				Role:           string(agent.Role),
-				PID:            agent.PID,
-				SpawnTime:      agent.SpawnTime,
-				SessionID:      agent.SessionID,
-				State:          string(agent.State),
-				LifecycleState: string(agent.State),
+				ID:              string(agent.ID),
+				TaskID:          agent.TaskID,
+				Role:            string(agent.Role),
+				PID:             agent.PID,
+				SpawnTime:       agent.SpawnTime,
+				Branch:          agentBranch(agent.TaskID),
+				SessionID:       agent.SessionID,
+				SessionCaptured: agent.SessionID != "",
+				SessionError:    agent.SessionError,
+				State:           string(agent.State),
+				LifecycleState:  string(agent.State),
 			}
 			applySessionSummaryToAgent(&enriched[i], sessionSummaryForAgent(agent, sessionIndex, events))
 		}
@@ -103,31 +166,19 @@ func BuildFullStatus(ctx context.Context, pool *Pool, spawns *SpawnRegistry, sst
 		// In manual mode, status must be prog-optional. Return pool snapshots
 		// only and skip all prog-dependent enrichment/queue calls.
 		if policy.ProgEnrichmentEnabled() {
-			var mu sync.Mutex
-			var errors []string
 			var wg sync.WaitGroup
 
-			for i, agent := range agents {
-				wg.Add(1)
-				go func(idx int, taskID string) {
-					defer wg.Done()
-
-					callCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
-					defer cancel()
-
-					title, lastLog, err := fetchTaskSummary(callCtx, taskID, cfg.Project, runner)
-					if err != nil {
-						mu.Lock()
-						errors = append(errors, fmt.Sprintf("prog show %s: %v", taskID, err))
-						mu.Unlock()
-						return
-					}
-
-					// No lock needed — each goroutine writes to its own index.
-					enriched[idx].TaskTitle = title
-					enriched[idx].LastLog = lastLog
-				}(i, agent.TaskID)
-			}
+			var summaries map[string]taskSummary
+			var summaryErrs map[string]error
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ids := make([]string, len(agents))
+				for i, agent := range agents {
+					ids[i] = agent.TaskID
+				}
+				summaries, summaryErrs = fetchTaskSummaries(ctx, ids, cfg.Project, runner)
+			}()
 
 			// Fetch the pending queue concurrently with agent enrichment.
 			var queue []Task
@@ -137,13 +188,27 @@ func BuildFullStatus(ctx context.Context, pool *Pool, spawns *SpawnRegistry, sst
 				defer wg.Done()
 				queueCtx, queueCancel := context.WithTimeout(ctx, 5*time.Second)
 				defer queueCancel()
-				queue, queueErr = fetchQueue(queueCtx, cfg.Project, runner)
+				queue, queueErr = pool.statusQueue(queueCtx, cfg.Project, runner)
 			}()
 
 			wg.Wait()
-			status.Errors = append(status.Errors, errors...)
+			for i, agent := range agents {
+				if err, ok := summaryErrs[agent.TaskID]; ok {
+					status.Errors = append(status.Errors, fmt.Sprintf("prog show %s: %v", agent.TaskID, err))
+					continue
+				}
+				enriched[i].TaskTitle = summaries[agent.TaskID].Title
+				enriched[i].LastLog = summaries[agent.TaskID].LastLog
+			}
 			if queueErr != nil {
-				status.Errors = append(status.Errors, fmt.Sprintf("prog ready: %v", queueErr))
+				source := "prog ready"
+				if pool.queue != nil {
+					source = "queue file"
+				}
+				status.Errors = append(status.Errors, fmt.Sprintf("%s: %v", source, queueErr))
+			} else {
+				queue = pool.withReadySince(queue)
+				status.SkipReasons = queueSkipReasons(pool.SkipReasons(), queue)
 			}
 			status.Queue = queue
 		}
@@ -157,18 +222,21 @@ func BuildFullStatus(ctx context.Context, pool *Pool, spawns *SpawnRegistry, sst
 
 	// Include spawned agents from the registry.
 	if spawns != nil {
+		status.SpawnsFull, status.SpawnsRejected = spawns.Saturation()
 		entries := spawns.List()
 		if len(entries) > 0 {
 			spawned := make([]SpawnStatus, len(entries))
 			for i, e := range entries {
 				spawned[i] = SpawnStatus{
-					SpawnID:   e.SpawnID,
-					PID:       e.PID,
-					SessionID: e.SessionID,
-					State:     e.State,
-					Prompt:    e.Prompt,
-					SpawnTime: e.SpawnTime,
-					ExitedAt:  e.ExitedAt,
+					SpawnID:      e.SpawnID,
+					PID:          e.PID,
+					SessionID:    e.SessionID,
+					State:        e.State,
+					Prompt:       e.Prompt,
+					WorktreePath: e.WorktreePath,
+					Branch:       agentBranch(e.SpawnID),
+					SpawnTime:    e.SpawnTime,
+					ExitedAt:     e.ExitedAt,
 				}
 				spawned[i].LifecycleState = string(e.State)
 				applySessionSummaryToSpawn(&spawned[i], sessionSummaryForSpawn(e, sessionIndex, events))
@@ -285,11 +353,39 @@ type AgentDetail struct {
 // StatusAgentParams is the query shape for the agent detail endpoint.
 type StatusAgentParams struct {
 	AgentName string `json:"agent_name"`
-	Limit     int    `json:"limit,omitempty"` // max tool calls to return; 0 = default (20)
+	Limit     int    `json:"limit,omitempty"`      // max tool calls to return; 0 = default (20)
+	ScanLimit int    `json:"scan_limit,omitempty"` // max events scanned, newest first; 0 = default (500)
+	// Tools keeps only tool calls with these names (e.g. "bash", "edit").
+	// The filter runs before Limit, so Limit counts matching calls only.
+	Tools []string `json:"tools,omitempty"`
 }
 
 const defaultToolCallLimit = 20
 
+// defaultToolCallScanLimit caps how many of a session's newest events are
+// scanned for tool calls, so detail building stays cheap on long sessions.
+// A tool call spans a few events (pending, running, completed), so this
+// leaves plenty of headroom for the default limit.
+const defaultToolCallScanLimit = 500
+
+// maxBatchAgents caps how many agents one batch status request may name.
+const maxBatchAgents = 64
+
+// StatusAgentsParams is the request shape for batch agent detail lookups.
+type StatusAgentsParams struct {
+	AgentNames []string `json:"agent_names"`
+	Limit      int      `json:"limit,omitempty"`      // max tool calls per agent; 0 = default (20)
+	ScanLimit  int      `json:"scan_limit,omitempty"` // max events scanned per agent; 0 = default (500)
+}
+
+// StatusAgentsResult maps each requested agent name to its detail. Agents
+// that could not be resolved (e.g. exited between polls) are reported in
+// Errors instead of failing the whole batch.
+type StatusAgentsResult struct {
+	Agents map[string]*AgentDetail `json:"agents"`
+	Errors map[string]string       `json:"errors,omitempty"`
+}
+
 // BuildAgentDetail assembles detailed status for a single agent.
 // It fetches task metadata from prog and reads tool calls from the event buffer.
 // Session ID comes from the agent's state (populated by claimSession on
@@ -320,12 +416,14 @@ func BuildAgentDetail(ctx context.Context, pool *Pool, spawns *SpawnRegistry, ss
 
 	detail := &AgentDetail{
 		AgentStatus: AgentStatus{
-			ID:        string(agent.ID),
-			TaskID:    agent.TaskID,
-			Role:      string(agent.Role),
-			PID:       agent.PID,
-			SpawnTime: agent.SpawnTime,
-			SessionID: agent.SessionID,
+			ID:              string(agent.ID),
+			TaskID:          agent.TaskID,
+			Role:            string(agent.Role),
+			PID:             agent.PID,
+			SpawnTime:       agent.SpawnTime,
+			SessionID:       agent.SessionID,
+			SessionCaptured: agent.SessionID != "",
+			SessionError:    agent.SessionError,
 		},
 	}
 	detail.Session = buildSessionMetadata(sstore, sessionMetadataFallback{
@@ -338,16 +436,7 @@ func BuildAgentDetail(ctx context.Context, pool *Pool, spawns *SpawnRegistry, ss
 		status:    string(agent.State),
 	})
 
-	limit := params.Limit
-	if limit <= 0 {
-		limit = defaultToolCallLimit
-	}
-
-	// Extract tool calls from the event buffer using the agent's session ID.
-	if events != nil && agent.SessionID != "" {
-		evs := events.Events(agent.SessionID)
-		detail.ToolCalls = ToolCallsFromEvents(evs, limit)
-	}
+	detail.ToolCalls = recentToolCalls(events, agent.SessionID, params, cfg.ToolInputFields)
 
 	// Fetch task title + last log from prog (only when prog enrichment is relevant).
 	if cfg.SpawnPolicy.Normalized().ProgEnrichmentEnabled() && agent.TaskID != "" {
@@ -375,13 +464,15 @@ const maxTitleDisplayRunes = 80
 func buildSpawnDetail(_ context.Context, entry *SpawnEntry, sstore *sessions.Store, events *EventBuffer, cfg Config, params StatusAgentParams) (*AgentDetail, error) {
 	detail := &AgentDetail{
 		AgentStatus: AgentStatus{
-			ID:        entry.SpawnID,
-			TaskID:    "",
-			Role:      string(RoleSpawn),
-			PID:       entry.PID,
-			SpawnTime: entry.SpawnTime,
-			SessionID: entry.SessionID,
-			TaskTitle: truncatePrompt(entry.Prompt, maxTitleDisplayRunes),
+			ID:              entry.SpawnID,
+			TaskID:          "",
+			Role:            string(RoleSpawn),
+			PID:             entry.PID,
+			SpawnTime:       entry.SpawnTime,
+			SessionID:       entry.SessionID,
+			SessionCaptured: entry.SessionID != "",
+			SessionError:    entry.SessionError,
+			TaskTitle:       truncatePrompt(entry.Prompt, maxTitleDisplayRunes),
 		},
 	}
 	detail.Session = buildSessionMetadata(sstore, sessionMetadataFallback{
@@ -393,18 +484,45 @@ func buildSpawnDetail(_ context.Context, entry *SpawnEntry, sstore *sessions.Sto
 		status:    string(entry.State),
 	})
 
+	detail.ToolCalls = recentToolCalls(events, entry.SessionID, params, cfg.ToolInputFields)
+
+	return detail, nil
+}
+
+// recentToolCalls extracts the session's most recent tool calls from the
+// event buffer. Only the newest ScanLimit events are scanned, so a call whose
+// later lifecycle events fall inside the window still reports its latest state.
+func recentToolCalls(events *EventBuffer, sessionID string, params StatusAgentParams, fields ToolInputFields) []ToolCall {
+	if events == nil || sessionID == "" {
+		return nil
+	}
 	limit := params.Limit
 	if limit <= 0 {
 		limit = defaultToolCallLimit
 	}
-
-	// Extract tool calls from the event buffer using the spawn's session ID.
-	if events != nil && entry.SessionID != "" {
-		evs := events.Events(entry.SessionID)
-		detail.ToolCalls = ToolCallsFromEvents(evs, limit)
+	scan := params.ScanLimit
+	if scan <= 0 {
+		scan = defaultToolCallScanLimit
+	}
+	if len(params.Tools) == 0 {
+		return ToolCallsFromEvents(events.Tail(sessionID, scan), limit, fields)
+	}
+	calls := filterToolCalls(ToolCallsFromEvents(events.Tail(sessionID, scan), 0, fields), params.Tools)
+	if len(calls) > limit {
+		calls = calls[len(calls)-limit:]
 	}
+	return calls
+}
 
-	return detail, nil
+// filterToolCalls keeps the calls whose tool name is in tools, in order.
+func filterToolCalls(calls []ToolCall, tools []string) []ToolCall {
+	out := make([]ToolCall, 0, len(calls))
+	for _, tc := range calls {
+		if slices.Contains(tools, tc.Tool) {
+			out = append(out, tc)
+		}
+	}
+	return out
 }
 
 // truncatePrompt shortens a user prompt for display in status views.
@@ -440,8 +558,156 @@ func fetchTaskSummary(ctx context.Context, taskID, project string, runner Comman
 	return resp.Title, lastLog, nil
 }
 
-// fetchQueue calls prog ready and returns the pending tasks.
-func fetchQueue(ctx context.Context, project string, runner CommandRunner) ([]Task, error) {
+// taskSummary is the part of a task status shows next to its agent.
+type taskSummary struct {
+	Title   string
+	LastLog string
+}
+
+// fetchTaskSummaries fetches the title and last log message of each task
+// in ids. With more than one task it first tries a single
+// `prog show <id>... --json`, which prog answers with a JSON array when it
+// supports showing several tasks at once. Tasks the batch didn't cover —
+// or all of them, when prog rejects the batch or prints a single object —
+// are fetched one call per task, concurrently. Failures are returned per
+// task rather than failing the whole lookup.
+func fetchTaskSummaries(ctx context.Context, ids []string, project string, runner CommandRunner) (map[string]taskSummary, map[string]error) {
+	summaries := make(map[string]taskSummary, len(ids))
+	if len(ids) > 1 {
+		callCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		batch, err := fetchTaskSummaryBatch(callCtx, ids, project, runner)
+		cancel()
+		if err == nil {
+			maps.Copy(summaries, batch)
+		}
+	}
+
+	var missing []string
+	for _, id := range ids {
+		if _, ok := summaries[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var errs map[string]error
+	for _, id := range missing {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			callCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			defer cancel()
+
+			title, lastLog, err := fetchTaskSummary(callCtx, id, project, runner)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if errs == nil {
+					errs = make(map[string]error)
+				}
+				errs[id] = err
+				return
+			}
+			summaries[id] = taskSummary{Title: title, LastLog: lastLog}
+		}()
+	}
+	wg.Wait()
+	return summaries, errs
+}
+
+// fetchTaskSummaryBatch runs one `prog show` for all of ids and decodes
+// the JSON array it prints. Any other output means prog doesn't support
+// batch show, and is returned as an error.
+func fetchTaskSummaryBatch(ctx context.Context, ids []string, project string, runner CommandRunner) (map[string]taskSummary, error) {
+	args := append([]string{"show"}, ids...)
+	args = append(args, "--json")
+	if project != "" {
+		args = append(args, "-p", project)
+	}
+
+	output, err := runner(ctx, "prog", args...)
+	if err != nil {
+		return nil, fmt.Errorf("%w (output: %s)", err, string(output))
+	}
+
+	var resp []struct {
+		ID string `json:"id"`
+		taskShowResponse
+	}
+	if err := json.Unmarshal(output, &resp); err != nil {
+		return nil, fmt.Errorf("parsing batch output: %w", err)
+	}
+
+	summaries := make(map[string]taskSummary, len(resp))
+	for _, task := range resp {
+		if task.ID == "" {
+			continue
+		}
+		summary := taskSummary{Title: task.Title}
+		if len(task.Logs) > 0 {
+			summary.LastLog = task.Logs[len(task.Logs)-1].Message
+		}
+		summaries[task.ID] = summary
+	}
+	return summaries, nil
+}
+
+// queueSkipReasons keeps the skip reasons for tasks still in queue.
+func queueSkipReasons(skips map[string]string, queue []Task) map[string]string {
+	var out map[string]string
+	for _, t := range queue {
+		reason, ok := skips[t.ID]
+		if !ok {
+			continue
+		}
+		if out == nil {
+			out = make(map[string]string)
+		}
+		out[t.ID] = reason
+	}
+	return out
+}
+
+// statusQueueTTL is how long status builds reuse the ready queue one of
+// them fetched. Status streams rebuild on every change, and without it each
+// rebuild would run prog ready.
+const statusQueueTTL = 2 * time.Second
+
+// queueCache holds the last ready queue a status build fetched.
+type queueCache struct {
+	mu        sync.Mutex
+	tasks     []Task
+	fetchedAt time.Time
+}
+
+// statusQueue returns the pool's ready queue for a status build, fetching
+// it with fetchQueue at most once per statusQueueTTL. Concurrent builds
+// wait for one fetch instead of each running their own. Failures are not
+// cached.
+func (p *Pool) statusQueue(ctx context.Context, project string, runner CommandRunner) ([]Task, error) {
+	c := &p.readyCache
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := p.clock.Now()
+	if !c.fetchedAt.IsZero() && now.Sub(c.fetchedAt) < statusQueueTTL {
+		return slices.Clone(c.tasks), nil
+	}
+	tasks, err := fetchQueue(ctx, project, runner, p.queue)
+	if err != nil {
+		return nil, err
+	}
+	c.tasks, c.fetchedAt = tasks, now
+	return slices.Clone(tasks), nil
+}
+
+// fetchQueue returns the pending tasks from queue, the source the pool
+// schedules from, or from prog ready when queue is nil.
+func fetchQueue(ctx context.Context, project string, runner CommandRunner, queue TaskQueue) ([]Task, error) {
+	if queue != nil {
+		return queue.Ready(ctx)
+	}
 	output, err := runner(ctx, "prog", "ready", "-p", project)
 	if err != nil {
 		return nil, fmt.Errorf("%w (output: %s)", err, string(output))
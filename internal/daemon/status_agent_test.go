@@ -7,6 +7,8 @@ import (
 	"io"
 	"log/slog"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 
@@ -18,7 +20,7 @@ func TestBuildAgentDetailHappyPath(t *testing.T) {
 	proc, release := newFakeProcess(1234)
 	defer release()
 
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		return proc, nil
 	}
 
@@ -185,7 +187,7 @@ func TestBuildAgentDetailNoSessionID(t *testing.T) {
 	proc, release := newFakeProcess(1234)
 	defer release()
 
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		return proc, nil
 	}
 
@@ -236,7 +238,7 @@ func TestBuildAgentDetailProgShowFails(t *testing.T) {
 	proc, release := newFakeProcess(1234)
 	defer release()
 
-	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer) (Process, error) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
 		return proc, nil
 	}
 
@@ -249,9 +251,10 @@ func TestBuildAgentDetailProgShowFails(t *testing.T) {
 	}
 	cfg.ApplyDefaults()
 
-	// Runner where prog show succeeds once (for FetchTaskMeta during spawn),
-	// then fails on subsequent calls (for BuildAgentDetail).
-	runner := progRunnerShowFailsAfterN(1)
+	// Runner where prog show succeeds twice (dependency check and
+	// FetchTaskMeta during spawn), then fails on subsequent calls (for
+	// BuildAgentDetail).
+	runner := progRunnerShowFailsAfterN(2)
 	pool := NewPool(cfg, runner, starter, testLogger())
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -461,3 +464,189 @@ func progRunnerShowFailsAfterN(n int) CommandRunner {
 		return nil, fmt.Errorf("unexpected command: %s %v", name, args)
 	}
 }
+
+func TestHandleStatusAgentsReturnsBothDetails(t *testing.T) {
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		proc, _ := newFakeProcess(1234)
+		return proc, nil
+	}
+
+	cfg := Config{
+		Project:     "testproject",
+		PoolSize:    2,
+		SpawnCmd:    "fake-agent",
+		SpawnPolicy: SpawnPolicyAuto,
+	}
+	cfg.ApplyDefaults()
+
+	runner := progRunnerWithShowJSON(`{"title": "Some task", "logs": []}`)
+	pool := NewPool(cfg, runner, starter, testLogger())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	taskCh := make(chan []Task, 1)
+	taskCh <- []Task{
+		{ID: "ts-one", Priority: 1, Title: "One"},
+		{ID: "ts-two", Priority: 1, Title: "Two"},
+	}
+	go pool.Run(ctx, taskCh)
+
+	waitFor(t, func() bool {
+		return len(pool.Status()) == 2
+	})
+
+	cfg.Runner = runner
+	d := &Daemon{config: cfg, pool: pool, spawns: NewSpawnRegistry(), events: NewEventBuffer(DefaultEventBufSize), log: testLogger()}
+
+	var names []string
+	wantTask := make(map[string]string)
+	for _, a := range pool.Status() {
+		names = append(names, string(a.ID))
+		wantTask[string(a.ID)] = a.TaskID
+	}
+
+	resp := d.handleStatusAgents(ctx, StatusAgentsParams{AgentNames: append(names, "no_such_agent")})
+	if !resp.Success {
+		t.Fatalf("handleStatusAgents error: %s", resp.Error)
+	}
+	var result StatusAgentsResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	if len(result.Agents) != 2 {
+		t.Fatalf("got %d agent details, want 2", len(result.Agents))
+	}
+	for _, name := range names {
+		detail, ok := result.Agents[name]
+		if !ok {
+			t.Errorf("missing detail for %s", name)
+			continue
+		}
+		if detail.TaskID != wantTask[name] {
+			t.Errorf("%s TaskID = %q, want %q", name, detail.TaskID, wantTask[name])
+		}
+	}
+	if _, ok := result.Errors["no_such_agent"]; !ok {
+		t.Errorf("errors = %v, want an entry for no_such_agent", result.Errors)
+	}
+}
+
+func TestHandleStatusAgentsRequiresNames(t *testing.T) {
+	d := &Daemon{log: testLogger()}
+	if resp := d.handleStatusAgents(context.Background(), StatusAgentsParams{}); resp.Success {
+		t.Error("expected error for empty agent list")
+	}
+}
+
+func TestBuildAgentDetailScanLimitOverHugeBuffer(t *testing.T) {
+	const total = 20000
+	const scan = 10
+	sessionID := "ses_long"
+
+	toolEvent := func(ts int64, partID, status string) SessionEvent {
+		return SessionEvent{
+			EventType: "message.part.updated",
+			SessionID: sessionID,
+			Timestamp: ts,
+			Data:      json.RawMessage(fmt.Sprintf(`{"part":{"id":%q,"type":"tool","tool":"bash","state":{"status":%q,"title":%q}}}`, partID, status, partID)),
+		}
+	}
+
+	events := NewEventBuffer(total + scan)
+	for i := range total {
+		events.Push(toolEvent(int64(i), fmt.Sprintf("prt_old_%d", i), "completed"))
+	}
+	// The scan window holds both lifecycle events of prt_last plus fillers.
+	events.Push(toolEvent(total, "prt_last", "running"))
+	for i := range scan - 2 {
+		events.Push(toolEvent(int64(total+1+i), fmt.Sprintf("prt_new_%d", i), "completed"))
+	}
+	events.Push(toolEvent(total+scan, "prt_last", "completed"))
+
+	spawns := NewSpawnRegistry()
+	if err := spawns.Register(SpawnEntry{
+		SpawnID:   "spawn-long",
+		PID:       999,
+		SessionID: sessionID,
+		State:     SpawnRunning,
+		SpawnTime: time.Now(),
+	}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	detail, err := BuildAgentDetail(context.Background(), nil, spawns, nil, events, Config{}, nil, StatusAgentParams{
+		AgentName: "spawn-long",
+		Limit:     100,
+		ScanLimit: scan,
+	})
+	if err != nil {
+		t.Fatalf("BuildAgentDetail: %v", err)
+	}
+
+	// Only parts inside the window, with prt_last deduped to its latest state.
+	if len(detail.ToolCalls) != scan-1 {
+		t.Fatalf("got %d tool calls, want %d", len(detail.ToolCalls), scan-1)
+	}
+	for _, tc := range detail.ToolCalls {
+		if strings.HasPrefix(tc.Title, "prt_old_") {
+			t.Errorf("tool call %q is outside the scan window", tc.Title)
+		}
+	}
+	first := detail.ToolCalls[0]
+	if first.Title != "prt_last" || first.Status != "completed" {
+		t.Errorf("first call = %q (%s), want prt_last (completed)", first.Title, first.Status)
+	}
+}
+
+func TestBuildAgentDetailFiltersToolsBeforeLimit(t *testing.T) {
+	sessionID := "ses_tools"
+	events := NewEventBuffer(DefaultEventBufSize)
+	// Interleave bash and edit calls, newest last: bash_0, edit_0, bash_1, ...
+	ts := int64(0)
+	for i := range 5 {
+		for _, tool := range []string{"bash", "edit"} {
+			ts++
+			partID := fmt.Sprintf("%s_%d", tool, i)
+			events.Push(SessionEvent{
+				EventType: "message.part.updated",
+				SessionID: sessionID,
+				Timestamp: ts,
+				Data:      json.RawMessage(fmt.Sprintf(`{"part":{"id":%q,"type":"tool","tool":%q,"state":{"status":"completed","title":%q}}}`, partID, tool, partID)),
+			})
+		}
+	}
+
+	spawns := NewSpawnRegistry()
+	if err := spawns.Register(SpawnEntry{
+		SpawnID:   "spawn-tools",
+		PID:       999,
+		SessionID: sessionID,
+		State:     SpawnRunning,
+		SpawnTime: time.Now(),
+	}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	detail, err := BuildAgentDetail(context.Background(), nil, spawns, nil, events, Config{}, nil, StatusAgentParams{
+		AgentName: "spawn-tools",
+		Limit:     3,
+		Tools:     []string{"edit"},
+	})
+	if err != nil {
+		t.Fatalf("BuildAgentDetail: %v", err)
+	}
+
+	// The limit applies to matching calls, so all three are edits.
+	var titles []string
+	for _, tc := range detail.ToolCalls {
+		if tc.Tool != "edit" {
+			t.Errorf("got %s call %q, want only edit", tc.Tool, tc.Title)
+		}
+		titles = append(titles, tc.Title)
+	}
+	if want := []string{"edit_2", "edit_3", "edit_4"}; !slices.Equal(titles, want) {
+		t.Errorf("tool calls = %v, want %v", titles, want)
+	}
+}
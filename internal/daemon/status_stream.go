@@ -0,0 +1,152 @@
+package daemon
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// statusStreamRefresh is how often an idle status stream rebuilds the
+	// status anyway, so changes nothing signals (session activity,
+	// throughput) still reach the client. A rebuild that matches the last
+	// frame is not sent.
+	statusStreamRefresh = 15 * time.Second
+
+	// statusStreamDebounce is how long a status stream waits after a change
+	// before rebuilding, so a burst of changes (a poll that starts several
+	// agents) costs one rebuild instead of one each.
+	statusStreamDebounce = 250 * time.Millisecond
+
+	// statusStreamWriteTimeout bounds each frame write, replacing the
+	// server-wide WriteTimeout that would otherwise end the stream.
+	statusStreamWriteTimeout = 10 * time.Second
+)
+
+// Changes returns a channel that is closed on the next change to the pool's
+// agents, queue, or mode. Fetch a fresh channel after each close.
+func (p *Pool) Changes() <-chan struct{} {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.changed
+}
+
+// notifyChange wakes everyone waiting on Changes, and the daemon's status
+// streams through onChange. Caller must hold p.mu for writing.
+func (p *Pool) notifyChange() {
+	close(p.changed)
+	p.changed = make(chan struct{})
+	if p.onChange != nil {
+		p.onChange()
+	}
+}
+
+// changeNotifier broadcasts "something changed" to any number of waiters,
+// the daemon-wide counterpart of Pool.Changes.
+type changeNotifier struct {
+	mu sync.Mutex
+	ch chan struct{}
+}
+
+func newChangeNotifier() *changeNotifier {
+	return &changeNotifier{ch: make(chan struct{})}
+}
+
+// Changes returns a channel that is closed on the next Notify. Fetch a
+// fresh channel after each close.
+func (n *changeNotifier) Changes() <-chan struct{} {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.ch
+}
+
+// Notify wakes everyone waiting on Changes.
+func (n *changeNotifier) Notify() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	close(n.ch)
+	n.ch = make(chan struct{})
+}
+
+// notifyChange wakes the status streams after a change they can't see
+// through a pool or the spawn registry, such as a session record update.
+func (d *Daemon) notifyChange() {
+	if d.changes != nil {
+		d.changes.Notify()
+	}
+}
+
+// httpStatusStream keeps the connection open and writes a FullStatus frame,
+// one JSON object per line, whenever the status changes. The first frame is
+// sent immediately. The stream ends when the client disconnects or the
+// daemon shuts down. A project query parameter streams that project of a
+// multi-project daemon, as for /api/v1/status. A change in any project's
+// pool, the spawn registry, or a session record rebuilds the frame once
+// changes settle; one that matches the last frame is not sent.
+func (d *Daemon) httpStatusStream(w http.ResponseWriter, r *http.Request) {
+	project := r.URL.Query().Get("project")
+	if project != "" && project != d.config.Project {
+		if _, err := d.poolFor(project); err != nil {
+			writeJSON(w, http.StatusBadRequest, &Response{Success: false, Error: err.Error()})
+			return
+		}
+	}
+
+	rc := http.NewResponseController(w)
+	// The server's read and write timeouts are sized for one-shot requests.
+	_ = rc.SetReadDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+
+	refresh := time.NewTicker(statusStreamRefresh)
+	defer refresh.Stop()
+
+	var last []byte
+	for {
+		// Grab the change channel before building, so a change that lands
+		// mid-build triggers another frame instead of being missed.
+		changed := d.changes.Changes()
+
+		status, err := d.buildProjectStatus(r.Context(), project)
+		if err != nil {
+			d.log.Warn("status.stream build failed", "error", err)
+			return
+		}
+		frame, err := json.Marshal(status)
+		if err != nil {
+			d.log.Warn("status.stream marshal failed", "error", err)
+			return
+		}
+		if !bytes.Equal(frame, last) {
+			_ = rc.SetWriteDeadline(time.Now().Add(statusStreamWriteTimeout))
+			if _, err := w.Write(append(frame, '\n')); err != nil {
+				d.logWriteError("status.stream", r, err)
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				d.logWriteError("status.stream", r, err)
+				return
+			}
+			last = frame
+		}
+
+		select {
+		case <-r.Context().Done():
+			return
+		case <-d.shutdown:
+			return
+		case <-changed:
+			select {
+			case <-r.Context().Done():
+				return
+			case <-d.shutdown:
+				return
+			case <-time.After(statusStreamDebounce):
+			}
+		case <-refresh.C:
+		}
+	}
+}
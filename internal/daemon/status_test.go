@@ -3,10 +3,14 @@ package daemon
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -216,6 +220,80 @@ func TestBuildFullStatusIncludesSpawnsWithoutPool(t *testing.T) {
 	}
 }
 
+func TestBuildFullStatusReportsFullSpawnRegistry(t *testing.T) {
+	spawns := NewSpawnRegistry()
+	spawns.pidAlive = func(int) bool { return true }
+	cfg := Config{PoolSize: 3, SpawnPolicy: SpawnPolicyManual}
+
+	for i := range maxSpawnEntries {
+		if err := spawns.Register(SpawnEntry{SpawnID: fmt.Sprintf("spawn-%d", i), PID: i + 1, State: SpawnRunning}); err != nil {
+			t.Fatalf("Register(%d) error = %v", i, err)
+		}
+	}
+	if status := BuildFullStatus(context.Background(), nil, spawns, nil, nil, cfg, nil); !status.SpawnsFull || status.SpawnsRejected != 0 {
+		t.Errorf("at capacity: SpawnsFull = %v, SpawnsRejected = %d, want true, 0", status.SpawnsFull, status.SpawnsRejected)
+	}
+
+	for i := range 2 {
+		err := spawns.Register(SpawnEntry{SpawnID: fmt.Sprintf("spawn-over-%d", i), PID: 9000 + i, State: SpawnRunning})
+		if !errors.Is(err, ErrSpawnRegistryFull) {
+			t.Fatalf("Register over capacity error = %v, want ErrSpawnRegistryFull", err)
+		}
+	}
+	status := BuildFullStatus(context.Background(), nil, spawns, nil, nil, cfg, nil)
+	if !status.SpawnsFull || status.SpawnsRejected != 2 {
+		t.Errorf("after rejections: SpawnsFull = %v, SpawnsRejected = %d, want true, 2", status.SpawnsFull, status.SpawnsRejected)
+	}
+
+	spawns.MarkExited("spawn-0")
+	if err := spawns.Register(SpawnEntry{SpawnID: "spawn-late", PID: 9999, State: SpawnRunning}); err != nil {
+		t.Fatalf("Register after a slot freed: %v", err)
+	}
+	spawns.MarkExited("spawn-1")
+	if status := BuildFullStatus(context.Background(), nil, spawns, nil, nil, cfg, nil); status.SpawnsFull || status.SpawnsRejected != 0 {
+		t.Errorf("with room: SpawnsFull = %v, SpawnsRejected = %d, want false, 0", status.SpawnsFull, status.SpawnsRejected)
+	}
+}
+
+func TestBuildFullStatusReportsBranch(t *testing.T) {
+	spawns := NewSpawnRegistry()
+	if err := spawns.Register(SpawnEntry{
+		SpawnID:   "spawn-ghost_wolf",
+		PID:       999,
+		State:     SpawnRunning,
+		Prompt:    "test prompt",
+		SpawnTime: time.Now(),
+	}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	pool := statusPool(t, map[string]*Agent{
+		"ts-abc": {ID: "blur_knife", TaskID: "ts-abc", Role: RoleWorker, PID: 1234, SpawnTime: time.Now(), State: AgentRunning},
+	})
+
+	cfg := Config{PoolSize: 3, SpawnPolicy: SpawnPolicyManual}
+	status := BuildFullStatus(context.Background(), pool, spawns, nil, nil, cfg, nil)
+
+	if len(status.Spawns) != 1 || status.Spawns[0].Branch != "af/spawn-ghost_wolf" {
+		t.Errorf("Spawns = %+v, want branch af/spawn-ghost_wolf", status.Spawns)
+	}
+	if len(status.Agents) != 1 || status.Agents[0].Branch != "af/ts-abc" {
+		t.Errorf("Agents = %+v, want branch af/ts-abc", status.Agents)
+	}
+}
+
+func TestBuildFullStatusReportsInstanceName(t *testing.T) {
+	cfg := Config{Project: "myproj", PoolSize: 3, SpawnPolicy: SpawnPolicyManual}
+	cfg.ApplyDefaults()
+	if status := BuildFullStatus(context.Background(), nil, nil, nil, nil, cfg, nil); status.Instance != "myproj" {
+		t.Errorf("Instance = %q, want project name by default", status.Instance)
+	}
+
+	cfg.InstanceName = "east"
+	if status := BuildFullStatus(context.Background(), nil, nil, nil, nil, cfg, nil); status.Instance != "east" {
+		t.Errorf("Instance = %q, want %q", status.Instance, "east")
+	}
+}
+
 func TestBuildFullStatusProgShowFails(t *testing.T) {
 	now := time.Now()
 
@@ -604,3 +682,120 @@ func TestBuildFullStatusSurfacesSessionIndexErrors(t *testing.T) {
 		t.Fatalf("Errors[0] = %q, want session index prefix", status.Errors[0])
 	}
 }
+
+func TestFetchTaskSummaries(t *testing.T) {
+	ids := []string{"ts-a", "ts-b", "ts-c"}
+	single := map[string]string{
+		"ts-a": `{"id":"ts-a","title":"Task A","logs":[{"message":"started"}]}`,
+		"ts-b": `{"id":"ts-b","title":"Task B","logs":[]}`,
+		"ts-c": `{"id":"ts-c","title":"Task C","logs":[{"message":"one"},{"message":"two"}]}`,
+	}
+	want := map[string]taskSummary{
+		"ts-a": {Title: "Task A", LastLog: "started"},
+		"ts-b": {Title: "Task B"},
+		"ts-c": {Title: "Task C", LastLog: "two"},
+	}
+
+	tests := []struct {
+		name string
+		// batch answers a show with several IDs; nil falls back to the
+		// single-task output for the first ID, like a prog without batch
+		// support.
+		batch     func(ids []string) ([]byte, error)
+		wantCalls int
+	}{
+		{
+			name: "batch supported",
+			batch: func(ids []string) ([]byte, error) {
+				parts := make([]string, len(ids))
+				for i, id := range ids {
+					parts[i] = single[id]
+				}
+				return []byte("[" + strings.Join(parts, ",") + "]"), nil
+			},
+			wantCalls: 1,
+		},
+		{
+			name:      "batch unsupported",
+			wantCalls: 1 + len(ids),
+		},
+		{
+			name: "batch rejected",
+			batch: func([]string) ([]byte, error) {
+				return []byte("accepts 1 arg"), fmt.Errorf("exit status 1")
+			},
+			wantCalls: 1 + len(ids),
+		},
+		{
+			name: "batch missing a task",
+			batch: func(ids []string) ([]byte, error) {
+				return []byte("[" + single["ts-a"] + "," + single["ts-c"] + "]"), nil
+			},
+			wantCalls: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var mu sync.Mutex
+			calls := 0
+			runner := func(_ context.Context, _ string, args ...string) ([]byte, error) {
+				mu.Lock()
+				calls++
+				mu.Unlock()
+				var shown []string
+				for _, a := range args[1:] {
+					if a == "--json" {
+						break
+					}
+					shown = append(shown, a)
+				}
+				if len(shown) > 1 && tt.batch != nil {
+					return tt.batch(shown)
+				}
+				return []byte(single[shown[0]]), nil
+			}
+
+			got, errs := fetchTaskSummaries(context.Background(), ids, "testproject", runner)
+			if len(errs) != 0 {
+				t.Fatalf("errs = %v, want none", errs)
+			}
+			if !maps.Equal(got, want) {
+				t.Errorf("summaries = %v, want %v", got, want)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("prog calls = %d, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestStatusQueueReusesRecentFetch(t *testing.T) {
+	var readies atomic.Int32
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if len(args) >= 1 && args[0] == "ready" {
+			readies.Add(1)
+			return []byte("ID           PRI  TITLE\nts-abc123    3    Fix the thing\n"), nil
+		}
+		return nil, fmt.Errorf("unexpected: %v", args)
+	}
+	pool := testPool(t, runner, nil)
+	clock := newFakeClock(time.Now())
+	pool.clock = clock
+
+	for range 3 {
+		if _, err := pool.statusQueue(context.Background(), "testproject", runner); err != nil {
+			t.Fatalf("statusQueue: %v", err)
+		}
+	}
+	if got := readies.Load(); got != 1 {
+		t.Errorf("prog ready ran %d times within the TTL, want 1", got)
+	}
+
+	clock.Advance(statusQueueTTL)
+	if _, err := pool.statusQueue(context.Background(), "testproject", runner); err != nil {
+		t.Fatalf("statusQueue: %v", err)
+	}
+	if got := readies.Load(); got != 2 {
+		t.Errorf("prog ready ran %d times after the TTL, want 2", got)
+	}
+}
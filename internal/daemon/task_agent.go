@@ -0,0 +1,72 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// TaskAgentParams is the request shape for resolving a task's agent.
+type TaskAgentParams struct {
+	ProjectSelector
+	TaskID string `json:"task_id"`
+}
+
+// TaskAgentResult is the pool agent currently working a task. A crashed
+// task's respawn gets a new agent name, so callers following a task
+// resolve it again rather than holding on to the name.
+type TaskAgentResult struct {
+	TaskID    string    `json:"task_id"`
+	Running   bool      `json:"running"` // an agent is working the task right now
+	AgentID   string    `json:"agent_id,omitempty"`
+	PID       int       `json:"pid,omitempty"`
+	SessionID string    `json:"session_id,omitempty"`
+	SpawnTime time.Time `json:"spawn_time,omitempty"`
+
+	// Outcome and Attempts come from the task history; both are empty
+	// for a task the pool has not scheduled since startup. Outcome is
+	// "running" while a respawn is pending even if no agent is up.
+	Outcome  TaskOutcome `json:"outcome,omitempty"`
+	Attempts int         `json:"attempts,omitempty"`
+}
+
+// TaskAgent resolves the agent currently assigned to taskID, along with
+// the task's history entry.
+func (p *Pool) TaskAgent(taskID string) TaskAgentResult {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	result := TaskAgentResult{TaskID: taskID}
+	if a, ok := p.agents[taskID]; ok && a.State == AgentRunning {
+		result.Running = true
+		result.AgentID = string(a.ID)
+		result.PID = a.PID
+		result.SessionID = a.SessionID
+		result.SpawnTime = a.SpawnTime
+	}
+	if h, ok := p.history[taskID]; ok {
+		result.Outcome = h.Outcome
+		result.Attempts = h.Attempts
+	}
+	return result
+}
+
+// handleTaskAgent resolves the pool agent working a task.
+func (d *Daemon) handleTaskAgent(params TaskAgentParams) *Response {
+	if params.TaskID == "" {
+		return &Response{Success: false, Error: "task_id is required"}
+	}
+	if !validTaskID.MatchString(params.TaskID) {
+		return &Response{Success: false, Error: fmt.Sprintf("invalid task_id %q", params.TaskID)}
+	}
+	pool, err := d.poolFor(params.Project)
+	if err != nil {
+		return &Response{Success: false, Error: err.Error()}
+	}
+
+	result, err := json.Marshal(pool.TaskAgent(params.TaskID))
+	if err != nil {
+		return &Response{Success: false, Error: fmt.Sprintf("marshal task agent: %v", err)}
+	}
+	return &Response{Success: true, Result: result}
+}
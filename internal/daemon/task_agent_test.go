@@ -0,0 +1,87 @@
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestHandleTaskAgentResolvesNewAgentAfterRespawn(t *testing.T) {
+	var spawns atomic.Int32
+	var mu sync.Mutex
+	var crash func()
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		n := spawns.Add(1)
+		if n == 1 {
+			proc, rel := newFakeProcessWithError(100, exitCodeError(1))
+			mu.Lock()
+			crash = rel
+			mu.Unlock()
+			return proc, nil
+		}
+		proc, _ := newFakeProcess(int(n) * 100)
+		return proc, nil
+	}
+	pool := testPool(t, progRunner(testTaskMeta), starter)
+	pool.ctx = context.Background()
+	d := &Daemon{config: pool.config, pool: pool, log: testLogger()}
+
+	resolve := func() TaskAgentResult {
+		t.Helper()
+		resp := d.handleTaskAgent(TaskAgentParams{TaskID: "ts-abc"})
+		if !resp.Success {
+			t.Fatalf("handleTaskAgent: %s", resp.Error)
+		}
+		var result TaskAgentResult
+		if err := json.Unmarshal(resp.Result, &result); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		return result
+	}
+
+	pool.schedule(context.Background(), []Task{{ID: "ts-abc"}})
+	first := resolve()
+	if !first.Running || first.AgentID == "" || first.PID != 100 || first.Attempts != 1 {
+		t.Fatalf("before crash = %+v, want running agent with pid 100 on attempt 1", first)
+	}
+
+	mu.Lock()
+	crash()
+	mu.Unlock()
+	waitFor(t, func() bool { return spawns.Load() == 2 && len(pool.Status()) == 1 })
+
+	second := resolve()
+	if !second.Running || second.PID != 200 || second.Attempts != 2 {
+		t.Fatalf("after respawn = %+v, want running agent with pid 200 on attempt 2", second)
+	}
+	if second.AgentID == first.AgentID {
+		t.Errorf("agent after respawn = %s, want a new agent name", second.AgentID)
+	}
+	if second.Outcome != TaskOutcomeRunning {
+		t.Errorf("outcome = %q, want %q", second.Outcome, TaskOutcomeRunning)
+	}
+}
+
+func TestHandleTaskAgentUnknownTask(t *testing.T) {
+	pool := testPool(t, progRunner(testTaskMeta), nil)
+	d := &Daemon{config: pool.config, pool: pool, log: testLogger()}
+
+	resp := d.handleTaskAgent(TaskAgentParams{TaskID: "ts-none"})
+	if !resp.Success {
+		t.Fatalf("handleTaskAgent: %s", resp.Error)
+	}
+	var result TaskAgentResult
+	if err := json.Unmarshal(resp.Result, &result); err != nil {
+		t.Fatal(err)
+	}
+	if result.Running || result.AgentID != "" || result.Outcome != "" {
+		t.Errorf("result = %+v, want no agent and no history", result)
+	}
+
+	if resp := d.handleTaskAgent(TaskAgentParams{TaskID: "../etc"}); resp.Success {
+		t.Error("invalid task ID should be rejected")
+	}
+}
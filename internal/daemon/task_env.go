@@ -0,0 +1,107 @@
+package daemon
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// taskEnvPrefix starts the name of every env var exported from task metadata.
+const taskEnvPrefix = "AETHERFLOW_TASK_"
+
+// validTaskEnvField matches metadata field names that can be exported. The
+// name becomes part of an env var, so it is kept to a safe character set.
+var validTaskEnvField = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// TaskEnv returns the AETHERFLOW_TASK_* env vars for the metadata fields
+// listed in fields. A field "labels" becomes AETHERFLOW_TASK_LABELS; list
+// values are comma-joined, and fields that are missing, empty, or not a
+// string, number, bool, or list of those are skipped. Only configured
+// fields are exported so task metadata doesn't leak into agents wholesale.
+func TaskEnv(meta TaskMeta, fields []string) []string {
+	var env []string
+	for _, field := range fields {
+		value, ok := taskFieldValue(meta, field)
+		if !ok || value == "" {
+			continue
+		}
+		name := taskEnvPrefix + strings.ToUpper(strings.ReplaceAll(field, "-", "_"))
+		env = append(env, name+"="+value)
+	}
+	return env
+}
+
+// taskFieldValue renders one metadata field as an env value. The typed
+// TaskMeta fields are used first so work sources without raw fields (the
+// queue file) still export them.
+func taskFieldValue(meta TaskMeta, field string) (string, bool) {
+	switch field {
+	case "id":
+		return meta.ID, true
+	case "type":
+		return meta.Type, true
+	case "status":
+		return meta.Status, true
+	case "definition_of_done":
+		return meta.DefinitionOfDone, true
+	case "labels":
+		return strings.Join(meta.Labels, ","), true
+	case "dependencies":
+		return strings.Join(meta.Dependencies, ","), true
+	}
+	raw, ok := meta.Fields[field]
+	if !ok {
+		return "", false
+	}
+	return rawEnvValue(raw)
+}
+
+// rawEnvValue renders a scalar or a list of scalars from prog's JSON.
+func rawEnvValue(raw json.RawMessage) (string, bool) {
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return "", false
+	}
+	if list, ok := v.([]any); ok {
+		parts := make([]string, 0, len(list))
+		for _, item := range list {
+			s, ok := scalarEnvValue(item)
+			if !ok {
+				return "", false
+			}
+			parts = append(parts, s)
+		}
+		return strings.Join(parts, ","), true
+	}
+	return scalarEnvValue(v)
+}
+
+func scalarEnvValue(v any) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case bool, float64:
+		return fmt.Sprint(v), true
+	}
+	return "", false
+}
+
+// setTaskEnv records the env a task's agent is started with, so respawns
+// of the same task get it too.
+func (p *Pool) setTaskEnv(taskID string, env []string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(env) == 0 {
+		delete(p.taskEnv, taskID)
+		return
+	}
+	p.taskEnv[taskID] = env
+}
+
+// taskEnvFor returns the env recorded for a task, if any.
+func (p *Pool) taskEnvFor(taskID string) []string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.taskEnv[taskID]
+}
@@ -0,0 +1,86 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// taskGoneCheckTimeout bounds the metadata lookup for one running agent's
+// task during the sweep.
+const taskGoneCheckTimeout = 10 * time.Second
+
+// taskGoneReason reports why a running agent's task should no longer be
+// worked on, given the result of looking it up: it was deleted, or it was
+// cancelled. Lookup failures other than not-found are not treated as gone,
+// so a prog outage never kills agents.
+func taskGoneReason(meta TaskMeta, err error) (string, bool) {
+	if err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			return "task no longer exists", true
+		}
+		return "", false
+	}
+	switch meta.Status {
+	case "canceled", "cancelled":
+		return "task was cancelled", true
+	}
+	return "", false
+}
+
+// stopAgentsForGoneTasks checks that each running agent's task still exists
+// and stops agents whose task was deleted or cancelled. Stopped agents are
+// treated like af session kill: not respawned and no retry is counted.
+func (p *Pool) stopAgentsForGoneTasks(ctx context.Context) {
+	p.mu.RLock()
+	var running []Agent
+	for _, a := range p.agents {
+		_, stopping := p.stopping[a.TaskID]
+		if a.State == AgentRunning && !stopping && !p.killed[a.TaskID] {
+			running = append(running, *a)
+		}
+	}
+	p.mu.RUnlock()
+
+	for _, agent := range running {
+		if ctx.Err() != nil {
+			return
+		}
+		cctx, cancel := context.WithTimeout(ctx, taskGoneCheckTimeout)
+		meta, err := p.work.GetMeta(cctx, agent.TaskID, p.config.Project)
+		cancel()
+		reason, gone := taskGoneReason(meta, err)
+		if !gone {
+			continue
+		}
+
+		p.mu.Lock()
+		current, ok := p.agents[agent.TaskID]
+		if !ok || current.PID != agent.PID {
+			p.mu.Unlock()
+			continue
+		}
+		p.killed[agent.TaskID] = true
+		p.mu.Unlock()
+
+		p.taskLog(agent.TaskID).Warn("stopping agent, its task is gone",
+			"agent_id", agent.ID,
+			"task_id", agent.TaskID,
+			"pid", agent.PID,
+			"reason", reason,
+		)
+		p.recordError("warn", OpErrorTaskGone, agent.TaskID, string(agent.ID), fmt.Errorf("stopped agent: %s", reason))
+		if err := p.stopProcess(agent.PID); err != nil {
+			p.mu.Lock()
+			delete(p.killed, agent.TaskID)
+			p.mu.Unlock()
+			p.log.Error("failed to stop agent for gone task",
+				"agent_id", agent.ID,
+				"task_id", agent.TaskID,
+				"pid", agent.PID,
+				"error", err,
+			)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestStopAgentsForGoneTasksStopsAgentOfDeletedTask(t *testing.T) {
+	var spawns atomic.Int32
+	var mu sync.Mutex
+	releases := map[int]func(){}
+	starter := func(ctx context.Context, spawnCmd string, prompt string, _ string, _ io.Writer, _ StartOptions) (Process, error) {
+		pid := 1000 + int(spawns.Add(1))
+		proc, rel := newFakeProcessWithError(pid, fmt.Errorf("signal: terminated"))
+		mu.Lock()
+		releases[pid] = rel
+		mu.Unlock()
+		return proc, nil
+	}
+
+	var deleted atomic.Bool
+	show := progRunner(testTaskMeta)
+	runner := func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if deleted.Load() && len(args) >= 2 && args[0] == "show" && args[1] == "ts-gone" {
+			return []byte("Error: task not found: ts-gone"), errors.New("exit status 1")
+		}
+		return show(ctx, name, args...)
+	}
+
+	pool := testPool(t, runner, starter)
+	pool.ctx = context.Background()
+	var stopped []int
+	pool.stopProcess = func(pid int) error {
+		stopped = append(stopped, pid)
+		mu.Lock()
+		rel := releases[pid]
+		mu.Unlock()
+		rel() // the agent exits on SIGTERM
+		return nil
+	}
+
+	pool.schedule(context.Background(), []Task{{ID: "ts-abc"}, {ID: "ts-gone"}})
+	if got := len(pool.Status()); got != 2 {
+		t.Fatalf("agents = %d, want 2", got)
+	}
+	var gonePID int
+	for _, a := range pool.Status() {
+		if a.TaskID == "ts-gone" {
+			gonePID = a.PID
+		}
+	}
+
+	deleted.Store(true)
+	pool.stopAgentsForGoneTasks(context.Background())
+
+	if len(stopped) != 1 || stopped[0] != gonePID {
+		t.Fatalf("stopped PIDs = %v, want only %d", stopped, gonePID)
+	}
+	waitFor(t, func() bool { return len(pool.Status()) == 1 })
+	if got := pool.Status()[0].TaskID; got != "ts-abc" {
+		t.Errorf("remaining agent task = %s, want ts-abc", got)
+	}
+	if got := spawns.Load(); got != 2 {
+		t.Errorf("spawns = %d, want 2 (agent of a gone task is not respawned)", got)
+	}
+	errs := pool.RecentErrors(1)
+	if len(errs) != 1 || errs[0].Kind != OpErrorTaskGone || errs[0].TaskID != "ts-gone" {
+		t.Errorf("recent errors = %+v, want one %s for ts-gone", errs, OpErrorTaskGone)
+	}
+}
+
+func TestTaskGoneReason(t *testing.T) {
+	tests := []struct {
+		name string
+		meta TaskMeta
+		err  error
+		gone bool
+	}{
+		{"in progress", TaskMeta{Status: "in_progress"}, nil, false},
+		{"cancelled", TaskMeta{Status: "cancelled"}, nil, true},
+		{"canceled", TaskMeta{Status: "canceled"}, nil, true},
+		{"not found", TaskMeta{}, fmt.Errorf("prog show ts-x: %w", ErrTaskNotFound), true},
+		{"prog unavailable", TaskMeta{}, errors.New("exec: \"prog\": executable file not found in $PATH"), false},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, gone := taskGoneReason(tc.meta, tc.err); gone != tc.gone {
+				t.Errorf("gone = %v, want %v", gone, tc.gone)
+			}
+		})
+	}
+}
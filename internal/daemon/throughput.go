@@ -0,0 +1,64 @@
+package daemon
+
+import "time"
+
+// throughputWindow is the span over which completed tasks are counted.
+const throughputWindow = time.Hour
+
+// minRateSpan is the shortest span used to extrapolate an hourly rate, so a
+// single completion right after startup doesn't report an absurd rate.
+const minRateSpan = 5 * time.Minute
+
+// completionRingSize bounds the completion history. Completions beyond this
+// many per window are undercounted, which is far above realistic pool
+// throughput.
+const completionRingSize = 512
+
+// completionRing records the times of clean agent exits in a fixed-size
+// ring, overwriting the oldest entry when full. Not safe for concurrent use;
+// the pool guards it with its mutex.
+type completionRing struct {
+	times []time.Time
+	next  int
+}
+
+func (r *completionRing) record(t time.Time) {
+	if len(r.times) < completionRingSize {
+		r.times = append(r.times, t)
+		return
+	}
+	r.times[r.next] = t
+	r.next = (r.next + 1) % completionRingSize
+}
+
+// countSince returns the number of completions at or after cutoff.
+func (r *completionRing) countSince(cutoff time.Time) int {
+	n := 0
+	for _, t := range r.times {
+		if !t.Before(cutoff) {
+			n++
+		}
+	}
+	return n
+}
+
+// Throughput returns the number of tasks completed in the last hour and the
+// hourly completion rate. Until the pool has run for a full hour, the rate
+// is extrapolated from its uptime (at least minRateSpan).
+func (p *Pool) Throughput() (completed int, ratePerHour float64) {
+	now := p.clock.Now()
+
+	p.mu.RLock()
+	completed = p.completions.countSince(now.Add(-throughputWindow))
+	started := p.startedAt
+	p.mu.RUnlock()
+
+	span := now.Sub(started)
+	if span > throughputWindow {
+		span = throughputWindow
+	}
+	if span < minRateSpan {
+		span = minRateSpan
+	}
+	return completed, float64(completed) / span.Hours()
+}
@@ -0,0 +1,69 @@
+package daemon
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPoolThroughputWindow(t *testing.T) {
+	pool := testPool(t, progRunner(testTaskMeta), nil)
+
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := newFakeClock(start)
+	pool.clock = clock
+	pool.startedAt = start
+
+	record := func() {
+		pool.mu.Lock()
+		pool.completions.record(clock.Now())
+		pool.mu.Unlock()
+	}
+
+	// Two completions in the first 10 minutes.
+	clock.Advance(5 * time.Minute)
+	record()
+	clock.Advance(5 * time.Minute)
+	record()
+
+	completed, rate := pool.Throughput()
+	if completed != 2 {
+		t.Errorf("completed = %d, want 2", completed)
+	}
+	if rate != 12 { // 2 in 10 minutes
+		t.Errorf("rate = %v, want 12", rate)
+	}
+
+	// A third completion 50 minutes later; the first two are still in window.
+	clock.Advance(50 * time.Minute)
+	record()
+	if completed, rate := pool.Throughput(); completed != 3 || rate != 3 {
+		t.Errorf("at 60m: completed=%d rate=%v, want 3 and 3", completed, rate)
+	}
+
+	// Advance past the first two: only the third remains in the last hour.
+	clock.Advance(10*time.Minute + time.Second)
+	if completed, rate := pool.Throughput(); completed != 1 || rate != 1 {
+		t.Errorf("at 70m: completed=%d rate=%v, want 1 and 1", completed, rate)
+	}
+}
+
+func TestCompletionRingOverwritesOldest(t *testing.T) {
+	var r completionRing
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < completionRingSize+10; i++ {
+		r.record(base.Add(time.Duration(i) * time.Second))
+	}
+	if len(r.times) != completionRingSize {
+		t.Fatalf("ring len = %d, want %d", len(r.times), completionRingSize)
+	}
+	// The 10 oldest entries were overwritten.
+	if got := r.countSince(base); got != completionRingSize {
+		t.Errorf("countSince(base) = %d, want %d", got, completionRingSize)
+	}
+	if got := r.countSince(base.Add(10 * time.Second)); got != completionRingSize {
+		t.Errorf("countSince(+10s) = %d, want %d", got, completionRingSize)
+	}
+	if got := r.countSince(base.Add(11 * time.Second)); got != completionRingSize-1 {
+		t.Errorf("countSince(+11s) = %d, want %d", got, completionRingSize-1)
+	}
+}
@@ -2,6 +2,8 @@ package daemon
 
 import (
 	"encoding/json"
+	"fmt"
+	"slices"
 	"time"
 )
 
@@ -15,10 +17,31 @@ type ToolCall struct {
 	DurationMs int       `json:"duration_ms,omitempty"`
 }
 
+// ToolInputFields maps a tool name to the input fields that summarize a
+// call to it, tried in order; the first non-empty string wins. It extends
+// the built-in mappings in extractKeyInput, so custom tools show a
+// meaningful input in status and logs. A configured tool whose fields are
+// all absent falls back to the built-in behavior.
+type ToolInputFields map[string][]string
+
+// validate rejects empty tool names and field lists.
+func (f ToolInputFields) validate() error {
+	for tool, fields := range f {
+		if tool == "" {
+			return fmt.Errorf("tool_input_fields: tool name must not be empty")
+		}
+		if len(fields) == 0 || slices.Contains(fields, "") {
+			return fmt.Errorf("tool_input_fields: %s needs at least one non-empty field name", tool)
+		}
+	}
+	return nil
+}
+
 // extractKeyInput pulls the most relevant field from a tool's input JSON.
 // Each tool has a different input shape; we extract the one field that gives
-// the best at-a-glance summary.
-func extractKeyInput(tool string, raw json.RawMessage) string {
+// the best at-a-glance summary. Fields configured for the tool in custom
+// are tried first.
+func extractKeyInput(tool string, raw json.RawMessage, custom ToolInputFields) string {
 	if len(raw) == 0 {
 		return ""
 	}
@@ -28,6 +51,12 @@ func extractKeyInput(tool string, raw json.RawMessage) string {
 		return ""
 	}
 
+	for _, key := range custom[tool] {
+		if v := unquoteField(m, key); v != "" {
+			return v
+		}
+	}
+
 	// Try tool-specific key fields in order of usefulness.
 	switch tool {
 	case "read", "edit", "write":
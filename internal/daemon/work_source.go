@@ -1,12 +1,27 @@
 package daemon
 
-import "context"
+import (
+	"context"
+	"fmt"
+)
 
 // WorkSource abstracts task/work item operations so session lifecycle code does
 // not hard-code a specific backend.
 type WorkSource interface {
 	Claim(ctx context.Context, workRef, project string) error
 	GetMeta(ctx context.Context, workRef, project string) (TaskMeta, error)
+	Dependencies(ctx context.Context, workRef, project string) ([]Dependency, error)
+	// Block releases a claimed work item without finishing it, recording
+	// reason, so it is neither worked on nor handed out again until a
+	// human reopens it.
+	Block(ctx context.Context, workRef, project, reason string) error
+}
+
+// Dependency is a work item that must be complete before its dependent
+// can be scheduled.
+type Dependency struct {
+	ID   string
+	Done bool
 }
 
 // ProgWorkSource is the default WorkSource backed by the prog CLI.
@@ -30,6 +45,45 @@ func (p *ProgWorkSource) Claim(ctx context.Context, workRef, project string) err
 	return err
 }
 
+// Block moves the task from in_progress to blocked with `prog block`.
+func (p *ProgWorkSource) Block(ctx context.Context, workRef, project, reason string) error {
+	args := []string{"block", workRef, reason}
+	if project != "" {
+		args = append(args, "-p", project)
+	}
+	output, err := p.runner(ctx, "prog", args...)
+	if err != nil {
+		return fmt.Errorf("prog block %s: %w (output: %s)", workRef, err, string(output))
+	}
+	return nil
+}
+
 func (p *ProgWorkSource) GetMeta(ctx context.Context, workRef, project string) (TaskMeta, error) {
 	return FetchTaskMeta(ctx, workRef, project, p.runner)
 }
+
+// Dependencies reads the task's dependency IDs from `prog show` and looks
+// up each one's status. A dependency counts as done only when prog reports
+// it as "done".
+func (p *ProgWorkSource) Dependencies(ctx context.Context, workRef, project string) ([]Dependency, error) {
+	meta, err := FetchTaskMeta(ctx, workRef, project, p.runner)
+	if err != nil {
+		return nil, err
+	}
+	if len(meta.Dependencies) == 0 {
+		return nil, nil
+	}
+
+	deps := make([]Dependency, 0, len(meta.Dependencies))
+	for _, id := range meta.Dependencies {
+		if !validTaskID.MatchString(id) {
+			return nil, fmt.Errorf("task %s has invalid dependency ID %q", workRef, id)
+		}
+		depMeta, err := FetchTaskMeta(ctx, id, project, p.runner)
+		if err != nil {
+			return nil, fmt.Errorf("checking dependency %s of %s: %w", id, workRef, err)
+		}
+		deps = append(deps, Dependency{ID: id, Done: depMeta.Status == "done"})
+	}
+	return deps, nil
+}
@@ -0,0 +1,146 @@
+package sessions
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Repair actions taken (or proposed) for an invalid record.
+const (
+	ActionDrop = "drop" // the record cannot be salvaged and is removed
+	ActionFix  = "fix"  // the record is kept with its bad fields corrected
+)
+
+// Problem is one invalid record found by Doctor.
+type Problem struct {
+	Index     int      `json:"index"` // position in the registry file
+	ServerRef string   `json:"server_ref,omitempty"`
+	SessionID string   `json:"session_id,omitempty"`
+	Issues    []string `json:"issues"`
+	Action    string   `json:"action"`
+}
+
+// DoctorReport is the outcome of a Doctor run.
+type DoctorReport struct {
+	Path     string    `json:"path"`
+	Records  int       `json:"records"` // records in the file, valid or not
+	Problems []Problem `json:"problems"`
+	Repaired bool      `json:"repaired"` // the registry was rewritten
+}
+
+// rawDiskState parses the registry leaving each record undecoded, so one
+// malformed record doesn't hide the rest.
+type rawDiskState struct {
+	SchemaVersion int               `json:"schema_version"`
+	Records       []json.RawMessage `json:"records"`
+}
+
+// Doctor validates every record in the registry: required fields, known
+// status and origin values, and duplicate keys. With repair, records that
+// cannot be salvaged are dropped, the rest are fixed in place, and the
+// registry is rewritten atomically. A registry that isn't valid JSON at all,
+// or has a newer schema version, is reported as an error and left alone.
+func (s *Store) Doctor(repair bool) (DoctorReport, error) {
+	report := DoctorReport{Path: s.path, Problems: []Problem{}}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	unlock, err := s.lockFile()
+	if err != nil {
+		return report, err
+	}
+	defer unlock()
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return report, nil
+		}
+		return report, fmt.Errorf("reading sessions registry: %w", err)
+	}
+	var raw rawDiskState
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return report, fmt.Errorf("parsing sessions registry: %w", err)
+	}
+	if raw.SchemaVersion > schemaVersion {
+		return report, fmt.Errorf("unsupported sessions schema version: %d", raw.SchemaVersion)
+	}
+	report.Records = len(raw.Records)
+
+	kept := make([]Record, 0, len(raw.Records))
+	seen := make(map[string]bool, len(raw.Records))
+	for i, msg := range raw.Records {
+		var rec Record
+		if err := json.Unmarshal(msg, &rec); err != nil {
+			report.Problems = append(report.Problems, Problem{
+				Index:  i,
+				Issues: []string{fmt.Sprintf("malformed record: %v", err)},
+				Action: ActionDrop,
+			})
+			continue
+		}
+		issues, action := checkRecord(&rec, seen)
+		if len(issues) > 0 {
+			report.Problems = append(report.Problems, Problem{
+				Index:     i,
+				ServerRef: rec.ServerRef,
+				SessionID: rec.SessionID,
+				Issues:    issues,
+				Action:    action,
+			})
+		}
+		if action == ActionDrop {
+			continue
+		}
+		seen[rec.key()] = true
+		kept = append(kept, rec)
+	}
+
+	if !repair || len(report.Problems) == 0 {
+		return report, nil
+	}
+	if err := s.writeLocked(diskState{Records: kept}); err != nil {
+		return report, err
+	}
+	report.Repaired = true
+	return report, nil
+}
+
+// checkRecord lists what is wrong with rec and corrects the fixable fields
+// in place. The action is ActionDrop when the record must be removed, and
+// ActionFix or "" otherwise. seen holds the keys of records already kept.
+func checkRecord(rec *Record, seen map[string]bool) ([]string, string) {
+	var issues []string
+	if rec.ServerRef == "" {
+		issues = append(issues, "missing server_ref")
+	}
+	if rec.SessionID == "" {
+		issues = append(issues, "missing session_id")
+	}
+	if len(issues) > 0 {
+		return issues, ActionDrop
+	}
+	if seen[rec.key()] {
+		return []string{"duplicate of an earlier record"}, ActionDrop
+	}
+
+	switch rec.Status {
+	case StatusActive, StatusIdle, StatusTerminated, StatusStale:
+	default:
+		// Stale records are ignored by the daemon and swept by age.
+		issues = append(issues, fmt.Sprintf("unknown status %q (set to %s)", rec.Status, StatusStale))
+		rec.Status = StatusStale
+	}
+	switch rec.Origin {
+	case "", OriginPool, OriginSpawn, OriginManual:
+	default:
+		issues = append(issues, fmt.Sprintf("unknown origin_type %q (set to %s)", rec.Origin, OriginManual))
+		rec.Origin = OriginManual
+	}
+	if len(issues) > 0 {
+		return issues, ActionFix
+	}
+	return nil, ""
+}
@@ -0,0 +1,92 @@
+package sessions
+
+import (
+	"os"
+	"testing"
+)
+
+func TestDoctorReportsAndRepairsInvalidRecords(t *testing.T) {
+	t.Parallel()
+
+	store, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	seed := `{
+  "schema_version": 1,
+  "records": [
+    {"server_ref": "http://127.0.0.1:4096", "session_id": "ses_good", "status": "idle"},
+    {"server_ref": "http://127.0.0.1:4096", "session_id": "", "status": "active"},
+    {"server_ref": "http://127.0.0.1:4096", "session_id": "ses_drift", "status": "paused", "origin_type": "cron"},
+    {"server_ref": "http://127.0.0.1:4096", "session_id": 42},
+    {"server_ref": "http://127.0.0.1:4096", "session_id": "ses_good", "status": "active"}
+  ]
+}`
+	if err := os.WriteFile(store.Path(), []byte(seed), 0o600); err != nil {
+		t.Fatalf("seeding registry: %v", err)
+	}
+
+	report, err := store.Doctor(false)
+	if err != nil {
+		t.Fatalf("Doctor(false) error = %v", err)
+	}
+	if report.Records != 5 {
+		t.Errorf("Records = %d, want 5", report.Records)
+	}
+	want := []struct {
+		index  int
+		action string
+	}{
+		{1, ActionDrop}, // missing session_id
+		{2, ActionFix},  // unknown status and origin
+		{3, ActionDrop}, // session_id is not a string
+		{4, ActionDrop}, // duplicate key
+	}
+	if len(report.Problems) != len(want) {
+		t.Fatalf("problems = %+v, want %d", report.Problems, len(want))
+	}
+	for i, w := range want {
+		if p := report.Problems[i]; p.Index != w.index || p.Action != w.action {
+			t.Errorf("problem %d = #%d %s, want #%d %s", i, p.Index, p.Action, w.index, w.action)
+		}
+	}
+	if report.Repaired {
+		t.Error("Repaired = true without repair")
+	}
+	// Without repair the file is untouched.
+	if data, _ := os.ReadFile(store.Path()); string(data) != seed {
+		t.Error("Doctor(false) modified the registry")
+	}
+
+	report, err = store.Doctor(true)
+	if err != nil {
+		t.Fatalf("Doctor(true) error = %v", err)
+	}
+	if !report.Repaired {
+		t.Error("Repaired = false with repair")
+	}
+
+	recs, err := store.List()
+	if err != nil {
+		t.Fatalf("List() after repair error = %v", err)
+	}
+	got := map[string]Record{}
+	for _, r := range recs {
+		got[r.SessionID] = r
+	}
+	if len(recs) != 2 || got["ses_good"].Status != StatusIdle {
+		t.Fatalf("records after repair = %+v, want ses_good (idle) and ses_drift", recs)
+	}
+	if drift := got["ses_drift"]; drift.Status != StatusStale || drift.Origin != OriginManual {
+		t.Errorf("ses_drift = %s/%s, want %s/%s", drift.Status, drift.Origin, StatusStale, OriginManual)
+	}
+
+	// A repaired registry is clean.
+	report, err = store.Doctor(false)
+	if err != nil {
+		t.Fatalf("Doctor() after repair error = %v", err)
+	}
+	if len(report.Problems) != 0 {
+		t.Errorf("problems after repair = %+v, want none", report.Problems)
+	}
+}
@@ -34,6 +34,11 @@ const (
 	OriginPool   OriginType = "pool"
 	OriginSpawn  OriginType = "spawn"
 	OriginManual OriginType = "manual"
+
+	// OriginExternal marks a session opencode knows about but the registry
+	// does not, listed by af sessions --include-unmanaged. It is never
+	// stored: af session adopt records such a session as OriginManual.
+	OriginExternal OriginType = "external"
 )
 
 // Record is one routing/enrichment entry in the global session registry.
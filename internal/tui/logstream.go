@@ -57,7 +57,7 @@ func NewLogStreamModel(agentID string, c *client.Client, width, height int) LogS
 func (m *LogStreamModel) initViewport() {
 	vpH := max(4, m.height-logHeaderRows-logFooterRows)
 	m.vp = viewport.New(m.width-2, vpH) // -2 for left margin
-	m.vp.SetContent(dimStyle.Render("Loading events..."))
+	m.vp.SetContent(st.dim.Render("Loading events..."))
 	m.ready = true
 }
 
@@ -128,7 +128,7 @@ func (m *LogStreamModel) refreshContent() {
 		return
 	}
 	if len(m.lines) == 0 {
-		m.vp.SetContent(dimStyle.Render("No events yet..."))
+		m.vp.SetContent(st.dim.Render("No events yet..."))
 		return
 	}
 	m.vp.SetContent(strings.Join(m.lines, "\n"))
@@ -159,7 +159,7 @@ func (m LogStreamModel) View() string {
 	b.WriteString("\n")
 
 	if !m.ready {
-		b.WriteString("  " + dimStyle.Render("Loading...") + "\n")
+		b.WriteString("  " + st.dim.Render("Loading...") + "\n")
 	} else {
 		b.WriteString("  ")
 		b.WriteString(m.vp.View())
@@ -172,9 +172,9 @@ func (m LogStreamModel) View() string {
 
 func (m LogStreamModel) viewHeader() string {
 	return fmt.Sprintf("\n  %s  %s  %s\n",
-		titleStyle.Render("aetherflow"),
-		paneHeaderStyle.Render("Log Stream"),
-		cyanStyle.Render(m.agentID),
+		st.title.Render("aetherflow"),
+		st.paneHeader.Render("Log Stream"),
+		st.cyan.Render(m.agentID),
 	)
 }
 
@@ -182,14 +182,14 @@ func (m LogStreamModel) viewFooter() string {
 	scrollLabel := ""
 	if m.ready {
 		pct := m.vp.ScrollPercent() * 100
-		scrollLabel = dimStyle.Render(fmt.Sprintf("  %.0f%%", pct))
+		scrollLabel = st.dim.Render(fmt.Sprintf("  %.0f%%", pct))
 	}
 	autoLabel := ""
 	if m.autoScroll {
-		autoLabel = "  " + greenStyle.Render("[follow]")
+		autoLabel = "  " + st.green.Render("[follow]")
 	}
 	return fmt.Sprintf("  %s%s%s\n",
-		dimStyle.Render("j/k scroll  g top  G bottom+follow  q back"),
+		st.dim.Render("j/k scroll  g top  G bottom+follow  q back"),
 		scrollLabel,
 		autoLabel,
 	)
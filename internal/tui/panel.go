@@ -95,7 +95,7 @@ const (
 	borderLR   = 2 // left + right border chars
 	paddingLR  = 2 // Padding(0,1) → 1 left + 1 right
 	borderTB   = 2 // top + bottom border rows
-	metaLines  = 6 // "Agent" header + name + pid + role/up + spawned + session
+	metaLines  = 7 // "Agent" header + name + pid + role/up + spawned + branch + session
 	headerRows = 3 // panel header bar + blank line
 	footerRows = 2 // blank line + help text
 )
@@ -167,15 +167,15 @@ func (m *PanelModel) initViewports() {
 // renderProgLogs formats prog log entries for the logs viewport.
 func (m *PanelModel) renderProgLogs(textW int) string {
 	if m.taskDetail == nil {
-		return paneHeaderStyle.Render("Prog Logs") + "\n" + dimStyle.Render("Loading...")
+		return st.paneHeader.Render("Prog Logs") + "\n" + st.dim.Render("Loading...")
 	}
 	logs := m.taskDetail.Logs
 	if len(logs) == 0 {
-		return paneHeaderStyle.Render("Prog Logs") + "\n" + dimStyle.Render("No logs yet")
+		return st.paneHeader.Render("Prog Logs") + "\n" + st.dim.Render("No logs yet")
 	}
 
 	var b strings.Builder
-	b.WriteString(paneHeaderStyle.Render(fmt.Sprintf("Prog Logs (%d)", len(logs))))
+	b.WriteString(st.paneHeader.Render(fmt.Sprintf("Prog Logs (%d)", len(logs))))
 	msgW := max(10, textW-18) // 16 for timestamp + 2 for gap
 	for _, log := range logs {
 		b.WriteString("\n")
@@ -183,7 +183,7 @@ func (m *PanelModel) renderProgLogs(textW int) string {
 		if len(ts) > 16 {
 			ts = ts[:16]
 		}
-		b.WriteString(fmt.Sprintf("%s  %s", dimStyle.Render(ts), wrapText(log.Message, msgW)))
+		b.WriteString(fmt.Sprintf("%s  %s", st.dim.Render(ts), wrapText(log.Message, msgW)))
 	}
 	return b.String()
 }
@@ -191,11 +191,11 @@ func (m *PanelModel) renderProgLogs(textW int) string {
 // renderToolCalls formats tool calls, limited to maxRows content lines.
 func (m *PanelModel) renderToolCalls(textW, maxRows int) string {
 	var b strings.Builder
-	b.WriteString(paneHeaderStyle.Render("Tool Calls"))
+	b.WriteString(st.paneHeader.Render("Tool Calls"))
 
 	if m.agentDetail == nil || len(m.agentDetail.ToolCalls) == 0 {
 		b.WriteString("\n")
-		b.WriteString(dimStyle.Render("waiting for tool calls..."))
+		b.WriteString(st.dim.Render("waiting for tool calls..."))
 		return b.String()
 	}
 
@@ -209,10 +209,10 @@ func (m *PanelModel) renderToolCalls(textW, maxRows int) string {
 
 	// Column headers use 1 row.
 	b.WriteString(fmt.Sprintf("\n%s  %s %s %s",
-		dimStyle.Render(padLeft("AGE", colTime)),
-		dimStyle.Render(padRight("TOOL", colTool)),
-		dimStyle.Render(padRight("INPUT", inputW)),
-		dimStyle.Render(padLeft("DUR", colDur)),
+		st.dim.Render(padLeft("AGE", colTime)),
+		st.dim.Render(padRight("TOOL", colTool)),
+		st.dim.Render(padRight("INPUT", inputW)),
+		st.dim.Render(padLeft("DUR", colDur)),
 	))
 
 	// Data rows. Header title + column labels = 2 lines.
@@ -236,10 +236,10 @@ func (m *PanelModel) renderToolCalls(textW, maxRows int) string {
 		}
 
 		b.WriteString(fmt.Sprintf("\n%s  %s %s %s",
-			dimStyle.Render(padLeft(age, colTime)),
-			cyanStyle.Render(padRight(tc.Tool, colTool)),
+			st.dim.Render(padLeft(age, colTime)),
+			st.cyan.Render(padRight(tc.Tool, colTool)),
 			padRight(label, inputW),
-			dimStyle.Render(padLeft(dur, colDur)),
+			st.dim.Render(padLeft(dur, colDur)),
 		))
 	}
 
@@ -326,7 +326,7 @@ func (m PanelModel) View() string {
 	b.WriteString(m.viewPanelHeader())
 	b.WriteString("\n")
 	if !m.ready {
-		b.WriteString("  " + dimStyle.Render("Loading...") + "\n")
+		b.WriteString("  " + st.dim.Render("Loading...") + "\n")
 	} else {
 		b.WriteString(m.viewBody())
 	}
@@ -362,9 +362,9 @@ func (m PanelModel) viewBody() string {
 // boxW = lipgloss Width (inside border, includes padding).
 // boxH = lipgloss Height (inside border, content rows).
 func (m PanelModel) boxStyle(id paneID, boxW, boxH int) lipgloss.Style {
-	base := paneBorder
+	base := st.paneBorder
 	if id == m.focus {
-		base = paneBorderSelected
+		base = st.paneBorderSelected
 	}
 	return base.Width(boxW).Height(boxH)
 }
@@ -381,29 +381,37 @@ func (m PanelModel) renderAgentMeta() string {
 	sessionStr := "—"
 	if a.SessionID != "" {
 		sessionStr = a.SessionID
+	} else if a.SessionError != "" {
+		sessionStr = st.red.Render("capture failed")
+	}
+
+	branchStr := "—"
+	if a.Branch != "" {
+		branchStr = a.Branch
 	}
 
 	var b strings.Builder
-	b.WriteString(paneHeaderStyle.Render("Agent") + "\n")
-	b.WriteString(fmt.Sprintf("%s %s\n", dimStyle.Render("Name:"), a.ID))
-	b.WriteString(fmt.Sprintf("%s %d\n", dimStyle.Render("PID:"), a.PID))
+	b.WriteString(st.paneHeader.Render("Agent") + "\n")
+	b.WriteString(fmt.Sprintf("%s %s\n", st.dim.Render("Name:"), a.ID))
+	b.WriteString(fmt.Sprintf("%s %d\n", st.dim.Render("PID:"), a.PID))
 	b.WriteString(fmt.Sprintf("%s %s  %s %s\n",
-		dimStyle.Render("Role:"), magentaStyle.Render(a.Role),
-		dimStyle.Render("Up:"), greenStyle.Render(uptime),
+		st.dim.Render("Role:"), st.magenta.Render(a.Role),
+		st.dim.Render("Up:"), st.green.Render(uptime),
 	))
-	b.WriteString(fmt.Sprintf("%s %s\n", dimStyle.Render("Spawned:"), spawnStr))
-	b.WriteString(fmt.Sprintf("%s %s", dimStyle.Render("Session:"), sessionStr))
+	b.WriteString(fmt.Sprintf("%s %s\n", st.dim.Render("Spawned:"), spawnStr))
+	b.WriteString(fmt.Sprintf("%s %s\n", st.dim.Render("Branch:"), branchStr))
+	b.WriteString(fmt.Sprintf("%s %s", st.dim.Render("Session:"), sessionStr))
 	return b.String()
 }
 
 // viewPanelHeader renders the top bar.
 func (m PanelModel) viewPanelHeader() string {
 	return fmt.Sprintf("\n  %s  %s  %s  %s  %s\n",
-		titleStyle.Render("aetherflow"),
-		paneHeaderStyle.Render(m.agent.ID),
-		blueStyle.Render(m.agent.TaskID),
-		greenStyle.Render(formatUptime(m.agent.SpawnTime)),
-		magentaStyle.Render(m.agent.Role),
+		st.title.Render("aetherflow"),
+		st.paneHeader.Render(m.agent.ID),
+		st.blue.Render(m.agent.TaskID),
+		st.green.Render(formatUptime(m.agent.SpawnTime)),
+		st.magenta.Render(m.agent.Role),
 	)
 }
 
@@ -423,17 +431,17 @@ func (m PanelModel) viewPanelFooter() string {
 	switch m.focus {
 	case paneTaskInfo:
 		if m.ready {
-			scrollPct = dimStyle.Render(fmt.Sprintf("  %.0f%%", m.taskVP.ScrollPercent()*100))
+			scrollPct = st.dim.Render(fmt.Sprintf("  %.0f%%", m.taskVP.ScrollPercent()*100))
 		}
 	case paneProgLogs:
 		if m.ready {
-			scrollPct = dimStyle.Render(fmt.Sprintf("  %.0f%%", m.logsVP.ScrollPercent()*100))
+			scrollPct = st.dim.Render(fmt.Sprintf("  %.0f%%", m.logsVP.ScrollPercent()*100))
 		}
 	}
 
 	return fmt.Sprintf("  %s  %s%s\n",
-		dimStyle.Render("j/k scroll  tab focus  l logs  q back"),
-		cyanStyle.Render(focusLabel),
+		st.dim.Render("j/k scroll  tab focus  l logs  q back"),
+		st.cyan.Render(focusLabel),
 		scrollPct,
 	)
 }
@@ -72,34 +72,34 @@ func renderMarkdown(md string, width int) string {
 // in the task info pane. Returns a string ready for a viewport.
 func renderTaskInfo(td *TaskDetail, width int) string {
 	if td == nil {
-		return dimStyle.Render("Loading task info...")
+		return st.dim.Render("Loading task info...")
 	}
 
 	var b strings.Builder
 
 	// Title
-	b.WriteString(paneHeaderStyle.Render(td.Title))
+	b.WriteString(st.paneHeader.Render(td.Title))
 	b.WriteString("\n\n")
 
 	// Status / Priority / ID
-	statusColor := dimStyle
+	statusColor := st.dim
 	switch td.Status {
 	case "in_progress":
-		statusColor = greenStyle
+		statusColor = st.green
 	case "open":
-		statusColor = blueStyle
+		statusColor = st.blue
 	case "blocked":
-		statusColor = redStyle
+		statusColor = st.red
 	case "done":
-		statusColor = dimStyle
+		statusColor = st.dim
 	}
 
 	b.WriteString(fmt.Sprintf("%s %s  %s %s  %s %s",
-		dimStyle.Render("Status:"),
+		st.dim.Render("Status:"),
 		statusColor.Render(td.Status),
-		dimStyle.Render("Priority:"),
+		st.dim.Render("Priority:"),
 		fmt.Sprintf("P%d", td.Priority),
-		dimStyle.Render("ID:"),
+		st.dim.Render("ID:"),
 		td.ID,
 	))
 	b.WriteString("\n")
@@ -107,8 +107,8 @@ func renderTaskInfo(td *TaskDetail, width int) string {
 	// Dependencies
 	if len(td.Dependencies) > 0 {
 		b.WriteString(fmt.Sprintf("%s %s",
-			dimStyle.Render("Deps:"),
-			blueStyle.Render(strings.Join(td.Dependencies, ", ")),
+			st.dim.Render("Deps:"),
+			st.blue.Render(strings.Join(td.Dependencies, ", ")),
 		))
 		b.WriteString("\n")
 	}
@@ -116,7 +116,7 @@ func renderTaskInfo(td *TaskDetail, width int) string {
 	// Description (rendered as markdown)
 	if td.Description != "" {
 		b.WriteString("\n")
-		b.WriteString(dimStyle.Render("── Description ──"))
+		b.WriteString(st.dim.Render("── Description ──"))
 		b.WriteString("\n")
 		b.WriteString(renderMarkdown(td.Description, width))
 		b.WriteString("\n")
@@ -125,7 +125,7 @@ func renderTaskInfo(td *TaskDetail, width int) string {
 	// Definition of Done (rendered as markdown)
 	if td.DefinitionOfDone != nil && *td.DefinitionOfDone != "" {
 		b.WriteString("\n")
-		b.WriteString(dimStyle.Render("── Definition of Done ──"))
+		b.WriteString(st.dim.Render("── Definition of Done ──"))
 		b.WriteString("\n")
 		b.WriteString(renderMarkdown(*td.DefinitionOfDone, width))
 		b.WriteString("\n")
@@ -0,0 +1,176 @@
+package tui
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Theme maps the TUI's style roles to colors. A color is anything lipgloss
+// accepts: an ANSI index ("0"-"255") or a hex value ("#1e66f5").
+type Theme struct {
+	Title    lipgloss.Color // screen titles
+	Dim      lipgloss.Color // secondary text and idle pane borders
+	Green    lipgloss.Color // healthy / active
+	Yellow   lipgloss.Color // waiting / draining
+	Red      lipgloss.Color // errors / paused
+	Cyan     lipgloss.Color // agent names
+	Blue     lipgloss.Color // task IDs
+	Magenta  lipgloss.Color // roles
+	Header   lipgloss.Color // pane headers
+	Selected lipgloss.Color // border of the focused pane
+}
+
+// DefaultThemeName is the theme used when none is configured.
+const DefaultThemeName = "default"
+
+// themes are the built-in themes, selectable by name.
+var themes = map[string]Theme{
+	// default uses the bright ANSI colors, tuned for dark terminals.
+	"default": {
+		Title:    "12",
+		Dim:      "8",
+		Green:    "10",
+		Yellow:   "11",
+		Red:      "9",
+		Cyan:     "14",
+		Blue:     "12",
+		Magenta:  "13",
+		Header:   "14",
+		Selected: "14",
+	},
+	// light uses the normal ANSI colors, which stay readable on light
+	// backgrounds where the bright ones wash out.
+	"light": {
+		Title:    "4",
+		Dim:      "244",
+		Green:    "2",
+		Yellow:   "130",
+		Red:      "1",
+		Cyan:     "6",
+		Blue:     "4",
+		Magenta:  "5",
+		Header:   "6",
+		Selected: "4",
+	},
+	// high-contrast keeps secondary text bright and avoids pairing red
+	// with green hues that are hard to tell apart.
+	"high-contrast": {
+		Title:    "15",
+		Dim:      "250",
+		Green:    "39",
+		Yellow:   "226",
+		Red:      "208",
+		Cyan:     "51",
+		Blue:     "75",
+		Magenta:  "213",
+		Header:   "15",
+		Selected: "226",
+	},
+}
+
+// ThemeNames returns the built-in theme names, sorted.
+func ThemeNames() []string {
+	names := make([]string, 0, len(themes))
+	for name := range themes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ResolveTheme returns the built-in theme called name ("" means
+// DefaultThemeName) with colors overridden per role. Override keys are role
+// names: title, dim, green, yellow, red, cyan, blue, magenta, header, selected.
+func ResolveTheme(name string, colors map[string]string) (Theme, error) {
+	if name == "" {
+		name = DefaultThemeName
+	}
+	t, ok := themes[name]
+	if !ok {
+		return Theme{}, fmt.Errorf("unknown theme %q (want one of %s)", name, strings.Join(ThemeNames(), ", "))
+	}
+	for role, color := range colors {
+		field := t.role(role)
+		if field == nil {
+			return Theme{}, fmt.Errorf("unknown theme color role %q", role)
+		}
+		if color == "" {
+			return Theme{}, fmt.Errorf("theme color for %q is empty", role)
+		}
+		*field = lipgloss.Color(color)
+	}
+	return t, nil
+}
+
+// role returns a pointer to the color for a role name, or nil.
+func (t *Theme) role(name string) *lipgloss.Color {
+	switch name {
+	case "title":
+		return &t.Title
+	case "dim":
+		return &t.Dim
+	case "green":
+		return &t.Green
+	case "yellow":
+		return &t.Yellow
+	case "red":
+		return &t.Red
+	case "cyan":
+		return &t.Cyan
+	case "blue":
+		return &t.Blue
+	case "magenta":
+		return &t.Magenta
+	case "header":
+		return &t.Header
+	case "selected":
+		return &t.Selected
+	}
+	return nil
+}
+
+// styles holds the lipgloss styles built from a theme. They are built once
+// per theme, not on every View() call. As panes and screens are added, new
+// styles go here.
+type styles struct {
+	title              lipgloss.Style
+	dim                lipgloss.Style
+	green              lipgloss.Style
+	yellow             lipgloss.Style
+	red                lipgloss.Style
+	cyan               lipgloss.Style
+	blue               lipgloss.Style
+	magenta            lipgloss.Style
+	paneHeader         lipgloss.Style
+	paneBorder         lipgloss.Style
+	paneBorderSelected lipgloss.Style
+}
+
+func newStyles(t Theme) styles {
+	return styles{
+		title:      lipgloss.NewStyle().Bold(true).Foreground(t.Title),
+		dim:        lipgloss.NewStyle().Foreground(t.Dim),
+		green:      lipgloss.NewStyle().Foreground(t.Green),
+		yellow:     lipgloss.NewStyle().Foreground(t.Yellow),
+		red:        lipgloss.NewStyle().Foreground(t.Red),
+		cyan:       lipgloss.NewStyle().Foreground(t.Cyan),
+		blue:       lipgloss.NewStyle().Foreground(t.Blue),
+		magenta:    lipgloss.NewStyle().Foreground(t.Magenta),
+		paneHeader: lipgloss.NewStyle().Bold(true).Foreground(t.Header),
+		paneBorder: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(t.Dim).
+			Padding(0, 1),
+		paneBorderSelected: lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(t.Selected).
+			Padding(0, 1),
+	}
+}
+
+// st is the active style set. Run replaces it with the configured theme
+// before the program starts; it is never changed while rendering.
+var st = newStyles(themes[DefaultThemeName])
@@ -2,8 +2,8 @@
 // the aetherflow daemon. It provides a k9s/btop-style interface with a
 // dashboard overview, agent detail panels, and log streaming.
 //
-// The TUI communicates with the daemon via its HTTP API
-// and polls for updates on a configurable interval.
+// The TUI communicates with the daemon via its HTTP API. Pool status arrives
+// over a streaming connection; agent details are polled on an interval.
 package tui
 
 import (
@@ -16,62 +16,35 @@ import (
 	"github.com/charmbracelet/lipgloss"
 )
 
-// pollInterval is the default interval between daemon status polls.
+// pollInterval is the default interval between agent detail polls, and
+// between reconnect attempts while the status stream is down.
 const pollInterval = 2 * time.Second
 
-// Styles are defined at package level so they're allocated once, not on
-// every View() call. As panes and screens are added, new styles go here.
-var (
-	titleStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("12")) // bright blue
-
-	dimStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("8"))
-
-	greenStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("10"))
-
-	yellowStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("11"))
-
-	redStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("9"))
-
-	cyanStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("14"))
-
-	blueStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("12"))
-
-	magentaStyle = lipgloss.NewStyle().
-			Foreground(lipgloss.Color("13"))
-
-	paneHeaderStyle = lipgloss.NewStyle().
-			Bold(true).
-			Foreground(lipgloss.Color("14"))
-
-	paneBorder = lipgloss.NewStyle().
-			Border(lipgloss.RoundedBorder()).
-			BorderForeground(lipgloss.Color("8")).
-			Padding(0, 1)
-
-	paneBorderSelected = lipgloss.NewStyle().
-				Border(lipgloss.RoundedBorder()).
-				BorderForeground(lipgloss.Color("14")).
-				Padding(0, 1)
-)
-
 // Config holds the configuration needed to run the TUI.
 type Config struct {
 	// DaemonURL is the HTTP URL for the daemon API.
 	DaemonURL string
+
+	// Project selects one project of a multi-project daemon. Empty shows
+	// the primary project, with a line per project under the header.
+	Project string
+
+	// Theme sets the colors. The zero value uses the default theme.
+	Theme Theme
 }
 
-// statusMsg carries the result of a daemon status poll.
+// statusMsg carries a status frame from the daemon. stream is the stream
+// it was read from, so the next frame can be awaited.
 type statusMsg struct {
 	status *client.FullStatus
 	err    error
+	stream *client.StatusStream
+}
+
+// streamMsg carries the result of opening the status stream.
+type streamMsg struct {
+	stream *client.StatusStream
+	err    error
 }
 
 // agentDetailsMsg carries the result of polling all agents' details.
@@ -91,15 +64,46 @@ const (
 	screenLogStream
 )
 
+// paneLayout controls how agent panes are arranged on the dashboard.
+type paneLayout int
+
+const (
+	layoutStacked paneLayout = iota // one full-width pane per row
+	layoutGrid                      // panes reflowed into columns
+)
+
+const (
+	// minGridPaneWidth is the narrowest pane (including border) the grid
+	// layout will produce. Below this the tool call columns get too cramped
+	// to read, so wide terminals get more columns and narrow ones get one.
+	minGridPaneWidth = 64
+
+	// maxGridColumns caps the grid so panes stay readable on ultra-wide
+	// terminals.
+	maxGridColumns = 4
+)
+
+// gridColumns returns how many pane columns fit in the given terminal width.
+// Always at least 1.
+func gridColumns(width int) int {
+	if width <= 0 {
+		width = 80
+	}
+	return max(1, min(maxGridColumns, width/minGridPaneWidth))
+}
+
 // Model is the top-level bubbletea model for the TUI.
 type Model struct {
 	config       Config
 	client       *client.Client
+	stream       *client.StatusStream // nil until connected, or after it drops
+	connecting   bool                 // a stream open is in flight
 	width        int
 	height       int
 	status       *client.FullStatus
 	err          error
 	selected     int                            // index of selected agent pane
+	layout       paneLayout                     // dashboard pane arrangement
 	agentDetails map[string]*client.AgentDetail // agentID → detail with tool calls
 	screen       screen                         // current screen
 	panel        PanelModel                     // agent master panel (active when screen == screenPanel)
@@ -108,39 +112,54 @@ type Model struct {
 
 // New creates a new TUI model with the given configuration.
 func New(cfg Config) Model {
+	c := client.NewPersistent(cfg.DaemonURL)
+	c.SetProject(cfg.Project)
 	return Model{
-		config: cfg,
-		client: client.New(cfg.DaemonURL),
+		config:     cfg,
+		client:     c,
+		connecting: true, // Init opens the stream
 	}
 }
 
-// Init implements tea.Model. Kicks off the first status poll and tick.
+// Init implements tea.Model. Opens the status stream and starts the tick.
 // Agent details for all running agents are fetched once the first
 // statusMsg arrives.
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(pollStatus(m.client), tick())
+	return tea.Batch(openStatusStream(m.client), tick())
 }
 
-// pollStatus fetches the full daemon status as a bubbletea Cmd.
-func pollStatus(c *client.Client) tea.Cmd {
+// openStatusStream connects to the daemon's status stream as a bubbletea Cmd.
+func openStatusStream(c *client.Client) tea.Cmd {
 	return func() tea.Msg {
-		status, err := c.StatusFull()
-		return statusMsg{status: status, err: err}
+		stream, err := c.StatusStream()
+		return streamMsg{stream: stream, err: err}
 	}
 }
 
-// pollAgentDetails fetches detail for all running agents. Each agent gets
-// its own RPC call; results are collected into a single message.
+// nextStatus waits for the next frame on the status stream.
+func nextStatus(stream *client.StatusStream) tea.Cmd {
+	return func() tea.Msg {
+		status, err := stream.Next()
+		return statusMsg{status: status, err: err, stream: stream}
+	}
+}
+
+// pollAgentDetails fetches detail for all running agents in a single
+// batched request. Agents the daemon couldn't resolve are left out.
 func pollAgentDetails(c *client.Client, agents []client.AgentStatus) tea.Cmd {
 	if len(agents) == 0 {
 		return nil
 	}
 	return func() tea.Msg {
+		names := make([]string, len(agents))
+		for i, a := range agents {
+			names[i] = a.ID
+		}
 		details := make(map[string]*client.AgentDetail, len(agents))
-		for _, a := range agents {
-			detail, err := c.StatusAgent(a.ID, 5)
-			if err == nil {
-				details[a.ID] = detail
+		result, err := c.StatusAgents(names, 5)
+		if err == nil {
+			for name, detail := range result.Agents {
+				details[name] = detail
 			}
 		}
 		return agentDetailsMsg{details: details}
@@ -156,6 +175,21 @@ func tick() tea.Cmd {
 
 // Update implements tea.Model. Handles key presses, window resize, and status polls.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Stream messages are handled on every screen so the read loop never
+	// stalls while a panel is open.
+	switch msg := msg.(type) {
+	case streamMsg:
+		m.connecting = false
+		if msg.err != nil {
+			m.err = msg.err // retried on the next dashboard tick
+			return m, nil
+		}
+		m.stream = msg.stream
+		return m, nextStatus(msg.stream)
+	case statusMsg:
+		return m.updateStatus(msg)
+	}
+
 	switch m.screen {
 	case screenPanel:
 		return m.updatePanel(msg)
@@ -172,15 +206,27 @@ func (m Model) updateDashboard(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "q", "esc", "ctrl+c":
-			return m, tea.Quit
+			return m.quit()
 		case "j", "down":
 			if m.status != nil && len(m.status.Agents) > 0 {
-				m.selected = min(m.selected+1, len(m.status.Agents)-1)
+				m.selected = min(m.selected+m.rowStep(), len(m.status.Agents)-1)
 			}
 		case "k", "up":
-			if m.selected > 0 {
+			m.selected = max(0, m.selected-m.rowStep())
+		case "l", "right":
+			if m.layout == layoutGrid && m.status != nil && len(m.status.Agents) > 0 {
+				m.selected = min(m.selected+1, len(m.status.Agents)-1)
+			}
+		case "h", "left":
+			if m.layout == layoutGrid && m.selected > 0 {
 				m.selected--
 			}
+		case "g":
+			if m.layout == layoutGrid {
+				m.layout = layoutStacked
+			} else {
+				m.layout = layoutGrid
+			}
 		case "enter":
 			if m.status != nil && m.selected < len(m.status.Agents) {
 				agent := m.status.Agents[m.selected]
@@ -197,23 +243,15 @@ func (m Model) updateDashboard(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 
-	case statusMsg:
-		m.status = msg.status
-		m.err = msg.err
-		// Clamp selection if agents list shrank.
-		if m.status != nil && m.selected >= len(m.status.Agents) {
-			m.selected = max(0, len(m.status.Agents)-1)
-		}
-		// Fetch details for all agents on first status arrival.
-		if m.agentDetails == nil && m.status != nil {
-			return m, pollAgentDetails(m.client, m.status.Agents)
-		}
-
 	case agentDetailsMsg:
 		m.agentDetails = msg.details
 
 	case tickMsg:
-		cmds := []tea.Cmd{pollStatus(m.client), tick()}
+		cmds := []tea.Cmd{tick()}
+		if m.stream == nil && !m.connecting {
+			m.connecting = true
+			cmds = append(cmds, openStatusStream(m.client))
+		}
 		if m.status != nil && len(m.status.Agents) > 0 {
 			cmds = append(cmds, pollAgentDetails(m.client, m.status.Agents))
 		}
@@ -223,6 +261,53 @@ func (m Model) updateDashboard(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
+// updateStatus applies a status frame and waits for the next one. A read
+// error drops the stream; the next dashboard tick reconnects.
+func (m Model) updateStatus(msg statusMsg) (tea.Model, tea.Cmd) {
+	m.status = msg.status
+	m.err = msg.err
+	// Clamp selection if agents list shrank.
+	if m.status != nil && m.selected >= len(m.status.Agents) {
+		m.selected = max(0, len(m.status.Agents)-1)
+	}
+
+	var cmds []tea.Cmd
+	if msg.stream != nil {
+		if msg.err != nil {
+			_ = msg.stream.Close()
+			if m.stream == msg.stream {
+				m.stream = nil
+			}
+		} else {
+			cmds = append(cmds, nextStatus(msg.stream))
+		}
+	}
+	// Fetch details for all agents on first status arrival.
+	if m.agentDetails == nil && m.status != nil {
+		cmds = append(cmds, pollAgentDetails(m.client, m.status.Agents))
+	}
+	return m, tea.Batch(cmds...)
+}
+
+// quit closes the status stream, so the daemon stops building frames for
+// it, and ends the program.
+func (m Model) quit() (tea.Model, tea.Cmd) {
+	if m.stream != nil {
+		_ = m.stream.Close()
+		m.stream = nil
+	}
+	return m, tea.Quit
+}
+
+// rowStep is how far j/k move the selection: one pane when stacked, one
+// row of panes in the grid.
+func (m Model) rowStep() int {
+	if m.layout == layoutGrid {
+		return gridColumns(m.width)
+	}
+	return 1
+}
+
 // fetchInitialEventsCmd returns a Cmd that fetches the initial events for an agent.
 func fetchInitialEventsCmd(c *client.Client, agentID string) tea.Cmd {
 	return func() tea.Msg {
@@ -240,7 +325,7 @@ func (m Model) updatePanel(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if keyMsg, ok := msg.(tea.KeyMsg); ok {
 		switch keyMsg.String() {
 		case "ctrl+c":
-			return m, tea.Quit
+			return m.quit()
 		case "q", "esc":
 			m.screen = screenDashboard
 			return m, nil
@@ -286,7 +371,7 @@ func (m Model) updateLogStream(msg tea.Msg) (tea.Model, tea.Cmd) {
 	if keyMsg, ok := msg.(tea.KeyMsg); ok {
 		switch keyMsg.String() {
 		case "ctrl+c":
-			return m, tea.Quit
+			return m.quit()
 		case "q", "esc":
 			m.screen = screenPanel
 			return m, nil
@@ -334,6 +419,7 @@ func (m Model) viewDashboard() string {
 	var b strings.Builder
 
 	b.WriteString(m.viewHeader())
+	b.WriteString(m.viewProjects())
 	b.WriteString("\n")
 	b.WriteString(m.viewAgentPanes())
 	b.WriteString(m.viewQueue())
@@ -346,15 +432,15 @@ func (m Model) viewDashboard() string {
 func (m Model) viewHeader() string {
 	if m.err != nil {
 		return fmt.Sprintf("\n  %s  %s\n",
-			titleStyle.Render("aetherflow"),
-			yellowStyle.Render("connecting to daemon..."),
+			st.title.Render("aetherflow"),
+			st.yellow.Render("connecting to daemon..."),
 		)
 	}
 
 	if m.status == nil {
 		return fmt.Sprintf("\n  %s  %s\n",
-			titleStyle.Render("aetherflow"),
-			dimStyle.Render("connecting..."),
+			st.title.Render("aetherflow"),
+			st.dim.Render("connecting..."),
 		)
 	}
 
@@ -363,35 +449,69 @@ func (m Model) viewHeader() string {
 
 	var util string
 	if active > 0 {
-		util = greenStyle.Render(fmt.Sprintf("%d/%d active", active, s.PoolSize))
+		util = st.green.Render(fmt.Sprintf("%d/%d active", active, s.PoolSize))
 	} else {
-		util = dimStyle.Render(fmt.Sprintf("%d/%d active", active, s.PoolSize))
+		util = st.dim.Render(fmt.Sprintf("%d/%d active", active, s.PoolSize))
 	}
 
 	mode := ""
 	switch s.PoolMode {
 	case "draining":
-		mode = "  " + yellowStyle.Render("[draining]")
+		mode = "  " + st.yellow.Render("[draining]")
 	case "paused":
-		mode = "  " + redStyle.Render("[paused]")
+		mode = "  " + st.red.Render("[paused]")
 	}
 	if s.IsManualSpawnPolicy() {
-		mode += "  " + yellowStyle.Render("[spawn:manual]")
+		mode += "  " + st.yellow.Render("[spawn:manual]")
+	}
+
+	throughput := ""
+	if s.CompletedLastHour > 0 {
+		throughput = "  " + st.dim.Render(fmt.Sprintf("%d done/1h (%.1f/h)", s.CompletedLastHour, s.RatePerHour))
 	}
 
 	project := ""
-	if s.Project != "" {
-		project = "  " + dimStyle.Render("("+s.Project+")")
+	if label := s.Label(); label != "" {
+		project = "  " + st.dim.Render("("+label+")")
 	}
 
-	return fmt.Sprintf("\n  %s  %s%s%s\n",
-		titleStyle.Render("aetherflow"),
-		util, mode, project,
+	return fmt.Sprintf("\n  %s  %s%s%s%s\n",
+		st.title.Render("aetherflow"),
+		util, mode, throughput, project,
 	)
 }
 
-// viewAgentPanes renders a stacked pane for every running agent. Each pane
-// has a header with agent metadata and a list of recent tool calls.
+// viewProjects renders one line per project on a multi-project daemon:
+// its agents, mode, and queue. The panes below show the primary project.
+func (m Model) viewProjects() string {
+	if m.status == nil || m.err != nil || len(m.status.Projects) < 2 {
+		return ""
+	}
+	var b strings.Builder
+	for _, p := range m.status.Projects {
+		util := st.dim.Render(fmt.Sprintf("%d/%d active", len(p.Agents), p.PoolSize))
+		if len(p.Agents) > 0 {
+			util = st.green.Render(fmt.Sprintf("%d/%d active", len(p.Agents), p.PoolSize))
+		}
+		mode := ""
+		switch p.PoolMode {
+		case "draining":
+			mode = "  " + st.yellow.Render("[draining]")
+		case "paused":
+			mode = "  " + st.red.Render("[paused]")
+		}
+		b.WriteString(fmt.Sprintf("  %s  %s%s  %s\n",
+			st.blue.Render(padRight(p.Project, 16)),
+			util, mode,
+			st.dim.Render(fmt.Sprintf("%d queued", len(p.Queue))),
+		))
+	}
+	return b.String()
+}
+
+// viewAgentPanes renders a pane for every running agent, stacked or in a
+// grid depending on the layout. Each pane has a header with agent metadata
+// and a list of recent tool calls.
 func (m Model) viewAgentPanes() string {
 	if m.status == nil || m.err != nil {
 		return ""
@@ -399,18 +519,38 @@ func (m Model) viewAgentPanes() string {
 
 	agents := m.status.Agents
 	if len(agents) == 0 {
-		return "  " + dimStyle.Render("No agents running") + "\n\n"
+		return "  " + st.dim.Render("No agents running") + "\n\n"
+	}
+
+	w := m.width
+	if w == 0 {
+		w = 80
 	}
 
 	var b strings.Builder
 
-	for i, a := range agents {
-		b.WriteString(m.viewOnePane(i, a))
+	if m.layout == layoutGrid {
+		cols := gridColumns(w)
+		paneWidth := w / cols
+		for start := 0; start < len(agents); start += cols {
+			end := min(start+cols, len(agents))
+			row := make([]string, 0, end-start)
+			for i := start; i < end; i++ {
+				row = append(row, m.viewOnePane(i, agents[i], paneWidth))
+			}
+			b.WriteString(lipgloss.JoinHorizontal(lipgloss.Top, row...))
+			b.WriteString("\n")
+		}
+	} else {
+		for i, a := range agents {
+			b.WriteString(m.viewOnePane(i, a, w))
+			b.WriteString("\n")
+		}
 	}
 
 	idle := m.status.PoolSize - len(agents)
 	if idle > 0 {
-		b.WriteString(fmt.Sprintf("  %s\n", dimStyle.Render(fmt.Sprintf("+ %d idle", idle))))
+		b.WriteString(fmt.Sprintf("  %s\n", st.dim.Render(fmt.Sprintf("+ %d idle", idle))))
 	}
 
 	return b.String()
@@ -424,17 +564,22 @@ func (m Model) viewQueue() string {
 
 	queue := m.status.Queue
 	if len(queue) == 0 {
-		return "  " + dimStyle.Render("Queue: empty") + "\n\n"
+		return "  " + st.dim.Render("Queue: empty") + "\n\n"
 	}
 
 	var b strings.Builder
-	b.WriteString(fmt.Sprintf("  %s\n", dimStyle.Render(fmt.Sprintf("Queue (%d tasks)", len(queue)))))
+	b.WriteString(fmt.Sprintf("  %s\n", st.dim.Render(fmt.Sprintf("Queue (%d tasks)", len(queue)))))
 
 	for _, t := range queue {
-		pri := dimStyle.Render(fmt.Sprintf("P%d", t.Priority))
-		b.WriteString(fmt.Sprintf("    %s  %s  %s\n",
-			blueStyle.Render(t.ID),
+		pri := st.dim.Render(fmt.Sprintf("P%d", t.Priority))
+		age := ""
+		if !t.ReadySince.IsZero() {
+			age = formatUptime(t.ReadySince)
+		}
+		b.WriteString(fmt.Sprintf("    %s  %s  %s  %s\n",
+			st.blue.Render(t.ID),
 			pri,
+			st.dim.Render(fmt.Sprintf("%5s", age)),
 			t.Title,
 		))
 	}
@@ -443,17 +588,12 @@ func (m Model) viewQueue() string {
 	return b.String()
 }
 
-// viewOnePane renders a single agent pane with a border. The pane contains
-// a header line with agent metadata and rows of recent tool calls.
-// Selected pane gets a bright cyan border; others get a dim border.
-func (m Model) viewOnePane(index int, a client.AgentStatus) string {
+// viewOnePane renders a single agent pane with a border, w columns wide.
+// The pane contains a header line with agent metadata and rows of recent
+// tool calls. Selected pane gets a bright cyan border; others get a dim border.
+func (m Model) viewOnePane(index int, a client.AgentStatus, w int) string {
 	// Border consumes 2 columns (1 each side), padding consumes 2 more
 	// (Padding(0,1) = 1 each side). Content area is width - 6.
-	// Default to 80 if we haven't received a WindowSizeMsg yet.
-	w := m.width
-	if w == 0 {
-		w = 80
-	}
 	innerWidth := max(20, w-6)
 	// boxWidth is what lipgloss Width() gets — excludes borders but includes padding.
 	boxWidth := innerWidth + 2
@@ -481,17 +621,17 @@ func (m Model) viewOnePane(index int, a client.AgentStatus) string {
 
 	// Assemble with styles.
 	var header strings.Builder
-	header.WriteString(paneHeaderStyle.Render(a.ID))
+	header.WriteString(st.paneHeader.Render(a.ID))
 	header.WriteString("  ")
-	header.WriteString(blueStyle.Render(a.TaskID))
+	header.WriteString(st.blue.Render(a.TaskID))
 	if titleText != "" {
 		header.WriteString(" ")
-		header.WriteString(dimStyle.Render(titleText))
+		header.WriteString(st.dim.Render(titleText))
 	}
 	header.WriteString(strings.Repeat(" ", gap))
-	header.WriteString(greenStyle.Render(uptime))
+	header.WriteString(st.green.Render(uptime))
 	header.WriteString("  ")
-	header.WriteString(magentaStyle.Render(a.Role))
+	header.WriteString(st.magenta.Render(a.Role))
 
 	b.WriteString(header.String())
 
@@ -510,15 +650,15 @@ func (m Model) viewOnePane(index int, a client.AgentStatus) string {
 
 	// Column headers.
 	b.WriteString(fmt.Sprintf("\n%s  %s %s %s",
-		dimStyle.Render(padLeft("AGE", colTime)),
-		dimStyle.Render(padRight("TOOL", colTool)),
-		dimStyle.Render(padRight("INPUT", titleMax)),
-		dimStyle.Render(padLeft("DUR", colDur)),
+		st.dim.Render(padLeft("AGE", colTime)),
+		st.dim.Render(padRight("TOOL", colTool)),
+		st.dim.Render(padRight("INPUT", titleMax)),
+		st.dim.Render(padLeft("DUR", colDur)),
 	))
 
 	detail, hasDetail := m.agentDetails[a.ID]
 	if !hasDetail || len(detail.ToolCalls) == 0 {
-		b.WriteString("\n" + dimStyle.Render("waiting for tool calls..."))
+		b.WriteString("\n" + st.dim.Render("waiting for tool calls..."))
 	} else {
 		// Tool calls arrive oldest-first; iterate in reverse for
 		// most-recent-at-top.
@@ -538,27 +678,30 @@ func (m Model) viewOnePane(index int, a client.AgentStatus) string {
 			}
 
 			b.WriteString(fmt.Sprintf("\n%s  %s %s %s",
-				dimStyle.Render(padLeft(age, colTime)),
-				cyanStyle.Render(padRight(tc.Tool, colTool)),
+				st.dim.Render(padLeft(age, colTime)),
+				st.cyan.Render(padRight(tc.Tool, colTool)),
 				padRight(label, titleMax),
-				dimStyle.Render(padLeft(dur, colDur)),
+				st.dim.Render(padLeft(dur, colDur)),
 			))
 		}
 	}
 
 	content := b.String()
 
-	border := paneBorder.Width(boxWidth)
+	border := st.paneBorder.Width(boxWidth)
 	if index == m.selected {
-		border = paneBorderSelected.Width(boxWidth)
+		border = st.paneBorderSelected.Width(boxWidth)
 	}
 
-	return border.Render(content) + "\n"
+	return border.Render(content)
 }
 
 // viewFooter renders the bottom help line.
 func (m Model) viewFooter() string {
-	return "  " + dimStyle.Render("j/k navigate  enter select  q quit") + "\n"
+	if m.layout == layoutGrid {
+		return "  " + st.dim.Render("h/j/k/l navigate  enter select  g stack  q quit") + "\n"
+	}
+	return "  " + st.dim.Render("j/k navigate  enter select  g grid  q quit") + "\n"
 }
 
 // formatRelativeTime returns a human-readable relative time string.
@@ -566,8 +709,10 @@ func formatRelativeTime(t time.Time) string {
 	if t.IsZero() {
 		return "?"
 	}
+	// A timestamp in the future comes from clock skew between the agent's
+	// host and ours; treat it, like anything under a second old, as now.
 	d := time.Since(t)
-	if d < 0 {
+	if d < time.Second {
 		return "now"
 	}
 	switch {
@@ -590,7 +735,8 @@ func formatUptime(t time.Time) string {
 	if t.IsZero() {
 		return "?"
 	}
-	d := time.Since(t)
+	// Clock skew can put the start in the future; count that as just started.
+	d := max(time.Since(t), 0)
 
 	switch {
 	case d < time.Minute:
@@ -643,7 +789,11 @@ func padLeft(s string, width int) string {
 
 // Run starts the TUI program with alternate screen buffer.
 func Run(cfg Config) error {
+	if cfg.Theme != (Theme{}) {
+		st = newStyles(cfg.Theme)
+	}
 	m := New(cfg)
+	defer m.client.Close()
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	_, err := p.Run()
 	return err
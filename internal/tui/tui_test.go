@@ -0,0 +1,105 @@
+package tui
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+func TestGridColumns(t *testing.T) {
+	tests := []struct {
+		width int
+		want  int
+	}{
+		{0, 1},   // no WindowSizeMsg yet — defaults to 80 columns
+		{40, 1},  // narrower than one pane still gets a single column
+		{80, 1},  // standard terminal
+		{127, 1}, // just short of two panes
+		{128, 2},
+		{200, 3},
+		{256, 4},
+		{400, 4}, // capped at maxGridColumns
+	}
+	for _, tc := range tests {
+		if got := gridColumns(tc.width); got != tc.want {
+			t.Errorf("gridColumns(%d) = %d, want %d", tc.width, got, tc.want)
+		}
+	}
+}
+
+func TestThemeOverridesTitleColor(t *testing.T) {
+	defaultTitle := newStyles(themes[DefaultThemeName]).title.GetForeground()
+
+	light, err := ResolveTheme("light", nil)
+	if err != nil {
+		t.Fatalf("ResolveTheme(light): %v", err)
+	}
+	if got := newStyles(light).title.GetForeground(); got != lipgloss.Color("4") || got == defaultTitle {
+		t.Errorf("light title color = %v, want 4 (default is %v)", got, defaultTitle)
+	}
+
+	custom, err := ResolveTheme("", map[string]string{"title": "#1e66f5"})
+	if err != nil {
+		t.Fatalf("ResolveTheme with override: %v", err)
+	}
+	if got := newStyles(custom).title.GetForeground(); got != lipgloss.Color("#1e66f5") {
+		t.Errorf("overridden title color = %v, want #1e66f5", got)
+	}
+	if got := newStyles(custom).dim.GetForeground(); got != themes[DefaultThemeName].Dim {
+		t.Errorf("dim color = %v, want the default theme's %v", got, themes[DefaultThemeName].Dim)
+	}
+
+	if _, err := ResolveTheme("solarized", nil); err == nil {
+		t.Error("expected error for unknown theme")
+	}
+	if _, err := ResolveTheme("", map[string]string{"background": "0"}); err == nil {
+		t.Error("expected error for unknown color role")
+	}
+}
+
+func TestRelativeTimesWithClockSkew(t *testing.T) {
+	future := time.Now().Add(90 * time.Second)
+	if got := formatRelativeTime(future); got != "now" {
+		t.Errorf("formatRelativeTime(future) = %q, want %q", got, "now")
+	}
+	if got := formatUptime(future); got != "0s" {
+		t.Errorf("formatUptime(future) = %q, want %q", got, "0s")
+	}
+	if got := formatRelativeTime(time.Now().Add(-15 * time.Second)); got != "15s ago" {
+		t.Errorf("formatRelativeTime(15s ago) = %q, want %q", got, "15s ago")
+	}
+}
+
+func TestQuitClosesStatusStream(t *testing.T) {
+	closed := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_, _ = w.Write([]byte(`{"pool_size":1}` + "\n"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+		close(closed)
+	}))
+	defer srv.Close()
+
+	m := New(Config{DaemonURL: srv.URL})
+	defer m.client.Close()
+	msg := openStatusStream(m.client)().(streamMsg)
+	if msg.err != nil {
+		t.Fatalf("opening stream: %v", msg.err)
+	}
+	model, _ := m.Update(msg)
+
+	_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Fatal("q did not quit")
+	}
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("status stream still open after quitting")
+	}
+}